@@ -185,6 +185,7 @@ $ azd config set defaults.location eastus`,
 	})
 
 	subFilterActions(group)
+	configVaultActions(group)
 
 	return group
 }
@@ -0,0 +1,81 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/spf13/cobra"
+)
+
+// configVaultActions registers the "azd config vault" command group.
+func configVaultActions(
+	configGroup *actions.ActionDescriptor,
+) *actions.ActionDescriptor {
+	group := configGroup.Add("vault", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "vault",
+			Short: "Manage the local vault that stores secret configuration values.",
+			Long: "Manage the local vault that stores secret configuration values, such as secure infrastructure " +
+				"parameters.\n" +
+				"Secrets are encrypted with a data key that is kept in the OS keyring, or protected with the " +
+				"passphrase in the " + config.VaultPassphraseEnvVarName + " environment variable when it is set.",
+		},
+	})
+
+	group.Add("rotate", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Short: "Re-encrypt all the vault secrets with a new data key.",
+			Long: "Generate a new data key, re-encrypt all the secrets stored in the local vaults with it and " +
+				"retire the previous key.\n" +
+				"Secrets written by previous versions of azd, which were only encoded, are encrypted as well.",
+		},
+		ActionResolver: newConfigVaultRotateAction,
+	})
+
+	return group
+}
+
+// azd config vault rotate
+
+type configVaultRotateAction struct {
+	console           input.Console
+	fileConfigManager config.FileConfigManager
+}
+
+func newConfigVaultRotateAction(
+	console input.Console,
+	fileConfigManager config.FileConfigManager,
+) actions.Action {
+	return &configVaultRotateAction{
+		console:           console,
+		fileConfigManager: fileConfigManager,
+	}
+}
+
+func (a *configVaultRotateAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Rotate the vault key (azd config vault rotate)",
+	})
+
+	spinnerMessage := "Re-encrypting vault secrets"
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+
+	rotated, err := config.RotateVaultKey(a.fileConfigManager)
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, fmt.Errorf("rotating the vault key: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Rotated the vault key and re-encrypted %d vault(s)", rotated),
+		},
+	}, nil
+}
@@ -54,6 +54,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/httputil"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/cost"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
@@ -340,6 +341,26 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 		},
 	)
 
+	container.MustRegisterSingleton(func(lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]) environment.Schema {
+		// The project config may not be available, e.g. outside of a project
+		projectConfig, _ := lazyProjectConfig.GetValue()
+		if projectConfig == nil {
+			return nil
+		}
+
+		return projectConfig.Env
+	})
+
+	container.MustRegisterSingleton(func(lazyProjectConfig *lazy.Lazy[*project.ProjectConfig]) environment.Templates {
+		// The project config may not be available, e.g. outside of a project
+		projectConfig, _ := lazyProjectConfig.GetValue()
+		if projectConfig == nil {
+			return nil
+		}
+
+		return projectConfig.Environments
+	})
+
 	container.MustRegisterSingleton(func(
 		lazyProjectConfig *lazy.Lazy[*project.ProjectConfig],
 		userConfigManager config.UserConfigManager,
@@ -566,6 +587,8 @@ func registerCommonDependencies(container *ioc.NestedContainer) {
 
 	container.MustRegisterSingleton(templates.NewTemplateManager)
 	container.MustRegisterSingleton(templates.NewSourceManager)
+	container.MustRegisterSingleton(templates.NewOciTemplatePuller)
+	container.MustRegisterSingleton(cost.NewPriceSheetManager)
 	container.MustRegisterScoped(project.NewResourceManager)
 	container.MustRegisterScoped(func(serviceLocator ioc.ServiceLocator) *lazy.Lazy[project.ResourceManager] {
 		return lazy.NewLazy(func() (project.ResourceManager, error) {
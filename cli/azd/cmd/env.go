@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"slices"
 	"strings"
@@ -120,6 +121,43 @@ func envActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 		ActionResolver: newEnvGetValueAction,
 	})
 
+	group.Add("copy", &actions.ActionDescriptorOptions{
+		Command:        newEnvCopyCmd(),
+		FlagsResolver:  newEnvCopyFlags,
+		ActionResolver: newEnvCopyAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvCopyHelpDescription,
+		},
+	})
+
+	group.Add("diff", &actions.ActionDescriptorOptions{
+		Command:        newEnvDiffCmd(),
+		FlagsResolver:  newEnvDiffFlags,
+		ActionResolver: newEnvDiffAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.TableFormat},
+		DefaultFormat:  output.TableFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvDiffHelpDescription,
+		},
+	})
+
+	group.Add("history", &actions.ActionDescriptorOptions{
+		Command:        newEnvHistoryCmd(),
+		FlagsResolver:  newEnvHistoryFlags,
+		ActionResolver: newEnvHistoryAction,
+		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
+		DefaultFormat:  output.NoneFormat,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdEnvHistoryHelpDescription,
+		},
+	})
+
+	group.Add("rollback", &actions.ActionDescriptorOptions{
+		Command:        newEnvRollbackCmd(),
+		FlagsResolver:  newEnvRollbackFlags,
+		ActionResolver: newEnvRollbackAction,
+	})
+
 	// Add env config sub-command group
 	configGroup := group.Add("config", &actions.ActionDescriptorOptions{
 		Command: &cobra.Command{
@@ -192,6 +230,7 @@ type envSetAction struct {
 	azdCtx     *azdcontext.AzdContext
 	env        *environment.Environment
 	envManager environment.Manager
+	schema     environment.Schema
 	flags      *envSetFlags
 	args       []string
 }
@@ -200,6 +239,7 @@ func newEnvSetAction(
 	azdCtx *azdcontext.AzdContext,
 	env *environment.Environment,
 	envManager environment.Manager,
+	schema environment.Schema,
 	console input.Console,
 	flags *envSetFlags,
 	args []string,
@@ -209,6 +249,7 @@ func newEnvSetAction(
 		azdCtx:     azdCtx,
 		env:        env,
 		envManager: envManager,
+		schema:     schema,
 		flags:      flags,
 		args:       args,
 	}
@@ -271,6 +312,23 @@ func (e *envSetAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
+	// Reject values that do not match the `env` schema of azure.yaml before changing anything
+	var violations []string
+	for _, key := range slices.Sorted(maps.Keys(keyValues)) {
+		if keyValues[key] == "" {
+			continue
+		}
+		if err := e.schema.CheckValue(key, keyValues[key]); err != nil {
+			violations = append(violations, fmt.Sprintf("%s %s", key, err.Error()))
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        &environment.SchemaError{Violations: violations},
+			Suggestion: "See the 'env' section of azure.yaml for the values this project expects.",
+		}
+	}
+
 	// Apply the values
 	for key, value := range keyValues {
 		warnKeyCaseConflicts(ctx, e.console, dotEnv, key)
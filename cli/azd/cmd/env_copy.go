@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getCmdEnvCopyHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Creates a new environment holding the .env values and config.json of an existing environment.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"Use %s and %s to select the .env keys to copy, with patterns like 'AZURE_*'.",
+				output.WithHighLightFormat("--include"), output.WithHighLightFormat("--exclude"))),
+			formatHelpNote(fmt.Sprintf(
+				"Use %s to leave out the values set from provisioning outputs, which belong to the source "+
+					"environment's resources.",
+				output.WithHighLightFormat("--no-outputs"))),
+			formatHelpNote(
+				"Defaults from the 'environments' section of azure.yaml matching the new name are applied first."),
+		})
+}
+
+func newEnvCopyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "copy <source> <target>",
+		Short: "Copy an environment's values and configuration to a new environment.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type envCopyFlags struct {
+	include   []string
+	exclude   []string
+	noOutputs bool
+	global    *internal.GlobalCommandOptions
+}
+
+func (f *envCopyFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	local.StringArrayVar(
+		&f.include,
+		"include",
+		nil,
+		"Only copy the .env keys matching this pattern (e.g. 'AZURE_*'). May be repeated.",
+	)
+	local.StringArrayVar(
+		&f.exclude,
+		"exclude",
+		nil,
+		"Do not copy the .env keys matching this pattern (e.g. 'SERVICE_*'). May be repeated.",
+	)
+	local.BoolVar(
+		&f.noOutputs,
+		"no-outputs",
+		false,
+		"Do not copy the values set from provisioning outputs.",
+	)
+	f.global = global
+}
+
+func newEnvCopyFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envCopyFlags {
+	flags := &envCopyFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+type envCopyAction struct {
+	envManager environment.Manager
+	console    input.Console
+	flags      *envCopyFlags
+	args       []string
+}
+
+func newEnvCopyAction(
+	envManager environment.Manager,
+	console input.Console,
+	flags *envCopyFlags,
+	args []string,
+) actions.Action {
+	return &envCopyAction{
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envCopyAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	source, target := a.args[0], a.args[1]
+
+	src, err := a.envManager.Get(ctx, source)
+	if err != nil {
+		return nil, diffEnvError(source, err)
+	}
+
+	outputKeys, err := provisioning.OutputKeys(src, a.envManager)
+	if err != nil {
+		return nil, err
+	}
+
+	// Environments provisioned before outputs were recorded have none, so every value would be copied.
+	if a.flags.noOutputs && len(outputKeys) == 0 {
+		a.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"No provisioning outputs are recorded for %s, so --no-outputs does not skip any value. "+
+					"Run 'azd provision' for %s to record them.", source, source),
+		})
+	}
+
+	spec, err := environment.CopySpec(src, target, environment.CopyOptions{
+		Include:     a.flags.include,
+		Exclude:     a.flags.exclude,
+		OutputKeys:  outputKeys,
+		SkipOutputs: a.flags.noOutputs,
+	})
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err),
+			Suggestion: "Use patterns like 'AZURE_*' with --include and --exclude.",
+		}
+	}
+
+	copied, err := a.envManager.Create(ctx, spec)
+	if err != nil {
+		if errors.Is(err, environment.ErrExists) {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf(
+					"Choose another name, or remove the environment first with 'azd env remove %s'.", target),
+			}
+		}
+
+		return nil, fmt.Errorf("creating environment: %w", err)
+	}
+
+	// Only the output keys that were copied remain outputs of the new environment.
+	var copiedOutputs []string
+	for _, key := range outputKeys {
+		if _, has := spec.Values[key]; has {
+			copiedOutputs = append(copiedOutputs, key)
+		}
+	}
+	if err := provisioning.SetOutputKeys(copied, a.envManager, copiedOutputs); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment %s was copied to %s.", source, target),
+			FollowUp: fmt.Sprintf(
+				"Run %s to use it.", output.WithHighLightFormat("azd env select %s", target)),
+		},
+	}, nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+)
+
+func Test_EnvCopyAction(t *testing.T) {
+	src := environment.NewWithValues("dev", map[string]string{
+		"AZURE_LOCATION": "westus2",
+		"API_URL":        "https://api-dev",
+	})
+	copied := environment.New("pr-1")
+
+	envMgr := &mockenv.MockEnvManager{}
+	root := t.TempDir()
+	for _, name := range []string{"dev", "pr-1", "legacy"} {
+		envMgr.On("EnvPath", mock.MatchedBy(func(env *environment.Environment) bool {
+			return env.Name() == name
+		})).Return(filepath.Join(root, name, environment.DotEnvFileName))
+	}
+	require.NoError(t, provisioning.SetOutputKeys(src, envMgr, []string{"API_URL"}))
+
+	envMgr.On("Get", mock.Anything, "dev").Return(src, nil)
+	envMgr.On("Get", mock.Anything, "missing").Return((*environment.Environment)(nil), environment.ErrNotFound)
+	envMgr.On("Create", mock.Anything, mock.MatchedBy(func(spec environment.Spec) bool {
+		return spec.Name == "pr-1"
+	})).Return(copied, nil)
+	envMgr.On("Create", mock.Anything, mock.Anything).
+		Return((*environment.Environment)(nil), environment.ErrExists)
+
+	flags := &envCopyFlags{noOutputs: true, global: &internal.GlobalCommandOptions{}}
+	action := newEnvCopyAction(envMgr, mockinput.NewMockConsole(), flags, []string{"dev", "pr-1"})
+	result, err := action.Run(t.Context())
+	require.NoError(t, err)
+	require.Contains(t, result.Message.Header, "copied to pr-1")
+
+	createCall := slices.IndexFunc(envMgr.Calls, func(call mock.Call) bool { return call.Method == "Create" })
+	spec := envMgr.Calls[createCall].Arguments.Get(1).(environment.Spec)
+	require.Equal(t, map[string]string{"AZURE_LOCATION": "westus2"}, spec.Values)
+	// The outputs that were skipped are not outputs of the copy.
+	copiedOutputs, err := provisioning.OutputKeys(copied, envMgr)
+	require.NoError(t, err)
+	require.Empty(t, copiedOutputs)
+
+	_, err = newEnvCopyAction(envMgr, mockinput.NewMockConsole(), flags, []string{"dev", "prod"}).Run(t.Context())
+	require.ErrorIs(t, err, environment.ErrExists)
+
+	_, err = newEnvCopyAction(envMgr, mockinput.NewMockConsole(), flags, []string{"missing", "pr-2"}).Run(t.Context())
+	require.ErrorIs(t, err, environment.ErrNotFound)
+
+	// Outputs are not recorded for environments provisioned before they were tracked.
+	console := mockinput.NewMockConsole()
+	envMgr.On("Get", mock.Anything, "legacy").Return(environment.NewWithValues("legacy", map[string]string{
+		"API_URL": "https://api-legacy",
+	}), nil)
+	_, err = newEnvCopyAction(envMgr, console, flags, []string{"legacy", "pr-1"}).Run(t.Context())
+	require.NoError(t, err)
+	require.Len(t, console.Output(), 1)
+	require.Contains(t, console.Output()[0], "No provisioning outputs are recorded for legacy")
+}
@@ -0,0 +1,335 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getCmdEnvDiffHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Compares the .env values and config.json of two environments, listing the keys added, removed or changed "+
+			"going from the first environment to the second.",
+		[]string{
+			formatHelpNote("With a single environment, it is compared with the default environment."),
+			formatHelpNote(fmt.Sprintf(
+				"With %s, the environment's values are compared with the outputs of its current deployment, "+
+					"listing the outputs that are stale or missing. Run 'azd env refresh' to update them.",
+				output.WithHighLightFormat("--deployed"))),
+			formatHelpNote("Values that look like secrets are masked."),
+		})
+}
+
+func newEnvDiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <environment> [<environment>]",
+		Short: "Compare the values of two environments, or of an environment and its deployment.",
+		// With --deployed, `azd env diff some-environment-name --deployed` behaves the same way as
+		// `azd env diff -e some-environment-name --deployed`, like `azd env refresh`.
+		Args: func(cmd *cobra.Command, args []string) error {
+			if deployed, _ := cmd.Flags().GetBool("deployed"); !deployed {
+				return cobra.RangeArgs(0, 2)(cmd, args)
+			}
+
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				return nil
+			}
+
+			if flagValue, err := cmd.Flags().GetString(internal.EnvironmentNameFlagName); err == nil {
+				if flagValue != "" && args[0] != flagValue {
+					return errors.New(
+						"the --environment flag and an explicit environment name as an argument may not be used together")
+				}
+			}
+
+			return cmd.Flags().Set(internal.EnvironmentNameFlagName, args[0])
+		},
+		Annotations: map[string]string{
+			"azdtest.use": "diff",
+		},
+	}
+}
+
+type envDiffFlags struct {
+	internal.EnvFlag
+	deployed bool
+	hint     string
+	global   *internal.GlobalCommandOptions
+}
+
+func (f *envDiffFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	local.BoolVar(
+		&f.deployed,
+		"deployed",
+		false,
+		"Compare the environment's values with the outputs of its current deployment.",
+	)
+	local.StringVar(&f.hint, "hint", "", "Hint to help identify the environment's deployment.")
+	f.global = global
+}
+
+func newEnvDiffFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envDiffFlags {
+	flags := &envDiffFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+type envDiffAction struct {
+	azdCtx         *azdcontext.AzdContext
+	envManager     environment.Manager
+	schema         environment.Schema
+	serviceLocator ioc.ServiceLocator
+	console        input.Console
+	formatter      output.Formatter
+	writer         io.Writer
+	flags          *envDiffFlags
+	args           []string
+}
+
+func newEnvDiffAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	schema environment.Schema,
+	serviceLocator ioc.ServiceLocator,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envDiffFlags,
+	args []string,
+) actions.Action {
+	return &envDiffAction{
+		azdCtx:         azdCtx,
+		envManager:     envManager,
+		schema:         schema,
+		serviceLocator: serviceLocator,
+		console:        console,
+		formatter:      formatter,
+		writer:         writer,
+		flags:          flags,
+		args:           args,
+	}
+}
+
+// deployedDiffName is the name the deployment is displayed with when compared with an environment.
+const deployedDiffName = "deployed"
+
+// envDiffResult is the displayed form of a comparison. Values that are or look like secrets are masked.
+type envDiffResult struct {
+	// Base is the environment the changes apply to.
+	Base string `json:"base"`
+	// Compare is the environment, or deployment, the changes lead to.
+	Compare string               `json:"compare"`
+	Changes []environment.Change `json:"changes"`
+}
+
+func (a *envDiffAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	var result *envDiffResult
+	var err error
+	if a.flags.deployed {
+		result, err = a.diffDeployed(ctx)
+	} else {
+		result, err = a.diffEnvironments(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i, change := range result.Changes {
+		change.Old = environment.MaskSecretValue(a.schema, change.Key, change.Old)
+		change.New = environment.MaskSecretValue(a.schema, change.Key, change.New)
+		result.Changes[i] = change
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(result, a.writer, nil)
+	}
+
+	if len(result.Changes) == 0 {
+		a.console.Message(ctx, fmt.Sprintf("No differences between %s and %s.", result.Base, result.Compare))
+		return nil, nil
+	}
+
+	return nil, a.formatter.Format(result.Changes, a.writer, output.TableFormatterOptions{
+		Columns: []output.Column{
+			{
+				Heading:       "KEY",
+				ValueTemplate: "{{.Key}}",
+			},
+			{
+				Heading:       "SOURCE",
+				ValueTemplate: "{{if .Config}}config.json{{else}}.env{{end}}",
+			},
+			{
+				Heading:       "CHANGE",
+				ValueTemplate: "{{.Kind}}",
+			},
+			{
+				Heading:       result.Base,
+				ValueTemplate: "{{.Old}}",
+			},
+			{
+				Heading:       result.Compare,
+				ValueTemplate: "{{.New}}",
+			},
+		},
+	})
+}
+
+// diffEnvironments compares the environments named by the arguments. With a single argument, the environment
+// selected with --environment, or the default environment, is the second one.
+func (a *envDiffAction) diffEnvironments(ctx context.Context) (*envDiffResult, error) {
+	if len(a.args) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("no environment to compare: %w", internal.ErrInvalidArgValue),
+			Suggestion: "Run 'azd env diff <environment> [<environment>]', or 'azd env diff --deployed'.",
+		}
+	}
+
+	names := a.args
+	if len(names) == 1 {
+		name, err := historyEnvName(a.azdCtx, a.flags.EnvironmentName)
+		if err != nil {
+			return nil, err
+		}
+		names = []string{a.args[0], name}
+	} else if a.flags.EnvironmentName != "" {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"--environment cannot be combined with two environment arguments: %w",
+				internal.ErrInvalidFlagCombination),
+			Suggestion: "Pass either two environment names, or one and --environment.",
+		}
+	}
+
+	envs := make([]*environment.Environment, len(names))
+	for i, name := range names {
+		env, err := a.envManager.Get(ctx, name)
+		if err != nil {
+			return nil, diffEnvError(name, err)
+		}
+		envs[i] = env
+	}
+
+	return &envDiffResult{
+		Base:    names[0],
+		Compare: names[1],
+		Changes: environment.Diff(envs[0], envs[1]),
+	}, nil
+}
+
+// diffDeployed compares the values of an environment with the outputs its deployment reports today. Only
+// outputs are compared: keys azd or the user set are never reported as removed.
+func (a *envDiffAction) diffDeployed(ctx context.Context) (*envDiffResult, error) {
+	// The environment named by the argument, if any, was set as --environment when the arguments were validated.
+	var env *environment.Environment
+	if err := a.serviceLocator.Resolve(&env); err != nil {
+		return nil, err
+	}
+
+	// Captured first: initializing the provisioning provider may add values to the environment.
+	values := env.Dotenv()
+
+	var projectConfig *project.ProjectConfig
+	if err := a.serviceLocator.Resolve(&projectConfig); err != nil {
+		return nil, err
+	}
+
+	var projectManager project.ProjectManager
+	if err := a.serviceLocator.Resolve(&projectManager); err != nil {
+		return nil, err
+	}
+
+	var importManager *project.ImportManager
+	if err := a.serviceLocator.Resolve(&importManager); err != nil {
+		return nil, err
+	}
+
+	var provisionManager *provisioning.Manager
+	if err := a.serviceLocator.Resolve(&provisionManager); err != nil {
+		return nil, err
+	}
+
+	if err := projectManager.Initialize(ctx, projectConfig); err != nil {
+		return nil, err
+	}
+
+	if err := projectManager.EnsureAllTools(ctx, projectConfig, nil); err != nil {
+		return nil, err
+	}
+
+	infra, err := importManager.ProjectInfrastructure(ctx, projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	// Same as 'azd env refresh': the provider looks up a resource group defined by the project.
+	projectResourceGroup, _ := projectConfig.ResourceGroupName.Envsubst(env.Getenv)
+	if _, has := env.LookupEnv(environment.ResourceGroupEnvVarName); !has && projectResourceGroup != "" {
+		env.DotenvSet(environment.ResourceGroupEnvVarName, projectResourceGroup)
+	}
+
+	deployed := map[string]string{}
+	for _, layer := range infra.Options.GetLayers() {
+		if err := provisionManager.Initialize(ctx, projectConfig.Path, layer); err != nil {
+			return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+		}
+
+		result, err := provisionManager.State(ctx, provisioning.NewStateOptions(a.flags.hint))
+		if err != nil {
+			return nil, fmt.Errorf("getting deployment: %w", err)
+		}
+
+		outputs, err := provisioning.OutputValues(result.State.Outputs)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(deployed, outputs)
+	}
+
+	current := map[string]string{}
+	for key := range deployed {
+		if value, has := values[key]; has {
+			current[key] = value
+		}
+	}
+
+	return &envDiffResult{
+		Base:    env.Name(),
+		Compare: deployedDiffName,
+		Changes: environment.DiffValues(current, deployed),
+	}, nil
+}
+
+func diffEnvError(name string, err error) error {
+	if errors.Is(err, environment.ErrNotFound) {
+		return &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("environment '%s' does not exist: %w", name, environment.ErrNotFound),
+			Suggestion: "Run 'azd env list' to see the available environments.",
+		}
+	}
+
+	return fmt.Errorf("loading environment '%s': %w", name, err)
+}
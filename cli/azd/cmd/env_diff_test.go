@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+)
+
+func newEnvDiffTestManager() *mockenv.MockEnvManager {
+	envMgr := &mockenv.MockEnvManager{}
+	envMgr.On("Get", mock.Anything, "staging").Return(environment.NewWithValues("staging", map[string]string{
+		"AZURE_LOCATION": "westus2",
+		"API_KEY":        "staging-secret",
+	}), nil)
+	envMgr.On("Get", mock.Anything, "prod").Return(environment.NewWithValues("prod", map[string]string{
+		"AZURE_LOCATION": "eastus",
+		"API_KEY":        "prod-secret",
+	}), nil)
+	envMgr.On("Get", mock.Anything, "missing").Return((*environment.Environment)(nil), environment.ErrNotFound)
+	return envMgr
+}
+
+func Test_EnvDiffAction(t *testing.T) {
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	setDefaultEnv(t, azdCtx, "prod")
+
+	for _, args := range [][]string{{"staging", "prod"}, {"staging"}} {
+		buf := &bytes.Buffer{}
+		flags := &envDiffFlags{global: &internal.GlobalCommandOptions{}}
+		action := newEnvDiffAction(azdCtx, newEnvDiffTestManager(), nil, nil, mockinput.NewMockConsole(),
+			&output.JsonFormatter{}, buf, flags, args)
+
+		_, err := action.Run(t.Context())
+		require.NoError(t, err)
+
+		var result envDiffResult
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+		require.Equal(t, envDiffResult{
+			Base:    "staging",
+			Compare: "prod",
+			Changes: []environment.Change{
+				{Key: "API_KEY", Kind: environment.ChangeModified, Old: "********", New: "********"},
+				{Key: "AZURE_LOCATION", Kind: environment.ChangeModified, Old: "westus2", New: "eastus"},
+			},
+		}, result)
+	}
+}
+
+func Test_EnvDiffAction_Errors(t *testing.T) {
+	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+
+	run := func(flags *envDiffFlags, args ...string) error {
+		flags.global = &internal.GlobalCommandOptions{}
+		action := newEnvDiffAction(azdCtx, newEnvDiffTestManager(), nil, nil, mockinput.NewMockConsole(),
+			&output.JsonFormatter{}, &bytes.Buffer{}, flags, args)
+		_, err := action.Run(t.Context())
+		return err
+	}
+
+	require.ErrorIs(t, run(&envDiffFlags{}), internal.ErrInvalidArgValue)
+	require.ErrorIs(t, run(&envDiffFlags{}, "staging", "missing"), environment.ErrNotFound)
+	require.ErrorIs(t, run(&envDiffFlags{}, "staging"), environment.ErrNameNotSpecified)
+	require.ErrorIs(t,
+		run(&envDiffFlags{EnvFlag: internal.EnvFlag{EnvironmentName: "prod"}}, "staging", "prod"),
+		internal.ErrInvalidFlagCombination)
+}
@@ -0,0 +1,312 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func getCmdEnvHistoryHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Lists the revisions recorded each time the environment's .env or config.json changed.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"The most recent %d revisions are kept in .azure/<environment>/%s.",
+				environment.MaxHistoryRevisions, environment.HistoryFileName)),
+			formatHelpNote("Values that look like secrets are masked."),
+			formatHelpNote("Run 'azd env rollback <revision>' to restore a revision."),
+		})
+}
+
+func newEnvHistoryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "history",
+		Short: "List the revisions of an environment's values.",
+		Args:  cobra.NoArgs,
+	}
+}
+
+type envHistoryFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envHistoryFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvHistoryFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envHistoryFlags {
+	flags := &envHistoryFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+type envHistoryAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	schema     environment.Schema
+	console    input.Console
+	formatter  output.Formatter
+	writer     io.Writer
+	flags      *envHistoryFlags
+}
+
+func newEnvHistoryAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	schema environment.Schema,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *envHistoryFlags,
+) actions.Action {
+	return &envHistoryAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		schema:     schema,
+		console:    console,
+		formatter:  formatter,
+		writer:     writer,
+		flags:      flags,
+	}
+}
+
+// envRevision is the displayed form of an environment revision. It leaves out the recorded state, and masks
+// values that are or look like secrets.
+type envRevision struct {
+	ID      int                  `json:"id"`
+	Time    time.Time            `json:"time"`
+	User    string               `json:"user,omitempty"`
+	Command string               `json:"command,omitempty"`
+	Changes []environment.Change `json:"changes"`
+}
+
+func newEnvRevision(revision *environment.Revision, schema environment.Schema) envRevision {
+	changes := make([]environment.Change, len(revision.Changes))
+	for i, change := range revision.Changes {
+		change.Old = environment.MaskSecretValue(schema, change.Key, change.Old)
+		change.New = environment.MaskSecretValue(schema, change.Key, change.New)
+		changes[i] = change
+	}
+
+	return envRevision{
+		ID:      revision.ID,
+		Time:    revision.Time,
+		User:    revision.User,
+		Command: revision.Command,
+		Changes: changes,
+	}
+}
+
+func (a *envHistoryAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, err := historyEnvName(a.azdCtx, a.flags.EnvironmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := a.envManager.History(ctx, name)
+	if err != nil {
+		return nil, historyEnvError(name, err)
+	}
+
+	// Most recent first, as in a log.
+	displayed := make([]envRevision, 0, len(revisions))
+	for _, revision := range slices.Backward(revisions) {
+		displayed = append(displayed, newEnvRevision(revision, a.schema))
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		return nil, a.formatter.Format(displayed, a.writer, nil)
+	}
+
+	if len(displayed) == 0 {
+		a.console.Message(ctx, fmt.Sprintf("No revisions recorded for environment %s.", name))
+		return nil, nil
+	}
+
+	for _, revision := range displayed {
+		a.console.Message(ctx, formatEnvRevision(revision))
+	}
+
+	return nil, nil
+}
+
+// formatEnvRevision renders a revision as a header line followed by one line per change.
+func formatEnvRevision(revision envRevision) string {
+	var sb strings.Builder
+
+	details := []string{revision.Time.Local().Format(time.DateTime)}
+	if revision.User != "" {
+		details = append(details, revision.User)
+	}
+	if revision.Command != "" {
+		details = append(details, revision.Command)
+	}
+	fmt.Fprintf(&sb, "%s  %s\n",
+		output.WithBold("Revision %d", revision.ID), output.WithGrayFormat(strings.Join(details, "  ")))
+
+	for _, change := range revision.Changes {
+		key := change.Key
+		if change.Config {
+			key = "config: " + key
+		}
+
+		switch change.Kind {
+		case environment.ChangeAdded:
+			fmt.Fprintf(&sb, "  %s %s = %s\n", output.WithSuccessFormat("+"), key, change.New)
+		case environment.ChangeRemoved:
+			fmt.Fprintf(&sb, "  %s %s\n", output.WithErrorFormat("-"), key)
+		default:
+			fmt.Fprintf(&sb, "  %s %s: %s -> %s\n", output.WithWarningFormat("~"), key, change.Old, change.New)
+		}
+	}
+
+	return sb.String()
+}
+
+func newEnvRollbackCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback <revision>",
+		Short: "Restore an environment's values from a revision.",
+		Long: "Restores the environment's .env and config.json to the state recorded in a revision listed by " +
+			"'azd env history'. The rollback is recorded as a new revision, so it can be undone.",
+		Args: cobra.ExactArgs(1),
+	}
+}
+
+type envRollbackFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *envRollbackFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+func newEnvRollbackFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *envRollbackFlags {
+	flags := &envRollbackFlags{}
+	flags.Bind(cmd.Flags(), global)
+	return flags
+}
+
+type envRollbackAction struct {
+	azdCtx     *azdcontext.AzdContext
+	envManager environment.Manager
+	console    input.Console
+	flags      *envRollbackFlags
+	args       []string
+}
+
+func newEnvRollbackAction(
+	azdCtx *azdcontext.AzdContext,
+	envManager environment.Manager,
+	console input.Console,
+	flags *envRollbackFlags,
+	args []string,
+) actions.Action {
+	return &envRollbackAction{
+		azdCtx:     azdCtx,
+		envManager: envManager,
+		console:    console,
+		flags:      flags,
+		args:       args,
+	}
+}
+
+func (a *envRollbackAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(a.args[0], "#"))
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("revision '%s' is not a number: %w", a.args[0], internal.ErrInvalidArgValue),
+			Suggestion: "Run 'azd env history' to list the revisions of the environment.",
+		}
+	}
+
+	name, err := historyEnvName(a.azdCtx, a.flags.EnvironmentName)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := a.envManager.History(ctx, name)
+	if err != nil {
+		return nil, historyEnvError(name, err)
+	}
+
+	index := slices.IndexFunc(revisions, func(r *environment.Revision) bool { return r.ID == id })
+	if index < 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("revision %d of environment '%s': %w", id, name, internal.ErrRevisionNotFound),
+			Suggestion: "Run 'azd env history' to list the revisions of the environment.",
+		}
+	}
+
+	env, err := a.envManager.Get(ctx, name)
+	if err != nil {
+		return nil, historyEnvError(name, err)
+	}
+
+	revisions[index].Apply(env)
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return nil, fmt.Errorf("saving environment: %w", err)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Environment %s was restored to revision %d.", name, id),
+			FollowUp: fmt.Sprintf(
+				"Run %s to review the changes.", output.WithHighLightFormat("azd env history")),
+		},
+	}, nil
+}
+
+// historyEnvName returns the environment selected with --environment, or the default environment.
+func historyEnvName(azdCtx *azdcontext.AzdContext, flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	name, err := azdCtx.GetDefaultEnvironmentName()
+	if err != nil {
+		return "", err
+	}
+
+	if name == "" {
+		return "", &internal.ErrorWithSuggestion{
+			Err:        environment.ErrNameNotSpecified,
+			Suggestion: "Select an environment with 'azd env select' or pass one with --environment.",
+		}
+	}
+
+	return name, nil
+}
+
+func historyEnvError(name string, err error) error {
+	if errors.Is(err, environment.ErrNotFound) {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("environment '%s' does not exist: %w", name, environment.ErrNotFound),
+			Suggestion: fmt.Sprintf(
+				"Run 'azd env list' to see environments, or 'azd env new %s' to create it.", name),
+		}
+	}
+
+	return fmt.Errorf("reading environment history: %w", err)
+}
@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_EnvRevision_MasksSecrets(t *testing.T) {
+	revision := newEnvRevision(&environment.Revision{
+		ID:   3,
+		Time: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		User: "dev",
+		Changes: []environment.Change{
+			{Key: "AZURE_LOCATION", Kind: environment.ChangeModified, Old: "westus2", New: "eastus"},
+			{Key: "API_KEY", Kind: environment.ChangeModified, Old: "old-secret", New: "new-secret"},
+			{Key: "OLD_KEY", Kind: environment.ChangeRemoved, Old: "value"},
+			{Key: "DB_LOGIN", Kind: environment.ChangeAdded, New: "admin"},
+		},
+		DotEnv: map[string]string{"API_KEY": "new-secret"},
+	}, environment.Schema{"DB_LOGIN": {Secret: true}})
+
+	require.Equal(t, "westus2", revision.Changes[0].Old)
+	require.Equal(t, "eastus", revision.Changes[0].New)
+	require.Equal(t, "********", revision.Changes[1].Old)
+	require.Equal(t, "********", revision.Changes[1].New)
+	require.Equal(t, "********", revision.Changes[3].New)
+
+	text := formatEnvRevision(revision)
+	require.Contains(t, text, "Revision 3")
+	require.Contains(t, text, "AZURE_LOCATION: westus2 -> eastus")
+	require.Contains(t, text, "API_KEY: ******** -> ********")
+	require.Contains(t, text, "OLD_KEY")
+	require.NotContains(t, text, "secret")
+	require.NotContains(t, text, "admin")
+}
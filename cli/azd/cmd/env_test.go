@@ -223,7 +223,7 @@ func Test_EnvSetAction_EmptyArgs(t *testing.T) {
 	env := environment.NewWithValues("test", map[string]string{})
 	mgr := newTestEnvManager()
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, nil)
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "no environment values provided")
@@ -236,12 +236,36 @@ func Test_EnvSetAction_KeyValueFromArgs(t *testing.T) {
 	mgr := newTestEnvManager()
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MY_KEY", "my_value"})
+	action := newEnvSetAction(
+		azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MY_KEY", "my_value"})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 	assert.Equal(t, "my_value", env.Getenv("MY_KEY"))
 }
 
+func Test_EnvSetAction_SchemaViolation(t *testing.T) {
+	t.Parallel()
+	azdCtx := newTestAzdContext(t)
+	env := environment.NewWithValues("test", map[string]string{})
+	mgr := newTestEnvManager()
+	schema := environment.Schema{
+		"API_PORT":  {Type: environment.VariableTypeInteger},
+		"LOG_LEVEL": {Enum: []string{"debug", "info"}},
+	}
+
+	action := newEnvSetAction(
+		azdCtx, env, mgr, schema, mockinput.NewMockConsole(), &envSetFlags{},
+		[]string{"API_PORT=http", "LOG_LEVEL=info"},
+	)
+	_, err := action.Run(t.Context())
+	require.ErrorIs(t, err, environment.ErrSchemaViolation)
+	require.ErrorContains(t, err, "API_PORT must be an integer")
+
+	// Nothing is set or saved.
+	assert.Empty(t, env.Getenv("LOG_LEVEL"))
+	mgr.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
 func Test_EnvSetAction_KeyEqualsValue(t *testing.T) {
 	t.Parallel()
 	azdCtx := newTestAzdContext(t)
@@ -249,7 +273,7 @@ func Test_EnvSetAction_KeyEqualsValue(t *testing.T) {
 	mgr := newTestEnvManager()
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MY_KEY=my_value"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MY_KEY=my_value"})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 	assert.Equal(t, "my_value", env.Getenv("MY_KEY"))
@@ -269,7 +293,7 @@ func Test_EnvSetAction_FromFile(t *testing.T) {
 	mgr := newTestEnvManager()
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{file: envFile}, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{file: envFile}, nil)
 	_, err = action.Run(t.Context())
 	require.NoError(t, err)
 	assert.Equal(t, "file_value", env.Getenv("FILE_KEY"))
@@ -282,7 +306,7 @@ func Test_EnvSetAction_FileNotFound(t *testing.T) {
 	env := environment.NewWithValues("test", map[string]string{})
 	mgr := newTestEnvManager()
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{file: "/nonexistent"}, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{file: "/nonexistent"}, nil)
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to open file")
@@ -297,7 +321,8 @@ func Test_EnvSetAction_CaseConflictWarning(t *testing.T) {
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
 	// Setting my_key (different case) - should trigger warning but still succeed
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"my_key=new_value"})
+	action := newEnvSetAction(
+		azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"my_key=new_value"})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 	// The value should still be set
@@ -731,7 +756,7 @@ func Test_EnvSetAction_Constructor(t *testing.T) {
 	azdCtx := newTestAzdContext(t)
 	env := environment.NewWithValues("test", map[string]string{})
 	mgr := newTestEnvManager()
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, nil)
 	require.NotNil(t, action)
 }
 
@@ -1752,7 +1777,7 @@ func Test_EnvSetAction_SaveError(t *testing.T) {
 	// envSetAction.Run directly calls Save (no Get). Mock Save to fail.
 	mgr.On("Save", mock.Anything, mock.Anything).Return(errors.New("disk full"))
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "saving environment")
@@ -1767,7 +1792,7 @@ func Test_EnvSetAction_Success(t *testing.T) {
 	mgr := newTestEnvManager()
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
 	result, err := action.Run(t.Context())
 	require.NoError(t, err)
 	require.Nil(t, result)
@@ -2055,7 +2080,7 @@ func Test_EnvSetAction_EnvNotFound(t *testing.T) {
 	// envSetAction doesn't call Get — it uses the env directly and then calls Save
 	mgr.On("Save", mock.Anything, mock.Anything).Return(environment.ErrNotFound)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"KEY=VALUE"})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "saving environment")
@@ -2071,7 +2096,7 @@ func Test_EnvSetAction_MultipleKVPairs(t *testing.T) {
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
 	action := newEnvSetAction(
-		azdCtx, env, mgr, mockinput.NewMockConsole(),
+		azdCtx, env, mgr, nil, mockinput.NewMockConsole(),
 		&envSetFlags{},
 		[]string{"KEY1=val1", "KEY2=val2", "KEY3=val3"},
 	)
@@ -2206,7 +2231,7 @@ func Test_EnvSetAction_KeyCaseConflict(t *testing.T) {
 	console := mockinput.NewMockConsole()
 
 	flags := &envSetFlags{}
-	action := newEnvSetAction(azdCtx, env, mgr, console, flags, []string{"my_key", "new"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, console, flags, []string{"my_key", "new"})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 }
@@ -2449,7 +2474,7 @@ func Test_EnvSetAction_FileParseError(t *testing.T) {
 	// dotenv parser fails on lines with bare = or other malformed content; use a control char
 	require.NoError(t, os.WriteFile(badFile, []byte("'unterminated\n"), 0600))
 	flags := &envSetFlags{file: badFile}
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), flags, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), flags, nil)
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to parse file")
@@ -2466,7 +2491,7 @@ func Test_EnvSetAction_EmptyFile(t *testing.T) {
 	emptyFile := filepath.Join(tmpDir, "empty.env")
 	require.NoError(t, os.WriteFile(emptyFile, []byte("\n\n# comment only\n\n"), 0600))
 	flags := &envSetFlags{file: emptyFile}
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), flags, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), flags, nil)
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "no environment values")
@@ -2772,7 +2797,7 @@ func Test_EnvSetAction_FileAndArgsMutuallyExclusive(t *testing.T) {
 	mgr := newTestEnvManager()
 
 	flags := &envSetFlags{file: "some.env"}
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), flags, []string{"KEY=VALUE"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), flags, []string{"KEY=VALUE"})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "cannot combine --file flag")
@@ -2792,7 +2817,7 @@ func Test_EnvSetAction_FileSuccess(t *testing.T) {
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
 	flags := &envSetFlags{file: envFile}
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), flags, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), flags, nil)
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 }
@@ -2805,7 +2830,7 @@ func Test_EnvSetAction_NoArgs(t *testing.T) {
 	env := environment.NewWithValues("myenv", nil)
 	mgr := newTestEnvManager()
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, nil)
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, nil)
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 }
@@ -2819,7 +2844,7 @@ func Test_EnvSetAction_SingleKeyValuePair(t *testing.T) {
 	mgr := newTestEnvManager()
 	mgr.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MYKEY", "MYVAL"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"MYKEY", "MYVAL"})
 	_, err := action.Run(t.Context())
 	require.NoError(t, err)
 }
@@ -2832,7 +2857,7 @@ func Test_EnvSetAction_BadKeyValueFormat(t *testing.T) {
 	env := environment.NewWithValues("myenv", nil)
 	mgr := newTestEnvManager()
 
-	action := newEnvSetAction(azdCtx, env, mgr, mockinput.NewMockConsole(), &envSetFlags{}, []string{"NOEQUALS"})
+	action := newEnvSetAction(azdCtx, env, mgr, nil, mockinput.NewMockConsole(), &envSetFlags{}, []string{"NOEQUALS"})
 	_, err := action.Run(t.Context())
 	require.Error(t, err)
 }
@@ -28,8 +28,11 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
@@ -98,7 +101,13 @@ registered, azd reuses that source.
 
 You can also pass the path to a self-contained extension bundle (.zip): azd
 extracts it and installs the bundled extension. Bundled extensions aren't
-tracked for updates; reinstall from a newer bundle to update.`,
+tracked for updates; reinstall from a newer bundle to update.
+
+Within a project, the resolved version, source and artifacts of the installed
+extensions and their dependencies are recorded in the azd-extensions.lock file
+next to azure.yaml. Use --locked to install exactly what the lock file records,
+either every locked extension or only the specified ones; the install fails when
+the extension sources no longer publish the locked versions and artifacts.`,
 		},
 		ActionResolver: newExtensionInstallAction,
 		FlagsResolver:  newExtensionInstallFlags,
@@ -139,6 +148,10 @@ satisfying the extension's declared constraints. Use
 --no-dependency-upgrades to opt out and upgrade only the named
 extension.
 
+Within a project, the upgraded extensions are recorded in the
+azd-extensions.lock file next to azure.yaml. With --all, only the
+extensions that are already locked are updated in the lock file.
+
 Use --output json for a structured report of all upgrade results.`,
 		},
 		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
@@ -195,7 +208,7 @@ Use --output json for a structured report of all upgrade results.`,
 			Short: "Validate an extension source's registry.json file.",
 			Long: "Validate an extension source's registry.json file.\n\n" +
 				"Accepts a source name (from 'azd extension source list'), a local file path,\n" +
-				"or a URL. Checks required fields, valid capabilities, semver version format,\n" +
+				"a URL or an oci:// reference. Checks required fields, valid capabilities, semver version format,\n" +
 				"platform artifact structure, and extension ID format.",
 		},
 		OutputFormats:  []output.Format{output.JsonFormat, output.NoneFormat},
@@ -808,6 +821,7 @@ type extensionInstallFlags struct {
 	version string
 	source  string
 	force   bool
+	locked  bool
 	global  *internal.GlobalCommandOptions
 }
 
@@ -822,6 +836,9 @@ func newExtensionInstallFlags(cmd *cobra.Command, global *internal.GlobalCommand
 	cmd.Flags().StringVarP(&flags.version, "version", "v", "", "The version of the extension to install")
 	cmd.Flags().
 		BoolVarP(&flags.force, "force", "f", false, "Force installation, including downgrades and reinstalls")
+	cmd.Flags().BoolVar(&flags.locked, "locked", false,
+		"Install the extensions exactly as recorded in the "+extensions.LockFileName+" file of the project, "+
+			"failing when they no longer match")
 
 	return flags
 }
@@ -833,6 +850,7 @@ type extensionInstallAction struct {
 	console          input.Console
 	extensionManager *extensions.Manager
 	sourceManager    *extensions.SourceManager
+	lazyAzdContext   *lazy.Lazy[*azdcontext.AzdContext]
 	// bundleSourceName is the transient source registered while installing from a
 	// self-contained bundle (.zip). It is removed during cleanup; extensions
 	// installed under it are re-pointed to extensions.BundleSourceName.
@@ -848,6 +866,7 @@ func newExtensionInstallAction(
 	console input.Console,
 	extensionManager *extensions.Manager,
 	sourceManager *extensions.SourceManager,
+	lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
 ) actions.Action {
 	return &extensionInstallAction{
 		args:             args,
@@ -855,6 +874,7 @@ func newExtensionInstallAction(
 		console:          console,
 		extensionManager: extensionManager,
 		sourceManager:    sourceManager,
+		lazyAzdContext:   lazyAzdContext,
 	}
 }
 
@@ -865,6 +885,10 @@ func (a *extensionInstallAction) Run(ctx context.Context) (*actions.ActionResult
 		TitleNote: "Installs the specified extension onto the local machine",
 	})
 
+	if a.flags.locked {
+		return a.installLocked(ctx)
+	}
+
 	// A single .zip argument is a self-contained bundle: extract it, register an
 	// ephemeral source, and queue its extensions for install (this rewrites
 	// a.args/a.flags.source for the loop below). The deferred cleanup removes the
@@ -910,6 +934,8 @@ func (a *extensionInstallAction) Run(ctx context.Context) (*actions.ActionResult
 	}
 
 	azdVersion := currentAzdSemver()
+	// lockIds are the extensions to record in the lock file of the project.
+	var lockIds []string
 
 	for index, extensionId := range extensionIds {
 		if index > 0 {
@@ -1000,6 +1026,7 @@ func (a *extensionInstallAction) Run(ctx context.Context) (*actions.ActionResult
 						stepMessage += output.WithGrayFormat(
 							" (version %s already installed)", installedExtension.Version)
 						a.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+						lockIds = append(lockIds, compatibleExtension.Id)
 						continue
 					}
 
@@ -1091,6 +1118,14 @@ func (a *extensionInstallAction) Run(ctx context.Context) (*actions.ActionResult
 		}
 
 		displayExtensionUsageAndExamples(ctx, a.console, extensionVersion)
+		lockIds = append(lockIds, compatibleExtension.Id)
+	}
+
+	// Extensions installed from a bundle are not locked, since there is no registry to install them from again.
+	if a.bundleSourceName == "" {
+		if err := updateExtensionLockFile(ctx, a.lazyAzdContext, a.extensionManager, lockIds, false); err != nil {
+			return nil, err
+		}
 	}
 
 	return &actions.ActionResult{
@@ -1482,7 +1517,7 @@ func registerSourceFromLocation(
 		}
 	}
 
-	if kind == extensions.SourceKindUrl {
+	if kind == extensions.SourceKindUrl || kind == extensions.SourceKindOci {
 		confirm, err := console.Confirm(ctx, input.ConsoleOptions{
 			Message: fmt.Sprintf(
 				"Register and use the extension source at %s?",
@@ -1720,6 +1755,9 @@ func inferSourceKind(location string) (extensions.SourceKind, bool) {
 	if strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") {
 		return extensions.SourceKindUrl, true
 	}
+	if oci.IsReference(location) {
+		return extensions.SourceKindOci, true
+	}
 	if info, err := os.Stat(location); err == nil && !info.IsDir() {
 		return extensions.SourceKindFile, true
 	}
@@ -1866,6 +1904,7 @@ type extensionUpgradeAction struct {
 	console          input.Console
 	sourceManager    *extensions.SourceManager
 	extensionManager *extensions.Manager
+	lazyAzdContext   *lazy.Lazy[*azdcontext.AzdContext]
 }
 
 func newExtensionUpgradeAction(
@@ -1876,6 +1915,7 @@ func newExtensionUpgradeAction(
 	console input.Console,
 	sourceManager *extensions.SourceManager,
 	extensionManager *extensions.Manager,
+	lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
 ) actions.Action {
 	return &extensionUpgradeAction{
 		args:             args,
@@ -1885,6 +1925,7 @@ func newExtensionUpgradeAction(
 		console:          console,
 		sourceManager:    sourceManager,
 		extensionManager: extensionManager,
+		lazyAzdContext:   lazyAzdContext,
 	}
 }
 
@@ -1995,6 +2036,20 @@ loop:
 		results = append(results, result)
 	}
 
+	var lockIds []string
+	for _, result := range results {
+		if result.Status == extensions.UpgradeStatusUpgraded || result.Status == extensions.UpgradeStatusPromoted {
+			lockIds = append(lockIds, result.ExtensionId)
+		}
+	}
+
+	// A batch upgrade only updates the extensions the project already locks.
+	if err := updateExtensionLockFile(
+		ctx, a.lazyAzdContext, a.extensionManager, lockIds, a.flags.all,
+	); err != nil {
+		return nil, err
+	}
+
 	// JSON output: emit structured report and return
 	if isJsonOutput {
 		report := extensions.UpgradeReport{
@@ -2702,9 +2757,10 @@ func (a *extensionSourceListAction) Run(ctx context.Context) (*actions.ActionRes
 }
 
 type extensionSourceAddFlags struct {
-	name     string
-	location string
-	kind     string
+	name       string
+	location   string
+	kind       string
+	publicKeys []string
 }
 
 func newExtensionSourceAddFlags(cmd *cobra.Command) *extensionSourceAddFlags {
@@ -2712,7 +2768,10 @@ func newExtensionSourceAddFlags(cmd *cobra.Command) *extensionSourceAddFlags {
 	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "The name of the extension source")
 	cmd.Flags().StringVarP(&flags.location, "location", "l", "", "The location of the extension source")
 	cmd.Flags().StringVarP(&flags.kind,
-		"type", "t", "", "The type of the extension source. Supported types are 'file' and 'url'")
+		"type", "t", "", "The type of the extension source. Supported types are 'file', 'url' and 'oci'")
+	cmd.Flags().StringArrayVar(&flags.publicKeys,
+		"public-key", nil, "A base64 encoded ed25519 public key trusted to sign the registry and the extensions of the "+
+			"source. Can be specified multiple times")
 
 	return flags
 }
@@ -2745,9 +2804,10 @@ func (a *extensionSourceAddAction) Run(ctx context.Context) (*actions.ActionResu
 	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
 
 	sourceConfig := &extensions.SourceConfig{
-		Type:     extensions.SourceKind(a.flags.kind),
-		Location: a.flags.location,
-		Name:     a.flags.name,
+		Type:       extensions.SourceKind(a.flags.kind),
+		Location:   a.flags.location,
+		Name:       a.flags.name,
+		PublicKeys: a.flags.publicKeys,
 	}
 
 	// Validate the custom source config
@@ -2759,7 +2819,8 @@ func (a *extensionSourceAddAction) Run(ctx context.Context) (*actions.ActionResu
 				Err: fmt.Errorf(
 					"extension source type '%s' not supported: %w",
 					a.flags.kind, internal.ErrValidationFailed),
-				Suggestion: fmt.Sprintf("Supported source types are %s.", ux.ListAsText([]string{"'file'", "'url'"})),
+				Suggestion: fmt.Sprintf(
+					"Supported source types are %s.", ux.ListAsText([]string{"'file'", "'url'", "'oci'"})),
 			}
 		}
 
@@ -3243,6 +3304,8 @@ func (a *extensionSourceValidateAction) Run(ctx context.Context) (*actions.Actio
 		kind := extensions.SourceKindFile
 		if strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://") {
 			kind = extensions.SourceKindUrl
+		} else if oci.IsReference(arg) {
+			kind = extensions.SourceKindOci
 		}
 		sourceConfig = &extensions.SourceConfig{
 			Name:     "validate",
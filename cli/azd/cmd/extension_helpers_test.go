@@ -73,6 +73,10 @@ func addLocalRegistrySource(ctx context.Context, t *testing.T, cli *azdcli.CLI)
 		"-l", registryPath,
 	)
 	require.NoError(t, err, "failed to add local registry source")
+
+	// The local copy of the registry has no trusted public keys to verify the extensions with
+	_, err = cli.RunCommand(ctx, "config", "set", "extension.requireSignatures", "false")
+	require.NoError(t, err, "failed to allow unsigned extensions")
 	return localSourceName
 }
 
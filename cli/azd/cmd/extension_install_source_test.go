@@ -52,6 +52,12 @@ func TestInferSourceKind(t *testing.T) {
 		require.Equal(t, extensions.SourceKindUrl, kind)
 	})
 
+	t.Run("OciReference", func(t *testing.T) {
+		kind, ok := inferSourceKind("oci://contoso.azurecr.io/azd/extensions:latest")
+		require.True(t, ok)
+		require.Equal(t, extensions.SourceKindOci, kind)
+	})
+
 	t.Run("ExistingFile", func(t *testing.T) {
 		kind, ok := inferSourceKind(existing)
 		require.True(t, ok)
@@ -0,0 +1,226 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+)
+
+// extensionLockFilePath returns the path of the extension lock file of the current project, or an error wrapping
+// azdcontext.ErrNoProject when azd does not run within a project.
+func extensionLockFilePath(lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext]) (string, error) {
+	if lazyAzdContext == nil {
+		return "", azdcontext.ErrNoProject
+	}
+
+	azdContext, err := lazyAzdContext.GetValue()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(azdContext.ProjectDirectory(), extensions.LockFileName), nil
+}
+
+// updateExtensionLockFile records the installed extensionIds, and the extensions they depend on, in the lock file of
+// the current project. It does nothing outside of a project. When onlyLocked is set, only the extensions the lock file
+// already records are updated.
+func updateExtensionLockFile(
+	ctx context.Context,
+	lazyAzdContext *lazy.Lazy[*azdcontext.AzdContext],
+	extensionManager *extensions.Manager,
+	extensionIds []string,
+	onlyLocked bool,
+) error {
+	if len(extensionIds) == 0 {
+		return nil
+	}
+
+	lockPath, err := extensionLockFilePath(lazyAzdContext)
+	if errors.Is(err, azdcontext.ErrNoProject) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	lock, err := extensions.LoadLockFile(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		if onlyLocked {
+			return nil
+		}
+		lock = extensions.NewLockFile()
+	} else if err != nil {
+		return err
+	}
+
+	for _, extensionId := range extensionIds {
+		if _, locked := lock.Extensions[extensionId]; onlyLocked && !locked {
+			continue
+		}
+
+		if err := extensionManager.LockInstalled(ctx, lock, extensionId); err != nil {
+			return fmt.Errorf("updating %s: %w", extensions.LockFileName, err)
+		}
+	}
+
+	return lock.Save(lockPath)
+}
+
+// installLocked installs the extensions recorded in the lock file of the project, or only the specified ones, exactly
+// as they are locked.
+func (a *extensionInstallAction) installLocked(ctx context.Context) (*actions.ActionResult, error) {
+	if a.flags.version != "" || a.flags.source != "" || isBundleArg(a.args) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"cannot specify --version, --source or an extension bundle with --locked: %w",
+				internal.ErrInvalidFlagCombination),
+			Suggestion: "The lock file records the version and the source of each extension. " +
+				"Run 'azd extension install --locked' without them.",
+		}
+	}
+
+	lockPath, err := extensionLockFilePath(a.lazyAzdContext)
+	if errors.Is(err, azdcontext.ErrNoProject) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("finding %s: %w", extensions.LockFileName, err),
+			Suggestion: "Run 'azd extension install --locked' from the directory of a project.",
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	lock, err := extensions.LoadLockFile(lockPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: err,
+			Suggestion: fmt.Sprintf(
+				"Run 'azd extension install <extension-id>' in the project to create %s.", extensions.LockFileName),
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	extensionIds := a.args
+	if len(extensionIds) == 0 {
+		extensionIds = slices.Sorted(maps.Keys(lock.Extensions))
+	}
+
+	if len(extensionIds) == 0 {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: internal.ErrNoExtensionsAvailable,
+			Suggestion: fmt.Sprintf(
+				"%s does not lock any extension. Run 'azd extension install <extension-id>' in the project to lock one.",
+				extensions.LockFileName),
+		}
+	}
+
+	for index, extensionId := range extensionIds {
+		if index > 0 {
+			a.console.Message(ctx, "")
+		}
+
+		stepMessage := fmt.Sprintf("Installing %s extension", output.WithHighLightFormat(extensionId))
+		a.console.ShowSpinner(ctx, stepMessage, input.Step)
+
+		extensionVersion, err := a.installLockedExtension(ctx, lock, extensionId)
+		if err != nil {
+			a.console.StopSpinner(ctx, stepMessage, input.StepFailed)
+			return nil, err
+		}
+
+		if extensionVersion == nil {
+			stepMessage += output.WithGrayFormat(
+				" (version %s already installed)", lock.Extensions[extensionId].Version)
+			a.console.StopSpinner(ctx, stepMessage, input.StepSkipped)
+			continue
+		}
+
+		stepMessage += output.WithGrayFormat(" (%s)", extensionVersion.Version)
+		a.console.StopSpinner(ctx, stepMessage, input.StepDone)
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: "Extension(s) installed successfully",
+		},
+	}, nil
+}
+
+// installLockedExtension installs, or moves an installed extension to, the locked version of extensionId along with
+// the locked versions of its dependencies. It returns a nil version when the locked version is already installed.
+func (a *extensionInstallAction) installLockedExtension(
+	ctx context.Context,
+	lock *extensions.LockFile,
+	extensionId string,
+) (*extensions.ExtensionVersion, error) {
+	locked, has := lock.Extensions[extensionId]
+	if !has {
+		return nil, fmt.Errorf("%w: %s is not locked", extensions.ErrLockMismatch, extensionId)
+	}
+
+	installed, err := a.extensionManager.GetInstalled(extensions.FilterOptions{Id: extensionId})
+	if err != nil && !errors.Is(err, extensions.ErrInstalledExtensionNotFound) {
+		return nil, fmt.Errorf("failed to get installed extension: %w", err)
+	}
+
+	if installed != nil && installed.Version == locked.Version && strings.EqualFold(installed.Source, locked.Source) {
+		return nil, nil
+	}
+
+	matches, err := a.extensionManager.FindExtensions(ctx, &extensions.FilterOptions{
+		Id:     extensionId,
+		Source: locked.Source,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find extension: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf(
+			"%w: %s was not found in extension source '%s'", extensions.ErrLockMismatch, extensionId, locked.Source)
+	}
+
+	if installed == nil {
+		extensionVersion, err := a.extensionManager.InstallWithOptions(ctx, matches[0], extensions.InstallOptions{
+			VersionPreference: locked.Version,
+			Lock:              lock,
+		})
+		if err != nil {
+			return nil, wrapDependencyError(fmt.Errorf("failed to install extension: %w", err))
+		}
+
+		return extensionVersion, nil
+	}
+
+	extensionVersion, dependencyUpgrades, err := a.extensionManager.Upgrade(ctx, matches[0], extensions.UpgradeOptions{
+		VersionPreference:   locked.Version,
+		UpgradeDependencies: true,
+		Lock:                lock,
+	})
+	if err != nil {
+		return nil, wrapDependencyError(fmt.Errorf("failed to install extension: %w", err))
+	}
+
+	for _, result := range dependencyUpgrades {
+		if result.Status == extensions.UpgradeStatusFailed {
+			return nil, fmt.Errorf("failed to install dependency %s: %w", result.ExtensionId, result.Error)
+		}
+	}
+
+	return extensionVersion, nil
+}
@@ -7,6 +7,8 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -22,8 +24,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/agent/consent"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
@@ -916,6 +920,7 @@ func Test_NewExtensionInstallAction(t *testing.T) {
 		mockinput.NewMockConsole(),
 		nil, // extensionManager
 		nil, // sourceManager
+		nil, // lazyAzdContext
 	)
 	require.NotNil(t, action)
 }
@@ -941,6 +946,7 @@ func Test_NewExtensionUpgradeAction(t *testing.T) {
 		mockinput.NewMockConsole(),
 		nil, // sourceManager
 		nil, // extensionManager
+		nil, // lazyAzdContext
 	)
 	require.NotNil(t, action)
 }
@@ -1078,6 +1084,47 @@ func Test_ExtensionInstallAction_Run_VersionWithMultipleArgs(t *testing.T) {
 	assert.ErrorIs(t, suggestion.Err, internal.ErrInvalidFlagCombination)
 }
 
+func Test_ExtensionInstallAction_Run_LockedWithVersion(t *testing.T) {
+	t.Parallel()
+	action := &extensionInstallAction{
+		args:    []string{"ext1"},
+		flags:   &extensionInstallFlags{locked: true, version: "1.0.0", global: &internal.GlobalCommandOptions{}},
+		console: mockinput.NewMockConsole(),
+	}
+	_, err := action.Run(t.Context())
+	require.Error(t, err)
+	var suggestion *internal.ErrorWithSuggestion
+	require.ErrorAs(t, err, &suggestion)
+	assert.ErrorIs(t, suggestion.Err, internal.ErrInvalidFlagCombination)
+}
+
+func Test_ExtensionInstallAction_Run_LockedWithoutLockFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("NoProject", func(t *testing.T) {
+		t.Parallel()
+		action := &extensionInstallAction{
+			flags:   &extensionInstallFlags{locked: true, global: &internal.GlobalCommandOptions{}},
+			console: mockinput.NewMockConsole(),
+		}
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, azdcontext.ErrNoProject)
+	})
+
+	t.Run("NoLockFile", func(t *testing.T) {
+		t.Parallel()
+		projectDir := t.TempDir()
+		action := &extensionInstallAction{
+			flags:          &extensionInstallFlags{locked: true, global: &internal.GlobalCommandOptions{}},
+			console:        mockinput.NewMockConsole(),
+			lazyAzdContext: lazy.From(azdcontext.NewAzdContextWithDirectory(projectDir)),
+		}
+		_, err := action.Run(t.Context())
+		require.ErrorIs(t, err, os.ErrNotExist)
+		require.NoFileExists(t, filepath.Join(projectDir, extensions.LockFileName))
+	})
+}
+
 func Test_ExtensionUninstallAction_Run_ArgsWithAllFlag(t *testing.T) {
 	t.Parallel()
 	action := &extensionUninstallAction{
@@ -141,6 +141,7 @@ func TestUpgradeAction_ContextCancellation(t *testing.T) {
 		mockinput.NewMockConsole(),
 		sourceManager,
 		manager,
+		nil,
 	)
 
 	result, err := action.Run(ctx)
@@ -323,6 +324,7 @@ func TestUpgradeAction_MixedBatch(t *testing.T) {
 		mockinput.NewMockConsole(),
 		sourceManager,
 		manager,
+		nil,
 	)
 
 	result, err := action.Run(t.Context())
@@ -4,8 +4,12 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/azure/azure-dev/cli/azd/cmd/actions"
 	"github.com/azure/azure-dev/cli/azd/cmd/middleware"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/spf13/cobra"
 )
@@ -51,5 +55,46 @@ func infraActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
 			DefaultFormat:  output.NoneFormat,
 		})
 
+	group.
+		Add("import", &actions.ActionDescriptorOptions{
+			Command:        newInfraImportCmd(),
+			FlagsResolver:  newInfraImportFlags,
+			ActionResolver: newInfraImportAction,
+			HelpOptions: actions.ActionHelpOptions{
+				Description: getCmdInfraImportHelpDescription,
+			},
+			OutputFormats: []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat: output.NoneFormat,
+		})
+
+	group.
+		Add("drift", &actions.ActionDescriptorOptions{
+			Command:        newInfraDriftCmd(),
+			FlagsResolver:  newInfraDriftFlags,
+			ActionResolver: newInfraDriftAction,
+			HelpOptions: actions.ActionHelpOptions{
+				Description: getCmdInfraDriftHelpDescription,
+			},
+			OutputFormats: []output.Format{output.JsonFormat, output.NoneFormat},
+			DefaultFormat: output.NoneFormat,
+		})
+
 	return group
 }
+
+// infraLayer returns the layer named by the first argument, or the only layer of the project when there is no
+// argument. command is the command suggested to the user when the project has several layers.
+func infraLayer(options provisioning.Options, args []string, command string) (provisioning.Options, error) {
+	if len(args) > 0 {
+		return options.GetLayer(args[0])
+	}
+
+	if layers := options.GetLayers(); len(layers) > 1 {
+		return provisioning.Options{}, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: the project has several layers", internal.ErrNoArgsProvided),
+			Suggestion: fmt.Sprintf("Run '%s <layer-name>' targeting a single layer.", command),
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,209 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/cmd"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// driftExitCode is the exit code of `azd infra drift` when drift is detected, which lets CI pipelines tell drift apart
+// from failures.
+const driftExitCode = 2
+
+type infraDriftFlags struct {
+	global *internal.GlobalCommandOptions
+	*internal.EnvFlag
+}
+
+func newInfraDriftFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraDriftFlags {
+	flags := &infraDriftFlags{
+		EnvFlag: &internal.EnvFlag{},
+	}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *infraDriftFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.global = global
+	f.EnvFlag.Bind(local, global)
+}
+
+func newInfraDriftCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "drift [<layer>]",
+		Short: "Detect changes made to the Azure resources since they were last provisioned.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+func getCmdInfraDriftHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Compares the Azure resources of the environment with their last successful deployment, without "+
+			"applying any change, and reports the changes made outside of provisioning.",
+		[]string{
+			formatHelpNote(fmt.Sprintf(
+				"Bicep projects are compared with %s, which requires the template and parameters to be unchanged "+
+					"since the last deployment.",
+				output.WithHighLightFormat("what-if"))),
+			formatHelpNote(fmt.Sprintf(
+				"Terraform projects are compared with a refresh-only %s.",
+				output.WithHighLightFormat("terraform plan"))),
+			formatHelpNote(fmt.Sprintf(
+				"The command exits with code %d when drift is detected.", driftExitCode)),
+		})
+}
+
+// infraDriftResult is the JSON output of `azd infra drift`.
+type infraDriftResult struct {
+	Drift     bool                 `json:"drift"`
+	Resources []infraDriftResource `json:"resources"`
+}
+
+// infraDriftResource is a resource that differs from its last successful deployment, with the change that provisioning
+// again would apply to it.
+type infraDriftResource struct {
+	ChangeType   provisioning.ChangeType `json:"changeType"`
+	ResourceType string                  `json:"resourceType"`
+	Name         string                  `json:"name"`
+	ResourceId   string                  `json:"resourceId,omitempty"`
+	Properties   []infraDriftProperty    `json:"properties,omitempty"`
+}
+
+// infraDriftProperty is a property of a resource that differs from its last successful deployment.
+type infraDriftProperty struct {
+	Path       string                          `json:"path"`
+	ChangeType provisioning.PropertyChangeType `json:"changeType"`
+	Before     any                             `json:"before,omitempty"`
+	After      any                             `json:"after,omitempty"`
+}
+
+type infraDriftAction struct {
+	projectConfig    *project.ProjectConfig
+	importManager    *project.ImportManager
+	provisionManager *provisioning.Manager
+	env              *environment.Environment
+	console          input.Console
+	formatter        output.Formatter
+	writer           io.Writer
+	args             []string
+}
+
+func newInfraDriftAction(
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	provisionManager *provisioning.Manager,
+	env *environment.Environment,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	args []string,
+) actions.Action {
+	return &infraDriftAction{
+		projectConfig:    projectConfig,
+		importManager:    importManager,
+		provisionManager: provisionManager,
+		env:              env,
+		console:          console,
+		formatter:        formatter,
+		writer:           writer,
+		args:             args,
+	}
+}
+
+func (a *infraDriftAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	a.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     fmt.Sprintf("Detecting infrastructure drift for environment %s (azd infra drift)", a.env.Name()),
+		TitleNote: "No changes will be applied to your Azure resources.",
+	})
+
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	layer, err := infraLayer(infra.Options, a.args, "azd infra drift")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.provisionManager.Initialize(ctx, a.projectConfig.Path, layer); err != nil {
+		return nil, fmt.Errorf("initializing provisioning manager: %w", err)
+	}
+
+	driftResult, err := a.provisionManager.Drift(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(newInfraDriftResult(driftResult), a.writer, nil); err != nil {
+			return nil, fmt.Errorf("writing drift result in JSON format: %w", err)
+		}
+	} else {
+		a.console.MessageUxItem(ctx, cmd.PreviewChangesToUx(driftResult.Changes))
+	}
+
+	if len(driftResult.Changes) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "No drift detected. The Azure resources match their last successful deployment.",
+			},
+		}, nil
+	}
+
+	return nil, &internal.ExitCodeError{
+		ExitCode: driftExitCode,
+		Err: &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("%w: %d resource(s) changed since the last successful deployment",
+				provisioning.ErrDriftDetected, len(driftResult.Changes)),
+			Suggestion: "Run 'azd provision' to restore the resources, or update your infrastructure code to " +
+				"keep the changes.",
+		},
+	}
+}
+
+func newInfraDriftResult(driftResult *provisioning.DriftResult) infraDriftResult {
+	result := infraDriftResult{
+		Drift:     len(driftResult.Changes) > 0,
+		Resources: []infraDriftResource{},
+	}
+
+	for _, change := range driftResult.Changes {
+		resource := infraDriftResource{
+			ChangeType:   change.ChangeType,
+			ResourceType: change.ResourceType,
+			Name:         change.Name,
+			ResourceId:   change.ResourceId.Id,
+		}
+
+		for _, delta := range change.Delta {
+			resource.Properties = append(resource.Properties, infraDriftProperty{
+				Path:       delta.Path,
+				ChangeType: delta.ChangeType,
+				Before:     delta.Before,
+				After:      delta.After,
+			})
+		}
+
+		result.Resources = append(result.Resources, resource)
+	}
+
+	return result
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_newInfraDriftResult(t *testing.T) {
+	t.Run("NoDrift", func(t *testing.T) {
+		result := newInfraDriftResult(&provisioning.DriftResult{})
+
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"drift":false,"resources":[]}`, string(data))
+	})
+
+	t.Run("Drift", func(t *testing.T) {
+		result := newInfraDriftResult(&provisioning.DriftResult{
+			Changes: []*provisioning.DeploymentPreviewChange{
+				{
+					ChangeType:   provisioning.ChangeTypeModify,
+					ResourceType: "Storage account",
+					Name:         "sttestenv",
+					ResourceId:   provisioning.Resource{Id: "/subscriptions/sub/resourceGroups/rg"},
+					Delta: []provisioning.DeploymentPreviewPropertyChange{
+						{
+							ChangeType: provisioning.PropertyChangeTypeModify,
+							Path:       "properties.minimumTlsVersion",
+							Before:     "TLS1_0",
+							After:      "TLS1_2",
+						},
+					},
+				},
+			},
+		})
+
+		data, err := json.Marshal(result)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"drift": true,
+			"resources": [
+				{
+					"changeType": "Modify",
+					"resourceType": "Storage account",
+					"name": "sttestenv",
+					"resourceId": "/subscriptions/sub/resourceGroups/rg",
+					"properties": [
+						{
+							"path": "properties.minimumTlsVersion",
+							"changeType": "Modify",
+							"before": "TLS1_0",
+							"after": "TLS1_2"
+						}
+					]
+				}
+			]
+		}`, string(data))
+	})
+}
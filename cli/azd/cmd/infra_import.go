@@ -0,0 +1,314 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/azure"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	infraTerraform "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type infraImportFlags struct {
+	global *internal.GlobalCommandOptions
+	*internal.EnvFlag
+	dryRun       bool
+	writeImports bool
+}
+
+func newInfraImportFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *infraImportFlags {
+	flags := &infraImportFlags{
+		EnvFlag: &internal.EnvFlag{},
+	}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func (f *infraImportFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.global = global
+	f.EnvFlag.Bind(local, global)
+	local.BoolVar(&f.dryRun, "dry-run", false, "Only report the resources that would be imported.")
+	local.BoolVar(
+		&f.writeImports,
+		"write-imports",
+		false,
+		fmt.Sprintf(
+			"Write import blocks to %s instead of importing the resources into the state right away.",
+			infraTerraform.ImportsFileName),
+	)
+}
+
+func newInfraImportCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "import [<layer>]",
+		Short: "Import the existing Azure resources of the environment into the Terraform state.",
+		Args:  cobra.MaximumNArgs(1),
+	}
+}
+
+func getCmdInfraImportHelpDescription(*cobra.Command) string {
+	return generateCmdHelpDescription(
+		"Matches the resources of the environment's resource group to the resources of your Terraform "+
+			"configuration, and imports them into the Terraform state.",
+		[]string{
+			formatHelpNote(
+				"Resources match when they have the same type and name. The matches are reported before " +
+					"anything is imported."),
+			formatHelpNote(fmt.Sprintf("Use %s to only report the matches.", output.WithHighLightFormat("--dry-run"))),
+			formatHelpNote(fmt.Sprintf(
+				"Use %s to write import blocks to %s, so the next %s imports the resources.",
+				output.WithHighLightFormat("--write-imports"),
+				infraTerraform.ImportsFileName,
+				output.WithHighLightFormat("azd provision"))),
+		})
+}
+
+type infraImportAction struct {
+	projectConfig   *project.ProjectConfig
+	importManager   *project.ImportManager
+	env             *environment.Environment
+	resourceManager project.ResourceManager
+	resourceService *azapi.ResourceService
+	serviceLocator  ioc.ServiceLocator
+	console         input.Console
+	formatter       output.Formatter
+	writer          io.Writer
+	flags           *infraImportFlags
+	args            []string
+}
+
+func newInfraImportAction(
+	projectConfig *project.ProjectConfig,
+	importManager *project.ImportManager,
+	env *environment.Environment,
+	resourceManager project.ResourceManager,
+	resourceService *azapi.ResourceService,
+	serviceLocator ioc.ServiceLocator,
+	console input.Console,
+	formatter output.Formatter,
+	writer io.Writer,
+	flags *infraImportFlags,
+	args []string,
+) actions.Action {
+	return &infraImportAction{
+		projectConfig:   projectConfig,
+		importManager:   importManager,
+		env:             env,
+		resourceManager: resourceManager,
+		resourceService: resourceService,
+		serviceLocator:  serviceLocator,
+		console:         console,
+		formatter:       formatter,
+		writer:          writer,
+		flags:           flags,
+		args:            args,
+	}
+}
+
+func (a *infraImportAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	infra, err := a.importManager.ProjectInfrastructure(ctx, a.projectConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	layer, err := infraLayer(infra.Options, a.args, "azd infra import")
+	if err != nil {
+		return nil, err
+	}
+
+	if layer.Provider != provisioning.Terraform {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"%w: importing resources requires the terraform provider, not '%s'",
+				internal.ErrUnsupportedOperation, layer.Provider),
+			Suggestion: "Set 'infra.provider' to 'terraform' in azure.yaml.",
+		}
+	}
+
+	terraformProvider, err := a.terraformProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := terraformProvider.Initialize(ctx, a.projectConfig.Path, layer); err != nil {
+		return nil, fmt.Errorf("initializing terraform provider: %w", err)
+	}
+
+	resourceIds, err := a.environmentResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	spinnerMessage := "Matching resources to the Terraform configuration"
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	plan, err := terraformProvider.PlanImports(ctx, resourceIds)
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	if a.formatter.Kind() == output.JsonFormat {
+		if err := a.formatter.Format(plan, a.writer, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		a.displayPlan(ctx, plan)
+	}
+
+	if len(plan.Imports) == 0 {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: "No resources of the environment match the Terraform configuration.",
+			},
+		}, nil
+	}
+
+	if a.flags.dryRun {
+		return &actions.ActionResult{
+			Message: &actions.ResultMessage{
+				Header: fmt.Sprintf("Dry run: %d resource(s) would be imported.", len(plan.Imports)),
+			},
+		}, nil
+	}
+
+	if a.flags.writeImports {
+		return a.writeImports(ctx, terraformProvider, plan)
+	}
+
+	confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      fmt.Sprintf("Import %d resource(s) into the Terraform state?", len(plan.Imports)),
+		DefaultValue: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !confirm {
+		return nil, internal.ErrOperationCancelled
+	}
+
+	spinnerMessage = "Importing resources"
+	a.console.ShowSpinner(ctx, spinnerMessage, input.Step)
+	err = terraformProvider.Import(ctx, plan)
+	a.console.StopSpinner(ctx, spinnerMessage, input.GetStepResultFormat(err))
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("%d resource(s) were imported into the Terraform state.", len(plan.Imports)),
+			FollowUp: fmt.Sprintf(
+				"Run %s to review the remaining differences.", output.WithHighLightFormat("azd provision --preview")),
+		},
+	}, nil
+}
+
+// terraformProvider resolves the Terraform provisioning provider, which is the only provider that supports imports.
+func (a *infraImportAction) terraformProvider() (*infraTerraform.TerraformProvider, error) {
+	var provider provisioning.Provider
+	if err := a.serviceLocator.ResolveNamed(string(provisioning.Terraform), &provider); err != nil {
+		return nil, fmt.Errorf("resolving terraform provider: %w", err)
+	}
+
+	terraformProvider, ok := provider.(*infraTerraform.TerraformProvider)
+	if !ok {
+		return nil, fmt.Errorf("unexpected provider type: %T", provider)
+	}
+
+	return terraformProvider, nil
+}
+
+// environmentResources returns the ids of the resource group of the environment and of the resources it holds.
+func (a *infraImportAction) environmentResources(ctx context.Context) ([]string, error) {
+	subscriptionId := a.env.GetSubscriptionId()
+	resourceGroupName, err := a.resourceManager.GetResourceGroupName(
+		ctx, subscriptionId, a.projectConfig.ResourceGroupName)
+	if err != nil {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("finding the resource group of environment '%s': %w", a.env.Name(), err),
+			Suggestion: fmt.Sprintf(
+				"Set the resource group holding the resources with 'azd env set %s <name>'.",
+				environment.ResourceGroupEnvVarName),
+		}
+	}
+
+	resources, err := a.resourceService.ListResourceGroupResources(ctx, subscriptionId, resourceGroupName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("listing resources of resource group '%s': %w", resourceGroupName, err)
+	}
+
+	resourceIds := []string{azure.ResourceGroupRID(subscriptionId, resourceGroupName)}
+	for _, resource := range resources {
+		resourceIds = append(resourceIds, resource.Id)
+	}
+
+	return resourceIds, nil
+}
+
+func (a *infraImportAction) displayPlan(ctx context.Context, plan *infraTerraform.ImportPlan) {
+	if len(plan.Imports) > 0 {
+		a.console.Message(ctx, output.WithBold("Resources to import:"))
+		for _, resourceImport := range plan.Imports {
+			a.console.Message(ctx, fmt.Sprintf("  %s", output.WithHighLightFormat(resourceImport.Address)))
+			a.console.Message(ctx, fmt.Sprintf("    %s", output.WithGrayFormat(resourceImport.ResourceId)))
+		}
+		a.console.Message(ctx, "")
+	}
+
+	if len(plan.Unmatched) > 0 {
+		a.console.Message(ctx, output.WithBold("Resources without a match in the Terraform configuration:"))
+		for _, resourceId := range plan.Unmatched {
+			a.console.Message(ctx, fmt.Sprintf("  %s", output.WithGrayFormat(resourceId)))
+		}
+		a.console.Message(ctx, "")
+	}
+}
+
+func (a *infraImportAction) writeImports(
+	ctx context.Context,
+	terraformProvider *infraTerraform.TerraformProvider,
+	plan *infraTerraform.ImportPlan,
+) (*actions.ActionResult, error) {
+	if _, err := os.Stat(terraformProvider.ImportsFilePath()); err == nil {
+		overwrite, err := a.console.Confirm(ctx, input.ConsoleOptions{
+			Message:      fmt.Sprintf("%s already exists. Overwrite it?", infraTerraform.ImportsFileName),
+			DefaultValue: false,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !overwrite {
+			return nil, internal.ErrOperationCancelled
+		}
+	}
+
+	importsFilePath, err := terraformProvider.WriteImports(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Import blocks were written to %s.", output.WithHighLightFormat(importsFilePath)),
+			FollowUp: fmt.Sprintf(
+				"Run %s to import the resources.", output.WithHighLightFormat("azd provision")),
+		},
+	}, nil
+}
@@ -210,6 +210,7 @@ func newRootCmd(
 	templatesActions(root)
 	authActions(root)
 	hooksActions(root)
+	workflowActions(root)
 	mcpActions(root)
 	copilotActions(root)
 	execActions(root)
@@ -445,6 +446,22 @@ func newRootCmd(
 				RootLevelHelp: actions.CmdGroupBeta,
 			},
 		})
+	root.
+		Add("remove", &actions.ActionDescriptorOptions{
+			Command:        add.NewRemoveCmd(),
+			ActionResolver: add.NewRemoveAction,
+			GroupingOptions: actions.CommandGroupOptions{
+				RootLevelHelp: actions.CmdGroupBeta,
+			},
+		})
+	root.
+		Add("rename", &actions.ActionDescriptorOptions{
+			Command:        add.NewRenameCmd(),
+			ActionResolver: add.NewRenameAction,
+			GroupingOptions: actions.CommandGroupOptions{
+				RootLevelHelp: actions.CmdGroupBeta,
+			},
+		})
 
 	// Register any global middleware defined by the caller
 	if len(middlewareChain) > 0 {
@@ -429,7 +429,7 @@ func newTemplateSourceAddFlags(cmd *cobra.Command) *templateSourceAddFlags {
 	flags := &templateSourceAddFlags{}
 
 	cmd.Flags().StringVarP(&flags.kind, "type", "t", "", "Kind of the template source. Supported types are "+
-		"'file', 'url' and 'gh'.")
+		"'file', 'url', 'gh' and 'oci'.")
 	cmd.Flags().StringVarP(&flags.location, "location", "l", "", "Location of the template source. "+
 		"Required when using type flag.")
 	cmd.Flags().StringVarP(&flags.name, "name", "n", "", "Display name of the template source.")
@@ -480,7 +480,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 				Suggestion: fmt.Sprintf(
 					"For custom keys, supported types are %s."+
 						" To add the known source '%s', run 'azd template source add %s' without --type.",
-					ux.ListAsText([]string{"'file'", "'url'", "'gh'"}), a.flags.kind, a.flags.kind),
+					ux.ListAsText([]string{"'file'", "'url'", "'gh'", "'oci'"}), a.flags.kind, a.flags.kind),
 			}
 		}
 	}
@@ -504,7 +504,7 @@ func (a *templateSourceAddAction) Run(ctx context.Context) (*actions.ActionResul
 						a.flags.kind, internal.ErrValidationFailed),
 					Suggestion: fmt.Sprintf(
 						"Supported source types are %s.",
-						ux.ListAsText([]string{"'file'", "'url'", "'gh'"})),
+						ux.ListAsText([]string{"'file'", "'url'", "'gh'", "'oci'"})),
 				}
 			}
 
@@ -5152,6 +5152,16 @@ const completionSpec: Fig.Spec = {
 						generators: azdGenerators.listConfigKeys,
 					},
 				},
+				{
+					name: ['vault'],
+					description: 'Manage the local vault that stores secret configuration values.',
+					subcommands: [
+						{
+							name: ['rotate'],
+							description: 'Re-encrypt all the vault secrets with a new data key.',
+						},
+					],
+				},
 			],
 		},
 		{
@@ -5494,6 +5504,72 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['copy'],
+					description: 'Copy an environment\'s values and configuration to a new environment.',
+					options: [
+						{
+							name: ['--exclude'],
+							description: 'Do not copy the .env keys matching this pattern (e.g. \'SERVICE_*\'). May be repeated.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'exclude',
+								},
+							],
+						},
+						{
+							name: ['--include'],
+							description: 'Only copy the .env keys matching this pattern (e.g. \'AZURE_*\'). May be repeated.',
+							isRepeatable: true,
+							args: [
+								{
+									name: 'include',
+								},
+							],
+						},
+						{
+							name: ['--no-outputs'],
+							description: 'Do not copy the values set from provisioning outputs.',
+						},
+					],
+					args: [
+						{
+							name: 'source',
+						},
+						{
+							name: 'target',
+						},
+					],
+				},
+				{
+					name: ['diff'],
+					description: 'Compare the values of two environments, or of an environment and its deployment.',
+					options: [
+						{
+							name: ['--deployed'],
+							description: 'Compare the environment\'s values with the outputs of its current deployment.',
+						},
+						{
+							name: ['--hint'],
+							description: 'Hint to help identify the environment\'s deployment.',
+							args: [
+								{
+									name: 'hint',
+								},
+							],
+						},
+					],
+					args: [
+						{
+							name: 'environment',
+						},
+						{
+							name: 'environment',
+							isOptional: true,
+						},
+					],
+				},
 				{
 					name: ['get-value'],
 					description: 'Get specific environment value.',
@@ -5506,6 +5582,10 @@ const completionSpec: Fig.Spec = {
 					name: ['get-values'],
 					description: 'Get all environment values.',
 				},
+				{
+					name: ['history'],
+					description: 'List the revisions of an environment\'s values.',
+				},
 				{
 					name: ['list', 'ls'],
 					description: 'List environments.',
@@ -5578,6 +5658,13 @@ const completionSpec: Fig.Spec = {
 						name: 'environment',
 					},
 				},
+				{
+					name: ['rollback'],
+					description: 'Restore an environment\'s values from a revision.',
+					args: {
+						name: 'revision',
+					},
+				},
 				{
 					name: ['select'],
 					description: 'Set the default environment.',
@@ -5666,6 +5753,10 @@ const completionSpec: Fig.Spec = {
 							description: 'Force installation, including downgrades and reinstalls',
 							isDangerous: true,
 						},
+						{
+							name: ['--locked'],
+							description: 'Install the extensions exactly as recorded in the azd-extensions.lock file of the project, failing when they no longer match',
+						},
 						{
 							name: ['--source', '-s'],
 							description: 'The extension source to use for installs. Accepts a registered source name or a registry location (URL or file path) to register and install from.',
@@ -5765,9 +5856,19 @@ const completionSpec: Fig.Spec = {
 										},
 									],
 								},
+								{
+									name: ['--public-key'],
+									description: 'A base64 encoded ed25519 public key trusted to sign the registry and the extensions of the source. Can be specified multiple times',
+									isRepeatable: true,
+									args: [
+										{
+											name: 'public-key',
+										},
+									],
+								},
 								{
 									name: ['--type', '-t'],
-									description: 'The type of the extension source. Supported types are \'file\' and \'url\'',
+									description: 'The type of the extension source. Supported types are \'file\', \'url\' and \'oci\'',
 									args: [
 										{
 											name: 'type',
@@ -5920,6 +6021,14 @@ const completionSpec: Fig.Spec = {
 			name: ['infra'],
 			description: 'Manage your Infrastructure as Code (IaC).',
 			subcommands: [
+				{
+					name: ['drift'],
+					description: 'Detect changes made to the Azure resources since they were last provisioned.',
+					args: {
+						name: 'layer',
+						isOptional: true,
+					},
+				},
 				{
 					name: ['generate', 'gen', 'synth'],
 					description: 'Write IaC for your project to disk, allowing you to manually manage it.',
@@ -5931,6 +6040,24 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['import'],
+					description: 'Import the existing Azure resources of the environment into the Terraform state.',
+					options: [
+						{
+							name: ['--dry-run'],
+							description: 'Only report the resources that would be imported.',
+						},
+						{
+							name: ['--write-imports'],
+							description: 'Write import blocks to imports.tf instead of importing the resources into the state right away.',
+						},
+					],
+					args: {
+						name: 'layer',
+						isOptional: true,
+					},
+				},
 			],
 		},
 		{
@@ -6196,6 +6323,25 @@ const completionSpec: Fig.Spec = {
 				isOptional: true,
 			},
 		},
+		{
+			name: ['remove'],
+			description: 'Remove a component from your project.',
+			args: {
+				name: 'name',
+			},
+		},
+		{
+			name: ['rename'],
+			description: 'Rename a component of your project.',
+			args: [
+				{
+					name: 'name',
+				},
+				{
+					name: 'new-name',
+				},
+			],
+		},
 		{
 			name: ['restore'],
 			description: 'Restores the project\'s dependencies.',
@@ -6291,7 +6437,7 @@ const completionSpec: Fig.Spec = {
 								},
 								{
 									name: ['--type', '-t'],
-									description: 'Kind of the template source. Supported types are \'file\', \'url\' and \'gh\'.',
+									description: 'Kind of the template source. Supported types are \'file\', \'url\', \'gh\' and \'oci\'.',
 									args: [
 										{
 											name: 'type',
@@ -6432,6 +6578,19 @@ const completionSpec: Fig.Spec = {
 						},
 					],
 				},
+				{
+					name: ['--plan'],
+					description: 'Prints the execution plan without running it. Supported formats: dot, mermaid, json.',
+					args: [
+						{
+							name: 'plan',
+						},
+					],
+				},
+				{
+					name: ['--resume'],
+					description: 'Resumes a previously failed run, skipping steps that already succeeded and whose inputs are unchanged.',
+				},
 				{
 					name: ['--subscription'],
 					description: 'ID of an Azure subscription to use for the new environment',
@@ -6736,6 +6895,19 @@ const completionSpec: Fig.Spec = {
 				},
 			],
 		},
+		{
+			name: ['workflow'],
+			description: 'Run workflows defined in your project.',
+			subcommands: [
+				{
+					name: ['run'],
+					description: 'Runs the specified workflow from the workflows section of azure.yaml.',
+					args: {
+						name: 'name',
+					},
+				},
+			],
+		},
 		{
 			name: ['help'],
 			description: 'Help about any command',
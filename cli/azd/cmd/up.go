@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/MakeNowJust/heredoc/v2"
@@ -14,9 +15,12 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/cmd"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
@@ -37,6 +41,8 @@ type upFlags struct {
 	// workflow runner that spawned `azd package` / `azd provision` as
 	// child processes — see UpGraphAction.Run).
 	flagSet *pflag.FlagSet
+	resume  bool
+	plan    string
 }
 
 func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
@@ -48,6 +54,19 @@ func (u *upFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptio
 	u.ProvisionFlags.SetCommon(&u.EnvFlag)
 	u.DeployFlags.BindNonCommon(local, global)
 	u.DeployFlags.SetCommon(&u.EnvFlag)
+
+	local.BoolVar(
+		&u.resume,
+		"resume",
+		false,
+		"Resumes a previously failed run, skipping steps that already succeeded and whose inputs are unchanged.",
+	)
+	local.StringVar(
+		&u.plan,
+		"plan",
+		"",
+		"Prints the execution plan without running it. Supported formats: dot, mermaid, json.",
+	)
 }
 
 func newUpFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *upFlags {
@@ -75,6 +94,8 @@ type upAction struct {
 	importManager       *project.ImportManager
 	workflowRunner      *workflow.Runner
 	upGraph             *cmd.UpGraphAction
+	commandRunner       exec.CommandRunner
+	serviceLocator      ioc.ServiceLocator
 }
 
 func newUpAction(
@@ -88,6 +109,8 @@ func newUpAction(
 	importManager *project.ImportManager,
 	workflowRunner *workflow.Runner,
 	upGraph *cmd.UpGraphAction,
+	commandRunner exec.CommandRunner,
+	serviceLocator ioc.ServiceLocator,
 ) actions.Action {
 	return &upAction{
 		flags:               flags,
@@ -100,10 +123,16 @@ func newUpAction(
 		importManager:       importManager,
 		workflowRunner:      workflowRunner,
 		upGraph:             upGraph,
+		commandRunner:       commandRunner,
+		serviceLocator:      serviceLocator,
 	}
 }
 
 func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	if u.flags.plan != "" {
+		return nil, u.printPlan(ctx)
+	}
+
 	// Apply --subscription and --location flags to the environment before provisioning
 	updatedEnv := false
 	if flagSub := u.flags.ProvisionFlags.Subscription(); flagSub != "" {
@@ -171,11 +200,19 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	// and deploy into a single execution so packaging can overlap with
 	// provisioning.
 	if upWorkflow, has := u.projectConfig.Workflows["up"]; has {
+		if u.flags.resume {
+			return nil, &internal.ErrorWithSuggestion{
+				Err:        internal.ErrResumeWithCustomWorkflow,
+				Suggestion: "Remove the 'workflows.up' section from azure.yaml or run 'azd up' without --resume.",
+			}
+		}
 		u.console.Message(ctx, output.WithGrayFormat("Note: Running custom 'up' workflow from azure.yaml"))
 		if u.flags.EnvironmentName != "" {
 			ctx = context.WithValue(ctx, envFlagCtxKey, u.flags.EnvFlag)
 		}
-		if err := u.workflowRunner.Run(ctx, upWorkflow); err != nil {
+		options := newWorkflowRunOptions(
+			u.projectConfig, u.env, u.envManager, u.commandRunner, u.console, u.serviceLocator)
+		if err := u.workflowRunner.RunWithOptions(ctx, upWorkflow, options); err != nil {
 			return nil, err
 		}
 		return &actions.ActionResult{
@@ -187,7 +224,44 @@ func (u *upAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 
 	layers := infra.Options.GetLayers()
-	return u.upGraph.Run(ctx, layers, &u.flags.DeployFlags, u.flags.flagSet, startTime)
+	return u.upGraph.Run(ctx, layers, &u.flags.DeployFlags, u.flags.flagSet, startTime, cmd.UpGraphRunOptions{
+		Resume: u.flags.resume,
+	})
+}
+
+// printPlan writes the unified `azd up` execution graph in the format requested
+// by --plan. Nothing is provisioned or deployed, and the environment is not
+// modified.
+func (u *upAction) printPlan(ctx context.Context) error {
+	format := exegraph.ExportFormat(u.flags.plan)
+	if !slices.Contains(exegraph.ExportFormats(), format) {
+		return &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("unsupported plan format '%s': %w", u.flags.plan, internal.ErrInvalidArgValue),
+			Suggestion: "Use one of: dot, mermaid, json.",
+		}
+	}
+	if u.flags.resume {
+		return &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("--plan and --resume: %w", internal.ErrInvalidFlagCombination),
+			Suggestion: "Run 'azd up --plan <format>' without --resume.",
+		}
+	}
+	if _, has := u.projectConfig.Workflows["up"]; has {
+		return &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf(
+				"--plan is not available for a custom 'up' workflow: %w", internal.ErrUnsupportedOperation),
+			Suggestion: "Remove the 'workflows.up' section from azure.yaml to use the unified execution graph.",
+		}
+	}
+
+	infra, err := u.importManager.ProjectInfrastructure(ctx, u.projectConfig)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = infra.Cleanup() }()
+
+	return u.upGraph.Plan(
+		ctx, infra.Options.GetLayers(), &u.flags.DeployFlags, format, u.console.Handles().Stdout)
 }
 
 func getCmdUpHelpDescription(c *cobra.Command) string {
@@ -218,7 +292,8 @@ func getCmdUpHelpDescription(c *cobra.Command) string {
 			    - azd: deploy --all
 			-------------------------
 
-			Any azd command and flags are supported in the workflow steps.`,
+			Any azd command and flags are supported in the workflow steps. Steps can also run scripts, run in
+			parallel and be conditional; see %s.`,
 			output.WithHighLightFormat("package"),
 			output.WithHighLightFormat("provision"),
 			output.WithHighLightFormat("deploy"),
@@ -231,6 +306,7 @@ func getCmdUpHelpDescription(c *cobra.Command) string {
 			output.WithHighLightFormat("workflows"),
 			output.WithHighLightFormat("azure.yaml"),
 			output.WithGrayFormat("# azure.yaml"),
+			output.WithHighLightFormat("azd workflow run --help"),
 		),
 		nil,
 	)
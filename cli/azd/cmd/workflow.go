@@ -0,0 +1,245 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/MakeNowJust/heredoc/v2"
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func workflowActions(root *actions.ActionDescriptor) *actions.ActionDescriptor {
+	group := root.Add("workflow", &actions.ActionDescriptorOptions{
+		Command: &cobra.Command{
+			Use:   "workflow",
+			Short: "Run workflows defined in your project.",
+		},
+		GroupingOptions: actions.CommandGroupOptions{
+			RootLevelHelp: actions.CmdGroupBeta,
+		},
+	})
+
+	group.Add("run", &actions.ActionDescriptorOptions{
+		Command:        newWorkflowRunCmd(),
+		FlagsResolver:  newWorkflowRunFlags,
+		ActionResolver: newWorkflowRunAction,
+		HelpOptions: actions.ActionHelpOptions{
+			Description: getCmdWorkflowRunHelpDescription,
+		},
+	})
+
+	return group
+}
+
+func newWorkflowRunFlags(cmd *cobra.Command, global *internal.GlobalCommandOptions) *workflowRunFlags {
+	flags := &workflowRunFlags{}
+	flags.Bind(cmd.Flags(), global)
+
+	return flags
+}
+
+func newWorkflowRunCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run <name>",
+		Short: "Runs the specified workflow from the workflows section of azure.yaml.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type workflowRunFlags struct {
+	internal.EnvFlag
+	global *internal.GlobalCommandOptions
+}
+
+func (f *workflowRunFlags) Bind(local *pflag.FlagSet, global *internal.GlobalCommandOptions) {
+	f.EnvFlag.Bind(local, global)
+	f.global = global
+}
+
+type workflowRunAction struct {
+	projectConfig  *project.ProjectConfig
+	env            *environment.Environment
+	envManager     environment.Manager
+	commandRunner  exec.CommandRunner
+	console        input.Console
+	workflowRunner *workflow.Runner
+	serviceLocator ioc.ServiceLocator
+	flags          *workflowRunFlags
+	args           []string
+}
+
+func newWorkflowRunAction(
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	envManager environment.Manager,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	workflowRunner *workflow.Runner,
+	serviceLocator ioc.ServiceLocator,
+	flags *workflowRunFlags,
+	args []string,
+) actions.Action {
+	return &workflowRunAction{
+		projectConfig:  projectConfig,
+		env:            env,
+		envManager:     envManager,
+		commandRunner:  commandRunner,
+		console:        console,
+		workflowRunner: workflowRunner,
+		serviceLocator: serviceLocator,
+		flags:          flags,
+		args:           args,
+	}
+}
+
+func (w *workflowRunAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := w.args[0]
+
+	wf, has := w.projectConfig.Workflows[name]
+	if !has {
+		suggestion := "Add the workflow to the 'workflows' section of azure.yaml."
+		if len(w.projectConfig.Workflows) > 0 {
+			suggestion = fmt.Sprintf("Available workflows: %s.",
+				strings.Join(slices.Sorted(maps.Keys(w.projectConfig.Workflows)), ", "))
+		}
+
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("workflow '%s': %w", name, internal.ErrWorkflowNotFound),
+			Suggestion: suggestion,
+		}
+	}
+
+	w.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title: "Running workflow (azd workflow run)",
+		TitleNote: fmt.Sprintf(
+			"Running the %s workflow for environment %s",
+			output.WithHighLightFormat(name),
+			output.WithHighLightFormat(w.env.Name()),
+		),
+	})
+
+	startTime := time.Now()
+
+	// Propagate the selected environment to the azd commands run by the workflow.
+	if w.flags.EnvironmentName != "" {
+		ctx = context.WithValue(ctx, envFlagCtxKey, w.flags.EnvFlag)
+	}
+
+	options := newWorkflowRunOptions(
+		w.projectConfig, w.env, w.envManager, w.commandRunner, w.console, w.serviceLocator)
+	if err := w.workflowRunner.RunWithOptions(ctx, wf, options); err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf("Your %s workflow completed in %s.", name, ux.DurationAsText(since(startTime))),
+		},
+	}, nil
+}
+
+// newWorkflowRunOptions returns the options that let workflow steps from azure.yaml run scripts
+// through the hook executors and evaluate `if` conditions against the selected environment.
+func newWorkflowRunOptions(
+	projectConfig *project.ProjectConfig,
+	env *environment.Environment,
+	envManager environment.Manager,
+	commandRunner exec.CommandRunner,
+	console input.Console,
+	serviceLocator ioc.ServiceLocator,
+) workflow.RunOptions {
+	return workflow.RunOptions{
+		Scripts: &workflowScriptRunner{
+			projectConfig:  projectConfig,
+			env:            env,
+			envManager:     envManager,
+			commandRunner:  commandRunner,
+			console:        console,
+			serviceLocator: serviceLocator,
+		},
+		Getenv: func(ctx context.Context) (func(string) string, error) {
+			// Earlier steps may have changed the environment, e.g. through `azd env set`.
+			if err := envManager.Reload(ctx, env); err != nil {
+				return nil, fmt.Errorf("reloading environment: %w", err)
+			}
+			return env.Getenv, nil
+		},
+	}
+}
+
+// workflowScriptRunner runs workflow script steps as project-level hooks.
+type workflowScriptRunner struct {
+	projectConfig  *project.ProjectConfig
+	env            *environment.Environment
+	envManager     environment.Manager
+	commandRunner  exec.CommandRunner
+	console        input.Console
+	serviceLocator ioc.ServiceLocator
+}
+
+// RunScript implements workflow.ScriptRunner.
+func (s *workflowScriptRunner) RunScript(ctx context.Context, name string, script *ext.HookConfig) error {
+	hooksManager := ext.NewHooksManager(ext.HooksManagerOptions{
+		Cwd: s.projectConfig.Path, ProjectDir: s.projectConfig.Path,
+	}, s.commandRunner)
+	hooksRunner := ext.NewHooksRunner(
+		hooksManager, s.commandRunner, s.envManager, s.console, s.projectConfig.Path, nil, s.env, s.serviceLocator)
+
+	// Validation resolves and caches paths on the hook, so run a copy to keep the project
+	// configuration untouched.
+	hook := *script
+	return hooksRunner.RunHook(ctx, name, "workflow", &hook, nil)
+}
+
+func getCmdWorkflowRunHelpDescription(c *cobra.Command) string {
+	return generateCmdHelpDescription(
+		heredoc.Docf(
+			`Runs a named workflow from the %s section of your %s.
+
+			Steps run in order. A step runs an azd command, a hook-style script, or a %s group of steps
+			that run concurrently. Steps can be skipped with an %s condition over environment values and
+			can set %s to keep the workflow going when they fail.
+
+			-------------------------
+			%s
+			workflows:
+			  release:
+			    - run: ./scripts/test.sh
+			    - azd: provision
+			      if: ${DEPLOY_INFRA}
+			    - parallel:
+			        - azd: deploy api
+			        - azd: deploy web
+			    - name: smoke test
+			      run: ./scripts/smoke.sh
+			      continueOnError: true
+			-------------------------`,
+			output.WithHighLightFormat("workflows"),
+			output.WithHighLightFormat("azure.yaml"),
+			output.WithHighLightFormat("parallel"),
+			output.WithHighLightFormat("if"),
+			output.WithHighLightFormat("continueOnError"),
+			output.WithGrayFormat("# azure.yaml"),
+		),
+		nil,
+	)
+}
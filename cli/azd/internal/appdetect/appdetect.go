@@ -27,6 +27,7 @@ const (
 	JavaScript    Language = "js"
 	TypeScript    Language = "ts"
 	Python        Language = "python"
+	Go            Language = "go"
 )
 
 func (pt Language) Display() string {
@@ -43,6 +44,8 @@ func (pt Language) Display() string {
 		return "TypeScript"
 	case Python:
 		return "Python"
+	case Go:
+		return "Go"
 	}
 
 	return ""
@@ -60,6 +63,11 @@ const (
 	PyFlask   Dependency = "flask"
 	PyDjango  Dependency = "django"
 	PyFastApi Dependency = "fastapi"
+
+	GoGin     Dependency = "gin"
+	GoEcho    Dependency = "echo"
+	GoChi     Dependency = "chi"
+	GoNetHttp Dependency = "net/http"
 )
 
 var WebUIFrameworks = map[Dependency]struct{}{
@@ -73,6 +81,8 @@ func (f Dependency) Language() Language {
 	switch f {
 	case JsReact, JsAngular, JsJQuery, JsVite:
 		return JavaScript
+	case GoGin, GoEcho, GoChi, GoNetHttp:
+		return Go
 	}
 
 	return ""
@@ -90,6 +100,14 @@ func (f Dependency) Display() string {
 		return "Vite"
 	case JsNext:
 		return "Next.js"
+	case GoGin:
+		return "Gin"
+	case GoEcho:
+		return "Echo"
+	case GoChi:
+		return "chi"
+	case GoNetHttp:
+		return "net/http"
 	}
 
 	return ""
@@ -181,6 +199,13 @@ type projectDetector interface {
 	DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error)
 }
 
+// rootedDetector is a projectDetector whose detection depends on the directory that projects are detected under.
+type rootedDetector interface {
+	projectDetector
+	// withRoot returns a detector of the projects under root.
+	withRoot(root string) projectDetector
+}
+
 var allDetectors = []projectDetector{
 	// Order here determines precedence when two projects are in the same directory.
 	// This is unlikely to occur in practice, but reordering could help to break the tie in these cases.
@@ -194,6 +219,7 @@ var allDetectors = []projectDetector{
 	&dotNetDetector{
 		dotnetCli: dotnet.NewCli(exec.NewCommandRunner(nil)),
 	},
+	&goDetector{},
 	&pythonDetector{},
 	&javaScriptDetector{},
 }
@@ -217,6 +243,13 @@ func DetectDirectory(ctx context.Context, directory string, options ...DetectDir
 
 func detectUnder(ctx context.Context, root string, config detectConfig) ([]Project, error) {
 	projects := []Project{}
+	detectors := make([]projectDetector, len(config.detectors))
+	for i, detector := range config.detectors {
+		detectors[i] = detector
+		if rooted, ok := detector.(rootedDetector); ok {
+			detectors[i] = rooted.withRoot(filepath.Clean(root))
+		}
+	}
 
 	walkFunc := func(path string, entries []fs.DirEntry) error {
 		relativePath, err := filepath.Rel(root, path)
@@ -237,7 +270,7 @@ func detectUnder(ctx context.Context, root string, config detectConfig) ([]Proje
 			}
 		}
 
-		project, err := detectAny(ctx, config.detectors, path, entries)
+		project, err := detectAny(ctx, detectors, path, entries)
 		if err != nil {
 			return err
 		}
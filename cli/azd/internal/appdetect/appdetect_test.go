@@ -146,6 +146,7 @@ func TestDetect(t *testing.T) {
 				WithoutJava(),
 				WithoutJavaScript(),
 				WithoutPython(),
+				WithoutGo(),
 			},
 			[]Project{
 				{
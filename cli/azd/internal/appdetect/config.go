@@ -255,3 +255,37 @@ func (o *excludeJavaScript) applyLang(c languageConfig) languageConfig {
 func WithoutJavaScript() LanguageOption {
 	return &excludeJavaScript{}
 }
+
+type includeGo struct {
+}
+
+func (o *includeGo) apply(c detectConfig) detectConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Go)
+	return c
+}
+
+func (o *includeGo) applyLang(c languageConfig) languageConfig {
+	c.IncludeLanguages = append(c.IncludeLanguages, Go)
+	return c
+}
+
+func WithGo() LanguageOption {
+	return &includeGo{}
+}
+
+type excludeGo struct {
+}
+
+func (o *excludeGo) apply(c detectConfig) detectConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Go)
+	return c
+}
+
+func (o *excludeGo) applyLang(c languageConfig) languageConfig {
+	c.ExcludeLanguages = append(c.ExcludeLanguages, Go)
+	return c
+}
+
+func WithoutGo() LanguageOption {
+	return &excludeGo{}
+}
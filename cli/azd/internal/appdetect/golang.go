@@ -0,0 +1,266 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type goDetector struct {
+	// root is the directory that projects are detected under, empty when a single directory is detected. Modules above
+	// root are not considered.
+	root string
+}
+
+// goModule is a Go module, as declared by a go.mod file.
+type goModule struct {
+	path string
+	// requires are the modules the module requires directly, leaving out those marked `// indirect`.
+	requires []string
+}
+
+func (gd *goDetector) Language() Language {
+	return Go
+}
+
+func (gd *goDetector) withRoot(root string) projectDetector {
+	return &goDetector{root: root}
+}
+
+func (gd *goDetector) DetectProject(ctx context.Context, path string, entries []fs.DirEntry) (*Project, error) {
+	for _, entry := range entries {
+		if entry.Name() == "go.mod" {
+			module, err := readGoModule(path)
+			if err != nil {
+				return nil, fmt.Errorf("reading go.mod: %w", err)
+			}
+
+			mainPackage := readGoMainPackage(path, entries)
+			if mainPackage == nil {
+				// The module may be made of several commands, e.g. cmd/api and cmd/worker, which are detected as the
+				// directories of the module are walked.
+				return nil, nil
+			}
+
+			return goProject(module, mainPackage, &Project{
+				Language:      Go,
+				Path:          path,
+				DetectionRule: "Inferred by presence of: go.mod",
+			}), nil
+		}
+	}
+
+	mainPackage := readGoMainPackage(path, entries)
+	if mainPackage == nil {
+		return nil, nil
+	}
+
+	module, err := goModuleOfCommand(path, gd.root)
+	if err != nil {
+		return nil, fmt.Errorf("reading go.mod: %w", err)
+	}
+
+	if module == nil {
+		return nil, nil
+	}
+
+	return goProject(module, mainPackage, &Project{
+		Language:      Go,
+		Path:          path,
+		RootPath:      module.path,
+		DetectionRule: "Inferred by presence of: go.mod, main package under cmd",
+	}), nil
+}
+
+// goModuleOfCommand returns the module that the directory is a command of, that is, the innermost module enclosing the
+// directory, when the directory is under its cmd directory. It returns nil otherwise. Modules above root are not
+// considered when root is set.
+func goModuleOfCommand(path string, root string) (*goModule, error) {
+	if path == root {
+		return nil, nil
+	}
+
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); errors.Is(err, os.ErrNotExist) {
+			if dir == root {
+				break
+			}
+
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(filepath.Join(dir, "cmd"), path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, nil
+		}
+
+		return readGoModule(dir)
+	}
+
+	return nil, nil
+}
+
+// goProject completes the project with the dependencies of the module and the imports of its main package.
+func goProject(module *goModule, mainPackage *goMainPackage, project *Project) *Project {
+	dependencyMap := map[Dependency]struct{}{}
+	databaseDepMap := map[DatabaseDep]struct{}{}
+
+	for _, require := range module.requires {
+		switch {
+		case goModuleMatches(require, "github.com/gin-gonic/gin"):
+			dependencyMap[GoGin] = struct{}{}
+		case goModuleMatches(require, "github.com/labstack/echo"):
+			dependencyMap[GoEcho] = struct{}{}
+		case goModuleMatches(require, "github.com/go-chi/chi"):
+			dependencyMap[GoChi] = struct{}{}
+		}
+
+		switch {
+		case goModuleMatches(require, "github.com/jackc/pgx"),
+			goModuleMatches(require, "github.com/lib/pq"):
+			databaseDepMap[DbPostgres] = struct{}{}
+		case goModuleMatches(require, "github.com/go-sql-driver/mysql"):
+			databaseDepMap[DbMySql] = struct{}{}
+		case goModuleMatches(require, "go.mongodb.org/mongo-driver"):
+			databaseDepMap[DbMongo] = struct{}{}
+		case goModuleMatches(require, "github.com/redis/go-redis"),
+			goModuleMatches(require, "github.com/go-redis/redis"):
+			databaseDepMap[DbRedis] = struct{}{}
+		case goModuleMatches(require, "github.com/microsoft/go-mssqldb"),
+			goModuleMatches(require, "github.com/denisenkom/go-mssqldb"):
+			databaseDepMap[DbSqlServer] = struct{}{}
+		}
+	}
+
+	// Services written against the standard library only have no dependency to tell them apart.
+	if len(dependencyMap) == 0 && slices.Contains(mainPackage.imports, "net/http") {
+		dependencyMap[GoNetHttp] = struct{}{}
+	}
+
+	if len(dependencyMap) > 0 {
+		project.Dependencies = slices.SortedFunc(maps.Keys(dependencyMap),
+			func(a, b Dependency) int {
+				return strings.Compare(string(a), string(b))
+			})
+	}
+
+	if len(databaseDepMap) > 0 {
+		project.DatabaseDeps = slices.SortedFunc(maps.Keys(databaseDepMap),
+			func(a, b DatabaseDep) int {
+				return strings.Compare(string(a), string(b))
+			})
+	}
+
+	return project
+}
+
+// goModuleMatches reports whether the module path is the module, or one of its major versions, e.g.
+// github.com/jackc/pgx/v5 for github.com/jackc/pgx.
+func goModuleMatches(modulePath string, module string) bool {
+	return modulePath == module || strings.HasPrefix(modulePath, module+"/v")
+}
+
+// readGoModule reads the module paths required by the go.mod file of the directory.
+func readGoModule(path string) (*goModule, error) {
+	file, err := os.Open(filepath.Join(path, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	module := &goModule{path: path}
+	inRequireBlock := false
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, comment, _ := strings.Cut(scanner.Text(), "//")
+		indirect := isGoIndirectComment(comment)
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch {
+		case inRequireBlock && fields[0] == ")":
+			inRequireBlock = false
+		case inRequireBlock && !indirect:
+			module.requires = append(module.requires, fields[0])
+		case fields[0] == "require" && len(fields) > 1 && fields[1] == "(":
+			inRequireBlock = true
+		case fields[0] == "require" && len(fields) > 1 && !indirect:
+			module.requires = append(module.requires, fields[1])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return module, nil
+}
+
+// isGoIndirectComment returns whether the comment of a require line of go.mod marks the requirement as indirect, as
+// `go mod tidy` does, e.g. `// indirect` or `// indirect; reason`.
+func isGoIndirectComment(comment string) bool {
+	comment = strings.TrimSpace(comment)
+	return comment == "indirect" || strings.HasPrefix(comment, "indirect;")
+}
+
+// goMainPackage is the main package of a Go command.
+type goMainPackage struct {
+	// imports are the paths of the packages imported by the files of the package.
+	imports []string
+}
+
+// readGoMainPackage returns the main package of the directory, or nil if the Go files of the directory, test files
+// excluded, are not part of a main package.
+func readGoMainPackage(path string, entries []fs.DirEntry) *goMainPackage {
+	var mainPackage *goMainPackage
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != ".go" || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filepath.Join(path, name), nil, parser.ImportsOnly)
+		if err != nil {
+			// Files that don't parse, such as templates named *.go, don't tell anything about the package.
+			continue
+		}
+
+		if file.Name.Name != "main" {
+			return nil
+		}
+
+		if mainPackage == nil {
+			mainPackage = &goMainPackage{}
+		}
+
+		for _, spec := range file.Imports {
+			importPath, err := strconv.Unquote(spec.Path.Value)
+			if err == nil && !slices.Contains(mainPackage.imports, importPath) {
+				mainPackage.imports = append(mainPackage.imports, importPath)
+			}
+		}
+	}
+
+	return mainPackage
+}
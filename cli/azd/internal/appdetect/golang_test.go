@@ -0,0 +1,174 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package appdetect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeGoFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+}
+
+const goModFull = `module example.com/full
+
+go 1.24
+
+require github.com/gin-gonic/gin v1.10.0
+
+require (
+	github.com/go-sql-driver/mysql v1.8.1
+	github.com/jackc/pgx/v5 v5.7.1
+	github.com/redis/go-redis/v9 v9.7.0 // redis cache
+	go.mongodb.org/mongo-driver v1.17.1
+	golang.org/x/net v0.33.0 // indirect
+)
+`
+
+func TestDetectGoProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		files map[string]string
+		// root is the directory projects are detected under, relative to the files.
+		root string
+		want []Project
+	}{
+		{
+			name: "MainAtRoot",
+			files: map[string]string{
+				"go.mod":  goModFull,
+				"main.go": "package main\n\nimport \"github.com/gin-gonic/gin\"\n\nfunc main() { gin.Default().Run() }\n",
+				// inner packages are not detected as projects
+				"cmd/tool/main.go": "package main\n\nfunc main() {}\n",
+			},
+			want: []Project{
+				{
+					Language:      Go,
+					Path:          ".",
+					DetectionRule: "Inferred by presence of: go.mod",
+					Dependencies:  []Dependency{GoGin},
+					DatabaseDeps:  []DatabaseDep{DbMongo, DbMySql, DbPostgres, DbRedis},
+				},
+			},
+		},
+		{
+			name: "NetHttp",
+			files: map[string]string{
+				// drivers required by dependencies are not used by the project
+				"go.mod":       "module example.com/nethttp\n\ngo 1.24\n\nrequire github.com/lib/pq v1.10.9 // indirect\n",
+				"main.go":      "package main\n\nimport (\n\t\"log\"\n\t\"net/http\"\n)\n\nfunc main() {}\n",
+				"main_test.go": "package main_test\n",
+			},
+			want: []Project{
+				{
+					Language:      Go,
+					Path:          ".",
+					DetectionRule: "Inferred by presence of: go.mod",
+					Dependencies:  []Dependency{GoNetHttp},
+				},
+			},
+		},
+		{
+			name: "Commands",
+			files: map[string]string{
+				"go.mod": "module example.com/cmds\n\ngo 1.24\n\n" +
+					"require (\n\tgithub.com/go-chi/chi/v5 v5.1.0\n\tgithub.com/lib/pq v1.10.9\n)\n",
+				"doc.go":             "package cmds\n",
+				"cmd/api/main.go":    "package main\n\nimport \"github.com/go-chi/chi/v5\"\n\nfunc main() {}\n",
+				"cmd/worker/main.go": "package main\n\nfunc main() {}\n",
+				"internal/db/db.go":  "package db\n",
+				"tools/gen/main.go":  "package main\n\nfunc main() {}\n",
+			},
+			want: []Project{
+				{
+					Language:      Go,
+					Path:          "cmd/api",
+					RootPath:      ".",
+					DetectionRule: "Inferred by presence of: go.mod, main package under cmd",
+					Dependencies:  []Dependency{GoChi},
+					DatabaseDeps:  []DatabaseDep{DbPostgres},
+				},
+				{
+					Language:      Go,
+					Path:          "cmd/worker",
+					RootPath:      ".",
+					DetectionRule: "Inferred by presence of: go.mod, main package under cmd",
+					Dependencies:  []Dependency{GoChi},
+					DatabaseDeps:  []DatabaseDep{DbPostgres},
+				},
+			},
+		},
+		{
+			name: "CommandsAboveRoot",
+			files: map[string]string{
+				"go.mod":          "module example.com/cmds\n\ngo 1.24\n",
+				"cmd/api/main.go": "package main\n\nfunc main() {}\n",
+			},
+			root: "cmd",
+			want: []Project{},
+		},
+		{
+			name: "Library",
+			files: map[string]string{
+				"go.mod": "module example.com/lib\n\ngo 1.24\n",
+				"lib.go": "package lib\n",
+			},
+			want: []Project{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			writeGoFiles(t, dir, tt.files)
+
+			projects, err := Detect(t.Context(), filepath.Join(dir, tt.root), WithGo())
+			require.NoError(t, err)
+
+			for i := range tt.want {
+				tt.want[i].Path = filepath.Join(dir, tt.want[i].Path)
+				if tt.want[i].RootPath != "" {
+					tt.want[i].RootPath = filepath.Join(dir, tt.want[i].RootPath)
+				}
+			}
+
+			require.Equal(t, tt.want, projects)
+		})
+	}
+}
+
+func TestDetectDirectoryGoCommand(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	writeGoFiles(t, dir, map[string]string{
+		"go.mod":             "module example.com/cmds\n\ngo 1.24\n\nrequire github.com/labstack/echo/v4 v4.12.0\n",
+		"cmd/server/main.go": "package main\n\nfunc main() {}\n",
+		"tools/gen/main.go":  "package main\n\nfunc main() {}\n",
+	})
+
+	project, err := DetectDirectory(t.Context(), filepath.Join(dir, "cmd", "server"))
+	require.NoError(t, err)
+	require.NotNil(t, project)
+	require.Equal(t, Go, project.Language)
+	require.Equal(t, dir, project.RootPath)
+	require.Equal(t, []Dependency{GoEcho}, project.Dependencies)
+
+	// main packages outside of cmd are not services
+	project, err = DetectDirectory(t.Context(), filepath.Join(dir, "tools", "gen"))
+	require.NoError(t, err)
+	require.Nil(t, project)
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -147,13 +146,9 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 	defer file.Close()
 
-	decoder := yaml.NewDecoder(file)
-	decoder.SetScanBlockScalarAsLiteral(true)
-
-	var doc yaml.Node
-	err = decoder.Decode(&doc)
+	doc, err := decodeProjectFile(file)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode: %w", err)
+		return nil, err
 	}
 
 	if serviceToAdd != nil {
@@ -162,7 +157,7 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			panic(fmt.Sprintf("encoding yaml node: %v", err))
 		}
 
-		err = yamlnode.Set(&doc, fmt.Sprintf("services?.%s", serviceToAdd.Name), serviceNode)
+		err = yamlnode.Set(doc, fmt.Sprintf("services?.%s", serviceToAdd.Name), serviceNode)
 		if err != nil {
 			return nil, fmt.Errorf("adding service: %w", err)
 		}
@@ -189,7 +184,7 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 			panic(fmt.Sprintf("encoding resource yaml node: %v", err))
 		}
 
-		err = yamlnode.Set(&doc, fmt.Sprintf("resources?.%s", resource.Name), resourceNode)
+		err = yamlnode.Set(doc, fmt.Sprintf("resources?.%s", resource.Name), resourceNode)
 		if err != nil {
 			return nil, fmt.Errorf("setting resource: %w", err)
 		}
@@ -199,7 +194,7 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		if slices.Contains(prjConfig.Resources[svc].Uses, resourceToAdd.Name) {
 			continue
 		}
-		err = yamlnode.Append(&doc, fmt.Sprintf("resources.%s.uses[]?", svc), &yaml.Node{
+		err = yamlnode.Append(doc, fmt.Sprintf("resources.%s.uses[]?", svc), &yaml.Node{
 			Kind:  yaml.ScalarNode,
 			Value: resourceToAdd.Name,
 		})
@@ -208,7 +203,7 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 		}
 	}
 
-	new, err := yaml.Marshal(&doc)
+	new, err := yaml.Marshal(doc)
 	if err != nil {
 		return nil, fmt.Errorf("marshalling yaml: %w", err)
 	}
@@ -242,22 +237,9 @@ func (a *AddAction) Run(ctx context.Context) (*actions.ActionResult, error) {
 	}
 
 	// Write modified YAML back to file
-	err = file.Truncate(0)
-	if err != nil {
-		return nil, fmt.Errorf("truncating file: %w", err)
-	}
-	_, err = file.Seek(0, io.SeekStart)
+	err = encodeProjectFile(file, doc)
 	if err != nil {
-		return nil, fmt.Errorf("seeking to start of file: %w", err)
-	}
-
-	encoder := yaml.NewEncoder(file)
-	encoder.SetIndent(2)
-	// preserve multi-line blocks style
-	encoder.SetAssumeBlockAsLiteral(true)
-	err = encoder.Encode(&doc)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode: %w", err)
+		return nil, err
 	}
 
 	err = file.Close()
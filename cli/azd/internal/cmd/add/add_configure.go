@@ -18,10 +18,11 @@ import (
 
 // DbMap is a map of supported database dependencies.
 var DbMap = map[appdetect.DatabaseDep]project.ResourceType{
-	appdetect.DbMongo:    project.ResourceTypeDbMongo,
-	appdetect.DbPostgres: project.ResourceTypeDbPostgres,
-	appdetect.DbMySql:    project.ResourceTypeDbMySql,
-	appdetect.DbRedis:    project.ResourceTypeDbRedis,
+	appdetect.DbMongo:     project.ResourceTypeDbMongo,
+	appdetect.DbPostgres:  project.ResourceTypeDbPostgres,
+	appdetect.DbMySql:     project.ResourceTypeDbMySql,
+	appdetect.DbRedis:     project.ResourceTypeDbRedis,
+	appdetect.DbSqlServer: project.ResourceTypeDbSqlServer,
 }
 
 // PromptOptions contains common options for prompting.
@@ -74,13 +75,15 @@ func Configure(
 
 	switch r.Type {
 	case project.ResourceTypeHostAppService,
-		project.ResourceTypeHostContainerApp:
+		project.ResourceTypeHostContainerApp,
+		project.ResourceTypeHostFunctionApp:
 		return fillUses(ctx, r, console, p)
 	case project.ResourceTypeOpenAiModel:
 		return fillOpenAiModelName(ctx, r, console, p)
 	case project.ResourceTypeDbPostgres,
 		project.ResourceTypeDbMySql,
-		project.ResourceTypeDbMongo:
+		project.ResourceTypeDbMongo,
+		project.ResourceTypeDbSqlServer:
 		return fillDatabaseName(ctx, r, console, p)
 	case project.ResourceTypeDbCosmos:
 		r, err := fillDatabaseName(ctx, r, console, p)
@@ -93,6 +96,27 @@ func Configure(
 		return fillEventHubs(ctx, r, console, p)
 	case project.ResourceTypeMessagingServiceBus:
 		return fillServiceBus(ctx, r, console, p)
+	case project.ResourceTypeMessagingEventGrid:
+		if _, exists := p.PrjConfig.Resources["eventgrid"]; exists {
+			return nil, fmt.Errorf("only one Event Grid resource is allowed at this time")
+		}
+
+		r.Name = "eventgrid"
+		return r, nil
+	case project.ResourceTypeMessagingSignalR:
+		if _, exists := p.PrjConfig.Resources["signalr"]; exists {
+			return nil, fmt.Errorf("only one SignalR resource is allowed at this time")
+		}
+
+		r.Name = "signalr"
+		return r, nil
+	case project.ResourceTypeMessagingWebPubSub:
+		if _, exists := p.PrjConfig.Resources["webpubsub"]; exists {
+			return nil, fmt.Errorf("only one Web PubSub resource is allowed at this time")
+		}
+
+		r.Name = "webpubsub"
+		return r, nil
 	case project.ResourceTypeDbRedis:
 		if _, exists := p.PrjConfig.Resources["redis"]; exists {
 			return nil, fmt.Errorf("only one Redis resource is allowed at this time")
@@ -121,6 +145,13 @@ func Configure(
 
 		r.Name = "vault"
 		return r, nil
+	case project.ResourceTypeAppConfig:
+		if _, exists := p.PrjConfig.Resources["appconfig"]; exists {
+			return nil, fmt.Errorf("only one App Configuration resource is allowed at this time")
+		}
+
+		r.Name = "appconfig"
+		return r, nil
 	default:
 		return r, nil
 	}
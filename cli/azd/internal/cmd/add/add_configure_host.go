@@ -31,11 +31,13 @@ var LanguageMap = map[appdetect.Language]project.ServiceLanguageKind{
 	appdetect.JavaScript: project.ServiceLanguageJavaScript,
 	appdetect.TypeScript: project.ServiceLanguageTypeScript,
 	appdetect.Python:     project.ServiceLanguagePython,
+	appdetect.Go:         project.ServiceLanguageGo,
 }
 
 var HostMap = map[project.ResourceType]project.ServiceTargetKind{
 	project.ResourceTypeHostAppService:   project.AppServiceTarget,
 	project.ResourceTypeHostContainerApp: project.ContainerAppTarget,
+	project.ResourceTypeHostFunctionApp:  project.AzureFunctionTarget,
 }
 
 // TODO: Dynamic support for versions using /providers/Microsoft.Web/webAppStacks API
@@ -54,6 +56,34 @@ var ServiceLanguageMap = map[project.ServiceLanguageKind]project.AppServiceRunti
 	},
 }
 
+// FunctionAppLanguageMap is a map of the languages supported on the Flex Consumption plan.
+var FunctionAppLanguageMap = map[project.ServiceLanguageKind]project.FunctionAppRuntime{
+	project.ServiceLanguagePython: {
+		Stack:   project.FunctionAppRuntimeStackPython,
+		Version: "3.12",
+	},
+	project.ServiceLanguageJavaScript: {
+		Stack:   project.FunctionAppRuntimeStackNode,
+		Version: "20",
+	},
+	project.ServiceLanguageTypeScript: {
+		Stack:   project.FunctionAppRuntimeStackNode,
+		Version: "20",
+	},
+	project.ServiceLanguageDotNet: {
+		Stack:   project.FunctionAppRuntimeStackDotNetIsolated,
+		Version: "8.0",
+	},
+	project.ServiceLanguageJava: {
+		Stack:   project.FunctionAppRuntimeStackJava,
+		Version: "17",
+	},
+	project.ServiceLanguageGo: {
+		Stack:   project.FunctionAppRuntimeStackCustom,
+		Version: "1.0",
+	},
+}
+
 func (a *AddAction) configureHost(
 	console input.Console,
 	ctx context.Context,
@@ -233,6 +263,17 @@ func (a *AddAction) projectAsService(
 				prj.Docker = docker
 			}
 		}
+	} else if kind == project.AzureFunctionTarget {
+		if prj.Docker != nil {
+			return nil, fmt.Errorf(
+				"dockerfile detected. Function Apps with custom containers are currently unsupported with `azd add`. " +
+					"Please use Container Apps instead")
+		}
+
+		if prj.HasWebUIFramework() {
+			return nil, fmt.Errorf(
+				"web UI framework detected. Function Apps cannot host static site applications")
+		}
 	} else if kind == project.AppServiceTarget {
 		if prj.Docker != nil {
 			return nil, fmt.Errorf(
@@ -268,6 +309,25 @@ func addServiceAsResource(
 	resSpec := project.ResourceConfig{
 		Name: svc.Name,
 	}
+	if svc.Host == project.AzureFunctionTarget {
+		// function apps are triggered by the Functions host, and do not listen on a port
+		resSpec.Type = project.ResourceTypeHostFunctionApp
+		runtime, ok := FunctionAppLanguageMap[svc.Language]
+		if !ok {
+			return nil, fmt.Errorf("unsupported language: %s", svc.Language)
+		}
+
+		console.Message(ctx,
+			fmt.Sprintf("\nazd will use %s to host this project on %s.",
+				output.WithHighLightFormat(string(runtime.Stack)+" "+runtime.Version),
+				output.WithHighLightFormat("Azure Functions Flex Consumption")))
+
+		resSpec.Props = project.FunctionAppProps{
+			Runtime: runtime,
+		}
+		return &resSpec, nil
+	}
+
 	if svc.Host != project.ContainerAppTarget && svc.Host != project.AppServiceTarget {
 		return nil, fmt.Errorf("unsupported service target: %s", svc.Host)
 	}
@@ -279,7 +339,8 @@ func addServiceAsResource(
 		if _, err := os.Stat(filepath.Join(svc.RelativePath, "Dockerfile")); errors.Is(err, os.ErrNotExist) {
 			// default builder always specifies port 80
 			port = 80
-			if svc.Language == project.ServiceLanguageJava || svc.Language.IsDotNet() {
+			if svc.Language == project.ServiceLanguageJava || svc.Language.IsDotNet() ||
+				svc.Language == project.ServiceLanguageGo {
 				port = 8080
 			}
 		}
@@ -413,7 +474,7 @@ func PromptPort(
 	name string,
 	svc appdetect.Project) (int, error) {
 	if svc.Docker == nil || svc.Docker.Path == "" { // using default builder from azd
-		if svc.Language == appdetect.Java || svc.Language == appdetect.DotNet {
+		if svc.Language == appdetect.Java || svc.Language == appdetect.DotNet || svc.Language == appdetect.Go {
 			return 8080, nil
 		}
 		return 80, nil
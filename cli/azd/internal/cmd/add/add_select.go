@@ -41,6 +41,7 @@ func (a *AddAction) selectMenu() []Menu {
 		{Namespace: "messaging", Label: "Messaging", SelectResource: selectMessaging},
 		{Namespace: "storage", Label: "Storage account", SelectResource: selectStorage},
 		{Namespace: "keyvault", Label: "Key Vault", SelectResource: selectKeyVault},
+		{Namespace: "appconfig", Label: "App Configuration", SelectResource: selectAppConfig},
 		{Namespace: "existing", Label: "~Existing resource", SelectResource: a.selectExistingResource},
 	}
 }
@@ -178,6 +179,12 @@ func selectKeyVault(console input.Console, ctx context.Context, p PromptOptions)
 	return r, nil
 }
 
+func selectAppConfig(console input.Console, ctx context.Context, p PromptOptions) (*project.ResourceConfig, error) {
+	r := &project.ResourceConfig{}
+	r.Type = project.ResourceTypeAppConfig
+	return r, nil
+}
+
 func (a *AddAction) selectExistingResource(
 	console input.Console,
 	ctx context.Context,
@@ -679,6 +679,7 @@ func TestPromptPort_NoDocker(t *testing.T) {
 		{"python returns 80", appdetect.Python, 80},
 		{"java returns 8080", appdetect.Java, 8080},
 		{"dotnet returns 8080", appdetect.DotNet, 8080},
+		{"go returns 8080", appdetect.Go, 8080},
 		{"javascript returns 80", appdetect.JavaScript, 80},
 		{"typescript returns 80", appdetect.TypeScript, 80},
 	}
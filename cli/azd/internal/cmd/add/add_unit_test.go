@@ -964,10 +964,11 @@ func TestMetadata(t *testing.T) {
 func TestDbMap(t *testing.T) {
 	t.Parallel()
 	expected := map[appdetect.DatabaseDep]project.ResourceType{
-		appdetect.DbMongo:    project.ResourceTypeDbMongo,
-		appdetect.DbPostgres: project.ResourceTypeDbPostgres,
-		appdetect.DbMySql:    project.ResourceTypeDbMySql,
-		appdetect.DbRedis:    project.ResourceTypeDbRedis,
+		appdetect.DbMongo:     project.ResourceTypeDbMongo,
+		appdetect.DbPostgres:  project.ResourceTypeDbPostgres,
+		appdetect.DbMySql:     project.ResourceTypeDbMySql,
+		appdetect.DbRedis:     project.ResourceTypeDbRedis,
+		appdetect.DbSqlServer: project.ResourceTypeDbSqlServer,
 	}
 	assert.Equal(t, expected, DbMap)
 }
@@ -1003,7 +1004,12 @@ func TestLanguageMap(t *testing.T) {
 		project.ServiceLanguageTypeScript,
 		LanguageMap[appdetect.TypeScript],
 	)
-	assert.Len(t, LanguageMap, 5)
+	assert.Equal(
+		t,
+		project.ServiceLanguageGo,
+		LanguageMap[appdetect.Go],
+	)
+	assert.Len(t, LanguageMap, 6)
 }
 
 // ---------------------------------------------------------------------------
@@ -1022,7 +1028,12 @@ func TestHostMap(t *testing.T) {
 		project.ContainerAppTarget,
 		HostMap[project.ResourceTypeHostContainerApp],
 	)
-	assert.Len(t, HostMap, 2)
+	assert.Equal(
+		t,
+		project.AzureFunctionTarget,
+		HostMap[project.ResourceTypeHostFunctionApp],
+	)
+	assert.Len(t, HostMap, 3)
 }
 
 // ---------------------------------------------------------------------------
@@ -8,7 +8,6 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/braydonk/yaml"
 	"github.com/fatih/color"
 	dmp "github.com/sergi/go-diff/diffmatchpatch"
@@ -17,8 +16,18 @@ import (
 // DiffBlocks returns a textual diff of new - old.
 //
 // It compares the values in old and new, and generates a textual diff for each value difference between old and new.
-// It doesn't currently support deletions of new from old.
-func DiffBlocks(old map[string]*project.ResourceConfig, new map[string]*project.ResourceConfig) (string, error) {
+// Values that are present in old but not in new are displayed as deletions.
+func DiffBlocks[T any](old map[string]T, new map[string]T) (string, error) {
+	return diffBlocks(old, new, false)
+}
+
+// diffLineBlocks is like DiffBlocks, but displays the changed lines of the values whole rather than the changed
+// characters. It suits edits that remove or rename entries, which character diffs display as fragments of words.
+func diffLineBlocks[T any](old map[string]T, new map[string]T) (string, error) {
+	return diffBlocks(old, new, true)
+}
+
+func diffBlocks[T any](old map[string]T, new map[string]T, byLine bool) (string, error) {
 	diffObj := dmp.New()
 
 	// dynamic programming: store marshaled entries for comparison
@@ -61,7 +70,13 @@ func DiffBlocks(old map[string]*project.ResourceConfig, new map[string]*project.
 			oldMarshaled[key] = oldContent
 		}
 
-		diffs := diffObj.DiffMain(oldContent, newContent, false)
+		var diffs []dmp.Diff
+		if byLine {
+			oldChars, newChars, lines := diffObj.DiffLinesToChars(oldContent, newContent)
+			diffs = diffObj.DiffCharsToLines(diffObj.DiffMain(oldChars, newChars, false), lines)
+		} else {
+			diffs = diffObj.DiffMain(oldContent, newContent, false)
+		}
 		if diffNotEq(diffs) {
 			allDiffs = append(allDiffs, diffBlock{
 				Header: diffLine{Type: dmp.DiffEqual, Text: key + ":"},
@@ -71,6 +86,22 @@ func DiffBlocks(old map[string]*project.ResourceConfig, new map[string]*project.
 		}
 	}
 
+	for key, oldVal := range old {
+		if _, ok := new[key]; ok {
+			continue
+		}
+
+		contents, err := yaml.Marshal(oldVal)
+		if err != nil {
+			return "", fmt.Errorf("marshaling old %v: %w", key, err)
+		}
+		allDiffs = append(allDiffs, diffBlock{
+			Header: diffLine{Type: dmp.DiffDelete, Text: key + ":"},
+			Lines:  lineDiffsFromStr(dmp.DiffDelete, string(contents)),
+			Indent: 4,
+		})
+	}
+
 	slices.SortFunc(allDiffs, func(a, b diffBlock) int {
 		return strings.Compare(a.Header.Text, b.Header.Text)
 	})
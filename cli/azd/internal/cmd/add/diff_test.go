@@ -6,6 +6,8 @@ package add
 import (
 	"testing"
 
+	"github.com/fatih/color"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -27,3 +29,53 @@ func TestFormatLine_AllOps(t *testing.T) {
 	require.NoError(t, err)
 	assert.NotEmpty(t, s)
 }
+
+func TestDiffBlocks_Deleted(t *testing.T) {
+	t.Parallel()
+	oldM := map[string]*project.ResourceConfig{
+		"keep":    {Name: "keep", Type: project.ResourceTypeDbRedis},
+		"removed": {Name: "removed", Type: project.ResourceTypeKeyVault},
+	}
+	newM := map[string]*project.ResourceConfig{
+		"keep": {Name: "keep", Type: project.ResourceTypeDbRedis},
+	}
+	s, err := DiffBlocks(oldM, newM)
+	require.NoError(t, err)
+	assert.Contains(t, s, "-  removed:")
+	assert.Contains(t, s, "-      type: keyvault")
+	assert.NotContains(t, s, "keep:")
+}
+
+// TestDiffBlocks_Added pins the preview of 'azd add', which displays the changed characters of existing values.
+func TestDiffBlocks_Added(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	t.Cleanup(func() { color.NoColor = noColor })
+
+	oldM := map[string]*project.ResourceConfig{
+		"web": {Name: "web", Type: project.ResourceTypeHostContainerApp, Uses: []string{"db"}},
+	}
+	newM := map[string]*project.ResourceConfig{
+		"web":   {Name: "web", Type: project.ResourceTypeHostContainerApp, Uses: []string{"db", "cache"}},
+		"cache": {Name: "cache", Type: project.ResourceTypeDbRedis},
+	}
+	s, err := DiffBlocks(oldM, newM)
+	require.NoError(t, err)
+	assert.Equal(t, `+  cache:
++      type: db.redis
+
+   web:
+       type: host.containerapp
+       uses:
+           - db
++          - cache
+`, s)
+
+	// the previews of 'azd remove' and 'azd rename' display changed lines whole instead
+	newM = map[string]*project.ResourceConfig{
+		"web": {Name: "web", Type: project.ResourceTypeHostContainerApp, Uses: []string{"database"}},
+	}
+	s, err = diffLineBlocks(oldM, newM)
+	require.NoError(t, err)
+	assert.Contains(t, s, "-          - db\n+          - database")
+}
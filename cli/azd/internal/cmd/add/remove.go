@@ -0,0 +1,372 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package add
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
+	"github.com/braydonk/yaml"
+	"github.com/spf13/cobra"
+)
+
+func NewRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove <name>",
+		Short: "Remove a component from your project.",
+		Args:  cobra.ExactArgs(1),
+	}
+}
+
+type RemoveAction struct {
+	azd        workflow.AzdCommandRunner
+	azdCtx     *azdcontext.AzdContext
+	lazyEnv    *lazy.Lazy[*environment.Environment]
+	envManager environment.Manager
+	console    input.Console
+	args       []string
+}
+
+func (a *RemoveAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name := a.args[0]
+
+	prjConfig, err := project.Load(ctx, a.azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRemove(prjConfig, name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(a.azdCtx.ProjectPath(), os.O_RDWR, osutil.PermissionFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+	defer file.Close()
+
+	doc, err := decodeProjectFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = removeFromProject(doc, prjConfig, name)
+	if err != nil {
+		return nil, err
+	}
+
+	new, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling yaml: %w", err)
+	}
+
+	newCfg, err := project.Parse(ctx, string(new))
+	if err != nil {
+		return nil, fmt.Errorf("re-parsing yaml: %w", err)
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("\nPreviewing changes to %s:\n", output.WithHighLightFormat("azure.yaml")))
+	diffString, diffErr := projectDiff(prjConfig, newCfg)
+	if diffErr != nil {
+		a.console.Message(ctx, "Preview unavailable. Pass --debug for more details.\n")
+		log.Printf("remove-diff: preview failed: %v", diffErr)
+	} else {
+		a.console.Message(ctx, diffString)
+	}
+
+	confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Accept changes to azure.yaml?",
+		DefaultValue: true,
+	})
+	if err != nil || !confirm {
+		return nil, err
+	}
+
+	// Write modified YAML back to file
+	err = encodeProjectFile(file, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("closing file: %w", err)
+	}
+
+	// Existing resources are referenced by the resource ID saved to the environment when they were added
+	if res, ok := prjConfig.Resources[name]; ok && res.Existing {
+		err = a.removeResourceId(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.console.MessageUxItem(ctx, &ux.ActionResult{
+		SuccessMessage: "azure.yaml updated.",
+	})
+
+	followUpMessage, err := infraGenFollowUp(ctx, a.console, a.azd, prjConfig,
+		"Resources that were already provisioned in Azure are not deleted by this command.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			FollowUp: followUpMessage,
+		},
+	}, nil
+}
+
+// infraGenFollowUp offers to re-generate the infrastructure of the project when it has infrastructure files, and
+// returns the follow up message of a command that changed the project, ending with note.
+func infraGenFollowUp(
+	ctx context.Context,
+	console input.Console,
+	azd workflow.AzdCommandRunner,
+	prjConfig *project.ProjectConfig,
+	note string,
+) (string, error) {
+	infraOptions, err := prjConfig.Infra.GetWithDefaults()
+	if err != nil {
+		return "", err
+	}
+
+	infraRoot := infraOptions.Path
+	if !filepath.IsAbs(infraRoot) {
+		infraRoot = filepath.Join(prjConfig.Path, infraRoot)
+	}
+
+	provisionFollowUp := fmt.Sprintf("'%s' to provision these changes anytime later.\n%s",
+		output.WithHighLightFormat("azd provision"), note)
+	followUpMessage := "Run " + provisionFollowUp
+
+	if hasInfra, err := pathHasInfraModule(infraRoot, infraOptions.Module); err == nil && hasInfra {
+		console.Message(ctx, "")
+		regenerate, err := console.Confirm(ctx, input.ConsoleOptions{
+			Message: fmt.Sprintf("Do you want to re-generate the infrastructure in %s?", infraOptions.Path),
+			Help: "The infrastructure files generated by 'azd infra gen' are overwritten, " +
+				"including any changes made to them.",
+			DefaultValue: false,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		if regenerate {
+			err = azd.ExecuteContext(ctx, []string{"infra", "gen", "--force"})
+			if err != nil {
+				return "", err
+			}
+		} else {
+			followUpMessage = fmt.Sprintf(
+				"Run '%s' to re-generate the infrastructure, then run %s",
+				output.WithHighLightFormat("azd infra gen"),
+				provisionFollowUp)
+		}
+	}
+
+	return followUpMessage, nil
+}
+
+// removeResourceId removes the resource ID of the resource from the environment, if an environment is available.
+func (a *RemoveAction) removeResourceId(ctx context.Context, name string) error {
+	env, err := a.lazyEnv.GetValue()
+	if err != nil {
+		log.Printf("remove: skipping environment update: %v", err)
+		return nil
+	}
+
+	key := infra.ResourceIdName(name)
+	if _, has := env.LookupEnv(key); !has {
+		return nil
+	}
+
+	env.DotenvDelete(key)
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil
+}
+
+// validateRemove checks that name is a resource or service of the project that no remaining resource requires.
+func validateRemove(prjConfig *project.ProjectConfig, name string) error {
+	err := validateComponent(prjConfig, name, "remove")
+	if err != nil {
+		return err
+	}
+
+	for _, resName := range slices.Sorted(maps.Keys(prjConfig.Resources)) {
+		if resName == name {
+			continue
+		}
+
+		for _, dep := range project.DependentResourcesOf(prjConfig.Resources[resName]) {
+			if dep.Name == name {
+				return &internal.ErrorWithSuggestion{
+					Err: fmt.Errorf("%w: resource '%s' is required by '%s'",
+						internal.ErrValidationFailed, name, resName),
+					Suggestion: fmt.Sprintf("Run '%s' first.", output.WithHighLightFormat("azd remove %s", resName)),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateComponent checks that name is a resource or service of the project, where verb describes what the command
+// does with it in errors.
+func validateComponent(prjConfig *project.ProjectConfig, name string, verb string) error {
+	_, isResource := prjConfig.Resources[name]
+	_, isService := prjConfig.Services[name]
+	if isResource || isService {
+		return nil
+	}
+
+	names := slices.Sorted(maps.Keys(prjConfig.Resources))
+	for svc := range prjConfig.Services {
+		if !slices.Contains(names, svc) {
+			names = append(names, svc)
+		}
+	}
+	slices.Sort(names)
+
+	suggestion := fmt.Sprintf("There are no resources or services in azure.yaml to %s.", verb)
+	if len(names) > 0 {
+		suggestion = fmt.Sprintf("Resources and services in azure.yaml: %s", strings.Join(names, ", "))
+	}
+
+	return &internal.ErrorWithSuggestion{
+		Err:        fmt.Errorf("%w: '%s' is not a resource or service in azure.yaml", internal.ErrInvalidArgValue, name),
+		Suggestion: suggestion,
+	}
+}
+
+// removeFromProject removes the resource and the service with the given name from the project document,
+// along with the references to them in the uses of the remaining resources and services.
+//
+// Sections of the project that are left empty are removed.
+func removeFromProject(doc *yaml.Node, prjConfig *project.ProjectConfig, name string) error {
+	if _, ok := prjConfig.Services[name]; ok {
+		err := yamlnode.Delete(doc, fmt.Sprintf("services.%s", name))
+		if err != nil {
+			return fmt.Errorf("removing service: %w", err)
+		}
+	}
+
+	if _, ok := prjConfig.Resources[name]; ok {
+		err := yamlnode.Delete(doc, fmt.Sprintf("resources.%s", name))
+		if err != nil {
+			return fmt.Errorf("removing resource: %w", err)
+		}
+	}
+
+	for _, resName := range slices.Sorted(maps.Keys(prjConfig.Resources)) {
+		if resName == name {
+			continue
+		}
+
+		err := removeUse(doc, fmt.Sprintf("resources.%s.uses", resName), prjConfig.Resources[resName].Uses, name)
+		if err != nil {
+			return fmt.Errorf("removing uses of resource %s: %w", resName, err)
+		}
+	}
+
+	for _, svcName := range slices.Sorted(maps.Keys(prjConfig.Services)) {
+		if svcName == name {
+			continue
+		}
+
+		err := removeUse(doc, fmt.Sprintf("services.%s.uses", svcName), prjConfig.Services[svcName].Uses, name)
+		if err != nil {
+			return fmt.Errorf("removing uses of service %s: %w", svcName, err)
+		}
+	}
+
+	for _, section := range []string{"services", "resources"} {
+		node, err := yamlnode.Find(doc, section)
+		if err != nil || node.Kind != yaml.MappingNode || len(node.Content) > 0 {
+			continue
+		}
+
+		err = yamlnode.Delete(doc, section)
+		if err != nil {
+			return fmt.Errorf("removing %s: %w", section, err)
+		}
+	}
+
+	return nil
+}
+
+// projectDiff returns a textual diff of the services and resources of new - old.
+func projectDiff(old *project.ProjectConfig, new *project.ProjectConfig) (string, error) {
+	servicesDiff, err := diffLineBlocks(old.Services, new.Services)
+	if err != nil {
+		return "", err
+	}
+
+	resourcesDiff, err := diffLineBlocks(old.Resources, new.Resources)
+	if err != nil {
+		return "", err
+	}
+
+	if servicesDiff == "" || resourcesDiff == "" {
+		return servicesDiff + resourcesDiff, nil
+	}
+
+	return servicesDiff + "\n" + resourcesDiff, nil
+}
+
+// removeUse removes name from the uses sequence at path. The sequence is removed when it would be left empty.
+func removeUse(doc *yaml.Node, path string, uses []string, name string) error {
+	idx := slices.Index(uses, name)
+	if idx < 0 {
+		return nil
+	}
+
+	if len(uses) == 1 {
+		return yamlnode.Delete(doc, path)
+	}
+
+	return yamlnode.Delete(doc, fmt.Sprintf("%s[%d]", path, idx))
+}
+
+func NewRemoveAction(
+	azdCtx *azdcontext.AzdContext,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	envManager environment.Manager,
+	azd workflow.AzdCommandRunner,
+	console input.Console,
+	args []string) actions.Action {
+	return &RemoveAction{
+		azd:        azd,
+		azdCtx:     azdCtx,
+		lazyEnv:    lazyEnv,
+		envManager: envManager,
+		console:    console,
+		args:       args,
+	}
+}
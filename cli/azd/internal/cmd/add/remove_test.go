@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package add
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const removeProjectYaml = `name: app
+services:
+  api:
+    project: ./src/api
+    host: containerapp
+    language: python
+  web:
+    project: ./src/web
+    host: containerapp
+    language: js
+resources:
+  api:
+    type: host.containerapp
+    port: 8080
+    # the api stores data in both databases
+    uses:
+      - db
+      - cache
+  web:
+    type: host.containerapp
+    port: 80
+    uses:
+      - api
+      - db
+  db:
+    type: db.postgres
+  cache:
+    type: db.redis
+  vault:
+    type: keyvault
+`
+
+func TestRemoveFromProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		remove        string
+		wantServices  []string
+		wantResources []string
+		wantUses      map[string][]string
+	}{
+		{
+			name:          "Resource",
+			remove:        "cache",
+			wantServices:  []string{"api", "web"},
+			wantResources: []string{"api", "db", "vault", "web"},
+			wantUses: map[string][]string{
+				"api": {"db"},
+				"web": {"api", "db"},
+			},
+		},
+		{
+			name:          "ServiceAndHost",
+			remove:        "api",
+			wantServices:  []string{"web"},
+			wantResources: []string{"cache", "db", "vault", "web"},
+			wantUses: map[string][]string{
+				"web": {"db"},
+			},
+		},
+		{
+			name:          "UsedByAll",
+			remove:        "db",
+			wantServices:  []string{"api", "web"},
+			wantResources: []string{"api", "cache", "vault", "web"},
+			wantUses: map[string][]string{
+				"api": {"cache"},
+				"web": {"api"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prjConfig, err := project.Parse(t.Context(), removeProjectYaml)
+			require.NoError(t, err)
+
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(removeProjectYaml), &doc))
+
+			err = removeFromProject(&doc, prjConfig, tt.remove)
+			require.NoError(t, err)
+
+			contents, err := yaml.Marshal(&doc)
+			require.NoError(t, err)
+
+			newCfg, err := project.Parse(t.Context(), string(contents))
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, tt.wantServices, keys(newCfg.Services))
+			assert.ElementsMatch(t, tt.wantResources, keys(newCfg.Resources))
+			for name, uses := range tt.wantUses {
+				assert.Equal(t, uses, newCfg.Resources[name].Uses, "uses of %s", name)
+			}
+
+			// comments of the remaining resources are preserved
+			if tt.remove != "api" {
+				assert.Contains(t, string(contents), "# the api stores data in both databases")
+			}
+		})
+	}
+}
+
+func TestRemoveFromProject_LastUseAndResource(t *testing.T) {
+	t.Parallel()
+	const prjYaml = `name: app
+resources:
+  web:
+    type: host.containerapp
+    port: 80
+    uses:
+      - search
+  search:
+    type: ai.search
+`
+	prjConfig, err := project.Parse(t.Context(), prjYaml)
+	require.NoError(t, err)
+
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(prjYaml), &doc))
+
+	require.NoError(t, removeFromProject(&doc, prjConfig, "search"))
+	contents, err := yaml.Marshal(&doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "uses")
+
+	newCfg, err := project.Parse(t.Context(), string(contents))
+	require.NoError(t, err)
+	require.NoError(t, removeFromProject(&doc, newCfg, "web"))
+	contents, err = yaml.Marshal(&doc)
+	require.NoError(t, err)
+	assert.Equal(t, "name: app\n", string(contents))
+}
+
+func TestValidateRemove(t *testing.T) {
+	t.Parallel()
+	prjConfig, err := project.Parse(t.Context(), removeProjectYaml)
+	require.NoError(t, err)
+
+	require.NoError(t, validateRemove(prjConfig, "cache"))
+	require.NoError(t, validateRemove(prjConfig, "api"))
+
+	err = validateRemove(prjConfig, "missing")
+	require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	errWithSuggestion, ok := err.(*internal.ErrorWithSuggestion)
+	require.True(t, ok)
+	assert.Equal(t, "Resources and services in azure.yaml: api, cache, db, vault, web", errWithSuggestion.Suggestion)
+
+	// the vault stores the secrets of the databases
+	err = validateRemove(prjConfig, "vault")
+	require.ErrorIs(t, err, internal.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "resource 'vault' is required by 'cache'")
+}
+
+func TestProjectDiff(t *testing.T) {
+	t.Parallel()
+	prjConfig, err := project.Parse(t.Context(), removeProjectYaml)
+	require.NoError(t, err)
+
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(removeProjectYaml), &doc))
+	require.NoError(t, removeFromProject(&doc, prjConfig, "api"))
+
+	contents, err := yaml.Marshal(&doc)
+	require.NoError(t, err)
+	newCfg, err := project.Parse(t.Context(), string(contents))
+	require.NoError(t, err)
+
+	diff, err := projectDiff(prjConfig, newCfg)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "-      project: ./src/api")
+	assert.Contains(t, diff, "-      type: host.containerapp")
+	assert.Contains(t, diff, "-          - api")
+}
+
+func keys[T any](m map[string]T) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+
+	return result
+}
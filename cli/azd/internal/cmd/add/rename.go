@@ -0,0 +1,269 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package add
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/cmd/actions"
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/internal/names"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/pkg/workflow"
+	"github.com/azure/azure-dev/cli/azd/pkg/yamlnode"
+	"github.com/braydonk/yaml"
+	"github.com/spf13/cobra"
+)
+
+func NewRenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <name> <new-name>",
+		Short: "Rename a component of your project.",
+		Args:  cobra.ExactArgs(2),
+	}
+}
+
+type RenameAction struct {
+	azd        workflow.AzdCommandRunner
+	azdCtx     *azdcontext.AzdContext
+	lazyEnv    *lazy.Lazy[*environment.Environment]
+	envManager environment.Manager
+	console    input.Console
+	args       []string
+}
+
+func (a *RenameAction) Run(ctx context.Context) (*actions.ActionResult, error) {
+	name, newName := a.args[0], a.args[1]
+
+	prjConfig, err := project.Load(ctx, a.azdCtx.ProjectPath())
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateRename(prjConfig, name, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(a.azdCtx.ProjectPath(), os.O_RDWR, osutil.PermissionFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading project file: %w", err)
+	}
+	defer file.Close()
+
+	doc, err := decodeProjectFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	err = renameInProject(doc, prjConfig, name, newName)
+	if err != nil {
+		return nil, err
+	}
+
+	new, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling yaml: %w", err)
+	}
+
+	newCfg, err := project.Parse(ctx, string(new))
+	if err != nil {
+		return nil, fmt.Errorf("re-parsing yaml: %w", err)
+	}
+
+	a.console.Message(ctx, fmt.Sprintf("\nPreviewing changes to %s:\n", output.WithHighLightFormat("azure.yaml")))
+	diffString, diffErr := projectDiff(prjConfig, newCfg)
+	if diffErr != nil {
+		a.console.Message(ctx, "Preview unavailable. Pass --debug for more details.\n")
+		log.Printf("rename-diff: preview failed: %v", diffErr)
+	} else {
+		a.console.Message(ctx, diffString)
+	}
+
+	confirm, err := a.console.Confirm(ctx, input.ConsoleOptions{
+		Message:      "Accept changes to azure.yaml?",
+		DefaultValue: true,
+	})
+	if err != nil || !confirm {
+		return nil, err
+	}
+
+	// Write modified YAML back to file
+	err = encodeProjectFile(file, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return nil, fmt.Errorf("closing file: %w", err)
+	}
+
+	// Existing resources are referenced by the resource ID saved to the environment when they were added
+	if res, ok := prjConfig.Resources[name]; ok && res.Existing {
+		err = a.renameResourceId(ctx, name, newName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	a.console.MessageUxItem(ctx, &ux.ActionResult{
+		SuccessMessage: "azure.yaml updated.",
+	})
+
+	followUpMessage, err := infraGenFollowUp(ctx, a.console, a.azd, prjConfig,
+		"Resources that were already provisioned in Azure are not renamed by this command, "+
+			"and may be provisioned again under the new name.")
+	if err != nil {
+		return nil, err
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			FollowUp: followUpMessage,
+		},
+	}, nil
+}
+
+// renameResourceId moves the resource ID of the resource in the environment to its new name, if an environment is
+// available.
+func (a *RenameAction) renameResourceId(ctx context.Context, name string, newName string) error {
+	env, err := a.lazyEnv.GetValue()
+	if err != nil {
+		log.Printf("rename: skipping environment update: %v", err)
+		return nil
+	}
+
+	key := infra.ResourceIdName(name)
+	resourceId, has := env.LookupEnv(key)
+	if !has {
+		return nil
+	}
+
+	env.DotenvDelete(key)
+	env.DotenvSet(infra.ResourceIdName(newName), resourceId)
+	if err := a.envManager.Save(ctx, env); err != nil {
+		return fmt.Errorf("saving environment: %w", err)
+	}
+
+	return nil
+}
+
+// validateRename checks that name is a resource or service of the project that no other resource requires by name,
+// and that newName is a valid name that is not used by another resource or service.
+func validateRename(prjConfig *project.ProjectConfig, name string, newName string) error {
+	err := validateComponent(prjConfig, name, "rename")
+	if err != nil {
+		return err
+	}
+
+	err = names.ValidateLabelName(newName)
+	if err != nil {
+		return fmt.Errorf("%w: %w", internal.ErrInvalidArgValue, err)
+	}
+
+	_, isResource := prjConfig.Resources[newName]
+	_, isService := prjConfig.Services[newName]
+	if isResource || isService {
+		return &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("%w: '%s' already exists in azure.yaml", internal.ErrInvalidArgValue, newName),
+			Suggestion: "Choose a name that is not used by another resource or service.",
+		}
+	}
+
+	for _, resName := range slices.Sorted(maps.Keys(prjConfig.Resources)) {
+		if resName == name {
+			continue
+		}
+
+		for _, dep := range project.DependentResourcesOf(prjConfig.Resources[resName]) {
+			if dep.Name == name {
+				return fmt.Errorf("%w: resource '%s' is required by '%s' under this name",
+					internal.ErrValidationFailed, name, resName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renameInProject renames the resource and the service with the given name in the project document, along with the
+// references to them in the uses of the other resources and services.
+func renameInProject(doc *yaml.Node, prjConfig *project.ProjectConfig, name string, newName string) error {
+	if _, ok := prjConfig.Services[name]; ok {
+		err := yamlnode.Rename(doc, fmt.Sprintf("services.%s", name), newName)
+		if err != nil {
+			return fmt.Errorf("renaming service: %w", err)
+		}
+	}
+
+	if _, ok := prjConfig.Resources[name]; ok {
+		err := yamlnode.Rename(doc, fmt.Sprintf("resources.%s", name), newName)
+		if err != nil {
+			return fmt.Errorf("renaming resource: %w", err)
+		}
+	}
+
+	for _, resName := range slices.Sorted(maps.Keys(prjConfig.Resources)) {
+		err := renameUse(doc, fmt.Sprintf("resources.%s.uses", resName), prjConfig.Resources[resName].Uses, name, newName)
+		if err != nil {
+			return fmt.Errorf("renaming uses of resource %s: %w", resName, err)
+		}
+	}
+
+	for _, svcName := range slices.Sorted(maps.Keys(prjConfig.Services)) {
+		err := renameUse(doc, fmt.Sprintf("services.%s.uses", svcName), prjConfig.Services[svcName].Uses, name, newName)
+		if err != nil {
+			return fmt.Errorf("renaming uses of service %s: %w", svcName, err)
+		}
+	}
+
+	return nil
+}
+
+// renameUse renames name to newName in the uses sequence at path.
+func renameUse(doc *yaml.Node, path string, uses []string, name string, newName string) error {
+	idx := slices.Index(uses, name)
+	if idx < 0 {
+		return nil
+	}
+
+	node, err := yamlnode.Find(doc, fmt.Sprintf("%s[%d]", path, idx))
+	if err != nil {
+		return err
+	}
+
+	node.Value = newName
+	return nil
+}
+
+func NewRenameAction(
+	azdCtx *azdcontext.AzdContext,
+	lazyEnv *lazy.Lazy[*environment.Environment],
+	envManager environment.Manager,
+	azd workflow.AzdCommandRunner,
+	console input.Console,
+	args []string) actions.Action {
+	return &RenameAction{
+		azd:        azd,
+		azdCtx:     azdCtx,
+		lazyEnv:    lazyEnv,
+		envManager: envManager,
+		console:    console,
+		args:       args,
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package add
+
+import (
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameInProject(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		rename        string
+		newName       string
+		wantServices  []string
+		wantResources []string
+		wantUses      map[string][]string
+	}{
+		{
+			name:          "Resource",
+			rename:        "cache",
+			newName:       "redis",
+			wantServices:  []string{"api", "web"},
+			wantResources: []string{"api", "db", "redis", "vault", "web"},
+			wantUses: map[string][]string{
+				"api": {"db", "redis"},
+				"web": {"api", "db"},
+			},
+		},
+		{
+			name:          "ServiceAndHost",
+			rename:        "api",
+			newName:       "backend",
+			wantServices:  []string{"backend", "web"},
+			wantResources: []string{"backend", "cache", "db", "vault", "web"},
+			wantUses: map[string][]string{
+				"backend": {"db", "cache"},
+				"web":     {"backend", "db"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			prjConfig, err := project.Parse(t.Context(), removeProjectYaml)
+			require.NoError(t, err)
+
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(removeProjectYaml), &doc))
+
+			err = renameInProject(&doc, prjConfig, tt.rename, tt.newName)
+			require.NoError(t, err)
+
+			contents, err := yaml.Marshal(&doc)
+			require.NoError(t, err)
+
+			newCfg, err := project.Parse(t.Context(), string(contents))
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, tt.wantServices, keys(newCfg.Services))
+			assert.ElementsMatch(t, tt.wantResources, keys(newCfg.Resources))
+			for name, uses := range tt.wantUses {
+				assert.Equal(t, uses, newCfg.Resources[name].Uses, "uses of %s", name)
+			}
+
+			// comments are preserved
+			assert.Contains(t, string(contents), "# the api stores data in both databases")
+		})
+	}
+}
+
+func TestValidateRename(t *testing.T) {
+	t.Parallel()
+	prjConfig, err := project.Parse(t.Context(), removeProjectYaml)
+	require.NoError(t, err)
+
+	require.NoError(t, validateRename(prjConfig, "cache", "redis"))
+	require.NoError(t, validateRename(prjConfig, "api", "backend"))
+
+	err = validateRename(prjConfig, "missing", "other")
+	require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+
+	err = validateRename(prjConfig, "cache", "db")
+	require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+	assert.Contains(t, err.Error(), "'db' already exists")
+
+	err = validateRename(prjConfig, "cache", "not a name")
+	require.ErrorIs(t, err, internal.ErrInvalidArgValue)
+
+	// the databases look up the vault by its name
+	err = validateRename(prjConfig, "vault", "secrets")
+	require.ErrorIs(t, err, internal.ErrValidationFailed)
+	assert.Contains(t, err.Error(), "resource 'vault' is required by 'cache'")
+}
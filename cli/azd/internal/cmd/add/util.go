@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -20,6 +21,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/braydonk/yaml"
 )
 
 func validateServiceName(name string, prj *project.ProjectConfig) error {
@@ -168,3 +170,41 @@ func getEnvDetails(
 	details.Subscription = subscriptionDisplay
 	return details, nil
 }
+
+// decodeProjectFile decodes the contents of the project file into a YAML node, which preserves the comments and
+// formatting of the file when edited.
+func decodeProjectFile(r io.Reader) (*yaml.Node, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.SetScanBlockScalarAsLiteral(true)
+
+	var doc yaml.Node
+	err := decoder.Decode(&doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode: %w", err)
+	}
+
+	return &doc, nil
+}
+
+// encodeProjectFile replaces the contents of the project file with the YAML node.
+func encodeProjectFile(file *os.File, doc *yaml.Node) error {
+	err := file.Truncate(0)
+	if err != nil {
+		return fmt.Errorf("truncating file: %w", err)
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("seeking to start of file: %w", err)
+	}
+
+	encoder := yaml.NewEncoder(file)
+	encoder.SetIndent(2)
+	// preserve multi-line blocks style
+	encoder.SetAssumeBlockAsLiteral(true)
+	err = encoder.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("failed to encode: %w", err)
+	}
+
+	return nil
+}
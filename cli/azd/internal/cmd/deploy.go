@@ -355,6 +355,7 @@ func (da *DeployAction) deployServicesGraph(
 	opts := exegraph.RunOptions{
 		MaxConcurrency: da.resolveDAGConcurrency(),
 		ErrorPolicy:    exegraph.FailFast,
+		Pools:          graphPools(stableServices, aspireBuildGateKey, da.projectConfig.Concurrency),
 		OnStepStart: func(stepName string) {
 			if svc, ok := strings.CutPrefix(stepName, "package-"); ok {
 				da.updateProgress(svc, phasePackaging, "")
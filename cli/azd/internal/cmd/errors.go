@@ -32,6 +32,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
@@ -314,6 +315,8 @@ func classifySentinel(err error) string {
 		return "internal.cannot_change_location"
 	case errors.Is(err, internal.ErrPreviewMultipleLayers):
 		return "internal.preview_multiple_layers"
+	case errors.Is(err, internal.ErrResumeWithCustomWorkflow):
+		return "internal.resume_with_custom_workflow"
 	case errors.Is(err, internal.ErrNoKeyNameProvided),
 		errors.Is(err, internal.ErrNoEnvValuesProvided),
 		errors.Is(err, internal.ErrInvalidFlagCombination):
@@ -329,6 +332,12 @@ func classifySentinel(err error) string {
 		return "internal.invalid_args"
 	case errors.Is(err, environment.ErrNotFound):
 		return "internal.env_not_found"
+	case errors.Is(err, environment.ErrRemoteConflict):
+		return "internal.env_remote_conflict"
+	case errors.Is(err, environment.ErrSchemaViolation):
+		return "internal.env_schema_violation"
+	case errors.Is(err, internal.ErrRevisionNotFound):
+		return "internal.env_revision_not_found"
 	case errors.Is(err, azdcontext.ErrNoProject):
 		return "internal.no_project"
 	case errors.Is(err, internal.ErrNoArgsProvided),
@@ -340,6 +349,8 @@ func classifySentinel(err error) string {
 		return "internal.extension_not_found"
 	case errors.Is(err, internal.ErrServiceNotFound):
 		return "internal.service_not_found"
+	case errors.Is(err, internal.ErrWorkflowNotFound):
+		return "internal.workflow_not_found"
 	case errors.Is(err, internal.ErrNoExtensionsAvailable):
 		return "internal.no_extensions_available"
 	case errors.Is(err, internal.ErrValidationFailed):
@@ -371,6 +382,14 @@ func classifySentinel(err error) string {
 		return "internal.cancel_not_supported"
 	case errors.Is(err, provisioning.ErrBindMountOperationDisabled):
 		return "internal.bind_mount_disabled"
+	case errors.Is(err, provisioning.ErrDriftNotSupported):
+		return "internal.drift_not_supported"
+	case errors.Is(err, provisioning.ErrUnknownProvider):
+		return "internal.unknown_provider"
+	case errors.Is(err, provisioning.ErrDriftBaselineChanged):
+		return "internal.drift_baseline_changed"
+	case errors.Is(err, provisioning.ErrDriftDetected):
+		return "internal.drift_detected"
 	case errors.Is(err, provisioning.ErrDeploymentInterruptedLeaveRunning):
 		return "user.canceled.leave_running"
 	case errors.Is(err, provisioning.ErrDeploymentCanceledByUser):
@@ -387,6 +406,12 @@ func classifySentinel(err error) string {
 		return "internal.remote_not_azdo"
 	case errors.Is(err, internal.ErrToolUpgradeFailed):
 		return "internal.tool_upgrade_failed"
+	case errors.Is(err, extensions.ErrSignatureVerificationFailed):
+		return "internal.extension_signature_invalid"
+	case errors.Is(err, extensions.ErrLockMismatch):
+		return "internal.extension_lock_mismatch"
+	case errors.Is(err, oci.ErrNotFound):
+		return "internal.oci_artifact_not_found"
 	default:
 		return ""
 	}
@@ -32,7 +32,9 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/extensions"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/pipeline"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/git"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mocktracing"
@@ -352,6 +354,54 @@ func Test_MapError(t *testing.T) {
 			wantErrReason:  "internal.bind_mount_disabled",
 			wantErrDetails: nil,
 		},
+		{
+			name:           "WithErrDriftNotSupported",
+			err:            fmt.Errorf("%w: 'pulumi'", provisioning.ErrDriftNotSupported),
+			wantErrReason:  "internal.drift_not_supported",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrUnknownProvider",
+			err:            fmt.Errorf("%w: 'bicpe'", provisioning.ErrUnknownProvider),
+			wantErrReason:  "internal.unknown_provider",
+			wantErrDetails: nil,
+		},
+		{
+			name:           "WithErrDriftBaselineChanged",
+			err:            provisioning.ErrDriftBaselineChanged,
+			wantErrReason:  "internal.drift_baseline_changed",
+			wantErrDetails: nil,
+		},
+		{
+			name: "WithErrDriftDetected",
+			err: &internal.ExitCodeError{
+				ExitCode: 2,
+				Err:      fmt.Errorf("%w: 1 resource(s) changed", provisioning.ErrDriftDetected),
+			},
+			wantErrReason:  "internal.drift_detected",
+			wantErrDetails: nil,
+		},
+		{
+			name: "WithErrSignatureVerificationFailed",
+			err: fmt.Errorf("failed to install extension: %w",
+				fmt.Errorf("%w: registry is not signed", extensions.ErrSignatureVerificationFailed)),
+			wantErrReason:  "internal.extension_signature_invalid",
+			wantErrDetails: nil,
+		},
+		{
+			name: "WithErrLockMismatch",
+			err: fmt.Errorf("failed to install extension: %w",
+				fmt.Errorf("%w: demo is not locked", extensions.ErrLockMismatch)),
+			wantErrReason:  "internal.extension_lock_mismatch",
+			wantErrDetails: nil,
+		},
+		{
+			name: "WithErrOciNotFound",
+			err: fmt.Errorf("failed to download artifact: %w",
+				fmt.Errorf("%w: contoso.azurecr.io/azd/extensions:1.0.0", oci.ErrNotFound)),
+			wantErrReason:  "internal.oci_artifact_not_found",
+			wantErrDetails: nil,
+		},
 		{
 			name:           "WithErrRemoteHostIsNotAzDo",
 			err:            fmt.Errorf("%w: https://dev.azure.com/org", pipeline.ErrRemoteHostIsNotAzDo),
@@ -510,6 +560,35 @@ func Test_MapError(t *testing.T) {
 				fields.ErrType.String("internal.env_not_found"),
 			},
 		},
+		{
+			name: "WithErrRemoteConflict",
+			err: fmt.Errorf(
+				"saving remote environment, %w",
+				fmt.Errorf("'dev': %w", environment.ErrRemoteConflict)),
+			wantErrReason: "internal.env_remote_conflict",
+		},
+		{
+			name: "WithErrSchemaViolation",
+			err: &internal.ErrorWithSuggestion{
+				Err:        &environment.SchemaError{Violations: []string{"API_PORT must be an integer"}},
+				Suggestion: "See the 'env' section of azure.yaml for the values this project expects.",
+			},
+			wantErrReason: "error.suggestion",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrType.String("internal.env_schema_violation"),
+			},
+		},
+		{
+			name: "WithErrRevisionNotFound",
+			err: &internal.ErrorWithSuggestion{
+				Err:        fmt.Errorf("revision 7 of environment 'dev': %w", internal.ErrRevisionNotFound),
+				Suggestion: "Run 'azd env history' to list the revisions of the environment.",
+			},
+			wantErrReason: "error.suggestion",
+			wantErrDetails: []attribute.KeyValue{
+				fields.ErrType.String("internal.env_revision_not_found"),
+			},
+		},
 		{
 			name: "WithErrInfraNotProvisioned",
 			err: &internal.ErrorWithSuggestion{
@@ -14,11 +14,13 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/account"
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/cost"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -138,6 +140,7 @@ type ProvisionAction struct {
 	portalUrlBase       string
 	defaultProvider     provisioning.DefaultProviderResolver
 	fileShareService    storage.FileShareService
+	priceSheetManager   *cost.PriceSheetManager
 	cloud               *cloud.Cloud
 
 	// Graph-shared state (lazily initialized via graphOnce). Used by the
@@ -169,6 +172,7 @@ func NewProvisionAction(
 	cloud *cloud.Cloud,
 	defaultProvider provisioning.DefaultProviderResolver,
 	fileShareService storage.FileShareService,
+	priceSheetManager *cost.PriceSheetManager,
 ) actions.Action {
 	return &ProvisionAction{
 		args:                args,
@@ -190,6 +194,7 @@ func NewProvisionAction(
 		portalUrlBase:       cloud.PortalUrlBase,
 		defaultProvider:     defaultProvider,
 		fileShareService:    fileShareService,
+		priceSheetManager:   priceSheetManager,
 		cloud:               cloud,
 	}
 }
@@ -308,8 +313,13 @@ func (p *ProvisionAction) Run(ctx context.Context) (*actions.ActionResult, error
 
 // deployResultToUx creates the ux element to display from a provision preview
 func deployResultToUx(previewResult *provisioning.DeployPreviewResult) ux.UxItem {
+	return PreviewChangesToUx(previewResult.Preview.Properties.Changes)
+}
+
+// PreviewChangesToUx creates the ux element to display a list of resource changes
+func PreviewChangesToUx(changes []*provisioning.DeploymentPreviewChange) ux.UxItem {
 	var operations []*ux.Resource
-	for _, change := range previewResult.Preview.Properties.Changes {
+	for _, change := range changes {
 		// Convert property deltas to UX format
 		var propertyDeltas []ux.PropertyDelta
 		for _, delta := range change.Delta {
@@ -333,6 +343,34 @@ func deployResultToUx(previewResult *provisioning.DeployPreviewResult) ux.UxItem
 	}
 }
 
+// costEstimateToUx creates the ux element to display the estimated monthly cost of a provision preview
+func costEstimateToUx(estimate *cost.Estimate) ux.UxItem {
+	resources := make([]ux.CostEstimateResource, len(estimate.Resources))
+	for i, resource := range estimate.Resources {
+		resourceType := resource.Type
+		if displayName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(resource.Type)); displayName != "" {
+			resourceType = displayName
+		}
+
+		resources[i] = ux.CostEstimateResource{
+			Type:                resourceType,
+			Name:                resource.Name,
+			Sku:                 resource.Sku,
+			Location:            resource.Location,
+			MonthlyCost:         resource.MonthlyCost,
+			PreviousMonthlyCost: resource.PreviousMonthlyCost,
+		}
+	}
+
+	return &ux.CostEstimate{
+		Currency:            estimate.Currency,
+		MonthlyCost:         estimate.MonthlyCost,
+		PreviousMonthlyCost: estimate.PreviousMonthlyCost,
+		Resources:           resources,
+		UnpricedResources:   estimate.UnpricedResources,
+	}
+}
+
 func GetCmdProvisionHelpDescription(c *cobra.Command) string {
 	return generateCmdHelpDescription(
 		fmt.Sprintf(
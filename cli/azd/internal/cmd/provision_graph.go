@@ -30,6 +30,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/cost"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
@@ -111,6 +112,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 
 		if err := g.AddStep(&exegraph.Step{
 			Name: provisionLayerStepName(layer),
+			Tags: []string{"provision"},
 			Action: func(ctx context.Context) error {
 				if err := p.provisionManager.Initialize(ctx, p.projectConfig.Path, layer); err != nil {
 					return fmt.Errorf("initializing provisioning manager: %w", err)
@@ -261,6 +263,7 @@ func (p *ProvisionAction) provisionLayersGraph(
 			if err := g.AddStep(&exegraph.Step{
 				Name:      stepNames[i],
 				DependsOn: deps,
+				Tags:      []string{"provision"},
 				Action: func(ctx context.Context) error {
 					outcome, err := p.provisionSingleLayerWithOutcome(ctx, layer, stepNames[i])
 					if err != nil {
@@ -374,6 +377,20 @@ func (p *ProvisionAction) provisionPreview(
 
 	p.console.MessageUxItem(ctx, deployResultToUx(deployPreviewResult))
 
+	// Estimate the monthly cost when the provider describes the billable resources. The estimate is informational,
+	// so failing to compute it doesn't fail the preview.
+	if p.priceSheetManager != nil && deployPreviewResult.BillableChanges != nil {
+		priceSheet, err := p.priceSheetManager.PriceSheet(
+			ctx, cost.BillableResources(deployPreviewResult.BillableChanges))
+		if err != nil {
+			log.Printf("failed to estimate costs: %v", err)
+		} else {
+			p.console.Message(ctx, "")
+			estimate := cost.EstimateCost(priceSheet, deployPreviewResult.BillableChanges)
+			p.console.MessageUxItem(ctx, costEstimateToUx(estimate))
+		}
+	}
+
 	return &actions.ActionResult{
 		Message: &actions.ResultMessage{
 			Header: fmt.Sprintf(
@@ -464,6 +481,7 @@ func (p *ProvisionAction) graphRunOptions(ctx context.Context, quiet bool) exegr
 
 	opts := exegraph.RunOptions{
 		ErrorPolicy: exegraph.FailFast,
+		Pools:       p.projectConfig.Concurrency,
 	}
 
 	if !quiet {
@@ -17,8 +17,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/cost"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/project"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
@@ -189,3 +191,30 @@ func TestProvisionAction_PreflightAborted(t *testing.T) {
 	// Verify project manager was called (action didn't exit prematurely)
 	pm.AssertExpectations(t)
 }
+
+func Test_costEstimateToUx(t *testing.T) {
+	uxItem := costEstimateToUx(&cost.Estimate{
+		Currency:            "USD",
+		MonthlyCost:         113.15,
+		PreviousMonthlyCost: 13.14,
+		Resources: []cost.ResourceEstimate{
+			{Type: "Microsoft.Web/serverfarms", Name: "plan", Sku: "P1v3", Location: "eastus",
+				MonthlyCost: 113.15, PreviousMonthlyCost: 13.14},
+			{Type: "Microsoft.Contoso/widgets", Name: "widget"},
+		},
+		UnpricedResources: 1,
+	})
+
+	require.Equal(t, &ux.CostEstimate{
+		Currency:            "USD",
+		MonthlyCost:         113.15,
+		PreviousMonthlyCost: 13.14,
+		Resources: []ux.CostEstimateResource{
+			{Type: "App Service plan", Name: "plan", Sku: "P1v3", Location: "eastus",
+				MonthlyCost: 113.15, PreviousMonthlyCost: 13.14},
+			// Types without a display name are kept as is.
+			{Type: "Microsoft.Contoso/widgets", Name: "widget"},
+		},
+		UnpricedResources: 1,
+	}, uxItem)
+}
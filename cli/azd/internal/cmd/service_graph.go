@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -157,18 +158,19 @@ type serviceGraphOptions struct {
 	onDeployTimeout func(ctx context.Context, svc *project.ServiceConfig)
 
 	// buildGateKey groups services that share a concurrent-unsafe build
-	// phase: for each non-empty key, a shared mutex is created and injected
-	// into each gated deploy step's context. The service target acquires
-	// the mutex only during image preparation (dotnet publish) and releases
-	// it before the Azure deployment begins. An empty key means "no gate"
-	// (full parallelism). Keys are opaque strings, so multiple independent
-	// gates can coexist (e.g. one group per shared build toolchain).
+	// phase: each non-empty key names an exegraph concurrency pool (sized to
+	// one slot by [graphPools]) that is injected into each
+	// gated deploy step's context. The service target holds a slot only
+	// during image preparation (dotnet publish) and releases it before the
+	// Azure deployment begins. An empty key means "no gate" (full
+	// parallelism). Keys are opaque strings, so multiple independent gates
+	// can coexist (e.g. one group per shared build toolchain).
 	//
 	// The graph builder itself is gate-agnostic: callers (including future
 	// extensions) inject the policy. `azd deploy` and `azd up` supply a
 	// callback that returns "aspire" for services owned by a .NET AppHost
 	// manifest. The preferred approach is --artifacts-path (full
-	// parallelism); the mutex is a fallback. If nil, no gating is applied.
+	// parallelism); the pool is a fallback. If nil, no gating is applied.
 	buildGateKey func(svc *project.ServiceConfig) string
 
 	// onPhaseProgress, if non-nil, is invoked with intra-phase progress
@@ -182,6 +184,14 @@ type serviceGraphOptions struct {
 	// IMPORTANT: This callback is invoked from worker goroutines and must
 	// not block. Blocking implementations stall the graph scheduler.
 	onPhaseProgress func(serviceName string, phase deployPhase, detail string)
+
+	// fingerprints, when non-nil, opts services into exegraph checkpointing:
+	// each service listed gets its package/publish/deploy steps checkpointed
+	// as one group under the given fingerprint (a hash of the service's
+	// source, computed lazily by exegraph). Deploy steps record their
+	// ServiceDeployResult so a resumed run can still display endpoints for
+	// services it skipped. Only `azd up` sets this.
+	fingerprints map[string]func() (string, error)
 }
 
 // serviceGraphHandles exposes the names of the steps that addServiceStepsToGraph
@@ -205,9 +215,15 @@ type serviceGraphHandles struct {
 //	                                                                                            │
 //	                                                                     opts.buildGateKey:
 //	                                                             services sharing a non-empty key
-//	                                                            get a runtime mutex in their context
+//	                                                           get a one-slot pool in their context
 //	                                                              (no graph edges between deploys).
 //
+// Step tags: package steps carry "package", plus [project.ContainerBuildPool]
+// ("build") for services hosted on a container target; publish steps carry
+// "publish"; deploy steps carry "deploy" plus "deploy:<host>" for built-in
+// hosts (e.g. "deploy:aks"). Callers can bound any of these through
+// [exegraph.RunOptions.Pools].
+//
 // Deploy ordering: when no service declares a `uses:` edge targeting
 // another service in this graph, deploy steps chain sequentially in the
 // order provided by opts.services (alphabetical via ServiceStable()) for
@@ -245,17 +261,6 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 		DeploySteps:  make([]string, 0, len(opts.services)),
 	}
 
-	// buildGateMu holds a per-gate-key mutex used to serialize only the
-	// image-preparation phase (dotnet publish) inside Aspire deploy steps,
-	// while leaving the Azure deployment portion fully parallel. The mutex
-	// is injected into the deploy step's context so the service target can
-	// acquire it during the build-only window. See build_gate.go in the
-	// project package.
-	//
-	// Services whose gate key is "" (or when buildGateKey is nil) are
-	// unconstrained and run in full parallelism.
-	buildGateMu := make(map[string]*sync.Mutex)
-
 	// serviceNames is the set of service names in this graph, used to
 	// resolve service-to-service edges declared via `services.<name>.uses`
 	// in azure.yaml. Resource-valued `uses:` entries (targeting entries
@@ -361,13 +366,29 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 		handles.PublishSteps = append(handles.PublishSteps, publishStepName)
 		handles.DeploySteps = append(handles.DeploySteps, deployStepName)
 
+		// Publish and deploy talk to Azure and registries and are the steps
+		// worth retrying on throttling or conflicts; packaging is local.
+		retry := serviceRetryPolicy(svc)
+
+		// Checkpointing: the three steps of a service form one group because
+		// publish and deploy consume the in-memory ServiceContext produced by
+		// package — resuming only part of the chain would leave it empty.
+		var pkgCheckpoint, publishCheckpoint, deployCheckpoint *exegraph.StepCheckpoint
+		if fp, ok := opts.fingerprints[svc.Name]; ok {
+			group := "service-" + svc.Name
+			pkgCheckpoint = &exegraph.StepCheckpoint{Fingerprint: fp, Group: group}
+			publishCheckpoint = &exegraph.StepCheckpoint{Fingerprint: fp, Group: group}
+			deployCheckpoint = deployResultCheckpoint(opts.state, svc.Name, fp, group)
+		}
+
 		// ── package-<svc> ── opts.packageExtraDeps (empty for stand-alone
 		// deploy → no deps → packaging overlaps with anything upstream).
 		pkgSvc := svc
 		if err := g.AddStep(&exegraph.Step{
-			Name:      pkgStepName,
-			DependsOn: opts.packageExtraDeps,
-			Tags:      []string{"package"},
+			Name:       pkgStepName,
+			DependsOn:  opts.packageExtraDeps,
+			Tags:       packageStepTags(svc),
+			Checkpoint: pkgCheckpoint,
 			Action: func(ctx context.Context) error {
 				sc := project.NewServiceContext()
 
@@ -407,9 +428,11 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 
 		pubSvc := svc
 		if err := g.AddStep(&exegraph.Step{
-			Name:      publishStepName,
-			DependsOn: publishDeps,
-			Tags:      []string{"publish"},
+			Name:       publishStepName,
+			DependsOn:  publishDeps,
+			Tags:       []string{"publish"},
+			Checkpoint: publishCheckpoint,
+			Retry:      retry,
 			Action: func(stepCtx context.Context) error {
 				sc := opts.state.LoadContext(pubSvc.Name)
 
@@ -459,19 +482,16 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 		// edges + any caller-supplied fan-in.
 		//
 		// Build-gate synchronization is handled at RUNTIME, not via graph
-		// topology: the deploy step's context carries a per-gate-key mutex
-		// that the service target acquires only during image preparation
-		// (dotnet publish) and releases before the Azure deployment begins.
-		// This serializes the race-prone build phase while keeping the slow
-		// Azure deployment portion fully parallel.
+		// topology: the deploy step's context names a per-gate-key pool with
+		// a single slot, which the service target holds only during image
+		// preparation (dotnet publish) and releases before the Azure
+		// deployment begins. This serializes the race-prone build phase while
+		// keeping the slow Azure deployment portion fully parallel.
 		deployDeps := make([]string, 0, 1+len(svc.Uses)+len(opts.deployExtraDeps))
 		deployDeps = append(deployDeps, publishStepName)
+		var gateKey string
 		if opts.buildGateKey != nil {
-			if key := opts.buildGateKey(svc); key != "" {
-				if _, ok := buildGateMu[key]; !ok {
-					buildGateMu[key] = &sync.Mutex{}
-				}
-			}
+			gateKey = opts.buildGateKey(svc)
 		}
 		// Translate `services.<name>.uses: [depSvc]` into a deploy-step
 		// edge so hooks that pass values between services (e.g. api's
@@ -515,9 +535,11 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 
 		depSvc := svc
 		if err := g.AddStep(&exegraph.Step{
-			Name:      deployStepName,
-			DependsOn: deployDeps,
-			Tags:      []string{"deploy"},
+			Name:       deployStepName,
+			DependsOn:  deployDeps,
+			Tags:       deployStepTags(svc),
+			Checkpoint: deployCheckpoint,
+			Retry:      retry,
 			Action: func(stepCtx context.Context) error {
 				sc := opts.state.LoadContext(depSvc.Name)
 
@@ -531,14 +553,10 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 				deployCtx, deployCancel := context.WithTimeout(stepCtx, opts.deployTimeout)
 				defer deployCancel()
 
-				// Inject the build gate mutex into the deploy context so the
-				// service target can serialize only the image-preparation phase.
-				if opts.buildGateKey != nil {
-					if key := opts.buildGateKey(depSvc); key != "" {
-						if mu, ok := buildGateMu[key]; ok {
-							deployCtx = project.ContextWithBuildGate(deployCtx, mu)
-						}
-					}
+				// Name the build gate pool in the deploy context so the service
+				// target can serialize only the image-preparation phase.
+				if gateKey != "" {
+					deployCtx = project.ContextWithBuildGate(deployCtx, gateKey)
 				}
 
 				progress := newPhaseProgress(depSvc.Name, phaseDeploying)
@@ -575,6 +593,31 @@ func addServiceStepsToGraph(g *exegraph.Graph, opts serviceGraphOptions) (*servi
 	return handles, nil
 }
 
+// deployResultCheckpoint returns the checkpoint settings for a deploy step:
+// the ServiceDeployResult stored in state is recorded as the step output and
+// restored into state when the step is resumed.
+func deployResultCheckpoint(
+	state *deployGraphState, serviceName string, fingerprint func() (string, error), group string,
+) *exegraph.StepCheckpoint {
+	return &exegraph.StepCheckpoint{
+		Fingerprint: fingerprint,
+		Group:       group,
+		Output: func() (json.RawMessage, error) {
+			return json.Marshal(state.GetResult(serviceName))
+		},
+		Restore: func(output json.RawMessage) error {
+			var result *project.ServiceDeployResult
+			if err := json.Unmarshal(output, &result); err != nil {
+				return err
+			}
+			if result != nil {
+				state.StoreResult(serviceName, result)
+			}
+			return nil
+		},
+	}
+}
+
 // deployTimeoutWarning is the UX element emitted when a deploy step exceeds
 // its timeout. Kept next to the graph builder so both call sites share
 // identical wording.
@@ -635,3 +678,45 @@ func suggestServiceDeps(services []*project.ServiceConfig) {
 		}
 	}
 }
+
+// packageStepTags returns the tags of a service's package step. Services hosted
+// on a container target build their image while packaging, so their package
+// steps also join the container build pool.
+func packageStepTags(svc *project.ServiceConfig) []string {
+	if svc.Host.RequiresContainer() {
+		return []string{"package", project.ContainerBuildPool}
+	}
+	return []string{"package"}
+}
+
+// deployStepTags returns the tags of a service's deploy step. Built-in hosts add
+// a "deploy:<host>" tag so deploys to one kind of target (e.g. AKS) can be
+// bounded separately. Extension-provided hosts are not tagged: tags are
+// emitted to telemetry verbatim and must stay a fixed vocabulary.
+func deployStepTags(svc *project.ServiceConfig) []string {
+	if slices.Contains(project.BuiltInServiceTargetKinds(), svc.Host) {
+		return []string{"deploy", "deploy:" + string(svc.Host)}
+	}
+	return []string{"deploy"}
+}
+
+// graphPools returns the [exegraph.RunOptions.Pools] for a graph holding the
+// service steps of services: a single slot for every build-gate key produced
+// by buildGateKey, then the limits configured in the `concurrency` section of
+// azure.yaml, which take precedence.
+func graphPools(
+	services []*project.ServiceConfig,
+	buildGateKey func(svc *project.ServiceConfig) string,
+	configured map[string]int,
+) map[string]int {
+	pools := map[string]int{}
+	if buildGateKey != nil {
+		for _, svc := range services {
+			if key := buildGateKey(svc); key != "" {
+				pools[key] = 1
+			}
+		}
+	}
+	maps.Copy(pools, configured)
+	return pools
+}
@@ -244,7 +244,7 @@ func TestDeployGraphState_StoreLoadContext(t *testing.T) {
 // TestBuildGateParallelWithArtifactsPath verifies that when a buildGateKey
 // is set, deploy steps still execute in PARALLEL at the graph level (no chain
 // edges). The build-race prevention is handled at runtime via --artifacts-path
-// and fallback mutex, not via graph topology. This is the fix for GitHub issue
+// and a fallback one-slot pool, not via graph topology. This is the fix for GitHub issue
 // #8177: full parallelism preserved while isolating intermediate build outputs.
 func TestBuildGateParallelWithArtifactsPath(t *testing.T) {
 	t.Parallel()
@@ -306,7 +306,7 @@ func TestBuildGateParallelWithArtifactsPath(t *testing.T) {
 }
 
 // TestBuildGateMultipleKeys verifies that multiple independent gate keys
-// produce distinct mutexes per key and no graph-level deploy edges. This
+// produce distinct pools per key and no graph-level deploy edges. This
 // ensures services in different gate groups are isolated from each other
 // (e.g. two different Aspire app hosts in the same project).
 func TestBuildGateMultipleKeys(t *testing.T) {
@@ -344,9 +344,12 @@ func TestBuildGateMultipleKeys(t *testing.T) {
 	require.Len(t, handles.DeploySteps, 4)
 	require.NoError(t, g.Validate())
 
+	pools := graphPools(services, opts.buildGateKey, nil)
+	require.Equal(t, map[string]int{"gate-A": 1, "gate-B": 1}, pools)
+
 	// Run the graph to verify no deadlock (which would occur if independent
-	// gate groups accidentally shared a mutex with blocking semantics).
-	err = exegraph.Run(t.Context(), g, exegraph.RunOptions{})
+	// gate groups accidentally shared a pool with blocking semantics).
+	err = exegraph.Run(t.Context(), g, exegraph.RunOptions{Pools: pools})
 	require.NoError(t, err)
 
 	// Verify no deploy→deploy edges exist for any gate group.
@@ -361,3 +364,43 @@ func TestBuildGateMultipleKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceStepTags(t *testing.T) {
+	t.Parallel()
+	services := []*project.ServiceConfig{
+		{Name: "api", Host: project.ContainerAppTarget},
+		{Name: "chart", Host: project.AksTarget},
+		{Name: "web", Host: project.AppServiceTarget},
+		{Name: "ext", Host: project.ServiceTargetKind("my.custom.host")},
+	}
+
+	opts, g := newGraphOpts(services)
+	_, err := addServiceStepsToGraph(g, opts)
+	require.NoError(t, err)
+
+	tags := map[string][]string{}
+	for _, s := range g.Steps() {
+		tags[s.Name] = s.Tags
+	}
+	require.Equal(t, []string{"package", "build"}, tags["package-api"])
+	require.Equal(t, []string{"package", "build"}, tags["package-chart"])
+	require.Equal(t, []string{"package"}, tags["package-web"])
+	require.Equal(t, []string{"deploy", "deploy:aks"}, tags["deploy-chart"])
+	require.Equal(t, []string{"deploy", "deploy:appservice"}, tags["deploy-web"])
+	require.Equal(t, []string{"deploy"}, tags["deploy-ext"], "extension hosts are not tagged")
+}
+
+func TestGraphPools(t *testing.T) {
+	t.Parallel()
+	services := []*project.ServiceConfig{
+		{Name: "api", DotNetContainerApp: &project.DotNetContainerAppOptions{Manifest: &apphost.Manifest{}}},
+		{Name: "web"},
+	}
+
+	require.Equal(t, map[string]int{"aspire": 1}, graphPools(services, aspireBuildGateKey, nil))
+	require.Equal(t,
+		map[string]int{"aspire": 2, "build": 2},
+		graphPools(services, aspireBuildGateKey, map[string]int{"aspire": 2, "build": 2}),
+		"configured limits override the build gate default")
+	require.Empty(t, graphPools(services, nil, nil))
+}
@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+)
+
+// serviceRetryPolicy returns the retry policy for a service's publish and
+// deploy steps from its `retry` section in azure.yaml, or nil when the service
+// does not opt in.
+func serviceRetryPolicy(svc *project.ServiceConfig) *exegraph.RetryPolicy {
+	if svc.Retry == nil || svc.Retry.MaxAttempts < 2 {
+		return nil
+	}
+	return &exegraph.RetryPolicy{
+		MaxAttempts:  svc.Retry.MaxAttempts,
+		InitialDelay: time.Duration(svc.Retry.DelaySeconds) * time.Second,
+		MaxDelay:     time.Duration(svc.Retry.MaxDelaySeconds) * time.Second,
+		Retryable:    isTransientServiceError,
+	}
+}
+
+// transientARMCodes are ARM error codes reported while a conflicting
+// operation on the same resource is still running, or when throttled.
+var transientARMCodes = []string{
+	"Conflict",
+	"AnotherOperationInProgress",
+	"TooManyRequests",
+	"RetryableError",
+}
+
+// transientMessages are fragments of error output, typically from docker,
+// kubectl or helm, that indicate throttling or a temporarily unavailable
+// endpoint.
+var transientMessages = []string{
+	"429 too many requests",
+	"toomanyrequests",
+	"503 service unavailable",
+	"the server is currently unable to handle the request",
+	"tls handshake timeout",
+	"i/o timeout",
+	"connection reset by peer",
+	"connection refused",
+}
+
+// isTransientServiceError reports whether a publish or deploy failure is
+// likely to succeed when retried: throttling, server errors and conflicts
+// with an operation that is still in progress.
+func isTransientServiceError(err error) bool {
+	if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok {
+		switch respErr.StatusCode {
+		case http.StatusConflict,
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		if hasTransientARMCode(respErr.ErrorCode) {
+			return true
+		}
+	}
+
+	if deployErr, ok := errors.AsType[*azapi.AzureDeploymentError](err); ok && deployErr.Details != nil {
+		if hasTransientDeploymentCode(deployErr.Details) {
+			return true
+		}
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, fragment := range transientMessages {
+		if strings.Contains(msg, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTransientARMCode(code string) bool {
+	for _, c := range transientARMCodes {
+		if strings.EqualFold(code, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTransientDeploymentCode walks an ARM deployment error tree for a
+// transient error code.
+func hasTransientDeploymentCode(line *azapi.DeploymentErrorLine) bool {
+	if hasTransientARMCode(line.Code) {
+		return true
+	}
+	for _, inner := range line.Inner {
+		if inner != nil && hasTransientDeploymentCode(inner) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceRetryPolicy(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, serviceRetryPolicy(&project.ServiceConfig{Name: "api"}))
+	require.Nil(t, serviceRetryPolicy(&project.ServiceConfig{
+		Name: "api", Retry: &project.ServiceRetryOptions{MaxAttempts: 1},
+	}), "a single attempt needs no policy")
+
+	policy := serviceRetryPolicy(&project.ServiceConfig{
+		Name:  "api",
+		Retry: &project.ServiceRetryOptions{MaxAttempts: 4, DelaySeconds: 5, MaxDelaySeconds: 30},
+	})
+	require.NotNil(t, policy)
+	require.Equal(t, 4, policy.MaxAttempts)
+	require.Equal(t, 5*time.Second, policy.InitialDelay)
+	require.Equal(t, 30*time.Second, policy.MaxDelay)
+	require.NotNil(t, policy.Retryable)
+}
+
+func TestIsTransientServiceError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"Throttled", &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}, true},
+		{"Conflict", fmt.Errorf("updating app: %w", &azcore.ResponseError{StatusCode: http.StatusConflict}), true},
+		{"ServerError", &azcore.ResponseError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"NotFound", &azcore.ResponseError{StatusCode: http.StatusNotFound}, false},
+		{
+			"OperationInProgressCode",
+			&azcore.ResponseError{StatusCode: http.StatusBadRequest, ErrorCode: "AnotherOperationInProgress"},
+			true,
+		},
+		{
+			"NestedDeploymentConflict",
+			&azapi.AzureDeploymentError{Details: &azapi.DeploymentErrorLine{
+				Code:  "DeploymentFailed",
+				Inner: []*azapi.DeploymentErrorLine{{Code: "Conflict"}},
+			}},
+			true,
+		},
+		{
+			"DeploymentValidation",
+			&azapi.AzureDeploymentError{Details: &azapi.DeploymentErrorLine{Code: "InvalidTemplate"}},
+			false,
+		},
+		{"RegistryThrottled", errors.New("push failed: toomanyrequests: retry later"), true},
+		{"KubernetesUnavailable", errors.New("Error: the server is currently unable to handle the request"), true},
+		{"BuildFailure", errors.New("exit code: 1, stderr: error CS1002: ; expected"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			require.Equal(t, tt.want, isTransientServiceError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/ignore"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// upCheckpointFileName is the name of the file, stored in the environment's
+// `.azure/<env>` directory, that records which `azd up` graph steps completed
+// successfully during the last run.
+const upCheckpointFileName = ".up-checkpoint.json"
+
+// upCheckpointFile is the on-disk shape of the `azd up` checkpoint.
+type upCheckpointFile struct {
+	// Inputs is a hash of the run-wide inputs (environment values and the
+	// project file) at the time the checkpoint was last written.
+	Inputs string `json:"inputs"`
+
+	*exegraph.Checkpoint
+}
+
+// upCheckpointStore is the [exegraph.CheckpointStore] used by `azd up`. Steps
+// carry their own per-step fingerprints (service source / infra module hashes);
+// the store adds a run-wide guard on top: when the environment values or
+// azure.yaml changed since the checkpoint was written, Load discards it so a
+// resumed run never skips steps whose inputs moved underneath them.
+type upCheckpointStore struct {
+	path        string
+	env         *environment.Environment
+	projectFile string
+}
+
+// newUpCheckpointStore creates a store for the checkpoint of env, located in
+// envRoot (the `.azure/<env>` directory).
+func newUpCheckpointStore(envRoot string, env *environment.Environment, projectFile string) *upCheckpointStore {
+	return &upCheckpointStore{
+		path:        filepath.Join(envRoot, upCheckpointFileName),
+		env:         env,
+		projectFile: projectFile,
+	}
+}
+
+// Load implements [exegraph.CheckpointStore].
+func (s *upCheckpointStore) Load(_ context.Context) (*exegraph.Checkpoint, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+
+	var file upCheckpointFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", s.path, err)
+	}
+
+	inputs, err := s.inputsHash()
+	if err != nil {
+		return nil, err
+	}
+	if file.Inputs != inputs {
+		log.Printf("up-graph: discarding checkpoint, environment values or %s changed since it was written",
+			filepath.Base(s.projectFile))
+		return nil, nil
+	}
+
+	return file.Checkpoint, nil
+}
+
+// Save implements [exegraph.CheckpointStore]. The file is written to a
+// temporary sibling first and renamed into place so an interrupted write never
+// leaves a truncated checkpoint behind.
+func (s *upCheckpointStore) Save(ctx context.Context, cp *exegraph.Checkpoint) error {
+	inputs, err := s.inputsHash()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(upCheckpointFile{Inputs: inputs, Checkpoint: cp}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling checkpoint: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating checkpoint directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing checkpoint: %w", err)
+	}
+
+	return osutil.Rename(ctx, tmp, s.path)
+}
+
+// Clear removes the checkpoint. A missing checkpoint is not an error.
+func (s *upCheckpointStore) Clear() error {
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("removing checkpoint: %w", err)
+	}
+	return nil
+}
+
+// inputsHash hashes the environment's .env values (in key order) and the
+// content of the project file.
+func (s *upCheckpointStore) inputsHash() (string, error) {
+	h := sha256.New()
+
+	values := s.env.Dotenv()
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		fmt.Fprintf(h, "%s=%s\n", k, values[k])
+	}
+
+	projectFile, err := os.ReadFile(s.projectFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("reading project file: %w", err)
+	}
+	h.Write(projectFile)
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// sourceTreeSkipDirs are directories never included in a source fingerprint:
+// VCS metadata, azd state and dependency caches that do not affect what azd
+// packages or provisions.
+var sourceTreeSkipDirs = []string{".git", ".azure", "node_modules", ".venv", "__pycache__"}
+
+// stepFingerprint returns a content hash of root (a directory, or the
+// directory containing root when it names a file) combined with the given
+// identifying parts. Files matched by the tree's .gitignore / .azdxignore are
+// excluded.
+func stepFingerprint(root string, parts ...string) (string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		root = filepath.Dir(root)
+	}
+
+	matcher, err := ignore.NewMatcher(root)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, p := range parts {
+		fmt.Fprintf(h, "%s\x00", p)
+	}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() && slices.Contains(sourceTreeSkipDirs, d.Name()) {
+			return filepath.SkipDir
+		}
+		if matcher.IsIgnored(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("hashing %s: %w", root, err)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
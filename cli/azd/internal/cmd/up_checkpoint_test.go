@@ -0,0 +1,186 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpCheckpointStore_RoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	projectFile := filepath.Join(dir, "azure.yaml")
+	require.NoError(t, os.WriteFile(projectFile, []byte("name: app\n"), osutil.PermissionFile))
+
+	env := environment.NewWithValues("dev", map[string]string{"AZURE_LOCATION": "eastus"})
+	store := newUpCheckpointStore(filepath.Join(dir, ".azure", "dev"), env, projectFile)
+
+	loaded, err := store.Load(t.Context())
+	require.NoError(t, err)
+	require.Nil(t, loaded, "missing checkpoint loads as nil")
+
+	cp := &exegraph.Checkpoint{Steps: map[string]exegraph.CheckpointEntry{
+		"provision": {Fingerprint: "abc"},
+	}}
+	require.NoError(t, store.Save(t.Context(), cp))
+
+	loaded, err = store.Load(t.Context())
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	assert.Equal(t, "abc", loaded.Steps["provision"].Fingerprint)
+
+	require.NoError(t, store.Clear())
+	loaded, err = store.Load(t.Context())
+	require.NoError(t, err)
+	assert.Nil(t, loaded)
+	require.NoError(t, store.Clear(), "clearing a missing checkpoint is not an error")
+}
+
+func TestUpCheckpointStore_InvalidatedByInputChanges(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		mutate func(t *testing.T, env *environment.Environment, projectFile string)
+	}{
+		{
+			name: "env value changed",
+			mutate: func(_ *testing.T, env *environment.Environment, _ string) {
+				env.DotenvSet("AZURE_LOCATION", "westus")
+			},
+		},
+		{
+			name: "env value added",
+			mutate: func(_ *testing.T, env *environment.Environment, _ string) {
+				env.DotenvSet("NEW_KEY", "1")
+			},
+		},
+		{
+			name: "project file changed",
+			mutate: func(t *testing.T, _ *environment.Environment, projectFile string) {
+				require.NoError(t, os.WriteFile(projectFile, []byte("name: other\n"), osutil.PermissionFile))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			projectFile := filepath.Join(dir, "azure.yaml")
+			require.NoError(t, os.WriteFile(projectFile, []byte("name: app\n"), osutil.PermissionFile))
+
+			env := environment.NewWithValues("dev", map[string]string{"AZURE_LOCATION": "eastus"})
+			store := newUpCheckpointStore(filepath.Join(dir, ".azure", "dev"), env, projectFile)
+			require.NoError(t, store.Save(t.Context(), &exegraph.Checkpoint{
+				Steps: map[string]exegraph.CheckpointEntry{"provision": {Fingerprint: "abc"}},
+			}))
+
+			tt.mutate(t, env, projectFile)
+
+			loaded, err := store.Load(t.Context())
+			require.NoError(t, err)
+			assert.Nil(t, loaded)
+		})
+	}
+}
+
+func TestStepFingerprint(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory))
+		require.NoError(t, os.WriteFile(path, []byte(content), osutil.PermissionFile))
+	}
+	write("main.py", "print('hi')")
+	write(".gitignore", "dist/\n")
+
+	base, err := stepFingerprint(dir, "api")
+	require.NoError(t, err)
+
+	// Ignored and skipped content does not change the fingerprint.
+	write("dist/out.txt", "build output")
+	write("node_modules/pkg/index.js", "module")
+	write(".azure/dev/.env", "X=1")
+	got, err := stepFingerprint(dir, "api")
+	require.NoError(t, err)
+	assert.Equal(t, base, got)
+
+	// A file path hashes its containing directory.
+	got, err = stepFingerprint(filepath.Join(dir, "main.py"), "api")
+	require.NoError(t, err)
+	assert.Equal(t, base, got)
+
+	// Identifying parts are part of the fingerprint.
+	got, err = stepFingerprint(dir, "web")
+	require.NoError(t, err)
+	assert.NotEqual(t, base, got)
+
+	// Source changes change the fingerprint.
+	write("main.py", "print('bye')")
+	got, err = stepFingerprint(dir, "api")
+	require.NoError(t, err)
+	assert.NotEqual(t, base, got)
+}
+
+func TestAddServiceStepsToGraph_Fingerprints(t *testing.T) {
+	t.Parallel()
+	services := []*project.ServiceConfig{{Name: "api"}, {Name: "web"}}
+	state := newDeployGraphState(services)
+	hashed := false
+	fingerprint := func() (string, error) {
+		hashed = true
+		return "h-api", nil
+	}
+
+	g := exegraph.NewGraph()
+	_, err := addServiceStepsToGraph(g, serviceGraphOptions{
+		services:       services,
+		serviceManager: &stubServiceManager{},
+		deployTimeout:  time.Minute,
+		state:          state,
+		fingerprints:   map[string]func() (string, error){"api": fingerprint},
+	})
+	require.NoError(t, err)
+	assert.False(t, hashed, "building the graph must not hash the service source")
+
+	byName := map[string]*exegraph.Step{}
+	for _, s := range g.Steps() {
+		byName[s.Name] = s
+	}
+	for _, name := range []string{"package-api", "publish-api", "deploy-api"} {
+		require.NotNil(t, byName[name].Checkpoint, name)
+		fp, err := byName[name].Checkpoint.Fingerprint()
+		require.NoError(t, err)
+		assert.Equal(t, "h-api", fp)
+		assert.Equal(t, "service-api", byName[name].Checkpoint.Group)
+	}
+	for _, name := range []string{"package-web", "publish-web", "deploy-web"} {
+		assert.Nil(t, byName[name].Checkpoint, name)
+	}
+
+	// The deploy step's output round-trips the deploy result into state.
+	deployCheckpoint := byName["deploy-api"].Checkpoint
+	state.StoreResult("api", &project.ServiceDeployResult{Artifacts: project.ArtifactCollection{{
+		Kind: project.ArtifactKindEndpoint, Location: "https://api.example.com",
+	}}})
+	output, err := deployCheckpoint.Output()
+	require.NoError(t, err)
+
+	restored := newDeployGraphState(services)
+	require.NoError(t, deployResultCheckpoint(restored, "api", fingerprint, "service-api").Restore(output))
+	require.NotNil(t, restored.GetResult("api"))
+	assert.Equal(t, "https://api.example.com", restored.GetResult("api").Artifacts[0].Location)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/azsdk/storage"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
@@ -70,6 +71,7 @@ import (
 //   - `provisionSingleLayer` continues to fire layer hooks + per-layer
 //     `ProjectEventProvision` events internally.
 type UpGraphAction struct {
+	azdCtx              *azdcontext.AzdContext
 	projectConfig       *project.ProjectConfig
 	env                 *environment.Environment
 	envManager          environment.Manager
@@ -92,6 +94,7 @@ type UpGraphAction struct {
 // NewUpGraphAction creates a new UpGraphAction. Dependencies are resolved via
 // the IoC container.
 func NewUpGraphAction(
+	azdCtx *azdcontext.AzdContext,
 	projectConfig *project.ProjectConfig,
 	env *environment.Environment,
 	envManager environment.Manager,
@@ -110,6 +113,7 @@ func NewUpGraphAction(
 	provisionManager *provisioning.Manager,
 ) *UpGraphAction {
 	return &UpGraphAction{
+		azdCtx:              azdCtx,
 		projectConfig:       projectConfig,
 		env:                 env,
 		envManager:          envManager,
@@ -130,17 +134,30 @@ func NewUpGraphAction(
 	}
 }
 
+// UpGraphRunOptions holds per-invocation switches for [UpGraphAction.Run].
+type UpGraphRunOptions struct {
+	// Resume skips steps that the environment's `azd up` checkpoint records as
+	// completed, provided their inputs (service source, infra module,
+	// environment values and azure.yaml) are unchanged.
+	Resume bool
+}
+
 // Run builds and executes the unified `azd up` graph. `layers` may be empty,
 // in which case a zero-layer graph (cmdhook-* + service steps + deploy
 // events) is built and executed. `deployFlags` is consulted by
 // [resolveDeployTimeout] so that `AZD_DEPLOY_TIMEOUT` and `--timeout`
 // behave identically to stand-alone `azd deploy`.
+//
+// Every run records a checkpoint of completed provision and service steps in
+// the environment directory; the checkpoint is removed once the run succeeds.
+// With runOpts.Resume, steps recorded by the previous failed run are skipped.
 func (u *UpGraphAction) Run(
 	ctx context.Context,
 	layers []provisioning.Options,
 	deployFlags *DeployFlags,
 	parentFlags *pflag.FlagSet,
 	startTime time.Time,
+	runOpts UpGraphRunOptions,
 ) (*actions.ActionResult, error) {
 	// Emit synthetic cmd.package and cmd.provision spans as children of the
 	// parent cmd.up span. The legacy `azd up` workflow runner spawned
@@ -174,30 +191,291 @@ func (u *UpGraphAction) Run(
 		provisionSpan.End()
 	}()
 
-	// 1. Analyze provision layer dependencies. Empty layers → empty graph.
-	var layerDeps *bicep.LayerDependencies
-	if len(layers) > 0 {
-		var err error
-		layerDeps, err = bicep.AnalyzeLayerDependencies(ctx, layers, u.projectConfig.Path)
-		if err != nil {
-			return nil, fmt.Errorf("analyzing layer dependencies: %w", err)
-		}
-	}
-
-	// 2. Initialize project and enumerate services.
+	// 1. Initialize project and enumerate services.
 	stableServices, err := u.initializeServices(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// 3. Resolve deploy timeout (honors --timeout flag and AZD_DEPLOY_TIMEOUT
+	// 2. Resolve deploy timeout (honors --timeout flag and AZD_DEPLOY_TIMEOUT
 	// env var for parity with stand-alone `azd deploy`).
 	deployTimeout, err := resolveDeployTimeout(deployFlags)
 	if err != nil {
 		return nil, err
 	}
 
-	// 4. Build the unified execution graph.
+	// Create a deploy progress tracker so users see real-time feedback
+	// during the package → publish → deploy phase (after provisioning).
+	// In JSON output mode or when no writer is available, skip the tracker.
+	var deployTracker *deployProgressTracker
+	if w := u.console.GetWriter(); u.formatter.Kind() != output.JsonFormat && w != nil {
+		serviceNames := make([]string, len(stableServices))
+		for i, svc := range stableServices {
+			serviceNames[i] = svc.Name
+		}
+		deployTracker = newDeployProgressTracker(w, u.console.IsSpinnerInteractive(), serviceNames)
+	}
+
+	updateDeployProgress := func(svcName string, phase deployPhase, detail string) {
+		if deployTracker != nil {
+			deployTracker.Update(svcName, phase, detail)
+		}
+	}
+
+	// 3. Build the unified execution graph.
+	g, state, err := u.buildGraph(ctx, layers, stableServices, deployTimeout, updateDeployProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Execute the unified graph.
+	u.console.MessageUxItem(ctx, &ux.MessageTitle{
+		Title:     "Provisioning and deploying (azd up)",
+		TitleNote: "Packaging overlaps with provisioning for faster execution",
+	})
+
+	// Start the deploy progress ticker lazily — only when the first
+	// publish or deploy step begins (not package, which runs silently
+	// in parallel with provisioning). This avoids conflicting with
+	// the provisioning progress display.
+	var (
+		tickerOnce sync.Once
+		stopTicker func()
+	)
+	if deployTracker != nil {
+		stopTicker = func() {} // no-op until started
+	}
+
+	// startDeployTicker is called once (via tickerOnce) when the first publish or deploy
+	// step begins. It starts the progress table ticker and suppresses the console previewer
+	// so that DI-injected ShowPreviewer callers (e.g. ContainerHelper's Docker output)
+	// don't corrupt the progress table display.
+	// Previewer is not paused during the earlier provision + hook phases so that
+	// preprovision/postprovision hook output remains visible (fixes #8237).
+	startDeployTicker := func() {
+		if deployTracker == nil {
+			return
+		}
+		// Pause the previewer before starting the ticker to avoid a window where
+		// the progress table renders while ShowPreviewer is still active.
+		if ps, ok := u.console.(input.PreviewerPauser); ok {
+			ps.PausePreviewer()
+			stop := deployTracker.StartTicker(ctx)
+			stopTicker = func() {
+				stop()
+				ps.ResumePreviewer()
+			}
+		} else {
+			stopTicker = deployTracker.StartTicker(ctx)
+		}
+	}
+
+	opts := u.runOptions()
+	checkpoint := newUpCheckpointStore(
+		u.azdCtx.EnvironmentRoot(u.env.Name()), u.env, u.azdCtx.ProjectPath())
+	opts.Checkpoint = checkpoint
+	opts.Resume = runOpts.Resume
+	opts.Pools = graphPools(stableServices, aspireBuildGateKey, u.projectConfig.Concurrency)
+	baseOnStepStart := opts.OnStepStart
+	baseOnStepDone := opts.OnStepDone
+
+	opts.OnStepStart = func(stepName string) {
+		if baseOnStepStart != nil {
+			baseOnStepStart(stepName)
+		}
+		// Update deploy progress tracker for service steps.
+		// Packaging runs in parallel with provisioning, so only update the
+		// data model silently. Start the visual ticker when the first
+		// publish or deploy step begins — these gate on provision completion,
+		// avoiding conflicts with the provisioning progress display.
+		if svc, ok := strings.CutPrefix(stepName, "package-"); ok {
+			updateDeployProgress(svc, phasePackaging, "")
+		} else if svc, ok := strings.CutPrefix(stepName, "publish-"); ok {
+			tickerOnce.Do(startDeployTicker)
+			updateDeployProgress(svc, phasePublish, "")
+		} else if svc, ok := strings.CutPrefix(stepName, "deploy-"); ok {
+			tickerOnce.Do(startDeployTicker)
+			updateDeployProgress(svc, phaseDeploying, "")
+		}
+	}
+	opts.OnStepDone = func(stepName string, err error) {
+		if baseOnStepDone != nil {
+			baseOnStepDone(stepName, err)
+		}
+		// Update deploy progress tracker on step completion.
+		if err != nil {
+			phase := phaseFailed
+			detail := err.Error()
+			switch {
+			case exegraph.IsStepSkipped(err):
+				phase = phaseSkipped
+				detail = ""
+			case errors.Is(err, context.Canceled):
+				phase = phaseSkipped
+				detail = "canceled"
+			}
+			for _, prefix := range []string{"deploy-", "publish-", "package-"} {
+				if svc, ok := strings.CutPrefix(stepName, prefix); ok {
+					updateDeployProgress(svc, phase, detail)
+					return
+				}
+			}
+		}
+		if svc, ok := strings.CutPrefix(stepName, "deploy-"); ok {
+			updateDeployProgress(svc, phaseDone, "")
+		}
+	}
+
+	result := exegraph.RunWithResult(ctx, g, opts)
+
+	// Stop the progress ticker and render a final summary table.
+	if stopTicker != nil {
+		stopTicker()
+	}
+	if deployTracker != nil && deployTracker.HasActivity() {
+		deployTracker.RenderFinal()
+	}
+
+	// Clean up temporary package artifacts regardless of success/failure.
+	state.CleanupTempArtifacts()
+
+	// Log per-step timing for diagnostics and benchmarking.
+	resumedSteps := 0
+	for _, st := range result.Steps {
+		if st.Resumed {
+			resumedSteps++
+			log.Printf("up-graph step %-30s  %s  (resumed from checkpoint)", st.Name, st.Status)
+			continue
+		}
+		log.Printf("up-graph step %-30s  %s  %s", st.Name, st.Status, st.Duration.Round(time.Millisecond))
+	}
+	log.Printf("up-graph total: %s (%d steps)", result.TotalDuration.Round(time.Millisecond), len(result.Steps))
+
+	if runOpts.Resume {
+		if resumedSteps > 0 {
+			u.console.Message(ctx, output.WithGrayFormat(
+				"Resumed %d step(s) that completed in a previous run.", resumedSteps))
+		} else {
+			u.console.Message(ctx, output.WithGrayFormat(
+				"No reusable checkpoint found for environment '%s'; all steps were run.", u.env.Name()))
+		}
+	}
+
+	if result.Error != nil {
+		if checkpointedStepDone(g, result) {
+			u.console.Message(ctx, output.WithGrayFormat(
+				"Progress was saved. Run 'azd up --resume' to skip the steps that already succeeded."))
+		}
+		// Only apply provision-specific error wrapping (state dump, OpenAI quota
+		// hints, Responsible-AI suggestions) when an actual provision-tagged
+		// step failed. For package/publish/deploy/hook failures, surface the
+		// underlying error verbatim so the message matches the legacy phase
+		// shape (e.g., `azd package` / `azd deploy` errors).
+		if provisionStepFailed(result) {
+			return nil, wrapProvisionError(ctx, result.Error, provisionErrorDeps{
+				console:          u.console,
+				formatter:        u.formatter,
+				writer:           u.writer,
+				provisionManager: u.provisionManager,
+				portalUrlBase:    u.portalUrlBase,
+			})
+		}
+		return nil, result.Error
+	}
+
+	// Display service endpoint artifacts collected during deploy steps.
+	for _, svc := range stableServices {
+		if dr := state.GetResult(svc.Name); dr != nil && len(dr.Artifacts) > 0 {
+			u.console.MessageUxItem(ctx, dr.Artifacts)
+		}
+	}
+
+	// The run succeeded: there is nothing left to resume.
+	if clearErr := checkpoint.Clear(); clearErr != nil {
+		log.Printf("warning: %v", clearErr)
+	}
+
+	// 5. Finalize: invalidate env cache.
+	if cacheErr := u.envManager.InvalidateEnvCache(ctx, u.env.Name()); cacheErr != nil {
+		log.Printf("warning: failed to invalidate state cache: %v", cacheErr)
+	}
+
+	// Emit per-phase duration telemetry for backend performance tracking.
+	totalMs := since(startTime).Milliseconds()
+	tracing.SetUsageAttributes(fields.PerfTotalDurationMs.Int64(totalMs))
+	provDur, deployDur := phaseDurations(result.Steps)
+	if provDur > 0 {
+		tracing.SetUsageAttributes(fields.PerfProvisionDurationMs.Int64(provDur.Milliseconds()))
+	}
+	if deployDur > 0 {
+		tracing.SetUsageAttributes(fields.PerfDeployDurationMs.Int64(deployDur.Milliseconds()))
+	}
+
+	return &actions.ActionResult{
+		Message: &actions.ResultMessage{
+			Header: fmt.Sprintf(
+				"Your application was provisioned and deployed to Azure in %s.",
+				ux.DurationAsText(since(startTime)),
+			),
+			FollowUp: phaseTimingBreakdown(result.Steps),
+		},
+	}, nil
+}
+
+// Plan builds the unified `azd up` graph without executing any of its steps
+// and writes it to w in the given format, including each step's dependencies,
+// tags and critical-path priority. Unlike [UpGraphAction.Run], it neither
+// initializes the project nor installs service target tools.
+func (u *UpGraphAction) Plan(
+	ctx context.Context,
+	layers []provisioning.Options,
+	deployFlags *DeployFlags,
+	format exegraph.ExportFormat,
+	w io.Writer,
+) error {
+	stableServices, err := u.importManager.ServiceStableFiltered(ctx, u.projectConfig, "", u.env.Getenv)
+	if err != nil {
+		return fmt.Errorf("enumerating services: %w", err)
+	}
+
+	deployTimeout, err := resolveDeployTimeout(deployFlags)
+	if err != nil {
+		return err
+	}
+
+	g, _, err := u.buildGraph(ctx, layers, stableServices, deployTimeout, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := g.Export(w, format); err != nil {
+		return fmt.Errorf("exporting execution graph: %w", err)
+	}
+	return nil
+}
+
+// buildGraph analyzes provision layer dependencies and builds the unified
+// `azd up` graph described on [UpGraphAction] without running it.
+// onPhaseProgress, when non-nil, receives intra-phase progress from service
+// steps. The returned state collects deploy results and service contexts
+// while the graph executes.
+func (u *UpGraphAction) buildGraph(
+	ctx context.Context,
+	layers []provisioning.Options,
+	stableServices []*project.ServiceConfig,
+	deployTimeout time.Duration,
+	onPhaseProgress func(serviceName string, phase deployPhase, detail string),
+) (*exegraph.Graph, *deployGraphState, error) {
+	// Analyze provision layer dependencies. Empty layers → empty graph.
+	var layerDeps *bicep.LayerDependencies
+	if len(layers) > 0 {
+		var err error
+		layerDeps, err = bicep.AnalyzeLayerDependencies(ctx, layers, u.projectConfig.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("analyzing layer dependencies: %w", err)
+		}
+	}
+
 	g := exegraph.NewGraph()
 	safeCon := &syncConsole{Console: u.console}
 	var envMu sync.Mutex
@@ -238,7 +516,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", preProvisionHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", preProvisionHookStep, err)
 	}
 
 	// ── provision layers ── depend on cmdhook-preprovision + bicep-inferred
@@ -247,7 +525,7 @@ func (u *UpGraphAction) Run(
 		g, layers, layerDeps, preProvisionHookStep, safeCon, &envMu,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// ── cmdhook-postprovision ── fans in from all provision sinks (or from
@@ -266,7 +544,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", postProvisionHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", postProvisionHookStep, err)
 	}
 
 	// ── cmdhook-predeploy ── chained after cmdhook-postprovision.
@@ -280,7 +558,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", preDeployHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", preDeployHookStep, err)
 	}
 
 	// ── service steps (package / publish / deploy) + deploy events ──
@@ -306,7 +584,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", prePackageHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", prePackageHookStep, err)
 	}
 
 	// ── event-prepackage ── fires ProjectEventPackage pre-handlers
@@ -324,25 +602,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", prePackageEventStep, err)
-	}
-
-	// Create a deploy progress tracker so users see real-time feedback
-	// during the package → publish → deploy phase (after provisioning).
-	// In JSON output mode or when no writer is available, skip the tracker.
-	var deployTracker *deployProgressTracker
-	if w := u.console.GetWriter(); u.formatter.Kind() != output.JsonFormat && w != nil {
-		serviceNames := make([]string, len(stableServices))
-		for i, svc := range stableServices {
-			serviceNames[i] = svc.Name
-		}
-		deployTracker = newDeployProgressTracker(w, u.console.IsSpinnerInteractive(), serviceNames)
-	}
-
-	updateDeployProgress := func(svcName string, phase deployPhase, detail string) {
-		if deployTracker != nil {
-			deployTracker.Update(svcName, phase, detail)
-		}
+		return nil, nil, fmt.Errorf("building %s step: %w", prePackageEventStep, err)
 	}
 
 	handles, err := addServiceStepsToGraph(g, serviceGraphOptions{
@@ -357,13 +617,12 @@ func (u *UpGraphAction) Run(
 		onDeployTimeout: func(cbCtx context.Context, svc *project.ServiceConfig) {
 			safeCon.MessageUxItem(cbCtx, deployTimeoutWarning(svc.Name, deployTimeout))
 		},
-		buildGateKey: aspireBuildGateKey,
-		onPhaseProgress: func(svcName string, phase deployPhase, detail string) {
-			updateDeployProgress(svcName, phase, detail)
-		},
+		buildGateKey:    aspireBuildGateKey,
+		onPhaseProgress: onPhaseProgress,
+		fingerprints:    serviceFingerprints(stableServices),
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// ── event-postpackage ── fans in from all package steps. Mirrors the
@@ -386,7 +645,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", postPackageEventStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", postPackageEventStep, err)
 	}
 
 	// ── cmdhook-postpackage ── shell hook after the post-event. Gates
@@ -403,7 +662,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", postPackageHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", postPackageHookStep, err)
 	}
 
 	// ── event-predeploy ── depends on cmdhook-predeploy + cmdhook-postpackage
@@ -426,7 +685,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", preDeployEventStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", preDeployEventStep, err)
 	}
 
 	// ── event-postdeploy ── depends on all deploy steps.
@@ -448,7 +707,7 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", postDeployEventStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", postDeployEventStep, err)
 	}
 
 	// ── cmdhook-postdeploy ── last; depends on event-postdeploy.
@@ -462,171 +721,10 @@ func (u *UpGraphAction) Run(
 			)
 		},
 	}); err != nil {
-		return nil, fmt.Errorf("building %s step: %w", postDeployHookStep, err)
+		return nil, nil, fmt.Errorf("building %s step: %w", postDeployHookStep, err)
 	}
 
-	// 5. Execute the unified graph.
-	u.console.MessageUxItem(ctx, &ux.MessageTitle{
-		Title:     "Provisioning and deploying (azd up)",
-		TitleNote: "Packaging overlaps with provisioning for faster execution",
-	})
-
-	// Start the deploy progress ticker lazily — only when the first
-	// publish or deploy step begins (not package, which runs silently
-	// in parallel with provisioning). This avoids conflicting with
-	// the provisioning progress display.
-	var (
-		tickerOnce sync.Once
-		stopTicker func()
-	)
-	if deployTracker != nil {
-		stopTicker = func() {} // no-op until started
-	}
-
-	// startDeployTicker is called once (via tickerOnce) when the first publish or deploy
-	// step begins. It starts the progress table ticker and suppresses the console previewer
-	// so that DI-injected ShowPreviewer callers (e.g. ContainerHelper's Docker output)
-	// don't corrupt the progress table display.
-	// Previewer is not paused during the earlier provision + hook phases so that
-	// preprovision/postprovision hook output remains visible (fixes #8237).
-	startDeployTicker := func() {
-		if deployTracker == nil {
-			return
-		}
-		// Pause the previewer before starting the ticker to avoid a window where
-		// the progress table renders while ShowPreviewer is still active.
-		if ps, ok := u.console.(input.PreviewerPauser); ok {
-			ps.PausePreviewer()
-			stop := deployTracker.StartTicker(ctx)
-			stopTicker = func() {
-				stop()
-				ps.ResumePreviewer()
-			}
-		} else {
-			stopTicker = deployTracker.StartTicker(ctx)
-		}
-	}
-
-	opts := u.runOptions()
-	baseOnStepStart := opts.OnStepStart
-	baseOnStepDone := opts.OnStepDone
-
-	opts.OnStepStart = func(stepName string) {
-		if baseOnStepStart != nil {
-			baseOnStepStart(stepName)
-		}
-		// Update deploy progress tracker for service steps.
-		// Packaging runs in parallel with provisioning, so only update the
-		// data model silently. Start the visual ticker when the first
-		// publish or deploy step begins — these gate on provision completion,
-		// avoiding conflicts with the provisioning progress display.
-		if svc, ok := strings.CutPrefix(stepName, "package-"); ok {
-			updateDeployProgress(svc, phasePackaging, "")
-		} else if svc, ok := strings.CutPrefix(stepName, "publish-"); ok {
-			tickerOnce.Do(startDeployTicker)
-			updateDeployProgress(svc, phasePublish, "")
-		} else if svc, ok := strings.CutPrefix(stepName, "deploy-"); ok {
-			tickerOnce.Do(startDeployTicker)
-			updateDeployProgress(svc, phaseDeploying, "")
-		}
-	}
-	opts.OnStepDone = func(stepName string, err error) {
-		if baseOnStepDone != nil {
-			baseOnStepDone(stepName, err)
-		}
-		// Update deploy progress tracker on step completion.
-		if err != nil {
-			phase := phaseFailed
-			detail := err.Error()
-			switch {
-			case exegraph.IsStepSkipped(err):
-				phase = phaseSkipped
-				detail = ""
-			case errors.Is(err, context.Canceled):
-				phase = phaseSkipped
-				detail = "canceled"
-			}
-			for _, prefix := range []string{"deploy-", "publish-", "package-"} {
-				if svc, ok := strings.CutPrefix(stepName, prefix); ok {
-					updateDeployProgress(svc, phase, detail)
-					return
-				}
-			}
-		}
-		if svc, ok := strings.CutPrefix(stepName, "deploy-"); ok {
-			updateDeployProgress(svc, phaseDone, "")
-		}
-	}
-
-	result := exegraph.RunWithResult(ctx, g, opts)
-
-	// Stop the progress ticker and render a final summary table.
-	if stopTicker != nil {
-		stopTicker()
-	}
-	if deployTracker != nil && deployTracker.HasActivity() {
-		deployTracker.RenderFinal()
-	}
-
-	// Clean up temporary package artifacts regardless of success/failure.
-	state.CleanupTempArtifacts()
-
-	// Log per-step timing for diagnostics and benchmarking.
-	for _, st := range result.Steps {
-		log.Printf("up-graph step %-30s  %s  %s", st.Name, st.Status, st.Duration.Round(time.Millisecond))
-	}
-	log.Printf("up-graph total: %s (%d steps)", result.TotalDuration.Round(time.Millisecond), len(result.Steps))
-
-	if result.Error != nil {
-		// Only apply provision-specific error wrapping (state dump, OpenAI quota
-		// hints, Responsible-AI suggestions) when an actual provision-tagged
-		// step failed. For package/publish/deploy/hook failures, surface the
-		// underlying error verbatim so the message matches the legacy phase
-		// shape (e.g., `azd package` / `azd deploy` errors).
-		if provisionStepFailed(result) {
-			return nil, wrapProvisionError(ctx, result.Error, provisionErrorDeps{
-				console:          u.console,
-				formatter:        u.formatter,
-				writer:           u.writer,
-				provisionManager: u.provisionManager,
-				portalUrlBase:    u.portalUrlBase,
-			})
-		}
-		return nil, result.Error
-	}
-
-	// Display service endpoint artifacts collected during deploy steps.
-	for _, svc := range stableServices {
-		if dr := state.GetResult(svc.Name); dr != nil && len(dr.Artifacts) > 0 {
-			u.console.MessageUxItem(ctx, dr.Artifacts)
-		}
-	}
-
-	// 6. Finalize: invalidate env cache.
-	if cacheErr := u.envManager.InvalidateEnvCache(ctx, u.env.Name()); cacheErr != nil {
-		log.Printf("warning: failed to invalidate state cache: %v", cacheErr)
-	}
-
-	// Emit per-phase duration telemetry for backend performance tracking.
-	totalMs := since(startTime).Milliseconds()
-	tracing.SetUsageAttributes(fields.PerfTotalDurationMs.Int64(totalMs))
-	provDur, deployDur := phaseDurations(result.Steps)
-	if provDur > 0 {
-		tracing.SetUsageAttributes(fields.PerfProvisionDurationMs.Int64(provDur.Milliseconds()))
-	}
-	if deployDur > 0 {
-		tracing.SetUsageAttributes(fields.PerfDeployDurationMs.Int64(deployDur.Milliseconds()))
-	}
-
-	return &actions.ActionResult{
-		Message: &actions.ResultMessage{
-			Header: fmt.Sprintf(
-				"Your application was provisioned and deployed to Azure in %s.",
-				ux.DurationAsText(since(startTime)),
-			),
-			FollowUp: phaseTimingBreakdown(result.Steps),
-		},
-	}, nil
+	return g, state, nil
 }
 
 // phaseTimingBreakdown computes wall-clock durations for provisioning and deploying phases
@@ -799,11 +897,24 @@ func (u *UpGraphAction) addProvisionSteps(
 			deps = append(deps, stepNames[depIdx])
 		}
 
+		// Checkpoint each layer under a hash of its module directory, which
+		// exegraph only computes when checkpointing or resuming. Layer outputs
+		// are already persisted to the environment, so no step output needs
+		// to be recorded.
+		layerPath := layers[i].AbsolutePath(u.projectConfig.Path)
+		layerStepName := stepNames[i]
+		layerCheckpoint := &exegraph.StepCheckpoint{
+			Fingerprint: func() (string, error) {
+				return stepFingerprint(layerPath, layerStepName)
+			},
+		}
+
 		layerIdx := i
 		if err := g.AddStep(&exegraph.Step{
-			Name:      stepNames[i],
-			DependsOn: deps,
-			Tags:      []string{"provision"},
+			Name:       stepNames[i],
+			DependsOn:  deps,
+			Tags:       []string{"provision"},
+			Checkpoint: layerCheckpoint,
 			Action: func(ctx context.Context) error {
 				return provisionSingleLayer(
 					ctx, provDeps, layers[layerIdx],
@@ -883,6 +994,36 @@ func (u *UpGraphAction) runOptions() exegraph.RunOptions {
 	return opts
 }
 
+// serviceFingerprints returns the checkpoint fingerprint of each service: a
+// hash of the service source tree, its name and host. The hash is computed on
+// first use, which exegraph defers until the service is checkpointed or
+// resumed, and shared by the steps of the service.
+func serviceFingerprints(services []*project.ServiceConfig) map[string]func() (string, error) {
+	fingerprints := make(map[string]func() (string, error), len(services))
+	for _, svc := range services {
+		fingerprints[svc.Name] = sync.OnceValues(func() (string, error) {
+			return stepFingerprint(svc.Path(), svc.Name, string(svc.Host))
+		})
+	}
+	return fingerprints
+}
+
+// checkpointedStepDone reports whether at least one checkpointed step
+// completed during the run, i.e. whether `azd up --resume` would have
+// anything to skip.
+func checkpointedStepDone(g *exegraph.Graph, result *exegraph.RunResult) bool {
+	checkpointed := make(map[string]bool, g.Len())
+	for _, s := range g.Steps() {
+		checkpointed[s.Name] = s.Checkpoint != nil
+	}
+	for _, st := range result.Steps {
+		if st.Status == exegraph.StepDone && checkpointed[st.Name] {
+			return true
+		}
+	}
+	return false
+}
+
 // provisionStepFailed reports whether any step tagged "provision" ended in
 // StepFailed. Used to scope provision-specific error wrapping (state dump,
 // OpenAI quota hint, Responsible-AI suggestions) to actual provision
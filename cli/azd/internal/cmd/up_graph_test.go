@@ -4,10 +4,16 @@
 package cmd
 
 import (
+	"bytes"
 	"testing"
 	"time"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/project"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPhaseTimingBreakdown(t *testing.T) {
@@ -83,3 +89,40 @@ func TestPhaseTimingBreakdown(t *testing.T) {
 		})
 	}
 }
+
+func TestUpGraphAction_BuildGraph(t *testing.T) {
+	t.Parallel()
+	projectConfig := &project.ProjectConfig{Path: t.TempDir()}
+	u := &UpGraphAction{
+		projectConfig:  projectConfig,
+		env:            environment.NewWithValues("dev", nil),
+		console:        mockinput.NewMockConsole(),
+		serviceManager: &stubServiceManager{},
+	}
+	services := []*project.ServiceConfig{{Name: "api", Project: projectConfig, RelativePath: "src/api"}}
+
+	g, _, err := u.buildGraph(t.Context(), nil, services, time.Minute, nil)
+	require.NoError(t, err)
+
+	plan, err := g.Plan()
+	require.NoError(t, err)
+	byName := map[string]exegraph.PlannedStep{}
+	for _, s := range plan {
+		byName[s.Name] = s
+	}
+
+	// Zero layers: cmdhook-postprovision gates directly on cmdhook-preprovision.
+	assert.Equal(t, []string{"cmdhook-preprovision"}, byName["cmdhook-postprovision"].DependsOn)
+	assert.Contains(t, byName["package-api"].DependsOn, "event-prepackage")
+	assert.Contains(t, byName["event-predeploy"].DependsOn, "package-api")
+	assert.Equal(t, []string{"event-postdeploy"}, byName["cmdhook-postdeploy"].DependsOn)
+
+	// cmdhook-prepackage is the single root, so it ranks first and every
+	// other step transitively depends on it.
+	assert.Equal(t, 1, byName["cmdhook-prepackage"].Rank)
+	assert.Equal(t, len(plan)-1, byName["cmdhook-prepackage"].Priority)
+
+	var buf bytes.Buffer
+	require.NoError(t, g.Export(&buf, exegraph.ExportMermaid))
+	assert.Contains(t, buf.String(), "deploy-api")
+}
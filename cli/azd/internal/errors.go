@@ -58,6 +58,11 @@ var (
 	ErrPreviewMultipleLayers    = errors.New("--preview cannot be used when provisioning multiple layers")
 )
 
+// Up command errors
+var (
+	ErrResumeWithCustomWorkflow = errors.New("--resume cannot be used with a custom 'up' workflow")
+)
+
 // Init command errors
 var (
 	ErrBranchRequiresTemplate = errors.New(
@@ -97,6 +102,16 @@ var (
 	ErrExtensionTokenFailed  = errors.New("failed to generate extension token")
 )
 
+// Environment history errors
+var (
+	ErrRevisionNotFound = errors.New("environment revision not found")
+)
+
+// Workflow errors
+var (
+	ErrWorkflowNotFound = errors.New("workflow not found in project")
+)
+
 // Service/resource errors
 var (
 	ErrServiceNotFound       = errors.New("service not found in project")
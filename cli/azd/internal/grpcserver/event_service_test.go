@@ -133,6 +133,10 @@ func (m *noOpEnvironmentManager) Delete(ctx context.Context, name string) error
 	return nil
 }
 
+func (m *noOpEnvironmentManager) History(ctx context.Context, name string) ([]*environment.Revision, error) {
+	return nil, nil
+}
+
 func (m *noOpEnvironmentManager) EnvPath(env *environment.Environment) string {
 	return ""
 }
@@ -25,6 +25,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
@@ -48,6 +49,7 @@ type Initializer struct {
 	dotnetCli      *dotnet.Cli
 	features       *alpha.FeatureManager
 	lazyEnvManager *lazy.Lazy[environment.Manager]
+	ociPuller      *templates.OciTemplatePuller
 }
 
 func NewInitializer(
@@ -56,6 +58,7 @@ func NewInitializer(
 	dotnetCli *dotnet.Cli,
 	features *alpha.FeatureManager,
 	lazyEnvManager *lazy.Lazy[environment.Manager],
+	ociPuller *templates.OciTemplatePuller,
 ) *Initializer {
 	return &Initializer{
 		console:        console,
@@ -63,10 +66,12 @@ func NewInitializer(
 		lazyEnvManager: lazyEnvManager,
 		dotnetCli:      dotnetCli,
 		features:       features,
+		ociPuller:      ociPuller,
 	}
 }
 
-// Initializes a local repository in the project directory from a remote repository or local template directory.
+// Initializes a local repository in the project directory from a remote repository, local template directory or
+// template pushed as an OCI artifact.
 //
 // A confirmation prompt is displayed for any existing files to be overwritten.
 func (i *Initializer) Initialize(
@@ -126,6 +131,8 @@ func (i *Initializer) Initialize(
 		if err == nil {
 			filesWithExecPerms, err = findExecutableFiles(staging)
 		}
+	} else if oci.IsReference(templateUrl) {
+		filesWithExecPerms, err = i.pullOciTemplate(ctx, templateUrl, templateBranch, staging)
 	} else {
 		filesWithExecPerms, err = i.fetchCode(ctx, templateUrl, templateBranch, staging)
 	}
@@ -216,6 +223,24 @@ func (i *Initializer) fetchCode(
 // whether it is a directory or a file (as in git worktrees/submodules). Unlike fetchCode which uses
 // git clone (and only includes committed content), this copies the full working tree including
 // uncommitted changes — useful for local template development.
+// pullOciTemplate extracts the template pushed as an OCI artifact at templateUrl into destination.
+func (i *Initializer) pullOciTemplate(
+	ctx context.Context,
+	templateUrl string,
+	templateBranch string,
+	destination string) (executableFilePaths []string, err error) {
+	if templateBranch != "" {
+		return nil, fmt.Errorf(
+			"template '%s' is an OCI artifact, which has no branches; reference a tag or digest instead", templateUrl)
+	}
+
+	if err := i.ociPuller.Pull(ctx, templateUrl, destination); err != nil {
+		return nil, err
+	}
+
+	return findExecutableFiles(destination)
+}
+
 func (i *Initializer) copyLocalTemplate(source, destination string) error {
 	// Verify the source is still a real directory and not a symlink.
 	// This mitigates TOCTOU attacks where the source directory could be replaced
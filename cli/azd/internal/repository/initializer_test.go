@@ -4,10 +4,13 @@
 package repository
 
 import (
+	"archive/tar"
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -33,6 +36,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
 	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockoci"
 	cp "github.com/otiai10/copy"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -69,6 +73,7 @@ func Test_Initializer_Initialize(t *testing.T) {
 				dotnet.NewCli(mockContext.CommandRunner),
 				mockContext.AlphaFeaturesManager,
 				lazy.From[environment.Manager](mockEnv),
+				nil,
 			)
 			err := i.Initialize(*mockContext.Context, azdCtx, &templates.Template{RepositoryPath: "local"}, "")
 			require.NoError(t, err)
@@ -115,6 +120,7 @@ func Test_Initializer_DevCenter(t *testing.T) {
 		dotnet.NewCli(mockContext.CommandRunner),
 		mockContext.AlphaFeaturesManager,
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 	err := i.Initialize(*mockContext.Context, azdCtx, template, "")
 	require.NoError(t, err)
@@ -187,6 +193,7 @@ func Test_Initializer_InitializeWithOverwritePrompt(t *testing.T) {
 				dotnet.NewCli(mockRunner),
 				alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 				lazy.From[environment.Manager](mockEnv),
+				nil,
 			)
 			err = i.Initialize(t.Context(), azdCtx, &templates.Template{RepositoryPath: "local"}, "")
 			require.NoError(t, err)
@@ -392,7 +399,7 @@ func Test_Initializer_WriteCoreAssets(t *testing.T) {
 			i := NewInitializer(
 				console, git.NewCli(realRunner), nil,
 				alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
-				lazy.From[environment.Manager](envManager))
+				lazy.From[environment.Manager](envManager), nil)
 			err := i.writeCoreAssets(t.Context(), azdCtx)
 			require.NoError(t, err)
 
@@ -841,6 +848,7 @@ func Test_Initializer_Initialize_LocalTemplate(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -869,6 +877,68 @@ func Test_Initializer_Initialize_LocalTemplate(t *testing.T) {
 	require.DirExists(t, azdCtx.EnvironmentDirectory())
 }
 
+func Test_Initializer_Initialize_OciTemplate(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	var archive bytes.Buffer
+	gzWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzWriter)
+	for _, file := range []struct {
+		name    string
+		mode    int64
+		content string
+	}{
+		{"azure.yaml", 0644, "name: oci-template\n"},
+		{"script/test.sh", 0755, "echo 'Hello world'\n"},
+	} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     file.name,
+			Mode:     file.mode,
+			Size:     int64(len(file.content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tarWriter.Write([]byte(file.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzWriter.Close())
+
+	registry := mockoci.NewRegistry(t)
+	templateRef := registry.Push(t, "azd/templates/oci-template", "1.0.0", mockoci.Layer{
+		MediaType: templates.TemplateMediaType,
+		Content:   archive.Bytes(),
+	})
+
+	projectDir := t.TempDir()
+	azdCtx := azdcontext.NewAzdContextWithDirectory(projectDir)
+
+	realRunner := exec.NewCommandRunner(nil)
+
+	mockEnv := &mockenv.MockEnvManager{}
+	mockEnv.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	i := NewInitializer(
+		mockinput.NewMockConsole(),
+		git.NewCli(realRunner),
+		dotnet.NewCli(realRunner),
+		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
+		lazy.From[environment.Manager](mockEnv),
+		templates.NewOciTemplatePuller(nil, http.DefaultClient),
+	)
+
+	err := i.Initialize(t.Context(), azdCtx, &templates.Template{RepositoryPath: templateRef}, "main")
+	require.ErrorContains(t, err, "has no branches")
+
+	err = i.Initialize(t.Context(), azdCtx, &templates.Template{RepositoryPath: templateRef}, "")
+	require.NoError(t, err)
+
+	require.FileExists(t, azdCtx.ProjectPath())
+	require.FileExists(t, filepath.Join(projectDir, ".gitignore"))
+	if runtime.GOOS != "windows" {
+		verifyExecutableFilePermissions(t, t.Context(), i.gitCli, projectDir, []string{"script/test.sh"})
+	}
+}
+
 func Test_Initializer_Initialize_LocalTemplateWithGitDir(t *testing.T) {
 	t.Parallel()
 	// Create a local template that also has a .git directory
@@ -896,6 +966,7 @@ func Test_Initializer_Initialize_LocalTemplateWithGitDir(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -969,6 +1040,7 @@ func Test_Initializer_Initialize_LocalTemplateRespectsGitignore(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1030,6 +1102,7 @@ func Test_Initializer_Initialize_LocalTemplateGitignoreNegation(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1071,6 +1144,7 @@ func Test_Initializer_Initialize_LocalTemplateGitFile(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1128,6 +1202,7 @@ func Test_Initializer_Initialize_LocalTemplateNestedGitignore(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1160,6 +1235,7 @@ func Test_Initializer_Initialize_LocalTemplateOverlapRejected(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	t.Run("SameDirectory", func(t *testing.T) {
@@ -1454,6 +1530,7 @@ func Test_Initializer_Initialize_AzdIgnoreRemoteTemplate(t *testing.T) {
 		dotnet.NewCli(mockRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1516,6 +1593,7 @@ func Test_Initializer_Initialize_AzdIgnoreLocalTemplate(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1575,6 +1653,7 @@ func Test_Initializer_Initialize_AzdIgnoreNegation(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1636,6 +1715,7 @@ func Test_Initializer_Initialize_AzdIgnoreWithGitignore(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1698,6 +1778,7 @@ func Test_Initializer_Initialize_AzdIgnoreSurvivesGitignore(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](mockEnv),
+		nil,
 	)
 
 	err := i.Initialize(t.Context(), azdCtx, &templates.Template{
@@ -1955,6 +2036,7 @@ func Test_copyLocalTemplate_SymlinksSkipped(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+		nil,
 	)
 
 	err := i.copyLocalTemplate(sourceDir, destDir)
@@ -1990,6 +2072,7 @@ func Test_copyLocalTemplate_SourceIsSymlink(t *testing.T) {
 		dotnet.NewCli(realRunner),
 		alpha.NewFeaturesManagerWithConfig(config.NewEmptyConfig()),
 		lazy.From[environment.Manager](&mockenv.MockEnvManager{}),
+		nil,
 	)
 
 	// copyLocalTemplate should reject a symlink as source (TOCTOU mitigation)
@@ -226,6 +226,10 @@ func HasAppService(services []ServiceSpec) bool {
 	return hasHostType(services, AppServiceKind)
 }
 
+func HasFunctionApp(services []ServiceSpec) bool {
+	return hasHostType(services, FunctionAppKind)
+}
+
 func IsACA(host HostKind) bool {
 	return host == ContainerAppKind
 }
@@ -234,6 +238,10 @@ func IsAppService(host HostKind) bool {
 	return host == AppServiceKind
 }
 
+func IsFunctionApp(host HostKind) bool {
+	return host == FunctionAppKind
+}
+
 func hasHostType(services []ServiceSpec, host HostKind) bool {
 	for _, service := range services {
 		if service.Host == host {
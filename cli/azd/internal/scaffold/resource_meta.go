@@ -77,6 +77,30 @@ var Resources = []ResourceMeta{
 		ResourceType: "Microsoft.App/managedEnvironments",
 		ApiVersion:   "2023-05-01",
 	},
+	{
+		ResourceType:      "Microsoft.AppConfiguration/configurationStores",
+		ApiVersion:        "2024-05-01",
+		StandardVarPrefix: "AZURE_APP_CONFIGURATION",
+		Variables: map[string]string{
+			"endpoint": "${.properties.endpoint}",
+		},
+		RoleAssignments: RoleAssignments{
+			Read: []RoleAssignment{
+				{
+					Name:               "DataReader",
+					RoleDefinitionName: "App Configuration Data Reader",
+					RoleDefinitionId:   "516239f1-63e1-4d78-a4de-a74fb236a071",
+				},
+			},
+			Write: []RoleAssignment{
+				{
+					Name:               "DataOwner",
+					RoleDefinitionName: "App Configuration Data Owner",
+					RoleDefinitionId:   "5ae67dd6-50cb-40e7-96ff-dc2bfa4b606b",
+				},
+			},
+		},
+	},
 	{
 		ResourceType:      "Microsoft.Cache/redis",
 		ApiVersion:        "2024-03-01",
@@ -179,6 +203,24 @@ var Resources = []ResourceMeta{
 			"url": "${vault.mongodb-url}",
 		},
 	},
+	{
+		ResourceType:      "Microsoft.EventGrid/topics",
+		ApiVersion:        "2022-06-15",
+		StandardVarPrefix: "AZURE_EVENT_GRID",
+		Variables: map[string]string{
+			"name":     "${.name}",
+			"endpoint": "${.properties.endpoint}",
+		},
+		RoleAssignments: RoleAssignments{
+			Write: []RoleAssignment{
+				{
+					Name:               "DataSender",
+					RoleDefinitionName: "EventGrid Data Sender",
+					RoleDefinitionId:   "d5a91429-5739-47e2-a06b-3470a27159e7",
+				},
+			},
+		},
+	},
 	{
 		ResourceType:      "Microsoft.EventHub/namespaces",
 		ApiVersion:        "2024-01-01",
@@ -237,6 +279,58 @@ var Resources = []ResourceMeta{
 			},
 		},
 	},
+	{
+		ResourceType:      "Microsoft.SignalRService/signalR",
+		ApiVersion:        "2024-03-01",
+		StandardVarPrefix: "AZURE_SIGNALR",
+		Variables: map[string]string{
+			"name":     "${.name}",
+			"endpoint": "https://${.properties.hostName}",
+		},
+		RoleAssignments: RoleAssignments{
+			Write: []RoleAssignment{
+				{
+					Name:               "AppServer",
+					RoleDefinitionName: "SignalR App Server",
+					RoleDefinitionId:   "420fcaa2-552c-430f-98ca-3264be4806c7",
+				},
+			},
+		},
+	},
+	{
+		ResourceType:      "Microsoft.SignalRService/webPubSub",
+		ApiVersion:        "2024-03-01",
+		StandardVarPrefix: "AZURE_WEB_PUBSUB",
+		Variables: map[string]string{
+			"name":     "${.name}",
+			"endpoint": "https://${.properties.hostName}",
+		},
+		RoleAssignments: RoleAssignments{
+			Write: []RoleAssignment{
+				{
+					Name:               "ServiceOwner",
+					RoleDefinitionName: "Web PubSub Service Owner",
+					RoleDefinitionId:   "12cf5a90-567b-43ae-8102-96cf46c7d9b4",
+				},
+			},
+		},
+	},
+	{
+		ResourceType:      "Microsoft.Sql/servers/databases",
+		ApiVersion:        "2023-08-01-preview",
+		StandardVarPrefix: "AZURE_SQL",
+		ParentForEval:     "Microsoft.Sql/servers",
+		//nolint:gosec // G101: template variable references, not hardcoded credentials
+		Variables: map[string]string{
+			"database": "${spec.name}",
+			"host":     "${.properties.fullyQualifiedDomainName}",
+			"username": "${.properties.administratorLogin}",
+			"port":     "1433",
+			"password": "${vault.sql-password}",
+			"connectionString": "Server=tcp:${host},${port};Database=${database};User ID=${username};" +
+				"Password=${password};Encrypt=true;Connection Timeout=30",
+		},
+	},
 	{
 		ResourceType:      "Microsoft.Storage/storageAccounts",
 		ApiVersion:        "2023-05-01",
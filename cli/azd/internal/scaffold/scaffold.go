@@ -35,8 +35,10 @@ func Load() (*template.Template, error) {
 		"formatParam":      FormatParameter,
 		"hasACA":           HasACA,
 		"hasAppService":    HasAppService,
+		"hasFunctionApp":   HasFunctionApp,
 		"isACA":            IsACA,
 		"isAppService":     IsAppService,
+		"isFunctionApp":    IsFunctionApp,
 	}
 
 	t, err := template.New("templates").
@@ -221,7 +223,7 @@ func executeToFS(targetFS *memfs.FS, tmpl *template.Template, name string, path
 }
 
 func preExecExpand(spec *InfraSpec) {
-	// postgres and mysql requires specific password seeding parameters
+	// postgres, mysql and sql server require specific password seeding parameters
 	if spec.DbPostgres != nil {
 		spec.Parameters = append(spec.Parameters,
 			Parameter{
@@ -240,6 +242,15 @@ func preExecExpand(spec *InfraSpec) {
 				Secret: true,
 			})
 	}
+	if spec.DbSqlServer != nil {
+		spec.Parameters = append(spec.Parameters,
+			Parameter{
+				Name:   "sqlDatabasePassword",
+				Value:  "$(secretOrRandomPassword ${AZURE_KEY_VAULT_NAME} sql-password)",
+				Type:   "string",
+				Secret: true,
+			})
+	}
 
 	for _, svc := range spec.Services {
 		if svc.Host == ContainerAppKind {
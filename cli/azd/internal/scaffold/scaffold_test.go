@@ -85,6 +85,21 @@ func TestExecInfra(t *testing.T) {
 				},
 			},
 		},
+		{
+			"Function App Python",
+			InfraSpec{
+				Services: []ServiceSpec{
+					{
+						Name: "func",
+						Host: "functionapp",
+						Runtime: &RuntimeInfo{
+							Type:    "python",
+							Version: "3.12",
+						},
+					},
+				},
+			},
+		},
 		{
 			"API and web",
 			InfraSpec{
@@ -148,11 +163,18 @@ func TestExecInfra(t *testing.T) {
 				DbCosmos: &DatabaseCosmos{
 					DatabaseName: "cosmos",
 				},
-				DbRedis:        &DatabaseRedis{},
+				DbRedis: &DatabaseRedis{},
+				DbSqlServer: &DatabaseSqlServer{
+					DatabaseName: "sqldb",
+				},
 				ServiceBus:     &ServiceBus{},
 				EventHubs:      &EventHubs{},
+				EventGrid:      &EventGrid{Name: "eventgrid"},
+				SignalR:        &SignalR{Name: "signalr"},
+				WebPubSub:      &WebPubSub{Name: "webpubsub"},
 				StorageAccount: &StorageAccount{},
 				KeyVault:       &KeyVault{},
+				AppConfig:      &AppConfig{Name: "appconfig"},
 				AISearch:       &AISearch{},
 				Services: []ServiceSpec{
 					{
@@ -180,10 +202,17 @@ func TestExecInfra(t *testing.T) {
 						DbMySql: &DatabaseReference{
 							DatabaseName: "mysqldb",
 						},
+						DbSqlServer: &DatabaseReference{
+							DatabaseName: "sqldb",
+						},
 						ServiceBus:       &ServiceBus{},
 						EventHubs:        &EventHubs{},
+						EventGrid:        &EventGridReference{},
+						SignalR:          &SignalRReference{},
+						WebPubSub:        &WebPubSubReference{},
 						StorageAccount:   &StorageReference{},
 						KeyVault:         &KeyVaultReference{},
+						AppConfig:        &AppConfigReference{},
 						AISearch:         &AISearchReference{},
 						AiFoundryProject: &AiFoundrySpec{},
 						Host:             "containerapp",
@@ -208,6 +237,24 @@ func TestExecInfra(t *testing.T) {
 							Type:    "python",
 							Version: "3.11",
 						},
+						DbSqlServer: &DatabaseReference{
+							DatabaseName: "sqldb",
+						},
+						AppConfig: &AppConfigReference{},
+						SignalR:   &SignalRReference{},
+					},
+					{
+						Name: "func",
+						Host: "functionapp",
+						Runtime: &RuntimeInfo{
+							Type:    "node",
+							Version: "20",
+						},
+						DbSqlServer: &DatabaseReference{
+							DatabaseName: "sqldb",
+						},
+						EventGrid: &EventGridReference{},
+						WebPubSub: &WebPubSubReference{},
 					},
 				},
 			},
@@ -24,13 +24,20 @@ type InfraSpec struct {
 	DbCosmosMongo *DatabaseCosmosMongo
 	DbCosmos      *DatabaseCosmos
 	DbRedis       *DatabaseRedis
+	DbSqlServer   *DatabaseSqlServer
 
 	// Key vault
 	KeyVault *KeyVault
 
+	// App configuration store
+	AppConfig *AppConfig
+
 	// Messaging services
 	ServiceBus *ServiceBus
 	EventHubs  *EventHubs
+	EventGrid  *EventGrid
+	SignalR    *SignalR
+	WebPubSub  *WebPubSub
 
 	// Storage account
 	StorageAccount *StorageAccount
@@ -76,6 +83,10 @@ type CosmosSqlDatabaseContainer struct {
 type DatabaseRedis struct {
 }
 
+type DatabaseSqlServer struct {
+	DatabaseName string
+}
+
 // AIModel represents a deployed, ready to use AI model.
 type AIModel struct {
 	Name  string
@@ -123,6 +134,22 @@ type EventHubs struct {
 type KeyVault struct {
 }
 
+type AppConfig struct {
+	Name string
+}
+
+type EventGrid struct {
+	Name string
+}
+
+type SignalR struct {
+	Name string
+}
+
+type WebPubSub struct {
+	Name string
+}
+
 type StorageAccount struct {
 	Containers []string
 }
@@ -134,7 +161,7 @@ type ServiceSpec struct {
 
 	Env map[string]string
 
-	// App Service specific configuration
+	// App Service and Function App specific configuration
 	Runtime        *RuntimeInfo
 	StartupCommand string
 
@@ -153,15 +180,21 @@ type ServiceSpec struct {
 	DbCosmosMongo *DatabaseReference
 	DbCosmos      *DatabaseReference
 	DbRedis       *DatabaseReference
+	DbSqlServer   *DatabaseReference
 
 	StorageAccount *StorageReference
 
+	AppConfig *AppConfigReference
+
 	// AI model connections
 	AIModels []AIModelReference
 
 	// Messaging services
 	ServiceBus *ServiceBus
 	EventHubs  *EventHubs
+	EventGrid  *EventGridReference
+	SignalR    *SignalRReference
+	WebPubSub  *WebPubSubReference
 
 	AiFoundryProject *AiFoundrySpec
 
@@ -176,6 +209,7 @@ type HostKind string
 const (
 	AppServiceKind   HostKind = "appservice"
 	ContainerAppKind HostKind = "containerapp"
+	FunctionAppKind  HostKind = "functionapp"
 )
 
 type RuntimeInfo struct {
@@ -212,6 +246,18 @@ type AISearchReference struct {
 type KeyVaultReference struct {
 }
 
+type AppConfigReference struct {
+}
+
+type EventGridReference struct {
+}
+
+type SignalRReference struct {
+}
+
+type WebPubSubReference struct {
+}
+
 type ExistingResource struct {
 	// The unique logical name of the existing resource in the infra scope.
 	Name string
@@ -363,7 +363,7 @@ var (
 		Classification: SystemMetadata,
 		Purpose:        FeatureInsight,
 	}
-	// The type of the hook run scope (project, layer, or service).
+	// The type of the hook run scope (project, layer, service, or workflow).
 	HooksTypeKey = AttributeKey{
 		Key:            attribute.Key("hooks.type"),
 		Classification: SystemMetadata,
@@ -787,6 +787,15 @@ var (
 		Purpose:        PerformanceAndHealth,
 		IsMeasurement:  true,
 	}
+
+	// ExeGraphStepAttemptsKey records how many times a step's action ran;
+	// values above one mean the step's retry policy retried it.
+	ExeGraphStepAttemptsKey = AttributeKey{
+		Key:            attribute.Key("exegraph.step.attempts"),
+		Classification: SystemMetadata,
+		Purpose:        PerformanceAndHealth,
+		IsMeasurement:  true,
+	}
 )
 
 // Multi-layer provision related fields. These power telemetry that lets the
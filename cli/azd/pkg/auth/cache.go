@@ -7,11 +7,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"strings"
 	"unicode"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 )
 
 // Known entries from msal cache contract. This is not an exhaustive list.
@@ -143,3 +145,66 @@ type Cache interface {
 }
 
 var errCacheKeyNotFound = errors.New("key not found")
+
+// envelopedData stores both the type of encryption used as well as the encrypted data (as a base64 encoded string),
+// allowing us to change the underlying encryption algorithm as needed (and then understand what we need to do decrypt)
+type envelopedData struct {
+	// The type of encryption that was used to store data.
+	Type encryptionType `json:"type"`
+	// The encrypted data, represented as a Base64 encoded string (using base64.StdEncoding)
+	Data string `json:"data"`
+}
+
+type encryptionType string
+
+// cacheBackendKey is the key we use in config to select the backend that stores the MSAL token cache and the persisted
+// credentials. It is only used on unix, as Windows always encrypts them with CryptProtectData.
+const cacheBackendKey = "auth.cache.backend"
+
+// cacheKeyFileKey is the key we use in config for the path to a file that holds the key which encrypts the caches when
+// the encryptedfile backend is used. The file contains a base64 encoded 256-bit key.
+const cacheKeyFileKey = "auth.cache.keyFile"
+
+type cacheBackend string
+
+const (
+	// cacheBackendFile stores the caches as plaintext files, protected only by file permissions.
+	cacheBackendFile cacheBackend = "file"
+	// cacheBackendSecretService stores the caches in the freedesktop Secret Service (e.g. GNOME Keyring or KWallet).
+	cacheBackendSecretService cacheBackend = "secretservice"
+	// cacheBackendEncryptedFile stores the caches as files encrypted with AES-GCM. The key is read from the file
+	// configured with [cacheKeyFileKey], or kept in the Linux kernel keyring when no key file is configured. Keys in the
+	// kernel keyring do not survive a reboot, so the caches must be filled again, i.e. users log in again, after one.
+	cacheBackendEncryptedFile cacheBackend = "encryptedfile"
+)
+
+// cacheOptions configure where the MSAL token cache and the persisted credentials are stored.
+type cacheOptions struct {
+	Backend cacheBackend
+	KeyFile string
+}
+
+// cacheOptionsFromConfig reads the cache options from the user configuration.
+func cacheOptionsFromConfig(cfg config.Config) (cacheOptions, error) {
+	options := cacheOptions{
+		Backend: cacheBackendFile,
+	}
+
+	if backend, has := cfg.GetString(cacheBackendKey); has && backend != "" {
+		options.Backend = cacheBackend(backend)
+	}
+
+	switch options.Backend {
+	case cacheBackendFile, cacheBackendSecretService, cacheBackendEncryptedFile:
+	default:
+		return cacheOptions{}, fmt.Errorf(
+			"unsupported value '%s' for %s, supported values are: %s, %s, %s",
+			options.Backend, cacheBackendKey, cacheBackendFile, cacheBackendSecretService, cacheBackendEncryptedFile)
+	}
+
+	if keyFile, has := cfg.GetString(cacheKeyFileKey); has {
+		options.KeyFile = keyFile
+	}
+
+	return options, nil
+}
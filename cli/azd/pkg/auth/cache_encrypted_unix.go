@@ -0,0 +1,182 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix
+
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// aesGcmEncryptionType is the encryption type that uses AES-256 in Galois/Counter Mode, with the random nonce
+// prepended to the sealed data.
+const aesGcmEncryptionType encryptionType = "AES-GCM"
+
+// encryptionKeySize is the size of the AES-256 key that encrypts the caches.
+const encryptionKeySize = 32
+
+// aesGcmCache is a Cache that wraps an existing Cache, encrypting and decrypting the cached value with AES-GCM. The key
+// of each entry is authenticated along with its value, so an entry cannot be passed off as another by renaming its file.
+type aesGcmCache struct {
+	aead  cipher.AEAD
+	inner Cache
+}
+
+func newAesGcmCache(key []byte, inner Cache) (*aesGcmCache, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cache cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating cache cipher: %w", err)
+	}
+
+	return &aesGcmCache{
+		aead:  aead,
+		inner: inner,
+	}, nil
+}
+
+func (c *aesGcmCache) Read(key string) ([]byte, error) {
+	val, err := c.inner.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(val) == 0 {
+		return val, nil
+	}
+
+	var data envelopedData
+	if err := json.Unmarshal(val, &data); err != nil {
+		return nil, fmt.Errorf("unmarshalling enveloped data: %w", err)
+	}
+
+	if data.Type != aesGcmEncryptionType {
+		return nil, fmt.Errorf("unsupported encryption type: %s", data.Type)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(data.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding base64 data: %w", err)
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("failed to decrypt data: data is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(key))
+	if err != nil {
+		// The key kept in the kernel keyring does not survive a reboot, after which the data written with the previous
+		// key can no longer be decrypted. Treat the entry as missing so the user is asked to log in again.
+		log.Printf("failed to decrypt cache entry, ignoring it: %v", err)
+		return nil, errCacheKeyNotFound
+	}
+
+	return plaintext, nil
+}
+
+func (c *aesGcmCache) Set(key string, val []byte) error {
+	if len(val) == 0 {
+		return c.inner.Set(key, val)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("generating nonce: %w", err)
+	}
+
+	toStore, err := json.Marshal(envelopedData{
+		Type: aesGcmEncryptionType,
+		Data: base64.StdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, val, []byte(key))),
+	})
+
+	// We never expect the above to fail.
+	if err != nil {
+		panic(fmt.Sprintf("failed to marshal enveloped data: %s", err))
+	}
+
+	return c.inner.Set(key, toStore)
+}
+
+// migratingCache is a Cache that wraps an encrypted Cache, moving the entries written by the plaintext file backend into
+// it. An entry is moved the first time it is read, and the plaintext copy of an entry is removed whenever it is set.
+type migratingCache struct {
+	inner     Cache
+	plaintext *fileCache
+}
+
+func (c *migratingCache) Read(key string) ([]byte, error) {
+	val, err := c.inner.Read(key)
+	if !errors.Is(err, errCacheKeyNotFound) {
+		return val, err
+	}
+
+	val, err = c.plaintext.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("moving plaintext cache entry %q to the encrypted cache", key)
+	if err := c.Set(key, val); err != nil {
+		return nil, fmt.Errorf("migrating plaintext cache: %w", err)
+	}
+
+	return val, nil
+}
+
+func (c *migratingCache) Set(key string, val []byte) error {
+	if err := c.inner.Set(key, val); err != nil {
+		return err
+	}
+
+	if err := c.plaintext.Remove(key); err != nil {
+		return fmt.Errorf("removing plaintext cache: %w", err)
+	}
+
+	return nil
+}
+
+// encryptionKey returns the key that encrypts the caches in root. The key is read from keyFile when it is set, and kept
+// in the kernel keyring otherwise. The kernel keyring is emptied on reboot, after which a new key is generated and the
+// entries encrypted with the previous one are treated as missing, so users log in again.
+func encryptionKey(root string, keyFile string) ([]byte, error) {
+	if keyFile == "" {
+		key, err := kernelKeyringKey("azd-auth-cache:" + root)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"reading the cache encryption key from the kernel keyring: %w. "+
+					"Set %s to the path of a file holding a base64 encoded 256-bit key instead",
+				err, cacheKeyFileKey)
+		}
+
+		return key, nil
+	}
+
+	contents, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("reading the cache encryption key from %s: %w", keyFile, err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(contents)))
+	if err != nil || len(key) != encryptionKeySize {
+		return nil, fmt.Errorf(
+			"the cache encryption key in %s must be a base64 encoded %d-bit key, e.g. generated with "+
+				"'openssl rand -base64 %d'", keyFile, encryptionKeySize*8, encryptionKeySize)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/sys/unix"
+)
+
+// secretServiceName is the service of the secrets azd stores in the freedesktop Secret Service.
+const secretServiceName = "azd"
+
+// secretServiceCache implements Cache by storing the data in the freedesktop Secret Service. Secrets are stored for the
+// [secretServiceName] service, with the path the entry would have in a [fileCache] as the user name.
+type secretServiceCache struct {
+	root   string
+	prefix string
+}
+
+// newSecretServiceCache creates a secretServiceCache, failing when the Secret Service is not available (e.g. in
+// sessions without a D-Bus session bus).
+func newSecretServiceCache(root string, prefix string) (*secretServiceCache, error) {
+	c := &secretServiceCache{
+		root:   root,
+		prefix: prefix,
+	}
+
+	if _, err := keyring.Get(secretServiceName, c.user("")); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return nil, fmt.Errorf(
+			"the Secret Service is not available: %w. Set %s to %s to store the cache in encrypted files instead",
+			err, cacheBackendKey, cacheBackendEncryptedFile)
+	}
+
+	return c, nil
+}
+
+func (c *secretServiceCache) Read(key string) ([]byte, error) {
+	val, err := keyring.Get(secretServiceName, c.user(key))
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil, errCacheKeyNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("reading secret: %w", err)
+	}
+
+	return []byte(val), nil
+}
+
+func (c *secretServiceCache) Set(key string, value []byte) error {
+	if len(value) == 0 {
+		if err := keyring.Delete(secretServiceName, c.user(key)); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+			return fmt.Errorf("deleting secret: %w", err)
+		}
+
+		return nil
+	}
+
+	if err := keyring.Set(secretServiceName, c.user(key), string(value)); err != nil {
+		return fmt.Errorf("writing secret: %w", err)
+	}
+
+	return nil
+}
+
+func (c *secretServiceCache) user(key string) string {
+	return filepath.Join(c.root, c.prefix+key)
+}
+
+// keyPermissions grants all permissions on a key to its possessor and to its user, so the key can be read by the
+// processes of the user that do not possess the user keyring (e.g. in sessions without pam_keyinit).
+const keyPermissions = 0x3f3f0000
+
+// kernelKeyringKey returns the encryption key with the given description from the user keyring of the Linux kernel,
+// generating and adding it when it does not exist. Keys in the user keyring do not survive a reboot.
+func kernelKeyringKey(description string) ([]byte, error) {
+	id, err := unix.KeyctlSearch(unix.KEY_SPEC_USER_KEYRING, "user", description, 0)
+	if errors.Is(err, unix.ENOKEY) {
+		key := make([]byte, encryptionKeySize)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("generating key: %w", err)
+		}
+
+		id, err = unix.AddKey("user", description, key, unix.KEY_SPEC_USER_KEYRING)
+		if err != nil {
+			return nil, fmt.Errorf("adding key: %w", err)
+		}
+
+		if err := unix.KeyctlSetperm(id, keyPermissions); err != nil {
+			return nil, fmt.Errorf("setting key permissions: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("searching key: %w", err)
+	}
+
+	// the key is read back even when it was just added, as another process may have added it concurrently
+	key := make([]byte, encryptionKeySize)
+	size, err := unix.KeyctlBuffer(unix.KEYCTL_READ, id, key, 0)
+	if err != nil {
+		return nil, fmt.Errorf("reading key: %w", err)
+	}
+
+	if size != encryptionKeySize {
+		return nil, fmt.Errorf("unexpected size %d of key %s", size, description)
+	}
+
+	return key, nil
+}
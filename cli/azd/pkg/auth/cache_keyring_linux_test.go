@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package auth
+
+import (
+	"testing"
+
+	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/unix"
+)
+
+func TestEncryptedFileCache_KernelKeyring(t *testing.T) {
+	root := t.TempDir()
+	description := "azd-auth-cache:" + root
+
+	key, err := kernelKeyringKey(description)
+	if err != nil {
+		t.Skipf("kernel keyring not available: %v", err)
+	}
+	t.Cleanup(func() {
+		if id, err := unix.KeyctlSearch(unix.KEY_SPEC_USER_KEYRING, "user", description, 0); err == nil {
+			_, _ = unix.KeyctlInt(unix.KEYCTL_UNLINK, id, unix.KEY_SPEC_USER_KEYRING, 0, 0)
+		}
+	})
+
+	// the key is kept in the keyring
+	again, err := kernelKeyringKey(description)
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	c, err := newCache(root, cacheOptions{Backend: cacheBackendEncryptedFile})
+	require.NoError(t, err)
+
+	data := fixedMarshaller{val: []byte("some data")}
+	require.NoError(t, c.Export(t.Context(), &data, cache.ExportHints{}))
+
+	// the data should be shared across instances.
+	c, err = newCache(root, cacheOptions{Backend: cacheBackendEncryptedFile})
+	require.NoError(t, err)
+
+	var reader fixedMarshaller
+	require.NoError(t, c.Replace(t.Context(), &reader, cache.ReplaceHints{}))
+	assert.Equal(t, data.val, reader.val)
+}
@@ -0,0 +1,21 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix && !linux
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+func newSecretServiceCache(_ string, _ string) (Cache, error) {
+	return nil, fmt.Errorf(
+		"the Secret Service is only supported on Linux. Set %s to %s to store the cache in encrypted files instead",
+		cacheBackendKey, cacheBackendEncryptedFile)
+}
+
+func kernelKeyringKey(_ string) ([]byte, error) {
+	return nil, errors.New("the kernel keyring is only supported on Linux")
+}
@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,7 +27,8 @@ func randSeq(n int, rng rand.Rand) string {
 func TestCache(t *testing.T) {
 	root := t.TempDir()
 	ctx := t.Context()
-	c := newCache(root)
+	c, err := newCache(root, cacheOptions{})
+	require.NoError(t, err)
 	// weak rng is fine for testing
 	//nolint:gosec
 	rng := rand.New(rand.NewSource(0))
@@ -40,7 +42,7 @@ func TestCache(t *testing.T) {
 	}
 
 	// write some data.
-	err := c.Export(ctx, &data, cache.ExportHints{PartitionKey: key()})
+	err = c.Export(ctx, &data, cache.ExportHints{PartitionKey: key()})
 	require.NoError(t, err)
 
 	// read back that data we wrote.
@@ -51,7 +53,8 @@ func TestCache(t *testing.T) {
 	require.Equal(t, data.val, reader.val)
 
 	// the data should be shared across instances.
-	c = newCache(root)
+	c, err = newCache(root, cacheOptions{})
+	require.NoError(t, err)
 	reader = fixedMarshaller{}
 	err = c.Replace(ctx, &reader, cache.ReplaceHints{PartitionKey: key()})
 	require.NoError(t, err)
@@ -74,7 +77,8 @@ func TestCache(t *testing.T) {
 func TestCredentialCache(t *testing.T) {
 	root := t.TempDir()
 
-	c := newCredentialCache(root)
+	c, err := newCredentialCache(root, cacheOptions{})
+	require.NoError(t, err)
 
 	d1 := []byte("some data")
 
@@ -97,7 +101,8 @@ func TestCredentialCache(t *testing.T) {
 	require.Equal(t, d2, r2)
 
 	// the data should be shared across instances.
-	c = newCredentialCache(root)
+	c, err = newCredentialCache(root, cacheOptions{})
+	require.NoError(t, err)
 
 	r1, err = c.Read("d1")
 	require.NoError(t, err)
@@ -437,3 +442,20 @@ func TestMsalCacheAdapter_ExportMarshalError(t *testing.T) {
 		cache.ExportHints{})
 	require.Error(t, err)
 }
+
+func TestCacheOptionsFromConfig(t *testing.T) {
+	options, err := cacheOptionsFromConfig(config.NewEmptyConfig())
+	require.NoError(t, err)
+	assert.Equal(t, cacheOptions{Backend: cacheBackendFile}, options)
+
+	cfg := config.NewEmptyConfig()
+	require.NoError(t, cfg.Set(cacheBackendKey, "encryptedfile"))
+	require.NoError(t, cfg.Set(cacheKeyFileKey, "/home/user/.azd/cache.key"))
+	options, err = cacheOptionsFromConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, cacheOptions{Backend: cacheBackendEncryptedFile, KeyFile: "/home/user/.azd/cache.key"}, options)
+
+	require.NoError(t, cfg.Set(cacheBackendKey, "keychain"))
+	_, err = cacheOptionsFromConfig(cfg)
+	require.ErrorContains(t, err, "unsupported value 'keychain' for auth.cache.backend")
+}
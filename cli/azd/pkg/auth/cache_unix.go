@@ -6,39 +6,88 @@
 package auth
 
 import (
+	"fmt"
+
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/cache"
 )
 
-func newMsalCacheStore(root string) Cache {
+func newMsalCacheStore(root string, options cacheOptions) (Cache, error) {
+	inner, err := newBackendCache(root, "cache", options)
+	if err != nil {
+		return nil, err
+	}
+
 	return &memoryCache{
 		cache: make(map[string][]byte),
-		inner: &fileCache{
-			prefix: "cache",
-			root:   root,
-			ext:    "json",
-		},
-	}
+		inner: inner,
+	}, nil
 }
 
 // newCache creates a cache implementation that satisfies [cache.ExportReplace] from the MSAL library.
 //
 // root must be created beforehand, and must point to a directory.
-func newCache(root string) cache.ExportReplace {
-	return &msalCacheAdapter{
-		cache: newMsalCacheStore(root),
+func newCache(root string, options cacheOptions) (cache.ExportReplace, error) {
+	store, err := newMsalCacheStore(root, options)
+	if err != nil {
+		return nil, err
 	}
+
+	return &msalCacheAdapter{
+		cache: store,
+	}, nil
 }
 
 // newCredentialCache creates a cache implementation for storing credentials.
 //
 // root must be created beforehand, and must point to a directory.
-func newCredentialCache(root string) Cache {
+func newCredentialCache(root string, options cacheOptions) (Cache, error) {
+	inner, err := newBackendCache(root, "cred", options)
+	if err != nil {
+		return nil, err
+	}
+
 	return &memoryCache{
 		cache: make(map[string][]byte),
-		inner: &fileCache{
-			prefix: "cred",
+		inner: inner,
+	}, nil
+}
+
+// newBackendCache creates the Cache of the backend selected by options, for entries named [prefix][key]. The encrypted
+// backends take over the entries of the plaintext files written by the file backend, see [migratingCache].
+func newBackendCache(root string, prefix string, options cacheOptions) (Cache, error) {
+	plaintext := &fileCache{
+		prefix: prefix,
+		root:   root,
+		ext:    "json",
+	}
+
+	switch options.Backend {
+	case "", cacheBackendFile:
+		return plaintext, nil
+	case cacheBackendSecretService:
+		inner, err := newSecretServiceCache(root, prefix)
+		if err != nil {
+			return nil, err
+		}
+
+		return &migratingCache{inner: inner, plaintext: plaintext}, nil
+	case cacheBackendEncryptedFile:
+		key, err := encryptionKey(root, options.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+
+		inner, err := newAesGcmCache(key, &fileCache{
+			prefix: prefix,
 			root:   root,
-			ext:    "json",
-		},
+			ext:    "bin",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return &migratingCache{inner: inner, plaintext: plaintext}, nil
+	default:
+		return nil, fmt.Errorf("unsupported value '%s' for %s", options.Backend, cacheBackendKey)
 	}
 }
@@ -0,0 +1,134 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+//go:build unix
+
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/az"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeKeyFile(t *testing.T) string {
+	key := make([]byte, encryptionKeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+
+	keyFile := filepath.Join(t.TempDir(), "cache.key")
+	require.NoError(t, os.WriteFile(
+		keyFile, []byte(base64.StdEncoding.EncodeToString(key)+"\n"), osutil.PermissionFileOwnerOnly))
+	return keyFile
+}
+
+func TestEncryptedFileCache(t *testing.T) {
+	root := t.TempDir()
+	options := cacheOptions{Backend: cacheBackendEncryptedFile, KeyFile: writeKeyFile(t)}
+
+	c, err := newCredentialCache(root, options)
+	require.NoError(t, err)
+	require.NoError(t, c.Set("d1", []byte("some secret")))
+
+	// the data is not stored in plaintext
+	contents, err := os.ReadFile(filepath.Join(root, "credd1.bin"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "some secret")
+	assert.Contains(t, string(contents), `"type":"AES-GCM"`)
+
+	// the data should be shared across instances.
+	c, err = newCredentialCache(root, options)
+	require.NoError(t, err)
+	val, err := c.Read("d1")
+	require.NoError(t, err)
+	assert.Equal(t, "some secret", string(val))
+
+	// an entry cannot be passed off as another one
+	require.NoError(t, os.Rename(filepath.Join(root, "credd1.bin"), filepath.Join(root, "credd2.bin")))
+	_, err = c.Read("d2")
+	require.ErrorIs(t, err, errCacheKeyNotFound)
+	require.NoError(t, os.Rename(filepath.Join(root, "credd2.bin"), filepath.Join(root, "credd1.bin")))
+
+	// the data cannot be read with a different key, which is the same as the data not being cached.
+	c, err = newCredentialCache(root, cacheOptions{Backend: cacheBackendEncryptedFile, KeyFile: writeKeyFile(t)})
+	require.NoError(t, err)
+	_, err = c.Read("d1")
+	require.ErrorIs(t, err, errCacheKeyNotFound)
+}
+
+func TestEncryptedFileCache_MigratesPlaintext(t *testing.T) {
+	root := t.TempDir()
+
+	plaintext, err := newCredentialCache(root, cacheOptions{Backend: cacheBackendFile})
+	require.NoError(t, err)
+	require.NoError(t, plaintext.Set("d1", []byte("read secret")))
+	require.NoError(t, plaintext.Set("d2", []byte("replaced secret")))
+
+	c, err := newCredentialCache(root, cacheOptions{Backend: cacheBackendEncryptedFile, KeyFile: writeKeyFile(t)})
+	require.NoError(t, err)
+
+	// reading an entry moves it to the encrypted cache
+	val, err := c.Read("d1")
+	require.NoError(t, err)
+	assert.Equal(t, "read secret", string(val))
+	assert.NoFileExists(t, filepath.Join(root, "credd1.json"))
+	assert.FileExists(t, filepath.Join(root, "credd1.bin"))
+
+	// setting an entry removes its plaintext copy
+	require.NoError(t, c.Set("d2", []byte("new secret")))
+	assert.NoFileExists(t, filepath.Join(root, "credd2.json"))
+
+	_, err = c.Read("nonExist")
+	require.ErrorIs(t, err, errCacheKeyNotFound)
+}
+
+func TestEncryptedFileCache_InvalidKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "cache.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("c2hvcnQ="), osutil.PermissionFileOwnerOnly))
+
+	_, err := newCredentialCache(t.TempDir(), cacheOptions{Backend: cacheBackendEncryptedFile, KeyFile: keyFile})
+	require.ErrorContains(t, err, "must be a base64 encoded 256-bit key")
+
+	_, err = newCredentialCache(t.TempDir(), cacheOptions{
+		Backend: cacheBackendEncryptedFile,
+		KeyFile: filepath.Join(t.TempDir(), "missing.key"),
+	})
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewManager_CacheBackendUnavailable(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("AZD_CONFIG_DIR", configDir)
+
+	userConfigManager := newMemoryUserConfigManager()
+	require.NoError(t, userConfigManager.config.Set(cacheBackendKey, string(cacheBackendEncryptedFile)))
+	require.NoError(t, userConfigManager.config.Set(cacheKeyFileKey, filepath.Join(t.TempDir(), "missing.key")))
+
+	_, err := NewManager(
+		newMemoryConfigManager(),
+		userConfigManager,
+		cloud.AzurePublic(),
+		http.DefaultClient,
+		nil,
+		ExternalAuthConfiguration{},
+		az.AzCli{},
+		"test-agent",
+	)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// the caches are never stored in plaintext files instead of the chosen backend
+	var suggestionErr *internal.ErrorWithSuggestion
+	require.ErrorAs(t, err, &suggestionErr)
+	require.Contains(t, suggestionErr.Suggestion, cacheBackendKey)
+	assert.NoFileExists(t, filepath.Join(configDir, "auth", "credd1.json"))
+}
@@ -15,23 +15,14 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-// envelopedData stores both the type of encryption used as well as the encrypted data (as a base64 encoded string),
-// allowing us to change the underlying encryption algorithm as needed (and then understand what we need to do decrypt)
-type envelopedData struct {
-	// The type of encryption that was used to store data.
-	Type encryptionType `json:"type"`
-	// The encrypted data, represented as a Base64 encoded string (using base64.StdEncoding)
-	Data string `json:"data"`
-}
-
-type encryptionType string
-
 // cryptProtectDataEncryptionType is the encryption type that uses CryptProtectData/CryptUnprotectData for
 // encryption and decryption.  See https://learn.microsoft.com/windows/win32/api/dpapi/nf-dpapi-cryptprotectdata
 // for more information on these APIs.
 const cryptProtectDataEncryptionType encryptionType = "CryptProtectData"
 
-func newMsalCacheStore(root string) Cache {
+// newMsalCacheStore creates the store of the MSAL cache. The cache is always encrypted with CryptProtectData on
+// Windows, so the backend of options is not used.
+func newMsalCacheStore(root string, _ cacheOptions) (Cache, error) {
 	return &memoryCache{
 		cache: make(map[string][]byte),
 		inner: &encryptedCache{
@@ -41,16 +32,21 @@ func newMsalCacheStore(root string) Cache {
 				ext:    "bin",
 			},
 		},
-	}
+	}, nil
 }
 
-func newCache(root string) cache.ExportReplace {
-	return &msalCacheAdapter{
-		cache: newMsalCacheStore(root),
+func newCache(root string, options cacheOptions) (cache.ExportReplace, error) {
+	store, err := newMsalCacheStore(root, options)
+	if err != nil {
+		return nil, err
 	}
+
+	return &msalCacheAdapter{
+		cache: store,
+	}, nil
 }
 
-func newCredentialCache(root string) Cache {
+func newCredentialCache(root string, _ cacheOptions) (Cache, error) {
 	return &memoryCache{
 		cache: make(map[string][]byte),
 		inner: &encryptedCache{
@@ -60,7 +56,7 @@ func newCredentialCache(root string) Cache {
 				ext:    "bin",
 			},
 		},
-	}
+	}, nil
 }
 
 // encryptedCache is a Cache that wraps an existing Cache, encrypting and decrypting the cached value with CryptProtectData
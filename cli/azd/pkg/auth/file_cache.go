@@ -72,3 +72,26 @@ func (c *fileCache) pathForCache(key string) string {
 func (c *fileCache) pathForLock(key string) string {
 	return filepath.Join(c.root, fmt.Sprintf("%s%s.%s.lock", c.prefix, key, c.ext))
 }
+
+// Remove deletes the entry for key from the cache. It is not an error to remove an entry that does not exist.
+func (c *fileCache) Remove(key string) error {
+	cachePath := c.pathForCache(key)
+	lockPath := c.pathForLock(key)
+
+	fl := flock.New(lockPath)
+
+	if err := fl.Lock(); err != nil {
+		return fmt.Errorf("locking file %s: %w", lockPath, err)
+	}
+	defer func() {
+		if err := fl.Unlock(); err != nil {
+			log.Printf("failed to release file lock: %v", err)
+		}
+	}()
+
+	if err := os.Remove(cachePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
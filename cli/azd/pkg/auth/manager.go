@@ -23,6 +23,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/AzureAD/microsoft-authentication-library-for-go/apps/public"
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/internal/runcontext"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing"
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
@@ -110,6 +111,21 @@ type Manager struct {
 	azCliCredentialsMu sync.Mutex
 }
 
+// newCacheStores creates the MSAL token cache in cacheRoot and the credential cache in authRoot.
+func newCacheStores(cacheRoot string, authRoot string, options cacheOptions) (Cache, Cache, error) {
+	msalCache, err := newMsalCacheStore(cacheRoot, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating msal cache: %w", err)
+	}
+
+	credentialCache, err := newCredentialCache(authRoot, options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating credential cache: %w", err)
+	}
+
+	return msalCache, credentialCache, nil
+}
+
 // UserAgent is a typed string for the application user-agent,
 // used for dependency injection.
 type UserAgent string
@@ -150,8 +166,31 @@ func NewManager(
 		return nil, fmt.Errorf("joining authority url: %w", err)
 	}
 
+	userConfig, err := userConfigManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading user config: %w", err)
+	}
+
+	cacheOptions, err := cacheOptionsFromConfig(userConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	msalClient := newUserAgentClient(httpClient, string(userAgent))
-	msalCache := newMsalCacheStore(cacheRoot)
+	msalCache, credentialCache, err := newCacheStores(cacheRoot, authRoot, cacheOptions)
+	if err != nil && cacheOptions.Backend != cacheBackendFile {
+		// The backend may not be available in this session, e.g. without a D-Bus session bus or a kernel keyring. The
+		// user chose it to keep tokens off the disk in plaintext, so the caches are not silently stored in plaintext files.
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("the %s auth cache backend is not available: %w", cacheOptions.Backend, err),
+			Suggestion: fmt.Sprintf(
+				"Make the %s backend available, choose another one, or run '%s' to store the caches in plaintext "+
+					"files protected by file permissions.",
+				cacheOptions.Backend, output.WithHighLightFormat("azd config set %s %s", cacheBackendKey, cacheBackendFile)),
+		}
+	} else if err != nil {
+		return nil, err
+	}
 
 	options := []public.Option{
 		public.WithCache(&msalCacheAdapter{cache: msalCache}),
@@ -171,7 +210,7 @@ func NewManager(
 		cloud:               cloud,
 		configManager:       configManager,
 		userConfigManager:   userConfigManager,
-		credentialCache:     newCredentialCache(authRoot),
+		credentialCache:     credentialCache,
 		httpClient:          httpClient,
 		console:             console,
 		externalAuthCfg:     externalAuthCfg,
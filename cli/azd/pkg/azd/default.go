@@ -13,6 +13,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	infraBicep "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/bicep"
+	infraPulumi "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/pulumi"
 	infraTerraform "github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning/terraform"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
@@ -22,6 +23,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/pkg/templates"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/bicep"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/terraform"
 )
 
@@ -50,6 +52,7 @@ func (p *DefaultPlatform) IsEnabled() bool {
 func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) error {
 	// Tools
 	container.MustRegisterSingleton(terraform.NewCli)
+	container.MustRegisterSingleton(pulumi.NewCli)
 	container.MustRegisterSingleton(bicep.NewCli)
 
 	container.MustRegisterTransient(func() *lazy.Lazy[*infraBicep.BicepProvider] {
@@ -80,6 +83,7 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	provisionProviderMap := map[provisioning.ProviderKind]any{
 		provisioning.Bicep:     infraBicep.NewBicepProvider,
 		provisioning.Terraform: infraTerraform.NewTerraformProvider,
+		provisioning.Pulumi:    infraPulumi.NewPulumiProvider,
 	}
 
 	for provider, constructor := range provisionProviderMap {
@@ -96,6 +100,7 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 	// Remote Environment State Providers
 	remoteStateProviderMap := map[environment.RemoteKind]any{
 		environment.RemoteKindAzureBlobStorage: environment.NewStorageBlobDataStore,
+		environment.RemoteKindGit:              environment.NewGitDataStore,
 	}
 
 	for remoteKind, constructor := range remoteStateProviderMap {
@@ -131,6 +136,24 @@ func (p *DefaultPlatform) ConfigureContainer(container *ioc.NestedContainer) err
 		return storageAccountConfig, nil
 	})
 
+	container.MustRegisterSingleton(func(remoteStateConfig *state.RemoteConfig) (*environment.GitStateConfig, error) {
+		gitConfig := &environment.GitStateConfig{}
+		if remoteStateConfig == nil || remoteStateConfig.Config == nil {
+			return gitConfig, nil
+		}
+
+		jsonBytes, err := json.Marshal(remoteStateConfig.Config)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling remote state config: %w", err)
+		}
+
+		if err := json.Unmarshal(jsonBytes, gitConfig); err != nil {
+			return nil, fmt.Errorf("unmarshalling remote state config: %w", err)
+		}
+
+		return gitConfig, nil
+	})
+
 	// Storage components
 	container.MustRegisterSingleton(storage.NewBlobClient)
 	container.MustRegisterSingleton(storage.NewBlobSdkClient)
@@ -7,7 +7,6 @@
 package config
 
 import (
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -70,6 +69,11 @@ func NewConfig(data map[string]any) Config {
 type config struct {
 	vaultId string
 	vault   Config
+	// pendingSecrets holds the secrets set since the vault was loaded, by vault entry. They are encrypted into the vault
+	// when the configuration is saved.
+	pendingSecrets map[string]string
+	// secrets holds the decrypted secrets of the vault, by vault entry.
+	secrets map[string]string
 	data    map[string]any
 }
 
@@ -134,11 +138,13 @@ func (c *config) SetSecret(path string, value string) error {
 		}
 	}
 
+	if c.pendingSecrets == nil {
+		c.pendingSecrets = map[string]string{}
+	}
+
 	pathId := uuid.New().String()
 	vaultRef := fmt.Sprintf("vault://%s/%s", c.vaultId, pathId)
-	if err := c.vault.Set(pathId, base64.StdEncoding.EncodeToString([]byte(value))); err != nil {
-		return fmt.Errorf("failed setting secret value: %w", err)
-	}
+	c.pendingSecrets[pathId] = value
 
 	return c.Set(path, vaultRef)
 }
@@ -294,17 +300,13 @@ func (c *config) GetSection(path string, section any) (bool, error) {
 
 // getSecret retrieves the secret stored at the specified path from a local user vault
 func (c *config) getSecret(vaultRef string) (string, bool) {
-	encodedValue, ok := c.vault.GetString(filepath.Base(vaultRef))
-	if !ok {
-		return "", false
-	}
-
-	bytes, err := base64.StdEncoding.DecodeString(encodedValue)
-	if err != nil {
-		return "", false
+	pathId := filepath.Base(vaultRef)
+	if secret, has := c.pendingSecrets[pathId]; has {
+		return secret, true
 	}
 
-	return string(bytes), true
+	secret, has := c.secrets[pathId]
+	return secret, has
 }
 
 // interpolateNodeValue processes the node, iterates on any nested nodes and interpolates any vault references
@@ -71,6 +71,9 @@ func (m *fileConfigManager) Load(filePath string) (Config, error) {
 
 		baseConfig.vaultId = vaultId
 		baseConfig.vault = vaultConfig
+		if err := baseConfig.openVault(); err != nil {
+			return nil, fmt.Errorf("failed loading vault configuration from '%s': %w", vaultPath, err)
+		}
 	}
 
 	return azdConfig, nil
@@ -87,6 +90,19 @@ func (m *fileConfigManager) Save(c Config, filePath string) error {
 // This is separated from Save to allow the recursive vault save without deadlocking
 // on the non-reentrant mutex.
 func (m *fileConfigManager) saveLocked(c Config, filePath string) error {
+	baseConfig, ok := c.(*config)
+	if !ok {
+		return fmt.Errorf("failed casting azd configuration to config")
+	}
+
+	// Encrypt the vault secrets before anything is written, so the configuration never references secrets that were not
+	// added to the vault.
+	if baseConfig.vaultId != "" {
+		if err := baseConfig.sealVault(); err != nil {
+			return fmt.Errorf("failed encrypting vault secrets: %w", err)
+		}
+	}
+
 	folderPath := filepath.Dir(filePath)
 	if err := os.MkdirAll(folderPath, osutil.PermissionDirectory); err != nil {
 		return fmt.Errorf("failed creating config directory: %w", err)
@@ -102,11 +118,6 @@ func (m *fileConfigManager) saveLocked(c Config, filePath string) error {
 		return fmt.Errorf("saving file config: %w", err)
 	}
 
-	baseConfig, ok := c.(*config)
-	if !ok {
-		return fmt.Errorf("failed casting azd configuration to config")
-	}
-
 	// If the configuration contains a vault, then also save the vault configuration
 	// Vault configuration always gets saved in a separate file in the users HOME directory.
 	if baseConfig.vaultId != "" {
@@ -53,6 +53,7 @@ func Test_FileConfigManager_GetSetSecrets(t *testing.T) {
 	azdConfigDir := filepath.Join(tempDir, ".azd")
 
 	t.Setenv("AZD_CONFIG_DIR", azdConfigDir)
+	useMemoryKeyring(t, nil)
 
 	// Set and save secrets
 	configFilePath := filepath.Join(tempDir, "config.json")
@@ -119,6 +120,7 @@ func Test_FileConfigManager_GetSetSecretsInSection(t *testing.T) {
 	azdConfigDir := filepath.Join(tempDir, ".azd")
 
 	t.Setenv("AZD_CONFIG_DIR", azdConfigDir)
+	useMemoryKeyring(t, nil)
 
 	// Set and save secrets
 	configFilePath := filepath.Join(tempDir, "config.json")
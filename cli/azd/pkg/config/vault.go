@@ -0,0 +1,652 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/google/uuid"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+// VaultPassphraseEnvVarName is the environment variable holding the passphrase that protects the data key of the user
+// vaults. When it is not set, the data key is kept in the OS keyring, and secrets cannot be stored without one.
+const VaultPassphraseEnvVarName = "AZD_CONFIG_VAULT_PASSPHRASE"
+
+// vaultKeyringService is the service of the data keys azd stores in the OS keyring.
+const vaultKeyringService = "azd-config-vault"
+
+// osKeyring is the OS keyring of the user.
+type osKeyring struct{}
+
+func (osKeyring) Set(service, user, password string) error {
+	return keyring.Set(service, user, password)
+}
+
+func (osKeyring) Get(service, user string) (string, error) {
+	return keyring.Get(service, user)
+}
+
+func (osKeyring) Delete(service, user string) error {
+	return keyring.Delete(service, user)
+}
+
+func (osKeyring) DeleteAll(service string) error {
+	return keyring.DeleteAll(service)
+}
+
+// vaultKeyring is the keyring that keeps the data keys protected by the OS keyring. Tests replace it with an in-memory
+// keyring.
+var vaultKeyring keyring.Keyring = osKeyring{}
+
+// encryptedSecretPrefix prefixes the secrets in a vault that are encrypted with the data key of the user. Secrets
+// without it were written by previous versions of azd, which only base64 encoded them.
+const encryptedSecretPrefix = "aesgcm:"
+
+// vaultScryptLogN is the scrypt work factor used to derive the key that wraps the data key from a passphrase.
+var vaultScryptLogN = 18
+
+type vaultKeyProtection string
+
+const (
+	// vaultKeyProtectionKeyring keeps the data key in the OS keyring.
+	vaultKeyProtectionKeyring vaultKeyProtection = "keyring"
+	// vaultKeyProtectionPassphrase keeps the data key in the key file, wrapped with a key derived from a passphrase.
+	vaultKeyProtectionPassphrase vaultKeyProtection = "passphrase"
+	// vaultKeyProtectionFile keeps the data key in the key file, only readable by the user. New keys are never protected
+	// this way, but existing key files are still read, and rotating the key protects the new one with the keyring or the
+	// passphrase.
+	vaultKeyProtectionFile vaultKeyProtection = "file"
+)
+
+// vaultKeyFile is the content of the file that describes the data key of the user vaults.
+type vaultKeyFile struct {
+	Id         string             `json:"id"`
+	Protection vaultKeyProtection `json:"protection"`
+	// Salt, LogN and WrappedKey are only set when the data key is protected with a passphrase
+	Salt       string `json:"salt,omitempty"`
+	LogN       int    `json:"logN,omitempty"`
+	WrappedKey string `json:"wrappedKey,omitempty"`
+	// Key is only set when the data key is kept in the key file itself
+	Key string `json:"key,omitempty"`
+	// Previous are the keys retired by a rotation that did not complete yet, which may still encrypt some secrets
+	Previous []vaultKeyFile `json:"previous,omitempty"`
+}
+
+// vaultKey is the data key that encrypts the secrets of the user vaults with AES-256-GCM.
+type vaultKey struct {
+	file vaultKeyFile
+	aead cipher.AEAD
+	// previous are the keys being retired, which only decrypt secrets
+	previous []*vaultKey
+}
+
+var (
+	vaultKeysMu sync.Mutex
+	// vaultKeys caches the data keys by the path of their key file, to only read the keyring or derive the key from the
+	// passphrase once per process.
+	vaultKeys = map[string]*vaultKey{}
+)
+
+// vaultKeyFilePath returns the path of the file that describes the data key of the user vaults.
+func vaultKeyFilePath() (string, error) {
+	configDir, err := GetUserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed getting user config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "vault-key.json"), nil
+}
+
+// loadVaultKey loads the data key of the user vaults, returning an error wrapping os.ErrNotExist when no key was created.
+func loadVaultKey() (*vaultKey, error) {
+	vaultKeysMu.Lock()
+	defer vaultKeysMu.Unlock()
+
+	return loadVaultKeyLocked()
+}
+
+func loadVaultKeyLocked() (*vaultKey, error) {
+	keyFilePath, err := vaultKeyFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	if key, has := vaultKeys[keyFilePath]; has {
+		return key, nil
+	}
+
+	contents, err := os.ReadFile(keyFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading vault key: %w", err)
+	}
+
+	var file vaultKeyFile
+	if err := json.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("reading vault key from '%s': %w", keyFilePath, err)
+	}
+
+	key, err := unwrapVaultKey(file)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultKeys[keyFilePath] = key
+	return key, nil
+}
+
+// loadOrCreateVaultKey loads the data key of the user vaults, creating it when it does not exist yet.
+func loadOrCreateVaultKey() (*vaultKey, error) {
+	vaultKeysMu.Lock()
+	defer vaultKeysMu.Unlock()
+
+	key, err := loadVaultKeyLocked()
+	if !errors.Is(err, os.ErrNotExist) {
+		return key, err
+	}
+
+	key, err = newVaultKey(defaultVaultKeyProtection())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := saveVaultKeyLocked(key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// defaultVaultKeyProtection returns how new data keys are protected: with the passphrase when one is set, and with the
+// OS keyring otherwise.
+func defaultVaultKeyProtection() vaultKeyProtection {
+	if os.Getenv(VaultPassphraseEnvVarName) != "" {
+		return vaultKeyProtectionPassphrase
+	}
+
+	return vaultKeyProtectionKeyring
+}
+
+// newVaultKey generates a new data key with the given protection. A key protected by the OS keyring is added to the
+// keyring under its own id, so the key it replaces remains available until it is retired. When the keyring is not
+// available, the key is protected with the passphrase if one is set, and an error suggesting to set one is returned
+// otherwise.
+func newVaultKey(protection vaultKeyProtection) (*vaultKey, error) {
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generating vault key: %w", err)
+	}
+
+	file := vaultKeyFile{
+		Id:         uuid.New().String(),
+		Protection: protection,
+	}
+
+	switch protection {
+	case vaultKeyProtectionKeyring:
+		err := vaultKeyring.Set(vaultKeyringService, file.Id, base64.StdEncoding.EncodeToString(dataKey))
+		if err == nil {
+			break
+		}
+
+		if os.Getenv(VaultPassphraseEnvVarName) == "" {
+			return nil, &internal.ErrorWithSuggestion{
+				Err: fmt.Errorf("storing the vault key in the OS keyring: %w", err),
+				Suggestion: fmt.Sprintf(
+					"Set %s to protect the key of the azd vaults with a passphrase when no OS keyring is available, "+
+						"e.g. in dev containers and CI agents.", VaultPassphraseEnvVarName),
+			}
+		}
+
+		log.Printf("storing the vault key in the OS keyring failed, protecting it with the passphrase instead: %v", err)
+		file.Protection = vaultKeyProtectionPassphrase
+		fallthrough
+	case vaultKeyProtectionPassphrase:
+		salt := make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("generating vault key salt: %w", err)
+		}
+
+		file.Salt = base64.StdEncoding.EncodeToString(salt)
+		file.LogN = vaultScryptLogN
+
+		wrappingKey, err := passphraseWrappingKey(file)
+		if err != nil {
+			return nil, err
+		}
+
+		file.WrappedKey = base64.StdEncoding.EncodeToString(seal(wrappingKey, dataKey, []byte(file.Id)))
+	default:
+		return nil, fmt.Errorf("unsupported vault key protection '%s'", protection)
+	}
+
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vaultKey{file: file, aead: aead}, nil
+}
+
+// unwrapVaultKey reads the data key described by file from the OS keyring, or unwraps it with the passphrase.
+func unwrapVaultKey(file vaultKeyFile) (*vaultKey, error) {
+	var dataKey []byte
+
+	switch file.Protection {
+	case vaultKeyProtectionKeyring:
+		encoded, err := vaultKeyring.Get(vaultKeyringService, file.Id)
+		if err != nil {
+			return nil, fmt.Errorf("reading the vault key from the OS keyring: %w", err)
+		}
+
+		dataKey, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decoding the vault key from the OS keyring: %w", err)
+		}
+	case vaultKeyProtectionPassphrase:
+		wrappingKey, err := passphraseWrappingKey(file)
+		if err != nil {
+			return nil, err
+		}
+
+		wrapped, err := base64.StdEncoding.DecodeString(file.WrappedKey)
+		if err != nil {
+			return nil, fmt.Errorf("decoding the wrapped vault key: %w", err)
+		}
+
+		dataKey, err = open(wrappingKey, wrapped, []byte(file.Id))
+		if err != nil {
+			return nil, fmt.Errorf("unwrapping the vault key, the value of %s may be wrong: %w",
+				VaultPassphraseEnvVarName, err)
+		}
+	case vaultKeyProtectionFile:
+		var err error
+		dataKey, err = base64.StdEncoding.DecodeString(file.Key)
+		if err != nil {
+			return nil, fmt.Errorf("decoding the vault key: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported vault key protection '%s'", file.Protection)
+	}
+
+	aead, err := newAead(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &vaultKey{file: file, aead: aead}
+	for _, previousFile := range file.Previous {
+		previous, err := unwrapVaultKey(previousFile)
+		if err != nil {
+			return nil, fmt.Errorf("unlocking retired vault key '%s': %w", previousFile.Id, err)
+		}
+
+		key.previous = append(key.previous, previous)
+	}
+
+	return key, nil
+}
+
+// passphraseWrappingKey derives the key that wraps the data key described by file from the passphrase, with scrypt.
+func passphraseWrappingKey(file vaultKeyFile) (cipher.AEAD, error) {
+	passphrase := os.Getenv(VaultPassphraseEnvVarName)
+	if passphrase == "" {
+		return nil, fmt.Errorf("the vault key is protected with a passphrase, set %s to unlock it",
+			VaultPassphraseEnvVarName)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(file.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("decoding the vault key salt: %w", err)
+	}
+
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<file.LogN, 8, 1, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving the vault key wrapping key: %w", err)
+	}
+
+	return newAead(key)
+}
+
+// saveVaultKeyLocked writes the key file of key and caches it as the data key of the user vaults. The key file is
+// replaced atomically, so it always describes a key that can decrypt the secrets.
+func saveVaultKeyLocked(key *vaultKey) error {
+	keyFilePath, err := vaultKeyFilePath()
+	if err != nil {
+		return err
+	}
+
+	contents, err := json.MarshalIndent(key.file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling vault key: %w", err)
+	}
+
+	// the temporary file is only readable by the user, like the key file
+	tmpFile, err := os.CreateTemp(filepath.Dir(keyFilePath), "vault-key.json.tmp-*")
+	if err != nil {
+		return fmt.Errorf("writing vault key: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+
+	if _, err := tmpFile.Write(contents); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing vault key: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing vault key: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing vault key: %w", err)
+	}
+	if err := osutil.Rename(context.Background(), tmpPath, keyFilePath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing vault key: %w", err)
+	}
+
+	vaultKeys[keyFilePath] = key
+	return nil
+}
+
+// encrypt encrypts the secret stored as the vault entry name. The name is authenticated along with the secret, so an
+// encrypted secret cannot be moved to another entry.
+func (k *vaultKey) encrypt(name string, secret string) string {
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(seal(k.aead, []byte(secret), []byte(name)))
+}
+
+// decrypt decrypts the value of the vault entry name, which may still be encrypted with a key being retired.
+func (k *vaultKey) decrypt(name string, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("decoding secret: %w", err)
+	}
+
+	secret, err := open(k.aead, sealed, []byte(name))
+	for _, previous := range k.previous {
+		if err == nil {
+			break
+		}
+
+		secret, err = open(previous.aead, sealed, []byte(name))
+	}
+	if err != nil {
+		return "", fmt.Errorf("decrypting secret: %w", err)
+	}
+
+	return string(secret), nil
+}
+
+// decryptVaultSecret returns the secret stored in a vault entry, whether it is encrypted or base64 encoded.
+func decryptVaultSecret(name string, value string) (string, error) {
+	if !strings.HasPrefix(value, encryptedSecretPrefix) {
+		secret, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return "", fmt.Errorf("decoding secret: %w", err)
+		}
+
+		return string(secret), nil
+	}
+
+	key, err := loadVaultKey()
+	if errors.Is(err, os.ErrNotExist) {
+		// a missing key must not pass for a missing configuration, which callers ignore
+		return "", fmt.Errorf("the secret is encrypted but the vault key cannot be found: %v", err)
+	} else if err != nil {
+		return "", err
+	}
+
+	return key.decrypt(name, value)
+}
+
+// openVault decrypts the secrets of the vault, so a secret that cannot be decrypted fails loading the configuration
+// rather than passing for a missing secret.
+func (c *config) openVault() error {
+	secrets := map[string]string{}
+	for name, value := range c.vault.Raw() {
+		encoded, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		secret, err := decryptVaultSecret(name, encoded)
+		if err != nil {
+			return fmt.Errorf("reading vault secret '%s': %w", name, err)
+		}
+
+		secrets[name] = secret
+	}
+
+	c.secrets = secrets
+	return nil
+}
+
+// sealVault encrypts the secrets set since the vault was loaded into it, along with the secrets written by previous
+// versions of azd, which were only base64 encoded. It fails when the vault key cannot be unlocked and there are new
+// secrets to store, which are never written without encryption.
+func (c *config) sealVault() error {
+	secrets := map[string]string{}
+	for name, value := range c.vault.Raw() {
+		if encoded, ok := value.(string); ok && !strings.HasPrefix(encoded, encryptedSecretPrefix) {
+			secret, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				log.Printf("skipping vault entry '%s' that is not a secret: %v", name, err)
+				continue
+			}
+
+			secrets[name] = string(secret)
+		}
+	}
+
+	for name, secret := range c.pendingSecrets {
+		secrets[name] = secret
+	}
+
+	// the vault key is only needed when there is something to encrypt
+	if len(secrets) == 0 {
+		return nil
+	}
+
+	key, err := loadOrCreateVaultKey()
+	if err != nil && len(c.pendingSecrets) == 0 {
+		// the base64 encoded secrets are already stored that way, they are encrypted by a later save
+		log.Printf("keeping base64 encoded vault secrets, the vault key is not available: %v", err)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	for name, secret := range secrets {
+		if err := c.vault.Set(name, key.encrypt(name, secret)); err != nil {
+			return fmt.Errorf("failed setting secret value: %w", err)
+		}
+	}
+
+	if c.secrets == nil {
+		c.secrets = map[string]string{}
+	}
+	maps.Copy(c.secrets, c.pendingSecrets)
+
+	c.pendingSecrets = nil
+	return nil
+}
+
+// RotateVaultKey re-encrypts the secrets of all the user vaults with a new data key and retires the previous one. The
+// new key is protected like the previous one, or as selected by [VaultPassphraseEnvVarName] when there was none.
+//
+// The new key is saved along with the previous one before any vault is re-encrypted, and the previous key is only
+// retired once every vault is saved, so a rotation that fails part way leaves every secret readable. Rotating again
+// completes it.
+//
+// It returns the number of vaults that were re-encrypted.
+func RotateVaultKey(manager FileConfigManager) (int, error) {
+	vaultKeysMu.Lock()
+	defer vaultKeysMu.Unlock()
+
+	previous, err := loadVaultKeyLocked()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+
+	configDir, err := GetUserConfigDir()
+	if err != nil {
+		return 0, fmt.Errorf("failed getting user config directory: %w", err)
+	}
+
+	vaultPaths, err := filepath.Glob(filepath.Join(configDir, "vaults", "*.json"))
+	if err != nil {
+		return 0, fmt.Errorf("listing vaults: %w", err)
+	}
+
+	// decrypt every vault before touching any of them, so a secret that cannot be decrypted does not leave the vaults
+	// encrypted with different keys.
+	vaults := make([]Config, len(vaultPaths))
+	for i, vaultPath := range vaultPaths {
+		vault, err := manager.Load(vaultPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed loading vault configuration from '%s': %w", vaultPath, err)
+		}
+
+		for name, value := range vault.Raw() {
+			encoded, ok := value.(string)
+			if !ok {
+				continue
+			}
+
+			var secret string
+			if !strings.HasPrefix(encoded, encryptedSecretPrefix) {
+				var decoded []byte
+				decoded, err = base64.StdEncoding.DecodeString(encoded)
+				secret = string(decoded)
+			} else if previous == nil {
+				err = errors.New("the secret is encrypted with an unknown key")
+			} else {
+				secret, err = previous.decrypt(name, encoded)
+			}
+			if err != nil {
+				return 0, fmt.Errorf("reading secret '%s' of vault '%s': %w", name, vaultPath, err)
+			}
+
+			if err := vault.Set(name, secret); err != nil {
+				return 0, fmt.Errorf("failed setting secret value: %w", err)
+			}
+		}
+
+		vaults[i] = vault
+	}
+
+	protection := defaultVaultKeyProtection()
+	if previous != nil && previous.file.Protection != vaultKeyProtectionFile {
+		protection = previous.file.Protection
+	}
+
+	key, err := newVaultKey(protection)
+	if err != nil {
+		return 0, err
+	}
+
+	// keep the keys being retired, including the ones of a previous rotation that failed, until every vault is saved
+	var retired []*vaultKey
+	if previous != nil {
+		retired = append([]*vaultKey{previous}, previous.previous...)
+	}
+
+	key.previous = retired
+	for _, retiredKey := range retired {
+		retiredFile := retiredKey.file
+		retiredFile.Previous = nil
+		key.file.Previous = append(key.file.Previous, retiredFile)
+	}
+
+	if err := saveVaultKeyLocked(key); err != nil {
+		return 0, err
+	}
+
+	for i, vault := range vaults {
+		for name, value := range vault.Raw() {
+			if secret, ok := value.(string); ok {
+				if err := vault.Set(name, key.encrypt(name, secret)); err != nil {
+					return 0, fmt.Errorf("failed setting secret value: %w", err)
+				}
+			}
+		}
+
+		if err := manager.Save(vault, vaultPaths[i]); err != nil {
+			return 0, fmt.Errorf(
+				"failed saving vault configuration to '%s', rotate the vault key again to complete the rotation: %w",
+				vaultPaths[i], err)
+		}
+	}
+
+	key.previous = nil
+	key.file.Previous = nil
+	if err := saveVaultKeyLocked(key); err != nil {
+		return 0, err
+	}
+
+	for _, retiredKey := range retired {
+		if retiredKey.file.Protection != vaultKeyProtectionKeyring {
+			continue
+		}
+
+		if err := vaultKeyring.Delete(vaultKeyringService, retiredKey.file.Id); err != nil {
+			log.Printf("failed removing retired vault key '%s' from the OS keyring: %v", retiredKey.file.Id, err)
+		}
+	}
+
+	return len(vaults), nil
+}
+
+func newAead(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating vault cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating vault cipher: %w", err)
+	}
+
+	return aead, nil
+}
+
+// seal encrypts plaintext with a random nonce, which is prepended to the result.
+func seal(aead cipher.AEAD, plaintext []byte, additionalData []byte) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		// crypto/rand never returns an error
+		panic(fmt.Sprintf("failed generating nonce: %s", err))
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, additionalData)
+}
+
+// open decrypts data sealed by [seal].
+func open(aead cipher.AEAD, sealed []byte, additionalData []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed data is too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, additionalData)
+}
@@ -0,0 +1,484 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/stretchr/testify/require"
+	"github.com/zalando/go-keyring"
+)
+
+// memoryKeyring is an in-memory OS keyring, which fails every operation with err when it is set.
+type memoryKeyring struct {
+	secrets map[string]string
+	err     error
+}
+
+func (k *memoryKeyring) Set(service, user, password string) error {
+	if k.err != nil {
+		return k.err
+	}
+
+	k.secrets[service+"/"+user] = password
+	return nil
+}
+
+func (k *memoryKeyring) Get(service, user string) (string, error) {
+	if k.err != nil {
+		return "", k.err
+	}
+
+	password, has := k.secrets[service+"/"+user]
+	if !has {
+		return "", keyring.ErrNotFound
+	}
+
+	return password, nil
+}
+
+func (k *memoryKeyring) Delete(service, user string) error {
+	if _, err := k.Get(service, user); err != nil {
+		return err
+	}
+
+	delete(k.secrets, service+"/"+user)
+	return nil
+}
+
+func (k *memoryKeyring) DeleteAll(service string) error {
+	if k.err != nil {
+		return k.err
+	}
+
+	for name := range k.secrets {
+		if strings.HasPrefix(name, service+"/") {
+			delete(k.secrets, name)
+		}
+	}
+
+	return nil
+}
+
+// useMemoryKeyring replaces the OS keyring that keeps the vault keys with an in-memory keyring for the duration of the
+// test, failing every operation with err when it is set.
+func useMemoryKeyring(t *testing.T, err error) *memoryKeyring {
+	previous := vaultKeyring
+	t.Cleanup(func() { vaultKeyring = previous })
+
+	memory := &memoryKeyring{secrets: map[string]string{}, err: err}
+	vaultKeyring = memory
+	return memory
+}
+
+// setupVaultTest points the user config directory to a temporary directory, with an in-memory OS keyring, and returns
+// the path of the user config file.
+func setupVaultTest(t *testing.T) string {
+	tempDir := t.TempDir()
+	t.Setenv("AZD_CONFIG_DIR", filepath.Join(tempDir, ".azd"))
+	useMemoryKeyring(t, nil)
+	forgetVaultKeys()
+
+	return filepath.Join(tempDir, "config.json")
+}
+
+// forgetVaultKeys simulates a new azd process, which has not loaded the vault key yet.
+func forgetVaultKeys() {
+	vaultKeysMu.Lock()
+	defer vaultKeysMu.Unlock()
+
+	vaultKeys = map[string]*vaultKey{}
+}
+
+func readVaultFile(t *testing.T, c Config) map[string]any {
+	vaultPath, err := resolveVaultPath(c.(*config).vaultId)
+	require.NoError(t, err)
+
+	contents, err := os.ReadFile(vaultPath)
+	require.NoError(t, err)
+
+	var vault map[string]any
+	require.NoError(t, json.Unmarshal(contents, &vault))
+
+	return vault
+}
+
+func Test_Vault_EncryptsSecrets(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	azdConfig := NewConfig(nil)
+	require.NoError(t, azdConfig.SetSecret("secrets.password", "P@55w0rd!"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	for _, value := range readVaultFile(t, azdConfig) {
+		require.True(t, strings.HasPrefix(value.(string), encryptedSecretPrefix))
+		require.NotContains(t, value, base64.StdEncoding.EncodeToString([]byte("P@55w0rd!")))
+	}
+
+	forgetVaultKeys()
+
+	loaded, err := configManager.Load(configFilePath)
+	require.NoError(t, err)
+
+	password, ok := loaded.GetString("secrets.password")
+	require.True(t, ok)
+	require.Equal(t, "P@55w0rd!", password)
+}
+
+func Test_Vault_EncryptedSecretCannotMove(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	azdConfig := NewConfig(nil)
+	require.NoError(t, azdConfig.SetSecret("secrets.first", "first"))
+	require.NoError(t, azdConfig.SetSecret("secrets.second", "second"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	// swap the encrypted values of the two entries
+	vault := readVaultFile(t, azdConfig)
+	var names []string
+	for name := range vault {
+		names = append(names, name)
+	}
+	vault[names[0]], vault[names[1]] = vault[names[1]], vault[names[0]]
+
+	vaultPath, err := resolveVaultPath(azdConfig.(*config).vaultId)
+	require.NoError(t, err)
+	require.NoError(t, configManager.Save(NewConfig(vault), vaultPath))
+
+	_, err = configManager.Load(configFilePath)
+	require.ErrorContains(t, err, "decrypting secret")
+}
+
+func Test_Vault_UpgradesBase64Secrets(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	// a configuration written by a previous version of azd
+	vaultId := "2e6f2a3c-5f3b-4a4e-9a55-6f0f4b0c8a11"
+	entryId := "7b0d3f9e-1c2a-4d5b-8e6f-9a0b1c2d3e4f"
+	legacyVault := NewConfig(map[string]any{
+		entryId: base64.StdEncoding.EncodeToString([]byte("legacy")),
+	})
+	vaultPath, err := resolveVaultPath(vaultId)
+	require.NoError(t, err)
+	require.NoError(t, configManager.Save(legacyVault, vaultPath))
+	require.NoError(t, configManager.Save(NewConfig(map[string]any{
+		"vault":   vaultId,
+		"secrets": map[string]any{"legacy": "vault://" + vaultId + "/" + entryId},
+	}), configFilePath))
+
+	azdConfig, err := configManager.Load(configFilePath)
+	require.NoError(t, err)
+
+	legacy, ok := azdConfig.GetString("secrets.legacy")
+	require.True(t, ok)
+	require.Equal(t, "legacy", legacy)
+
+	// reading does not need the vault key, and does not change the vault
+	_, err = os.Stat(filepath.Join(os.Getenv("AZD_CONFIG_DIR"), "vault-key.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, azdConfig.SetSecret("secrets.new", "new"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	vault := readVaultFile(t, azdConfig)
+	require.Len(t, vault, 2)
+	for _, value := range vault {
+		require.True(t, strings.HasPrefix(value.(string), encryptedSecretPrefix))
+	}
+
+	forgetVaultKeys()
+
+	azdConfig, err = configManager.Load(configFilePath)
+	require.NoError(t, err)
+
+	legacy, ok = azdConfig.GetString("secrets.legacy")
+	require.True(t, ok)
+	require.Equal(t, "legacy", legacy)
+
+	newSecret, ok := azdConfig.GetString("secrets.new")
+	require.True(t, ok)
+	require.Equal(t, "new", newSecret)
+}
+
+func Test_Vault_Passphrase(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	logN := vaultScryptLogN
+	vaultScryptLogN = 10
+	t.Cleanup(func() { vaultScryptLogN = logN })
+
+	t.Setenv(VaultPassphraseEnvVarName, "correct horse battery staple")
+
+	azdConfig := NewConfig(nil)
+	require.NoError(t, azdConfig.SetSecret("secrets.password", "P@55w0rd!"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	key, err := loadVaultKey()
+	require.NoError(t, err)
+	require.Equal(t, vaultKeyProtectionPassphrase, key.file.Protection)
+	require.NotEmpty(t, key.file.WrappedKey)
+
+	t.Run("CorrectPassphrase", func(t *testing.T) {
+		forgetVaultKeys()
+
+		loaded, err := configManager.Load(configFilePath)
+		require.NoError(t, err)
+
+		password, ok := loaded.GetString("secrets.password")
+		require.True(t, ok)
+		require.Equal(t, "P@55w0rd!", password)
+	})
+
+	t.Run("WrongPassphrase", func(t *testing.T) {
+		forgetVaultKeys()
+		t.Setenv(VaultPassphraseEnvVarName, "wrong")
+
+		_, err := configManager.Load(configFilePath)
+		require.ErrorContains(t, err, VaultPassphraseEnvVarName)
+		require.NotErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_Vault_KeyringUnavailable(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	logN := vaultScryptLogN
+	vaultScryptLogN = 10
+	t.Cleanup(func() { vaultScryptLogN = logN })
+
+	// e.g. Linux without a Secret Service, in a dev container or on a CI agent
+	useMemoryKeyring(t, errors.New("The name org.freedesktop.secrets was not provided by any .service files"))
+
+	t.Run("NoPassphrase", func(t *testing.T) {
+		azdConfig := NewConfig(nil)
+		require.NoError(t, azdConfig.SetSecret("secrets.password", "P@55w0rd!"))
+
+		err := configManager.Save(azdConfig, configFilePath)
+		var suggestionErr *internal.ErrorWithSuggestion
+		require.ErrorAs(t, err, &suggestionErr)
+		require.Contains(t, suggestionErr.Suggestion, VaultPassphraseEnvVarName)
+
+		// the secret is never written without encryption
+		vaultPath, err := resolveVaultPath(azdConfig.(*config).vaultId)
+		require.NoError(t, err)
+		require.NoFileExists(t, vaultPath)
+
+		keyFilePath, err := vaultKeyFilePath()
+		require.NoError(t, err)
+		require.NoFileExists(t, keyFilePath)
+	})
+
+	t.Run("Passphrase", func(t *testing.T) {
+		t.Setenv(VaultPassphraseEnvVarName, "correct horse battery staple")
+
+		azdConfig := NewConfig(nil)
+		require.NoError(t, azdConfig.SetSecret("secrets.password", "P@55w0rd!"))
+		require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+		key, err := loadVaultKey()
+		require.NoError(t, err)
+		require.Equal(t, vaultKeyProtectionPassphrase, key.file.Protection)
+		require.Empty(t, key.file.Key)
+
+		if runtime.GOOS != "windows" {
+			keyFilePath, err := vaultKeyFilePath()
+			require.NoError(t, err)
+
+			info, err := os.Stat(keyFilePath)
+			require.NoError(t, err)
+			require.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		}
+
+		forgetVaultKeys()
+
+		loaded, err := configManager.Load(configFilePath)
+		require.NoError(t, err)
+
+		password, ok := loaded.GetString("secrets.password")
+		require.True(t, ok)
+		require.Equal(t, "P@55w0rd!", password)
+	})
+}
+
+func Test_Vault_KeyUnavailable(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	logN := vaultScryptLogN
+	vaultScryptLogN = 10
+	t.Cleanup(func() { vaultScryptLogN = logN })
+
+	t.Setenv(VaultPassphraseEnvVarName, "correct horse battery staple")
+
+	azdConfig := NewConfig(nil)
+	require.NoError(t, azdConfig.SetSecret("secrets.first", "first"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	// the passphrase of the vault key is not set in this process
+	forgetVaultKeys()
+	t.Setenv(VaultPassphraseEnvVarName, "")
+
+	_, err := configManager.Load(configFilePath)
+	require.ErrorContains(t, err, VaultPassphraseEnvVarName)
+	require.NotErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, azdConfig.SetSecret("secrets.second", "second"))
+	require.ErrorContains(t, configManager.Save(azdConfig, configFilePath), VaultPassphraseEnvVarName)
+
+	// the secret that could not be encrypted was not written
+	vault := readVaultFile(t, azdConfig)
+	require.Len(t, vault, 1)
+
+	t.Run("MissingKeyFile", func(t *testing.T) {
+		keyFilePath, err := vaultKeyFilePath()
+		require.NoError(t, err)
+		require.NoError(t, os.Rename(keyFilePath, keyFilePath+".bak"))
+		t.Cleanup(func() { _ = os.Rename(keyFilePath+".bak", keyFilePath) })
+
+		// callers ignore a missing configuration, which a missing key must not pass for
+		_, err = configManager.Load(configFilePath)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func Test_Vault_RotateVaultKey(t *testing.T) {
+	configFilePath := setupVaultTest(t)
+	configManager := NewFileConfigManager(NewManager())
+
+	azdConfig := NewConfig(nil)
+	require.NoError(t, azdConfig.SetSecret("secrets.password", "P@55w0rd!"))
+	require.NoError(t, configManager.Save(azdConfig, configFilePath))
+
+	previous, err := loadVaultKey()
+	require.NoError(t, err)
+	previousVault := readVaultFile(t, azdConfig)
+
+	rotated, err := RotateVaultKey(configManager)
+	require.NoError(t, err)
+	require.Equal(t, 1, rotated)
+
+	current, err := loadVaultKey()
+	require.NoError(t, err)
+	require.NotEqual(t, previous.file.Id, current.file.Id)
+	require.NotEqual(t, previousVault, readVaultFile(t, azdConfig))
+
+	// the previous key is removed from the OS keyring
+	_, err = vaultKeyring.Get(vaultKeyringService, previous.file.Id)
+	require.ErrorIs(t, err, keyring.ErrNotFound)
+
+	forgetVaultKeys()
+
+	loaded, err := configManager.Load(configFilePath)
+	require.NoError(t, err)
+
+	password, ok := loaded.GetString("secrets.password")
+	require.True(t, ok)
+	require.Equal(t, "P@55w0rd!", password)
+}
+
+// failingConfigManager fails to save configurations once failAfter of them were saved.
+type failingConfigManager struct {
+	FileConfigManager
+	failAfter int
+	saved     int
+}
+
+func (m *failingConfigManager) Save(c Config, filePath string) error {
+	if m.saved == m.failAfter {
+		return errors.New("disk full")
+	}
+
+	m.saved++
+	return m.FileConfigManager.Save(c, filePath)
+}
+
+func Test_Vault_RotateVaultKey_PartialFailure(t *testing.T) {
+	tests := []struct {
+		name       string
+		passphrase string
+	}{
+		{name: "Keyring"},
+		{name: "Passphrase", passphrase: "correct horse battery staple"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFilePath := setupVaultTest(t)
+			configManager := NewFileConfigManager(NewManager())
+
+			logN := vaultScryptLogN
+			vaultScryptLogN = 10
+			t.Cleanup(func() { vaultScryptLogN = logN })
+
+			t.Setenv(VaultPassphraseEnvVarName, tt.passphrase)
+
+			configFilePaths := []string{configFilePath, filepath.Join(filepath.Dir(configFilePath), "other.json")}
+			for i, path := range configFilePaths {
+				azdConfig := NewConfig(nil)
+				require.NoError(t, azdConfig.SetSecret("secrets.password", fmt.Sprintf("P@55w0rd-%d", i)))
+				require.NoError(t, configManager.Save(azdConfig, path))
+			}
+
+			previous, err := loadVaultKey()
+			require.NoError(t, err)
+
+			// the second vault cannot be saved, after the first one was re-encrypted with the new key
+			_, err = RotateVaultKey(&failingConfigManager{FileConfigManager: configManager, failAfter: 1})
+			require.ErrorContains(t, err, "disk full")
+
+			requireSecrets := func() {
+				forgetVaultKeys()
+
+				for i, path := range configFilePaths {
+					loaded, err := configManager.Load(path)
+					require.NoError(t, err)
+
+					password, ok := loaded.GetString("secrets.password")
+					require.True(t, ok)
+					require.Equal(t, fmt.Sprintf("P@55w0rd-%d", i), password)
+				}
+			}
+
+			// the new key was saved along with the previous one, so every secret can still be read
+			requireSecrets()
+
+			key, err := loadVaultKey()
+			require.NoError(t, err)
+			require.NotEqual(t, previous.file.Id, key.file.Id)
+			require.Len(t, key.file.Previous, 1)
+			require.Equal(t, previous.file.Id, key.file.Previous[0].Id)
+
+			// rotating again completes the rotation and retires both keys
+			rotated, err := RotateVaultKey(configManager)
+			require.NoError(t, err)
+			require.Equal(t, 2, rotated)
+
+			requireSecrets()
+
+			key, err = loadVaultKey()
+			require.NoError(t, err)
+			require.Empty(t, key.file.Previous)
+
+			if tt.passphrase == "" {
+				_, err = vaultKeyring.Get(vaultKeyringService, previous.file.Id)
+				require.ErrorIs(t, err, keyring.ErrNotFound)
+			}
+		})
+	}
+}
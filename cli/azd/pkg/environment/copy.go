@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+)
+
+// CopyOptions selects the values copied by [CopySpec].
+type CopyOptions struct {
+	// Include, when set, limits the copied .env keys to those matching one of these patterns, in path.Match syntax.
+	Include []string
+	// Exclude skips the .env keys matching one of these patterns, in path.Match syntax.
+	Exclude []string
+	// OutputKeys are the .env keys of the source that were set from provisioning outputs.
+	OutputKeys []string
+	// SkipOutputs skips the OutputKeys.
+	SkipOutputs bool
+}
+
+// CopySpec returns the spec of a new environment named name, holding the .env values and config.json of src
+// selected by options. The name of src itself is never copied.
+func CopySpec(src *Environment, name string, options CopyOptions) (Spec, error) {
+	for _, pattern := range slices.Concat(options.Include, options.Exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return Spec{}, fmt.Errorf("invalid key pattern '%s': %w", pattern, err)
+		}
+	}
+
+	values := map[string]string{}
+	for key, value := range src.Dotenv() {
+		if key == EnvNameEnvVarName ||
+			(options.SkipOutputs && slices.Contains(options.OutputKeys, key)) ||
+			(len(options.Include) > 0 && !matchesAny(options.Include, key)) ||
+			matchesAny(options.Exclude, key) {
+			continue
+		}
+		values[key] = value
+	}
+
+	// Deep copy, so the new environment does not share nested values with src.
+	var cfg map[string]any
+	raw, err := json.Marshal(src.Config.Raw())
+	if err != nil {
+		return Spec{}, fmt.Errorf("copying config: %w", err)
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return Spec{}, fmt.Errorf("copying config: %w", err)
+	}
+
+	return Spec{
+		Name:   name,
+		Values: values,
+		Config: cfg,
+	}, nil
+}
+
+func matchesAny(patterns []string, key string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		matched, _ := path.Match(pattern, key)
+		return matched
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newCopySource(t *testing.T) *Environment {
+	src := NewWithValues("dev", map[string]string{
+		"AZURE_ENV_NAME":        "dev",
+		"AZURE_LOCATION":        "westus2",
+		"AZURE_SUBSCRIPTION_ID": "sub",
+		"SERVICE_API_NAME":      "api-dev",
+		"API_URL":               "https://api-dev",
+	})
+	require.NoError(t, src.Config.Set("infra.parameters.sku", "S1"))
+	return src
+}
+
+var copyOutputKeys = []string{"API_URL", "SERVICE_API_NAME"}
+
+func Test_CopySpec(t *testing.T) {
+	t.Run("All", func(t *testing.T) {
+		spec, err := CopySpec(newCopySource(t), "pr-1", CopyOptions{})
+		require.NoError(t, err)
+		require.Equal(t, "pr-1", spec.Name)
+		require.Equal(t, map[string]string{
+			"AZURE_LOCATION":        "westus2",
+			"AZURE_SUBSCRIPTION_ID": "sub",
+			"SERVICE_API_NAME":      "api-dev",
+			"API_URL":               "https://api-dev",
+		}, spec.Values)
+		require.Equal(t, map[string]any{
+			"infra": map[string]any{
+				"parameters": map[string]any{"sku": "S1"},
+			},
+		}, spec.Config)
+	})
+
+	t.Run("NoOutputs", func(t *testing.T) {
+		spec, err := CopySpec(newCopySource(t), "pr-1", CopyOptions{OutputKeys: copyOutputKeys, SkipOutputs: true})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"AZURE_LOCATION":        "westus2",
+			"AZURE_SUBSCRIPTION_ID": "sub",
+		}, spec.Values)
+		require.Equal(t, map[string]any{
+			"infra": map[string]any{"parameters": map[string]any{"sku": "S1"}},
+		}, spec.Config)
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		spec, err := CopySpec(newCopySource(t), "pr-1", CopyOptions{
+			Include: []string{"AZURE_*", "SERVICE_*"},
+			Exclude: []string{"AZURE_SUBSCRIPTION_ID"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{
+			"AZURE_LOCATION":   "westus2",
+			"SERVICE_API_NAME": "api-dev",
+		}, spec.Values)
+	})
+
+	t.Run("BadPattern", func(t *testing.T) {
+		_, err := CopySpec(newCopySource(t), "pr-1", CopyOptions{Include: []string{"["}})
+		require.Error(t, err)
+	})
+
+	t.Run("SourceIsUnchanged", func(t *testing.T) {
+		src := newCopySource(t)
+		spec, err := CopySpec(src, "pr-1", CopyOptions{OutputKeys: copyOutputKeys, SkipOutputs: true})
+		require.NoError(t, err)
+
+		spec.Config["infra"].(map[string]any)["parameters"].(map[string]any)["sku"] = "P1"
+		sku, _ := src.Config.Get("infra.parameters.sku")
+		require.Equal(t, "S1", sku)
+		require.Equal(t, "api-dev", src.Getenv("SERVICE_API_NAME"))
+	})
+}
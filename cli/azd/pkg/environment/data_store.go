@@ -15,16 +15,21 @@ type RemoteKind string
 
 const (
 	RemoteKindAzureBlobStorage RemoteKind = "AzureBlobStorage"
+	RemoteKindGit              RemoteKind = "Git"
 )
 
 var ValidRemoteKinds = []string{
 	string(RemoteKindAzureBlobStorage),
+	string(RemoteKindGit),
 }
 
 // SaveOptions provide additional metadata for the save operation
 type SaveOptions struct {
 	// Whether or not the environment is new
 	IsNew bool
+	// Whether values that do not match the schema are reported as a warning rather than failing the save, for
+	// values that were already applied outside of the environment, e.g. provisioning outputs.
+	WarnOnSchemaViolation bool
 }
 
 type DataStore interface {
@@ -0,0 +1,143 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ChangeKind describes how a value differs between two states of an environment.
+type ChangeKind string
+
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a single value that differs between two states of an environment, e.g. two revisions.
+type Change struct {
+	// Key is the .env key, or the dotted path of the value for config.json changes.
+	Key  string     `json:"key"`
+	Kind ChangeKind `json:"kind"`
+	// Config is set for changes to config.json rather than .env.
+	Config bool   `json:"config,omitempty"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// Diff returns the changes that turn the .env values and config.json of from into those of to.
+func Diff(from *Environment, to *Environment) []Change {
+	changes := diffValues(from.Dotenv(), to.Dotenv(), false)
+	return append(changes, diffValues(flattenConfig(from.Config.Raw()), flattenConfig(to.Config.Raw()), true)...)
+}
+
+// DiffValues returns the changes that turn the .env values from into to.
+func DiffValues(from map[string]string, to map[string]string) []Change {
+	return diffValues(from, to, false)
+}
+
+// diffValues returns the changes between old and new, sorted by key.
+func diffValues(oldValues, newValues map[string]string, isConfig bool) []Change {
+	var changes []Change
+	for key, newValue := range newValues {
+		oldValue, had := oldValues[key]
+		switch {
+		case !had:
+			changes = append(changes, Change{Key: key, Kind: ChangeAdded, Config: isConfig, New: newValue})
+		case oldValue != newValue:
+			changes = append(changes, Change{
+				Key: key, Kind: ChangeModified, Config: isConfig, Old: oldValue, New: newValue,
+			})
+		}
+	}
+
+	for key, oldValue := range oldValues {
+		if _, has := newValues[key]; !has {
+			changes = append(changes, Change{Key: key, Kind: ChangeRemoved, Config: isConfig, Old: oldValue})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	return changes
+}
+
+// flattenConfig maps the leaves of a config.json document to their dotted paths. Non-string leaves are JSON
+// encoded.
+func flattenConfig(cfg map[string]any) map[string]string {
+	flat := map[string]string{}
+
+	var walk func(prefix string, value any)
+	walk = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				path := key
+				if prefix != "" {
+					path = prefix + "." + key
+				}
+				walk(path, v[key])
+			}
+		case string:
+			flat[prefix] = v
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				encoded = fmt.Appendf(nil, "%v", v)
+			}
+			flat[prefix] = string(encoded)
+		}
+	}
+
+	if cfg != nil {
+		walk("", cfg)
+	}
+
+	return flat
+}
+
+// secretKeyWords are the words that mark a key as holding a secret, matched against the `_`, `-` or `.`
+// separated words of the key.
+var secretKeyWords = []string{"SECRET", "PASSWORD", "PWD", "TOKEN", "CREDENTIAL", "CREDENTIALS", "CONNECTIONSTRING"}
+
+// IsSecretLike reports whether the value stored under key looks like a secret that should not be displayed.
+func IsSecretLike(key string, value string) bool {
+	words := strings.FieldsFunc(strings.ToUpper(key), func(r rune) bool {
+		return r == '_' || r == '-' || r == '.'
+	})
+	for i, word := range words {
+		if slices.Contains(secretKeyWords, word) {
+			return true
+		}
+		// e.g. API_KEY, STORAGE_ACCOUNT_KEY or CONNECTION_STRING, but not KEY_VAULT_NAME.
+		if word == "KEY" && i == len(words)-1 && i > 0 {
+			return true
+		}
+		if word == "CONNECTION" && i+1 < len(words) && words[i+1] == "STRING" {
+			return true
+		}
+	}
+
+	lower := strings.ToLower(value)
+	return strings.Contains(lower, "accountkey=") ||
+		strings.Contains(lower, "password=") ||
+		strings.Contains(lower, "sharedaccesskey=") ||
+		strings.HasPrefix(value, "-----BEGIN")
+}
+
+// MaskSecretValue returns value, or a mask in its place when schema marks key as a secret or it looks like one.
+func MaskSecretValue(schema Schema, key string, value string) string {
+	if value != "" && (schema.IsSecret(key) || IsSecretLike(key, value)) {
+		return "********"
+	}
+
+	return value
+}
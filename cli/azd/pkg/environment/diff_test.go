@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Diff(t *testing.T) {
+	staging := NewWithValues("staging", map[string]string{
+		"AZURE_LOCATION": "westus2",
+		"ONLY_STAGING":   "1",
+		"SHARED":         "same",
+	})
+	require.NoError(t, staging.Config.Set("infra.parameters.sku", "S1"))
+	require.NoError(t, staging.Config.Set("infra.parameters.replicas", 1))
+
+	prod := NewWithValues("prod", map[string]string{
+		"AZURE_LOCATION": "eastus",
+		"ONLY_PROD":      "1",
+		"SHARED":         "same",
+	})
+	require.NoError(t, prod.Config.Set("infra.parameters.sku", "P1"))
+
+	require.Equal(t, []Change{
+		{Key: "AZURE_LOCATION", Kind: ChangeModified, Old: "westus2", New: "eastus"},
+		{Key: "ONLY_PROD", Kind: ChangeAdded, New: "1"},
+		{Key: "ONLY_STAGING", Kind: ChangeRemoved, Old: "1"},
+		{Key: "infra.parameters.replicas", Kind: ChangeRemoved, Config: true, Old: "1"},
+		{Key: "infra.parameters.sku", Kind: ChangeModified, Config: true, Old: "S1", New: "P1"},
+	}, Diff(staging, prod))
+
+	require.Empty(t, Diff(staging, staging))
+}
+
+func Test_IsSecretLike(t *testing.T) {
+	tests := []struct {
+		key    string
+		value  string
+		secret bool
+	}{
+		{"AZURE_LOCATION", "westus2", false},
+		{"AZURE_KEY_VAULT_NAME", "kv-dev", false},
+		{"KEY", "value", false},
+		{"API_KEY", "abc", true},
+		{"STORAGE_ACCOUNT_KEY", "abc", true},
+		{"DB_PASSWORD", "abc", true},
+		{"GITHUB_TOKEN", "abc", true},
+		{"client-secret", "abc", true},
+		{"SQL_CONNECTION_STRING", "Server=x", true},
+		{"STORAGE", "DefaultEndpointsProtocol=https;AccountName=x;AccountKey=abc", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			require.Equal(t, tt.secret, IsSecretLike(tt.key, tt.value))
+		})
+	}
+
+	require.Equal(t, "********", MaskSecretValue(nil, "API_KEY", "abc"))
+	require.Equal(t, "", MaskSecretValue(nil, "API_KEY", ""))
+	require.Equal(t, "westus2", MaskSecretValue(nil, "AZURE_LOCATION", "westus2"))
+
+	// Values the schema marks as secrets are masked whatever their name.
+	schema := Schema{"DB_LOGIN": {Secret: true}, "AZURE_LOCATION": {}}
+	require.Equal(t, "********", MaskSecretValue(schema, "DB_LOGIN", "admin"))
+	require.Equal(t, "westus2", MaskSecretValue(schema, "AZURE_LOCATION", "westus2"))
+}
+
+func Test_DiffValues(t *testing.T) {
+	require.Equal(t, []Change{
+		{Key: "ADDED", Kind: ChangeAdded, New: "new"},
+		{Key: "MODIFIED", Kind: ChangeModified, Old: "old", New: "new"},
+		{Key: "REMOVED", Kind: ChangeRemoved, Old: "old"},
+	}, DiffValues(
+		map[string]string{"MODIFIED": "old", "REMOVED": "old", "SAME": "same"},
+		map[string]string{"MODIFIED": "new", "ADDED": "new", "SAME": "same"},
+	))
+
+	require.Empty(t, DiffValues(nil, nil))
+}
+
+func Test_flattenConfig(t *testing.T) {
+	require.Equal(t, map[string]string{
+		"infra.parameters.sku":      "S1",
+		"infra.parameters.replicas": "2",
+		"infra.parameters.tags":     `["a","b"]`,
+		"enabled":                   "true",
+	}, flattenConfig(map[string]any{
+		"infra": map[string]any{
+			"parameters": map[string]any{
+				"sku":      "S1",
+				"replicas": 2,
+				"tags":     []any{"a", "b"},
+			},
+		},
+		"enabled": true,
+	}))
+
+	require.Empty(t, flattenConfig(nil))
+}
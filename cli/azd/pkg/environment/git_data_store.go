@@ -0,0 +1,684 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/gofrs/flock"
+	"github.com/joho/godotenv"
+)
+
+// ErrRemoteConflict is returned when an environment changed in the remote state store since it was last
+// read, so saving it would overwrite someone else's changes.
+var ErrRemoteConflict = errors.New("remote environment was changed by someone else")
+
+// DefaultGitStateBranch is the branch used by the Git remote state backend when none is configured.
+const DefaultGitStateBranch = "azd-env-state"
+
+// gitBaseFileName is the name of the file, stored in the `.azure/<env>` directory of the checkout, that records
+// the commit of the state branch the environment was last read or written at.
+const gitBaseFileName = ".remote-base"
+
+// gitPushAttempts is the number of times Save rebuilds its commit on top of the latest remote branch when a
+// push is rejected because another writer updated a different environment in the meantime.
+const gitPushAttempts = 3
+
+// GitStateConfig is the configuration of the Git remote state backend, read from `state.remote.config`.
+type GitStateConfig struct {
+	// Repository is the URL or path of the repository holding the state. Defaults to the `origin` remote of
+	// the project repository.
+	Repository string `json:"repository"`
+	// Branch is the branch holding the state. Defaults to DefaultGitStateBranch.
+	Branch string `json:"branch"`
+	// Path is an optional folder within the branch under which environments are stored.
+	Path string `json:"path"`
+}
+
+// GitDataStore is a RemoteDataStore that stores environments as commits on a dedicated branch of a git
+// repository.
+//
+// The store works in a cache clone under the azd config directory and never touches the project's working
+// tree. Writes use optimistic concurrency: the commit an environment was last read or written at is
+// recorded next to its local copy under .azure, and Save fails with ErrRemoteConflict when the environment
+// changed on the remote branch since then. Pushes that race with writes to other environments are rebased
+// and retried.
+type GitDataStore struct {
+	configManager config.Manager
+	commandRunner exec.CommandRunner
+	azdContext    *azdcontext.AzdContext
+	config        *GitStateConfig
+
+	// cacheRoot overrides where cache clones are kept. Defaults to the azd config directory.
+	cacheRoot string
+
+	mu sync.Mutex
+}
+
+// NewGitDataStore creates a new GitDataStore instance
+func NewGitDataStore(
+	configManager config.Manager,
+	commandRunner exec.CommandRunner,
+	azdContext *azdcontext.AzdContext,
+	gitConfig *GitStateConfig,
+) RemoteDataStore {
+	if gitConfig == nil {
+		gitConfig = &GitStateConfig{}
+	}
+
+	return &GitDataStore{
+		configManager: configManager,
+		commandRunner: commandRunner,
+		azdContext:    azdContext,
+		config:        gitConfig,
+	}
+}
+
+// EnvPath returns the path of the .env file for the given environment within the state branch
+func (gs *GitDataStore) EnvPath(env *Environment) string {
+	return gs.repoPath(env.name, DotEnvFileName)
+}
+
+// ConfigPath returns the path of the config.json file for the given environment within the state branch
+func (gs *GitDataStore) ConfigPath(env *Environment) string {
+	return gs.repoPath(env.name, ConfigFileName)
+}
+
+func (gs *GitDataStore) branch() string {
+	if gs.config.Branch != "" {
+		return gs.config.Branch
+	}
+
+	return DefaultGitStateBranch
+}
+
+// repoPath joins elem under the configured folder using forward slashes, as git expects.
+func (gs *GitDataStore) repoPath(elem ...string) string {
+	return path.Join(append([]string{strings.Trim(filepath.ToSlash(gs.config.Path), "/")}, elem...)...)
+}
+
+func (gs *GitDataStore) List(ctx context.Context) ([]*contracts.EnvListEnvironment, error) {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	repo, unlock, err := gs.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	head, err := repo.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := []*contracts.EnvListEnvironment{}
+	if head == "" {
+		return envs, nil
+	}
+
+	files, err := repo.listFiles(ctx, head, gs.repoPath())
+	if err != nil {
+		return nil, err
+	}
+
+	envMap := map[string]*contracts.EnvListEnvironment{}
+	for _, file := range files {
+		dir, name := path.Split(file)
+		envName := path.Base(dir)
+		if name != DotEnvFileName && name != ConfigFileName || path.Clean(dir) != gs.repoPath(envName) {
+			continue
+		}
+
+		env, has := envMap[envName]
+		if !has {
+			env = &contracts.EnvListEnvironment{Name: envName}
+			envMap[envName] = env
+			envs = append(envs, env)
+		}
+
+		if name == DotEnvFileName {
+			env.DotEnvPath = file
+		} else {
+			env.ConfigPath = file
+		}
+	}
+
+	slices.SortFunc(envs, func(a, b *contracts.EnvListEnvironment) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return envs, nil
+}
+
+func (gs *GitDataStore) Get(ctx context.Context, name string) (*Environment, error) {
+	env := &Environment{name: name}
+	if err := gs.reload(ctx, env, true); err != nil {
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (gs *GitDataStore) Reload(ctx context.Context, env *Environment) error {
+	return gs.reload(ctx, env, false)
+}
+
+// reload reads the environment from the tip of the state branch and records that commit as the base for
+// conflict detection on the next Save. When mustExist is set, a missing environment is an ErrNotFound error.
+func (gs *GitDataStore) reload(ctx context.Context, env *Environment, mustExist bool) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	repo, unlock, err := gs.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	head, err := repo.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if head != "" {
+		if files, err = repo.listFiles(ctx, head, gs.repoPath(env.name)); err != nil {
+			return err
+		}
+	}
+
+	if mustExist && len(files) == 0 {
+		return fmt.Errorf("'%s': %w", env.name, ErrNotFound)
+	}
+
+	envMap := map[string]string{}
+	if slices.Contains(files, gs.EnvPath(env)) {
+		contents, err := repo.readFile(ctx, head, gs.EnvPath(env))
+		if err != nil {
+			return err
+		}
+
+		if parsed, err := godotenv.Parse(bytes.NewReader(contents)); err == nil {
+			envMap = parsed
+		}
+	}
+	env.replaceState(envMap, make(map[string]struct{}))
+
+	env.Config = config.NewEmptyConfig()
+	if slices.Contains(files, gs.ConfigPath(env)) {
+		contents, err := repo.readFile(ctx, head, gs.ConfigPath(env))
+		if err != nil {
+			return err
+		}
+
+		cfg, err := gs.configManager.Load(bytes.NewReader(contents))
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		env.Config = cfg
+	}
+
+	if err := repo.setBase(env.name, head); err != nil {
+		return err
+	}
+
+	if env.Name() != "" {
+		tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	}
+
+	return nil
+}
+
+func (gs *GitDataStore) Save(ctx context.Context, env *Environment, options *SaveOptions) error {
+	cfgWriter := new(bytes.Buffer)
+	if err := gs.configManager.Save(env.Config, cfgWriter); err != nil {
+		return fmt.Errorf("saving config: %w", err)
+	}
+
+	marshalled, err := marshallDotEnv(env)
+	if err != nil {
+		return fmt.Errorf("marshalling .env: %w", err)
+	}
+
+	isNew := options != nil && options.IsNew
+	err = gs.commit(ctx, env.name, fmt.Sprintf("Update environment %s", env.name), isNew, map[string][]byte{
+		gs.EnvPath(env):    []byte(marshalled + "\n"),
+		gs.ConfigPath(env): cfgWriter.Bytes(),
+	})
+	if err != nil {
+		return err
+	}
+
+	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
+	return nil
+}
+
+func (gs *GitDataStore) Delete(ctx context.Context, name string) error {
+	envs, err := gs.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(envs, func(env *contracts.EnvListEnvironment) bool { return env.Name == name }) {
+		return fmt.Errorf("'%s': %w", name, ErrNotFound)
+	}
+
+	// A nil content removes the file.
+	return gs.commit(ctx, name, fmt.Sprintf("Delete environment %s", name), false, map[string][]byte{
+		gs.repoPath(name, DotEnvFileName): nil,
+		gs.repoPath(name, ConfigFileName): nil,
+	})
+}
+
+// commit writes files for the named environment as a new commit on the state branch and pushes it. A nil
+// content removes the file. It fails with ErrRemoteConflict when the environment changed remotely since it
+// was last read or written, or when isNew is set and the environment already exists remotely.
+func (gs *GitDataStore) commit(
+	ctx context.Context, envName string, message string, isNew bool, files map[string][]byte,
+) error {
+	gs.mu.Lock()
+	defer gs.mu.Unlock()
+
+	repo, unlock, err := gs.open(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	envDir := gs.repoPath(envName)
+	base, err := repo.base(envName)
+	if err != nil {
+		return err
+	}
+
+	for range gitPushAttempts {
+		head, err := repo.fetch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := repo.checkConflict(ctx, envName, envDir, base, head, isNew); err != nil {
+			return err
+		}
+
+		commit, err := repo.commitFiles(ctx, head, message, files)
+		if err != nil {
+			return err
+		}
+
+		pushed, err := repo.push(ctx, commit)
+		if err != nil {
+			return err
+		}
+		if pushed {
+			if !slices.ContainsFunc(slices.Collect(maps.Values(files)), func(c []byte) bool { return c != nil }) {
+				// Every file was removed, so there is nothing left to compare against.
+				commit = ""
+			}
+			return repo.setBase(envName, commit)
+		}
+
+		log.Printf("push of environment '%s' to %s was rejected, retrying against the latest state", envName, repo.url)
+	}
+
+	return fmt.Errorf(
+		"'%s': %w: the '%s' branch kept changing while saving", envName, ErrRemoteConflict, gs.branch())
+}
+
+// open prepares the cache clone for the configured repository and locks it against other azd processes.
+// The returned function releases the lock.
+func (gs *GitDataStore) open(ctx context.Context) (*gitStateRepo, func(), error) {
+	url, err := gs.repositoryUrl(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheRoot := gs.cacheRoot
+	if cacheRoot == "" {
+		configDir, err := config.GetUserConfigDir()
+		if err != nil {
+			return nil, nil, err
+		}
+		cacheRoot = filepath.Join(configDir, "state", "git")
+	}
+
+	key := sha256.Sum256([]byte(url + "\x00" + gs.branch()))
+	repo := &gitStateRepo{
+		commandRunner: gs.commandRunner,
+		dir:           filepath.Join(cacheRoot, hex.EncodeToString(key[:8])),
+		url:           url,
+		branch:        gs.branch(),
+	}
+
+	// The cache clone is shared by every checkout of the repository, so the bases are kept with the checkout
+	// that loaded the environments. Without a project there is no checkout to keep them in.
+	repo.baseDir = filepath.Join(repo.dir, ".git", "azd-base")
+	if gs.azdContext != nil {
+		repo.baseDir = gs.azdContext.EnvironmentDirectory()
+	}
+
+	if err := os.MkdirAll(repo.dir, osutil.PermissionDirectory); err != nil {
+		return nil, nil, fmt.Errorf("creating git state cache: %w", err)
+	}
+
+	fl := flock.New(repo.dir + ".lock")
+	locked, err := fl.TryLockContext(ctx, envLockRetryDelay)
+	if err != nil {
+		return nil, nil, fmt.Errorf("locking git state cache: %w", err)
+	}
+	if !locked {
+		return nil, nil, fmt.Errorf("locking git state cache: %w", ctx.Err())
+	}
+	unlock := func() { releaseEnvLock(fl) }
+
+	if err := repo.init(ctx); err != nil {
+		unlock()
+		return nil, nil, err
+	}
+
+	return repo, unlock, nil
+}
+
+// repositoryUrl returns the configured repository, or the `origin` remote of the project repository.
+func (gs *GitDataStore) repositoryUrl(ctx context.Context) (string, error) {
+	if gs.config.Repository != "" {
+		return gs.config.Repository, nil
+	}
+
+	if gs.azdContext == nil {
+		return "", errors.New("git remote state: 'repository' is not configured and there is no project")
+	}
+
+	res, err := gs.commandRunner.Run(ctx, exec.NewRunArgs(
+		"git", "-C", gs.azdContext.ProjectDirectory(), "remote", "get-url", "origin"))
+	if err != nil {
+		return "", fmt.Errorf(
+			"git remote state: 'repository' is not configured and the project has no 'origin' remote: %w", err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+// gitStateRepo is a cache clone of a state repository. It only uses plumbing commands against a private
+// index, so it has no working tree to keep in sync.
+type gitStateRepo struct {
+	commandRunner exec.CommandRunner
+	dir           string
+	url           string
+	branch        string
+	// baseDir holds a directory per environment with the file recording its base commit.
+	baseDir string
+}
+
+func (r *gitStateRepo) git(ctx context.Context, args ...string) (exec.RunResult, error) {
+	return r.commandRunner.Run(ctx, exec.NewRunArgs("git", append([]string{"-C", r.dir}, args...)...))
+}
+
+func (r *gitStateRepo) remoteRef() string {
+	return "refs/remotes/origin/" + r.branch
+}
+
+func (r *gitStateRepo) init(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(r.dir, ".git")); err == nil {
+		_, err := r.git(ctx, "remote", "set-url", "origin", r.url)
+		return err
+	}
+
+	if _, err := r.git(ctx, "init", "--quiet"); err != nil {
+		return fmt.Errorf("initializing git state cache: %w", err)
+	}
+
+	if _, err := r.git(ctx, "remote", "add", "origin", r.url); err != nil {
+		return fmt.Errorf("initializing git state cache: %w", err)
+	}
+
+	return nil
+}
+
+// fetch updates the cache from the remote and returns the commit at the tip of the state branch, or an
+// empty string when the branch does not exist yet.
+func (r *gitStateRepo) fetch(ctx context.Context) (string, error) {
+	res, err := r.git(ctx,
+		"fetch", "--quiet", "--no-tags", "origin", fmt.Sprintf("+refs/heads/%s:%s", r.branch, r.remoteRef()))
+	if err != nil {
+		if strings.Contains(res.Stderr, "couldn't find remote ref") {
+			// The branch may have been deleted remotely; forget what we knew about it.
+			_, _ = r.git(ctx, "update-ref", "-d", r.remoteRef())
+			return "", nil
+		}
+
+		return "", fmt.Errorf("fetching '%s' from %s: %w", r.branch, r.url, err)
+	}
+
+	res, err = r.git(ctx, "rev-parse", "--verify", r.remoteRef())
+	if err != nil {
+		return "", fmt.Errorf("resolving '%s': %w", r.branch, err)
+	}
+
+	return strings.TrimSpace(res.Stdout), nil
+}
+
+// listFiles returns the paths of the files under dir at commit.
+func (r *gitStateRepo) listFiles(ctx context.Context, commit string, dir string) ([]string, error) {
+	args := []string{"ls-tree", "-r", "--name-only", "-z", commit}
+	if dir != "" && dir != "." {
+		args = append(args, "--", dir+"/")
+	}
+
+	res, err := r.git(ctx, args...)
+	if err != nil {
+		return nil, fmt.Errorf("listing remote environments: %w", err)
+	}
+
+	return slices.DeleteFunc(strings.Split(res.Stdout, "\x00"), func(s string) bool { return s == "" }), nil
+}
+
+func (r *gitStateRepo) readFile(ctx context.Context, commit string, file string) ([]byte, error) {
+	res, err := r.git(ctx, "cat-file", "blob", commit+":"+file)
+	if err != nil {
+		return nil, fmt.Errorf("reading '%s': %w", file, err)
+	}
+
+	return []byte(res.Stdout), nil
+}
+
+// checkConflict fails with ErrRemoteConflict when saving on top of head would overwrite remote changes to
+// the environment made since base.
+func (r *gitStateRepo) checkConflict(
+	ctx context.Context, envName string, envDir string, base string, head string, isNew bool,
+) error {
+	if head == "" || base == head {
+		return nil
+	}
+
+	if base == "" {
+		if !isNew {
+			// The environment was never read from this store, e.g. it was created before the remote
+			// backend was configured. There is nothing to compare against, so the save wins.
+			return nil
+		}
+
+		files, err := r.listFiles(ctx, head, envDir)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			return fmt.Errorf("'%s': %w: it already exists on the '%s' branch", envName, ErrRemoteConflict, r.branch)
+		}
+
+		return nil
+	}
+
+	_, err := r.git(ctx, "diff", "--quiet", base, head, "--", envDir+"/")
+	if exitErr, ok := errors.AsType[*exec.ExitError](err); ok && exitErr.ExitCode == 1 {
+		return fmt.Errorf(
+			"'%s': %w: it changed on the '%s' branch since it was last loaded. "+
+				"Move the local copy under .azure/%s out of the .azure folder, run the command again to load the "+
+				"latest values, and reapply your changes from the moved copy",
+			envName, ErrRemoteConflict, r.branch, envName)
+	} else if err != nil {
+		// The base commit is gone, e.g. the branch was force-pushed; the remote history can't be trusted.
+		return fmt.Errorf(
+			"'%s': %w: the history of the '%s' branch was rewritten", envName, ErrRemoteConflict, r.branch)
+	}
+
+	return nil
+}
+
+// commitFiles creates a commit on top of parent, which may be empty, with files written or, for nil
+// contents, removed. The project's working tree and index are never touched.
+func (r *gitStateRepo) commitFiles(
+	ctx context.Context, parent string, message string, files map[string][]byte,
+) (string, error) {
+	indexFile, err := os.CreateTemp("", "azd-git-state-index-*")
+	if err != nil {
+		return "", fmt.Errorf("creating git index: %w", err)
+	}
+	indexPath := indexFile.Name()
+	indexFile.Close()
+	// git refuses to read an empty file as an index, so start from no file at all.
+	_ = os.Remove(indexPath)
+	defer os.Remove(indexPath)
+
+	run := func(stdin []byte, args ...string) (string, error) {
+		runArgs := exec.NewRunArgs("git", append([]string{"-C", r.dir}, args...)...).
+			WithEnv(append([]string{"GIT_INDEX_FILE=" + indexPath}, r.identityEnv(ctx)...))
+		if stdin != nil {
+			runArgs = runArgs.WithStdIn(bytes.NewReader(stdin))
+		}
+
+		res, err := r.commandRunner.Run(ctx, runArgs)
+		if err != nil {
+			return "", fmt.Errorf("writing environment commit: %w", err)
+		}
+
+		return strings.TrimSpace(res.Stdout), nil
+	}
+
+	if parent != "" {
+		if _, err := run(nil, "read-tree", parent); err != nil {
+			return "", err
+		}
+	}
+
+	for _, file := range slices.Sorted(maps.Keys(files)) {
+		contents := files[file]
+		if contents == nil {
+			if _, err := run(nil, "update-index", "--force-remove", "--", file); err != nil {
+				return "", err
+			}
+			continue
+		}
+
+		blob, err := run(contents, "hash-object", "-w", "--stdin")
+		if err != nil {
+			return "", err
+		}
+
+		if _, err := run(nil, "update-index", "--add", "--cacheinfo", "100644,"+blob+","+file); err != nil {
+			return "", err
+		}
+	}
+
+	tree, err := run(nil, "write-tree")
+	if err != nil {
+		return "", err
+	}
+
+	args := []string{"commit-tree", tree, "-m", message}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+
+	return run(nil, args...)
+}
+
+// identityEnv supplies a committer identity when the user has none configured, so saving state never
+// fails on a machine where git was never set up for committing.
+func (r *gitStateRepo) identityEnv(ctx context.Context) []string {
+	if res, err := r.git(ctx, "config", "user.email"); err == nil && strings.TrimSpace(res.Stdout) != "" {
+		return nil
+	}
+
+	return []string{
+		"GIT_AUTHOR_NAME=azd",
+		"GIT_AUTHOR_EMAIL=azd@localhost",
+		"GIT_COMMITTER_NAME=azd",
+		"GIT_COMMITTER_EMAIL=azd@localhost",
+	}
+}
+
+// push publishes commit as the new tip of the state branch. It returns false when the remote rejected the
+// push because the branch moved since it was fetched.
+func (r *gitStateRepo) push(ctx context.Context, commit string) (bool, error) {
+	res, err := r.git(ctx, "push", "--quiet", "--porcelain", "origin", commit+":refs/heads/"+r.branch)
+	if err != nil {
+		output := res.Stdout + res.Stderr
+		if strings.Contains(output, "[rejected]") || strings.Contains(output, "non-fast-forward") ||
+			strings.Contains(output, "fetch first") {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("pushing '%s' to %s: %w", r.branch, r.url, err)
+	}
+
+	return true, nil
+}
+
+// basePath is where the commit an environment was last read or written at is recorded. It lives inside the
+// cache's .git directory so it is never part of the state branch.
+func (r *gitStateRepo) basePath(envName string) string {
+	return filepath.Join(r.baseDir, envName, gitBaseFileName)
+}
+
+func (r *gitStateRepo) base(envName string) (string, error) {
+	contents, err := os.ReadFile(r.basePath(envName))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("reading remote environment base: %w", err)
+	}
+
+	return strings.TrimSpace(string(contents)), nil
+}
+
+func (r *gitStateRepo) setBase(envName string, commit string) error {
+	if commit == "" {
+		if err := os.Remove(r.basePath(envName)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clearing remote environment base: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.basePath(envName)), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("recording remote environment base: %w", err)
+	}
+
+	if err := os.WriteFile(r.basePath(envName), []byte(commit+"\n"), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("recording remote environment base: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	osexec "os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/stretchr/testify/require"
+)
+
+// newBareRepo creates an empty bare repository to act as the shared remote.
+func newBareRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := osexec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := filepath.Join(t.TempDir(), "state.git")
+	out, err := osexec.Command("git", "init", "--bare", "--quiet", dir).CombinedOutput()
+	require.NoError(t, err, string(out))
+	return dir
+}
+
+// newGitDataStore returns a store for repo with its own cache, as if it ran on a separate machine.
+func newGitDataStore(t *testing.T, repo string, gitConfig *GitStateConfig) *GitDataStore {
+	t.Helper()
+	if gitConfig == nil {
+		gitConfig = &GitStateConfig{}
+	}
+	gitConfig.Repository = repo
+
+	store := NewGitDataStore(config.NewManager(), exec.NewCommandRunner(nil), nil, gitConfig).(*GitDataStore)
+	store.cacheRoot = t.TempDir()
+	return store
+}
+
+func newTestEnv(name string, values map[string]string) *Environment {
+	env := New(name)
+	for key, value := range values {
+		env.DotenvSet(key, value)
+	}
+	return env
+}
+
+func Test_GitDataStore_SaveAndGet(t *testing.T) {
+	repo := newBareRepo(t)
+	store := newGitDataStore(t, repo, &GitStateConfig{Path: "envs"})
+
+	envs, err := store.List(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, envs)
+
+	env := newTestEnv("dev", map[string]string{"AZURE_LOCATION": "westus2"})
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "S1"))
+	require.NoError(t, store.Save(t.Context(), env, &SaveOptions{IsNew: true}))
+	require.NoError(t, store.Save(t.Context(), newTestEnv("prod", nil), &SaveOptions{IsNew: true}))
+
+	// A second machine sees both environments.
+	other := newGitDataStore(t, repo, &GitStateConfig{Path: "envs"})
+	envs, err = other.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, envs, 2)
+	require.Equal(t, "dev", envs[0].Name)
+	require.Equal(t, "envs/dev/.env", envs[0].DotEnvPath)
+	require.Equal(t, "envs/dev/config.json", envs[0].ConfigPath)
+	require.Equal(t, "prod", envs[1].Name)
+
+	loaded, err := other.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Equal(t, "westus2", loaded.Getenv("AZURE_LOCATION"))
+	sku, has := loaded.Config.Get("infra.parameters.sku")
+	require.True(t, has)
+	require.Equal(t, "S1", sku)
+
+	_, err = other.Get(t.Context(), "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	// The state lives on its own branch.
+	out, err := osexec.Command("git", "-C", repo, "branch", "--list").CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.Contains(t, string(out), DefaultGitStateBranch)
+}
+
+func Test_GitDataStore_Reload(t *testing.T) {
+	repo := newBareRepo(t)
+	alice := newGitDataStore(t, repo, nil)
+	bob := newGitDataStore(t, repo, nil)
+
+	require.NoError(t, alice.Save(t.Context(), newTestEnv("dev", map[string]string{"KEY": "one"}), nil))
+
+	env, err := bob.Get(t.Context(), "dev")
+	require.NoError(t, err)
+
+	require.NoError(t, alice.Save(t.Context(), newTestEnv("dev", map[string]string{"KEY": "two"}), nil))
+	require.NoError(t, bob.Reload(t.Context(), env))
+	require.Equal(t, "two", env.Getenv("KEY"))
+}
+
+func Test_GitDataStore_Conflict(t *testing.T) {
+	repo := newBareRepo(t)
+	alice := newGitDataStore(t, repo, nil)
+	bob := newGitDataStore(t, repo, nil)
+
+	require.NoError(t, alice.Save(t.Context(), newTestEnv("dev", map[string]string{"KEY": "one"}), nil))
+
+	aliceEnv, err := alice.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	bobEnv, err := bob.Get(t.Context(), "dev")
+	require.NoError(t, err)
+
+	bobEnv.DotenvSet("KEY", "bob")
+	require.NoError(t, bob.Save(t.Context(), bobEnv, nil))
+
+	t.Run("StaleSaveIsRejected", func(t *testing.T) {
+		aliceEnv.DotenvSet("KEY", "alice")
+		err := alice.Save(t.Context(), aliceEnv, nil)
+		require.ErrorIs(t, err, ErrRemoteConflict)
+
+		// Bob's value is untouched.
+		env, err := newGitDataStore(t, repo, nil).Get(t.Context(), "dev")
+		require.NoError(t, err)
+		require.Equal(t, "bob", env.Getenv("KEY"))
+	})
+
+	t.Run("SaveAfterReloadSucceeds", func(t *testing.T) {
+		require.NoError(t, alice.Reload(t.Context(), aliceEnv))
+		aliceEnv.DotenvSet("KEY", "alice")
+		require.NoError(t, alice.Save(t.Context(), aliceEnv, nil))
+	})
+
+	t.Run("ChangesToOtherEnvironmentsDoNotConflict", func(t *testing.T) {
+		require.NoError(t, bob.Save(t.Context(), newTestEnv("prod", nil), &SaveOptions{IsNew: true}))
+		aliceEnv.DotenvSet("OTHER", "value")
+		require.NoError(t, alice.Save(t.Context(), aliceEnv, nil))
+	})
+
+	t.Run("NewEnvironmentAlreadyExists", func(t *testing.T) {
+		carol := newGitDataStore(t, repo, nil)
+		err := carol.Save(t.Context(), newTestEnv("dev", nil), &SaveOptions{IsNew: true})
+		require.ErrorIs(t, err, ErrRemoteConflict)
+	})
+}
+
+func Test_GitDataStore_SeparateCheckouts(t *testing.T) {
+	repo := newBareRepo(t)
+	cacheRoot := t.TempDir()
+
+	// Two checkouts of the same project on one machine share the cache clone of the state repository.
+	newCheckoutStore := func() *GitDataStore {
+		store := NewGitDataStore(
+			config.NewManager(),
+			exec.NewCommandRunner(nil),
+			azdcontext.NewAzdContextWithDirectory(t.TempDir()),
+			&GitStateConfig{Repository: repo},
+		).(*GitDataStore)
+		store.cacheRoot = cacheRoot
+		return store
+	}
+	first := newCheckoutStore()
+	second := newCheckoutStore()
+
+	require.NoError(t, first.Save(t.Context(), newTestEnv("dev", map[string]string{"KEY": "one"}), nil))
+	firstEnv, err := first.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	require.FileExists(t, filepath.Join(first.azdContext.EnvironmentRoot("dev"), gitBaseFileName))
+
+	secondEnv, err := second.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	secondEnv.DotenvSet("KEY", "two")
+	require.NoError(t, second.Save(t.Context(), secondEnv, nil))
+
+	// Loading and saving in the second checkout does not move the base of the first one.
+	firstEnv.DotenvSet("KEY", "stale")
+	err = first.Save(t.Context(), firstEnv, nil)
+	require.ErrorIs(t, err, ErrRemoteConflict)
+	require.Contains(t, err.Error(), "out of the .azure folder")
+}
+
+func Test_GitDataStore_Delete(t *testing.T) {
+	repo := newBareRepo(t)
+	store := newGitDataStore(t, repo, &GitStateConfig{Branch: "state"})
+
+	require.NoError(t, store.Save(t.Context(), newTestEnv("dev", nil), &SaveOptions{IsNew: true}))
+	require.NoError(t, store.Save(t.Context(), newTestEnv("prod", nil), &SaveOptions{IsNew: true}))
+	require.NoError(t, store.Delete(t.Context(), "dev"))
+	require.ErrorIs(t, store.Delete(t.Context(), "dev"), ErrNotFound)
+
+	envs, err := store.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, envs, 1)
+	require.Equal(t, "prod", envs[0].Name)
+
+	// The environment can be created again once deleted.
+	require.NoError(t, store.Save(t.Context(), newTestEnv("dev", nil), &SaveOptions{IsNew: true}))
+}
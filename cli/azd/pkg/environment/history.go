@@ -0,0 +1,177 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/baggage"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/events"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// HistoryFileName is the name of the file, next to the .env file, holding the revision log of an environment.
+const HistoryFileName = "history.json"
+
+// MaxHistoryRevisions bounds the revision log; the oldest revisions are dropped first.
+const MaxHistoryRevisions = 50
+
+// Revision is an entry in the revision log of an environment, recorded each time a save changes its .env or
+// config.json. It holds the full state after the change so it can be restored.
+type Revision struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	User    string    `json:"user,omitempty"`
+	Command string    `json:"command,omitempty"`
+	Changes []Change  `json:"changes"`
+
+	DotEnv map[string]string `json:"dotenv"`
+	Config map[string]any    `json:"config,omitempty"`
+}
+
+// Apply replaces the values and configuration of env with the state recorded in the revision. The
+// environment still needs to be saved.
+func (r *Revision) Apply(env *Environment) {
+	for key := range env.Dotenv() {
+		if _, has := r.DotEnv[key]; !has {
+			env.DotenvDelete(key)
+		}
+	}
+
+	for key, value := range r.DotEnv {
+		env.DotenvSet(key, value)
+	}
+
+	env.Config = config.NewConfig(r.Config)
+}
+
+// historyPath returns the path of the revision log of the named environment.
+func (fs *LocalFileDataStore) historyPath(name string) string {
+	return filepath.Join(fs.azdContext.EnvironmentRoot(name), HistoryFileName)
+}
+
+// History returns the revisions of the named environment, oldest first.
+func (fs *LocalFileDataStore) History(ctx context.Context, name string) ([]*Revision, error) {
+	if _, err := os.Stat(fs.azdContext.EnvironmentRoot(name)); errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("'%s': %w", name, ErrNotFound)
+	} else if err != nil {
+		return nil, fmt.Errorf("listing env root: %w", err)
+	}
+
+	fl, err := fs.acquireEnvLock(ctx, New(name))
+	if err != nil {
+		return nil, err
+	}
+	defer releaseEnvLock(fl)
+
+	return fs.readHistory(name)
+}
+
+func (fs *LocalFileDataStore) readHistory(name string) ([]*Revision, error) {
+	contents, err := os.ReadFile(fs.historyPath(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return []*Revision{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading environment history: %w", err)
+	}
+
+	var revisions []*Revision
+	if err := json.Unmarshal(contents, &revisions); err != nil {
+		return nil, fmt.Errorf("parsing environment history: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// recordRevision appends a revision to the log of the named environment when the state changed. Caller MUST
+// hold the env file lock. Failures are logged rather than returned: losing a history entry must never fail
+// the save that produced it.
+func (fs *LocalFileDataStore) recordRevision(
+	ctx context.Context,
+	name string,
+	oldDotEnv map[string]string,
+	oldConfig map[string]any,
+	newDotEnv map[string]string,
+	newConfig map[string]any,
+) {
+	changes := diffValues(oldDotEnv, newDotEnv, false)
+	changes = append(changes, diffValues(flattenConfig(oldConfig), flattenConfig(newConfig), true)...)
+	if len(changes) == 0 {
+		return
+	}
+
+	revisions, err := fs.readHistory(name)
+	if err != nil {
+		// A corrupt log is replaced rather than blocking history forever.
+		log.Printf("discarding environment history for '%s': %v", name, err)
+		revisions = nil
+	}
+
+	id := 1
+	if len(revisions) > 0 {
+		id = revisions[len(revisions)-1].ID + 1
+	}
+
+	revisions = append(revisions, &Revision{
+		ID:      id,
+		Time:    time.Now().UTC(),
+		User:    historyUser(),
+		Command: historyCommand(ctx),
+		Changes: changes,
+		DotEnv:  newDotEnv,
+		Config:  newConfig,
+	})
+	if len(revisions) > MaxHistoryRevisions {
+		revisions = revisions[len(revisions)-MaxHistoryRevisions:]
+	}
+
+	contents, err := json.MarshalIndent(revisions, "", "  ")
+	if err != nil {
+		log.Printf("failed to marshal environment history for '%s': %v", name, err)
+		return
+	}
+
+	// Same permissions as the .env file: the log holds the same values.
+	if err := os.WriteFile(fs.historyPath(name), contents, osutil.PermissionFile); err != nil {
+		log.Printf("failed to write environment history for '%s': %v", name, err)
+	}
+}
+
+// historyUser returns who made a change, as the local user name.
+func historyUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+
+	for _, name := range []string{"USER", "USERNAME"} {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// historyCommand returns the azd command making a change, e.g. "azd env set". It never includes arguments,
+// which may hold the secret being set.
+func historyCommand(ctx context.Context) string {
+	entry, ok := baggage.BaggageFromContext(ctx).Lookup(fields.CmdEntry.Key)
+	if !ok || entry.AsString() == "" {
+		return ""
+	}
+
+	command := strings.TrimPrefix(entry.AsString(), events.CommandEventPrefix)
+	return "azd " + strings.ReplaceAll(command, ".", " ")
+}
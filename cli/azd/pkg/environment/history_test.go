@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/internal/tracing"
+	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
+	"github.com/stretchr/testify/require"
+)
+
+func newHistoryDataStore(t *testing.T) *LocalFileDataStore {
+	azdContext := azdcontext.NewAzdContextWithDirectory(t.TempDir())
+	fileConfigManager := config.NewFileConfigManager(config.NewManager())
+	return NewLocalFileDataStore(azdContext, fileConfigManager).(*LocalFileDataStore)
+}
+
+func Test_LocalFileDataStore_History(t *testing.T) {
+	dataStore := newHistoryDataStore(t)
+	ctx := tracing.SetBaggageInContext(t.Context(), fields.CmdEntry.String("cmd.env.set"))
+
+	env := New("dev")
+	env.DotenvSet("AZURE_LOCATION", "westus2")
+	env.DotenvSet("API_KEY", "first")
+	require.NoError(t, dataStore.Save(ctx, env, nil))
+
+	// Saving without changes records nothing.
+	require.NoError(t, dataStore.Save(ctx, env, nil))
+
+	env.DotenvSet("AZURE_LOCATION", "eastus")
+	env.DotenvDelete("API_KEY")
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "S1"))
+	require.NoError(t, dataStore.Save(ctx, env, nil))
+
+	revisions, err := dataStore.History(ctx, "dev")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	first := revisions[0]
+	require.Equal(t, 1, first.ID)
+	require.Equal(t, "azd env set", first.Command)
+	require.NotEmpty(t, first.User)
+	require.Equal(t, []Change{
+		{Key: "API_KEY", Kind: ChangeAdded, New: "first"},
+		{Key: "AZURE_ENV_NAME", Kind: ChangeAdded, New: "dev"},
+		{Key: "AZURE_LOCATION", Kind: ChangeAdded, New: "westus2"},
+	}, first.Changes)
+
+	second := revisions[1]
+	require.Equal(t, 2, second.ID)
+	require.Equal(t, []Change{
+		{Key: "API_KEY", Kind: ChangeRemoved, Old: "first"},
+		{Key: "AZURE_LOCATION", Kind: ChangeModified, Old: "westus2", New: "eastus"},
+		{Key: "infra.parameters.sku", Kind: ChangeAdded, Config: true, New: "S1"},
+	}, second.Changes)
+
+	_, err = dataStore.History(ctx, "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func Test_LocalFileDataStore_HistoryIsBounded(t *testing.T) {
+	dataStore := newHistoryDataStore(t)
+
+	env := New("dev")
+	for i := range MaxHistoryRevisions + 5 {
+		env.DotenvSet("COUNTER", fmt.Sprint(i))
+		require.NoError(t, dataStore.Save(t.Context(), env, nil))
+	}
+
+	revisions, err := dataStore.History(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Len(t, revisions, MaxHistoryRevisions)
+	require.Equal(t, 6, revisions[0].ID)
+	require.Equal(t, MaxHistoryRevisions+5, revisions[len(revisions)-1].ID)
+}
+
+func Test_Revision_Apply(t *testing.T) {
+	dataStore := newHistoryDataStore(t)
+
+	env := New("dev")
+	env.DotenvSet("KEEP", "old")
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "S1"))
+	require.NoError(t, dataStore.Save(t.Context(), env, nil))
+
+	env.DotenvSet("KEEP", "clobbered")
+	env.DotenvSet("EXTRA", "value")
+	require.NoError(t, env.Config.Set("infra.parameters.sku", "S2"))
+	require.NoError(t, dataStore.Save(t.Context(), env, nil))
+
+	revisions, err := dataStore.History(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+
+	revisions[0].Apply(env)
+	require.NoError(t, dataStore.Save(t.Context(), env, nil))
+
+	restored, err := dataStore.Get(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"AZURE_ENV_NAME": "dev", "KEEP": "old"}, restored.Dotenv())
+	sku, _ := restored.Config.Get("infra.parameters.sku")
+	require.Equal(t, "S1", sku)
+
+	// The rollback itself is a revision.
+	revisions, err = dataStore.History(t.Context(), "dev")
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+}
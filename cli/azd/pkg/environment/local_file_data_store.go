@@ -226,6 +226,12 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 	}
 	defer releaseEnvLock(fl)
 
+	// Remember the configuration being replaced for the revision log.
+	var oldConfig map[string]any
+	if cfg, err := fs.configManager.Load(fs.ConfigPath(env)); err == nil {
+		oldConfig = cfg.Raw()
+	}
+
 	// Update configuration (under the lock so concurrent readers never
 	// observe a half-written config.json).
 	if err := fs.configManager.Save(env.Config, fs.ConfigPath(env)); err != nil {
@@ -244,6 +250,7 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 	if err := fs.reloadLocked(ctx, env); err != nil {
 		return fmt.Errorf("failed reloading env vars, %w", err)
 	}
+	oldDotEnv := env.Dotenv()
 
 	// Overlay cached values and replay deletions under Lock so we don't race
 	// with concurrent DotenvSet/DotenvDelete on the new env.dotenv map.
@@ -304,6 +311,8 @@ func (fs *LocalFileDataStore) Save(ctx context.Context, env *Environment, option
 		return fmt.Errorf("renaming temp .env: %w", err)
 	}
 
+	fs.recordRevision(ctx, env.name, oldDotEnv, oldConfig, env.Dotenv(), env.Config.Raw())
+
 	tracing.SetUsageAttributes(fields.StringHashed(fields.EnvNameKey, env.Name()))
 	return nil
 }
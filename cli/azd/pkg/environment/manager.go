@@ -17,6 +17,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 )
@@ -40,6 +41,10 @@ type Spec struct {
 	Name         string
 	Subscription string
 	Location     string
+	// Values are the initial .env values. Subscription and Location take precedence over them.
+	Values map[string]string
+	// Config is the initial content of config.json.
+	Config map[string]any
 	// suggest is the name that is offered as a suggestion if we need to prompt the user for an environment name.
 	Examples []string
 }
@@ -82,6 +87,9 @@ type Manager interface {
 	// Delete deletes the environment from local storage.
 	Delete(ctx context.Context, name string) error
 
+	// History returns the revisions recorded for the environment with the given name, oldest first.
+	History(ctx context.Context, name string) ([]*Revision, error)
+
 	EnvPath(env *Environment) string
 	ConfigPath(env *Environment) string
 
@@ -129,6 +137,16 @@ type manager struct {
 
 	// State cache manager for managing cached Azure resource information
 	stateCacheManager *state.StateCacheManager
+
+	// templates hold the defaults of new environments, from the `environments` section of azure.yaml.
+	templates Templates
+
+	// schema describes the values the project relies on, from the `env` section of azure.yaml.
+	schema Schema
+
+	// schemaWarned holds the names of the environments whose schema violations were already reported, guarded by
+	// cacheMu.
+	schemaWarned map[string]bool
 }
 
 // NewManager creates a new Manager instance
@@ -159,6 +177,18 @@ func NewManager(
 		}
 	}
 
+	// Environment templates and schema are only available within a project.
+	var templates Templates
+	var schema Schema
+	if serviceLocator != nil {
+		if err := serviceLocator.Resolve(&templates); err != nil && !errors.Is(err, ioc.ErrResolveInstance) {
+			return nil, fmt.Errorf("resolving environment templates: %w", err)
+		}
+		if err := serviceLocator.Resolve(&schema); err != nil && !errors.Is(err, ioc.ErrResolveInstance) {
+			return nil, fmt.Errorf("resolving environment schema: %w", err)
+		}
+	}
+
 	// Initialize state cache manager with environment directory path
 	// If azdContext is nil (no project), use empty path (cache won't be usable)
 	envDir := ""
@@ -173,6 +203,8 @@ func NewManager(
 		console:           console,
 		envCache:          make(map[string]*Environment),
 		stateCacheManager: state.NewStateCacheManager(envDir),
+		templates:         templates,
+		schema:            schema,
 	}, nil
 }
 
@@ -197,7 +229,18 @@ func (m *manager) Create(ctx context.Context, spec Spec) (*Environment, error) {
 		return nil, fmt.Errorf("%w: '%s'", ErrExists, spec.Name)
 	}
 
-	env := New(spec.Name)
+	env, err := m.newEnvironment(spec.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mergeConfig(env, "", spec.Config); err != nil {
+		return nil, err
+	}
+
+	for key, value := range spec.Values {
+		env.DotenvSet(key, value)
+	}
 
 	if spec.Subscription != "" {
 		env.SetSubscriptionId(spec.Subscription)
@@ -223,6 +266,17 @@ func (m *manager) LoadOrInitInteractive(ctx context.Context, environmentName str
 		return nil, err
 	}
 
+	prompted, err := m.promptMissingValues(ctx, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if prompted && !isNew {
+		if err := m.Save(ctx, env); err != nil {
+			return nil, err
+		}
+	}
+
 	if isNew {
 		if err := m.SaveWithOptions(ctx, env, &SaveOptions{IsNew: isNew}); err != nil {
 			return nil, err
@@ -365,7 +419,78 @@ func (m *manager) loadOrInitEnvironment(ctx context.Context, environmentName str
 		return nil, false, err
 	}
 
-	return New(spec.Name), true, nil
+	env, err := m.newEnvironment(spec.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return env, true, nil
+}
+
+// promptMissingValues prompts for the values the schema requires that env does not have. It reports whether any
+// value was set; the environment still needs to be saved.
+func (m *manager) promptMissingValues(ctx context.Context, env *Environment) (bool, error) {
+	missing := m.schema.MissingKeys(env.Dotenv())
+	if len(missing) == 0 {
+		return false, nil
+	}
+
+	if m.console.IsNoPromptMode() {
+		inputs := make([]input.RequiredInput, len(missing))
+		for i, key := range missing {
+			inputs[i] = input.RequiredInput{
+				Name:        key,
+				Description: m.schema[key].Description,
+				Sources: []input.InputSource{
+					{Kind: input.InputSourceEnvironment, Name: key},
+				},
+			}
+		}
+
+		return false, &input.PromptRequiredError{
+			Message: fmt.Sprintf("Environment '%s' is missing values required by azure.yaml", env.Name()),
+			Inputs:  inputs,
+		}
+	}
+
+	for _, key := range missing {
+		variable := m.schema[key]
+		for {
+			value, err := m.console.Prompt(ctx, input.ConsoleOptions{
+				Message:    fmt.Sprintf("Enter a value for %s:", key),
+				Help:       variable.Description,
+				IsPassword: variable.Secret,
+			})
+			if err != nil {
+				return false, fmt.Errorf("prompting for '%s': %w", key, err)
+			}
+
+			if value == "" {
+				m.console.Message(ctx, output.WithErrorFormat("%s is required.", key))
+				continue
+			}
+
+			if err := m.schema.CheckValue(key, value); err != nil {
+				m.console.Message(ctx, output.WithErrorFormat("%s %s.", key, err.Error()))
+				continue
+			}
+
+			env.DotenvSet(key, value)
+			break
+		}
+	}
+
+	return true, nil
+}
+
+// newEnvironment returns a new environment with the defaults of the templates matching its name.
+func (m *manager) newEnvironment(name string) (*Environment, error) {
+	env := New(name)
+	if err := m.templates.Apply(env); err != nil {
+		return nil, err
+	}
+
+	return env, nil
 }
 
 // ConfigPath returns the path to the environment config file
@@ -475,6 +600,11 @@ func (m *manager) Get(ctx context.Context, name string) (*Environment, error) {
 		localEnv = remoteEnv
 	}
 
+	// Reported rather than returned, so the values can still be fixed, e.g. with 'azd env set'.
+	if err := m.schema.Check(localEnv.Dotenv(), false); err != nil && m.markSchemaWarned(name) {
+		m.warnSchemaViolation(name, err)
+	}
+
 	// Ensures local environment variable name is synced with the environment name
 	envName, ok := localEnv.LookupEnv(EnvNameEnvVarName)
 	if !ok || envName != name {
@@ -530,6 +660,14 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 	m.saveMu.Lock()
 	defer m.saveMu.Unlock()
 
+	if err := m.checkChangedValues(ctx, env); err != nil {
+		if !options.WarnOnSchemaViolation {
+			return fmt.Errorf("saving environment '%s': %w", env.Name(), err)
+		}
+
+		m.warnSchemaViolation(env.Name(), err)
+	}
+
 	if err := m.local.Save(ctx, env, options); err != nil {
 		return fmt.Errorf("saving local environment, %w", err)
 	}
@@ -545,6 +683,57 @@ func (m *manager) SaveWithOptions(ctx context.Context, env *Environment, options
 	return nil
 }
 
+// checkChangedValues checks the values of env that differ from the saved ones against the schema. Required values may
+// be set later, e.g. by provisioning, and values that were already saved are only reported by Get, so that a malformed
+// value does not block saving the others.
+func (m *manager) checkChangedValues(ctx context.Context, env *Environment) error {
+	if len(m.schema) == 0 {
+		return nil
+	}
+
+	var saved map[string]string
+	savedEnv, err := m.local.Get(ctx, env.Name())
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	} else if err == nil {
+		saved = savedEnv.Dotenv()
+	}
+
+	changed := map[string]string{}
+	for key, value := range env.Dotenv() {
+		if savedValue, has := saved[key]; !has || savedValue != value {
+			changed[key] = value
+		}
+	}
+
+	return m.schema.Check(changed, false)
+}
+
+// warnSchemaViolation reports the schema violations of the named environment on stderr, so that they do not mix with
+// structured output.
+func (m *manager) warnSchemaViolation(name string, err error) {
+	fmt.Fprintf(m.console.Handles().Stderr, "%s environment '%s': %s\n",
+		output.WithWarningFormat("WARNING:"), name, err.Error())
+}
+
+// markSchemaWarned records that the schema violations of the named environment were reported, and returns whether
+// they were not reported before.
+func (m *manager) markSchemaWarned(name string) bool {
+	m.cacheMu.Lock()
+	defer m.cacheMu.Unlock()
+
+	if m.schemaWarned[name] {
+		return false
+	}
+
+	if m.schemaWarned == nil {
+		m.schemaWarned = map[string]bool{}
+	}
+
+	m.schemaWarned[name] = true
+	return true
+}
+
 // Reload reloads the environment from the persistent data store
 func (m *manager) Reload(ctx context.Context, env *Environment) error {
 	// Reload swaps the in-memory dotenv map; serialize against Save so that
@@ -585,6 +774,23 @@ func (m *manager) Delete(ctx context.Context, name string) error {
 	return nil
 }
 
+// History returns the revisions recorded for the environment with the given name, oldest first.
+// Only local environments keep a revision log.
+func (m *manager) History(ctx context.Context, name string) ([]*Revision, error) {
+	if name == "" {
+		return nil, ErrNameNotSpecified
+	}
+
+	history, ok := m.local.(interface {
+		History(ctx context.Context, name string) ([]*Revision, error)
+	})
+	if !ok {
+		return []*Revision{}, nil
+	}
+
+	return history.History(ctx, name)
+}
+
 // ensureValidEnvironmentName ensures the environment name is valid, if it is not, an error is printed
 // and the user is prompted for a new name.
 // In --no-prompt mode, when no name is provided, the name is auto-generated from the working directory basename.
@@ -4,6 +4,7 @@
 package environment
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -21,8 +22,10 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment/azdcontext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/state"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockinput"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -127,6 +130,18 @@ func Test_EnvManager_PromptEnvironmentName(t *testing.T) {
 	})
 }
 
+// stderrConsole is a mock console that keeps what is written to stderr.
+type stderrConsole struct {
+	*mockinput.MockConsole
+	stderr *bytes.Buffer
+}
+
+func (c *stderrConsole) Handles() input.ConsoleHandles {
+	handles := c.MockConsole.Handles()
+	handles.Stderr = c.stderr
+	return handles
+}
+
 func createEnvManagerForManagerTest(t *testing.T, mockContext *mocks.MockContext) Manager {
 	azdCtx := azdcontext.NewAzdContextWithDirectory(t.TempDir())
 	localDataStore := NewLocalFileDataStore(azdCtx, config.NewFileConfigManager(config.NewManager()))
@@ -827,3 +842,152 @@ func Test_CleanName_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func Test_EnvManager_Create_WithTemplatesAndValues(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+	envManager.templates = Templates{
+		"pr-*": {
+			Values: map[string]osutil.ExpandableString{
+				"AZURE_LOCATION":       osutil.NewExpandableString("eastus2"),
+				"AZURE_RESOURCE_GROUP": osutil.NewExpandableString("rg-${AZURE_ENV_NAME}"),
+			},
+			Config: map[string]any{"infra": map[string]any{"parameters": map[string]any{"sku": "B1"}}},
+		},
+	}
+
+	_, err := envManager.Create(*mockContext.Context, Spec{
+		Name:     "pr-42",
+		Values:   map[string]string{"AZURE_LOCATION": "westus2", "API_URL": "https://api"},
+		Config:   map[string]any{"infra": map[string]any{"parameters": map[string]any{"replicas": 2}}},
+		Location: "westus3",
+	})
+	require.NoError(t, err)
+
+	saved, err := envManager.Get(*mockContext.Context, "pr-42")
+	require.NoError(t, err)
+	require.Equal(t, "westus3", saved.GetLocation())
+	require.Equal(t, "rg-pr-42", saved.Getenv("AZURE_RESOURCE_GROUP"))
+	require.Equal(t, "https://api", saved.Getenv("API_URL"))
+	sku, _ := saved.Config.Get("infra.parameters.sku")
+	require.Equal(t, "B1", sku)
+	replicas, _ := saved.Config.Get("infra.parameters.replicas")
+	require.Equal(t, float64(2), replicas)
+
+	// Templates only apply to matching names.
+	other, err := envManager.Create(*mockContext.Context, Spec{Name: "dev"})
+	require.NoError(t, err)
+	require.Empty(t, other.GetLocation())
+}
+
+func Test_EnvManager_Schema(t *testing.T) {
+	schema := Schema{
+		"API_PORT": {Type: VariableTypeInteger},
+		"API_KEY":  {Required: true, Secret: true, Description: "Key of the API."},
+	}
+
+	t.Run("SaveRejectsMalformedValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+		envManager.schema = schema
+
+		// Missing required values do not block saving.
+		env, err := envManager.Create(*mockContext.Context, Spec{Name: "dev"})
+		require.NoError(t, err)
+
+		env.DotenvSet("API_PORT", "http")
+		err = envManager.Save(*mockContext.Context, env)
+		require.ErrorIs(t, err, ErrSchemaViolation)
+		require.ErrorContains(t, err, "API_PORT must be an integer")
+	})
+
+	t.Run("SavedMalformedValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+		envManager.schema = schema
+		stderr := &bytes.Buffer{}
+		envManager.console = &stderrConsole{MockConsole: mockContext.Console, stderr: stderr}
+
+		// A value saved before the schema declared it, or edited by hand.
+		saved := New("dev")
+		saved.DotenvSet("API_PORT", "http")
+		require.NoError(t, envManager.local.Save(*mockContext.Context, saved, nil))
+
+		env, err := envManager.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		_, err = envManager.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+
+		// Warnings go to stderr, so they do not mix with structured output.
+		require.Equal(t, 1, strings.Count(stderr.String(), "API_PORT must be an integer"))
+		require.Empty(t, mockContext.Console.Output())
+
+		// The malformed value does not block saving other values, but changing it requires a valid value.
+		env.DotenvSet("API_KEY", "secret")
+		require.NoError(t, envManager.Save(*mockContext.Context, env))
+
+		env.DotenvSet("API_PORT", "https")
+		require.ErrorIs(t, envManager.Save(*mockContext.Context, env), ErrSchemaViolation)
+
+		env.DotenvSet("API_PORT", "8080")
+		require.NoError(t, envManager.Save(*mockContext.Context, env))
+	})
+
+	t.Run("WarnOnSchemaViolation", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+		envManager.schema = schema
+		stderr := &bytes.Buffer{}
+		envManager.console = &stderrConsole{MockConsole: mockContext.Console, stderr: stderr}
+
+		env, err := envManager.Create(*mockContext.Context, Spec{Name: "dev"})
+		require.NoError(t, err)
+
+		// Values such as provisioning outputs are saved anyway, as they were already applied.
+		env.DotenvSet("API_PORT", "http")
+		err = envManager.SaveWithOptions(*mockContext.Context, env, &SaveOptions{WarnOnSchemaViolation: true})
+		require.NoError(t, err)
+		require.Contains(t, stderr.String(), "API_PORT must be an integer")
+
+		saved, err := envManager.local.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "http", saved.Getenv("API_PORT"))
+	})
+
+	t.Run("PromptsForMissingValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.Console.WhenConfirm(func(options input.ConsoleOptions) bool {
+			return strings.Contains(options.Message, "would you like to create it?")
+		}).Respond(true)
+		mockContext.Console.WhenPrompt(func(options input.ConsoleOptions) bool {
+			return options.Message == "Enter a value for API_KEY:" && options.IsPassword
+		}).Respond("secret")
+
+		envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+		envManager.schema = schema
+
+		env, err := envManager.LoadOrInitInteractive(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "secret", env.Getenv("API_KEY"))
+
+		saved, err := envManager.local.Get(*mockContext.Context, "dev")
+		require.NoError(t, err)
+		require.Equal(t, "secret", saved.Getenv("API_KEY"))
+	})
+
+	t.Run("NoPromptReportsMissingValues", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.Console.SetNoPromptMode(true)
+
+		envManager := createEnvManagerForManagerTest(t, mockContext).(*manager)
+		envManager.schema = schema
+		_, err := envManager.Create(*mockContext.Context, Spec{Name: "dev"})
+		require.NoError(t, err)
+
+		_, err = envManager.LoadOrInitInteractive(*mockContext.Context, "dev")
+		promptErr, ok := errors.AsType[*input.PromptRequiredError](err)
+		require.True(t, ok)
+		require.Len(t, promptErr.Inputs, 1)
+		require.Equal(t, "API_KEY", promptErr.Inputs[0].Name)
+	})
+}
@@ -0,0 +1,168 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrSchemaViolation is returned when environment values do not match the `env` schema of azure.yaml.
+var ErrSchemaViolation = errors.New("environment values do not match the schema")
+
+// VariableType is the type of an environment value, which are always stored as strings.
+type VariableType string
+
+const (
+	VariableTypeString  VariableType = "string"
+	VariableTypeInteger VariableType = "integer"
+	VariableTypeNumber  VariableType = "number"
+	VariableTypeBoolean VariableType = "boolean"
+)
+
+// VariableSchema describes an environment value the project relies on, as configured in the `env` section of
+// azure.yaml.
+type VariableSchema struct {
+	Description string `yaml:"description,omitempty"`
+	// Required values are prompted for when an environment is loaded interactively.
+	Required bool `yaml:"required,omitempty"`
+	// Type defaults to string.
+	Type VariableType `yaml:"type,omitempty"`
+	// Pattern is a regular expression the value must match.
+	Pattern string   `yaml:"pattern,omitempty"`
+	Enum    []string `yaml:"enum,omitempty"`
+	// Secret values are prompted for without echo, and never displayed in errors.
+	Secret bool `yaml:"secret,omitempty"`
+}
+
+// Schema maps environment keys to the description of their values.
+type Schema map[string]*VariableSchema
+
+// Validate checks that the schema itself is well formed.
+func (s Schema) Validate() error {
+	for _, key := range slices.Sorted(maps.Keys(s)) {
+		variable := s[key]
+		if variable == nil {
+			continue
+		}
+
+		switch variable.Type {
+		case "", VariableTypeString, VariableTypeInteger, VariableTypeNumber, VariableTypeBoolean:
+		default:
+			return fmt.Errorf("env '%s': unsupported type '%s'", key, variable.Type)
+		}
+
+		if variable.Pattern != "" {
+			if _, err := regexp.Compile(variable.Pattern); err != nil {
+				return fmt.Errorf("env '%s': invalid pattern: %w", key, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// IsSecret reports whether the schema marks key as a secret.
+func (s Schema) IsSecret(key string) bool {
+	return s[key] != nil && s[key].Secret
+}
+
+// CheckValue returns an error describing why value is not valid for key, or nil when it is valid or key is not
+// in the schema.
+func (s Schema) CheckValue(key string, value string) error {
+	variable := s[key]
+	if variable == nil {
+		return nil
+	}
+
+	switch variable.Type {
+	case VariableTypeInteger:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return errors.New("must be an integer")
+		}
+	case VariableTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case VariableTypeBoolean:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("must be true or false")
+		}
+	}
+
+	if len(variable.Enum) > 0 && !slices.Contains(variable.Enum, value) {
+		return fmt.Errorf("must be one of %s", strings.Join(variable.Enum, ", "))
+	}
+
+	if variable.Pattern != "" {
+		re, err := regexp.Compile(variable.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern: %w", err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("must match the pattern '%s'", variable.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// MissingKeys returns the required keys that have no value, sorted.
+func (s Schema) MissingKeys(values map[string]string) []string {
+	var missing []string
+	for _, key := range slices.Sorted(maps.Keys(s)) {
+		if s[key] != nil && s[key].Required && values[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	return missing
+}
+
+// Check returns a *SchemaError listing the values that do not match the schema, or nil when all do. Missing
+// required keys are only reported when checkRequired is set.
+func (s Schema) Check(values map[string]string, checkRequired bool) error {
+	var violations []string
+	for _, key := range slices.Sorted(maps.Keys(s)) {
+		value, has := values[key]
+		if !has || value == "" {
+			continue
+		}
+
+		if err := s.CheckValue(key, value); err != nil {
+			violations = append(violations, fmt.Sprintf("%s %s", key, err.Error()))
+		}
+	}
+
+	if checkRequired {
+		for _, key := range s.MissingKeys(values) {
+			violations = append(violations, fmt.Sprintf("%s is required", key))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return &SchemaError{Violations: violations}
+}
+
+// SchemaError lists the environment values that do not match the schema. Values themselves are never included,
+// as they may be secrets.
+type SchemaError struct {
+	Violations []string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrSchemaViolation.Error(), strings.Join(e.Violations, "; "))
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaViolation
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSchema(t *testing.T) Schema {
+	var schema Schema
+	require.NoError(t, yaml.Unmarshal([]byte(`
+AZURE_LOCATION:
+  required: true
+  pattern: ^[a-z0-9]+$
+API_PORT:
+  type: integer
+LOG_LEVEL:
+  enum: [debug, info, warning]
+API_KEY:
+  required: true
+  secret: true
+`), &schema))
+	require.NoError(t, schema.Validate())
+	return schema
+}
+
+func Test_Schema_CheckValue(t *testing.T) {
+	schema := newTestSchema(t)
+
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"AZURE_LOCATION", "westus2", ""},
+		{"AZURE_LOCATION", "West US 2", "must match the pattern '^[a-z0-9]+$'"},
+		{"API_PORT", "8080", ""},
+		{"API_PORT", "http", "must be an integer"},
+		{"LOG_LEVEL", "info", ""},
+		{"LOG_LEVEL", "trace", "must be one of debug, info, warning"},
+		{"UNKNOWN", "anything", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := schema.CheckValue(tt.key, tt.value)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	require.True(t, schema.IsSecret("API_KEY"))
+	require.False(t, schema.IsSecret("API_PORT"))
+}
+
+func Test_Schema_Check(t *testing.T) {
+	schema := newTestSchema(t)
+	values := map[string]string{"AZURE_LOCATION": "westus2", "API_PORT": "http"}
+
+	require.Equal(t, []string{"API_KEY"}, schema.MissingKeys(values))
+
+	err := schema.Check(values, false)
+	require.ErrorIs(t, err, ErrSchemaViolation)
+	require.Equal(t, []string{"API_PORT must be an integer"}, err.(*SchemaError).Violations)
+
+	err = schema.Check(values, true)
+	require.Equal(t, []string{"API_PORT must be an integer", "API_KEY is required"}, err.(*SchemaError).Violations)
+
+	values["API_PORT"] = "80"
+	values["API_KEY"] = "secret"
+	require.NoError(t, schema.Check(values, true))
+}
+
+func Test_Schema_Validate(t *testing.T) {
+	require.Error(t, Schema{"A": {Type: "date"}}.Validate())
+	require.Error(t, Schema{"A": {Pattern: "("}}.Validate())
+	require.NoError(t, Schema{"A": nil}.Validate())
+}
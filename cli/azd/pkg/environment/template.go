@@ -0,0 +1,93 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// Template holds the defaults of the new environments whose name matches a pattern, as configured in the
+// `environments` section of azure.yaml.
+type Template struct {
+	// Values are the default .env values. They may reference other values, e.g. rg-${AZURE_ENV_NAME}.
+	Values map[string]osutil.ExpandableString `yaml:"values,omitempty"`
+	// Config is the default content of config.json.
+	Config map[string]any `yaml:"config,omitempty"`
+}
+
+// Templates maps environment name patterns, in path.Match syntax (e.g. "pr-*"), to the template of the matching
+// environments.
+type Templates map[string]*Template
+
+// Validate checks that every pattern is well formed.
+func (t Templates) Validate() error {
+	for pattern := range t {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid environment name pattern '%s': %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Apply sets the defaults of the templates matching the name of a new environment. When several templates
+// match, longer patterns take precedence.
+func (t Templates) Apply(env *Environment) error {
+	patterns := slices.SortedFunc(maps.Keys(t), func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), cmp.Compare(a, b))
+	})
+
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, env.Name())
+		if err != nil {
+			return fmt.Errorf("invalid environment name pattern '%s': %w", pattern, err)
+		}
+		if !matched || t[pattern] == nil {
+			continue
+		}
+
+		if err := mergeConfig(env, "", t[pattern].Config); err != nil {
+			return fmt.Errorf("applying environment template '%s': %w", pattern, err)
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(t[pattern].Values)) {
+			value, err := t[pattern].Values[key].Envsubst(env.Getenv)
+			if err != nil {
+				return fmt.Errorf("applying environment template '%s': value '%s': %w", pattern, key, err)
+			}
+			env.DotenvSet(key, value)
+		}
+	}
+
+	return nil
+}
+
+// mergeConfig sets each leaf of values in the config of env, under prefix, keeping the other existing values.
+func mergeConfig(env *Environment, prefix string, values map[string]any) error {
+	for key, value := range values {
+		fullPath := key
+		if prefix != "" {
+			fullPath = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]any); ok {
+			if err := mergeConfig(env, fullPath, nested); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := env.Config.Set(fullPath, value); err != nil {
+			return fmt.Errorf("setting config '%s': %w", fullPath, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package environment
+
+import (
+	"testing"
+
+	"github.com/braydonk/yaml"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Templates_Apply(t *testing.T) {
+	var templates Templates
+	require.NoError(t, yaml.Unmarshal([]byte(`
+pr-*:
+  values:
+    AZURE_LOCATION: eastus2
+    AZURE_RESOURCE_GROUP: rg-${AZURE_ENV_NAME}
+  config:
+    infra:
+      parameters:
+        sku: B1
+pr-1*:
+  values:
+    AZURE_LOCATION: westus3
+`), &templates))
+	require.NoError(t, templates.Validate())
+
+	env := New("pr-123")
+	require.NoError(t, env.Config.Set("infra.parameters.replicas", 1))
+	require.NoError(t, templates.Apply(env))
+
+	// The longer pattern wins.
+	require.Equal(t, "westus3", env.Getenv("AZURE_LOCATION"))
+	require.Equal(t, "rg-pr-123", env.Getenv("AZURE_RESOURCE_GROUP"))
+	sku, _ := env.Config.Get("infra.parameters.sku")
+	require.Equal(t, "B1", sku)
+	replicas, _ := env.Config.Get("infra.parameters.replicas")
+	require.Equal(t, 1, replicas)
+
+	other := New("dev")
+	require.NoError(t, templates.Apply(other))
+	require.Equal(t, map[string]string{"AZURE_ENV_NAME": "dev"}, other.Dotenv())
+
+	require.Error(t, Templates{"pr-[": {}}.Validate())
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"maps"
+	"time"
+)
+
+// StepCheckpoint opts a step into checkpoint/resume. When a run is configured
+// with [RunOptions.Checkpoint], every successful checkpointed step is recorded
+// with its fingerprint (and optional output). A later run with
+// [RunOptions.Resume] set skips the step's Action when the recorded
+// fingerprint still matches.
+type StepCheckpoint struct {
+	// Fingerprint returns an opaque content hash of the step's inputs (e.g. a
+	// hash of the service source tree). A recorded completion is reused only
+	// when its fingerprint is identical. An empty fingerprint, or an error,
+	// disables resume for the step.
+	//
+	// Hashing inputs can be costly, so the scheduler calls Fingerprint lazily:
+	// when resuming, only for steps the prior checkpoint holds an entry for;
+	// when checkpointing, just before the step's Action runs. Steps sharing
+	// inputs should share a memoized function.
+	Fingerprint func() (string, error)
+
+	// Group ties together steps whose results are only meaningful as a unit,
+	// e.g. the package → publish → deploy chain of one service where deploy
+	// consumes in-memory state produced by package. When non-empty, a step is
+	// resumed only if every step in the graph sharing the group can be resumed.
+	Group string
+
+	// Output, if non-nil, is invoked after the step's Action succeeds. The
+	// returned value is stored in the checkpoint entry alongside the
+	// fingerprint.
+	Output func() (json.RawMessage, error)
+
+	// Restore, if non-nil, is invoked with the recorded output in place of the
+	// Action when the step is resumed. A non-nil error falls back to running
+	// the Action.
+	Restore func(output json.RawMessage) error
+}
+
+// Checkpoint is the persisted record of steps that completed successfully
+// during a graph execution.
+type Checkpoint struct {
+	// Steps maps step name to its completion record.
+	Steps map[string]CheckpointEntry `json:"steps"`
+}
+
+// CheckpointEntry records a single successful step completion.
+type CheckpointEntry struct {
+	Fingerprint string          `json:"fingerprint"`
+	Output      json.RawMessage `json:"output,omitempty"`
+	CompletedAt time.Time       `json:"completedAt"`
+}
+
+// CheckpointStore loads and persists a [Checkpoint]. Implementations decide
+// where the checkpoint lives and may invalidate it (by returning nil from
+// Load) when run-wide inputs have changed.
+type CheckpointStore interface {
+	// Load returns the previously saved checkpoint, or nil when none exists.
+	Load(ctx context.Context) (*Checkpoint, error)
+
+	// Save persists cp, replacing any previously saved checkpoint. The
+	// scheduler calls Save from its coordinator goroutine after each
+	// checkpointed step completes, so implementations should be fast.
+	Save(ctx context.Context, cp *Checkpoint) error
+}
+
+// fingerprint returns the fingerprint of the step named name, or "" when the
+// step has none or it cannot be computed.
+func (c *StepCheckpoint) fingerprint(name string) string {
+	if c.Fingerprint == nil {
+		return ""
+	}
+	fingerprint, err := c.Fingerprint()
+	if err != nil {
+		log.Printf("exegraph: fingerprinting %q: %v", name, err)
+		return ""
+	}
+	return fingerprint
+}
+
+// matches reports whether the entry was recorded for the given fingerprint.
+func (e CheckpointEntry) matches(fingerprint string) bool {
+	return fingerprint != "" && e.Fingerprint == fingerprint
+}
+
+// clone returns a copy of the checkpoint whose Steps map may be handed to a
+// store without aliasing the scheduler's working copy.
+func (c *Checkpoint) clone() *Checkpoint {
+	return &Checkpoint{Steps: maps.Clone(c.Steps)}
+}
+
+// resumeSet returns the names of steps that can be skipped because prior holds
+// a matching entry for them (and for every other member of their group), along
+// with the fingerprints it computed to find them.
+func resumeSet(g *Graph, prior *Checkpoint) (map[string]bool, map[string]string) {
+	resumable := make(map[string]bool)
+	fingerprints := make(map[string]string)
+	if prior == nil || len(prior.Steps) == 0 {
+		return resumable, fingerprints
+	}
+
+	groupComplete := make(map[string]bool)
+	for _, name := range g.order {
+		s := g.steps[name]
+		if s.Checkpoint == nil {
+			continue
+		}
+		match := false
+		if entry, ok := prior.Steps[name]; ok {
+			fingerprints[name] = s.Checkpoint.fingerprint(name)
+			match = entry.matches(fingerprints[name])
+		}
+		if s.Checkpoint.Group != "" {
+			complete, seen := groupComplete[s.Checkpoint.Group]
+			groupComplete[s.Checkpoint.Group] = match && (complete || !seen)
+		}
+		if match {
+			resumable[name] = true
+		}
+	}
+
+	for name := range resumable {
+		if group := g.steps[name].Checkpoint.Group; group != "" && !groupComplete[group] {
+			delete(resumable, name)
+		}
+	}
+	return resumable, fingerprints
+}
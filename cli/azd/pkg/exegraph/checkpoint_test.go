@@ -0,0 +1,276 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memCheckpointStore is an in-memory CheckpointStore for tests.
+type memCheckpointStore struct {
+	mu sync.Mutex
+	cp *Checkpoint
+}
+
+func (m *memCheckpointStore) Load(_ context.Context) (*Checkpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cp, nil
+}
+
+func (m *memCheckpointStore) Save(_ context.Context, cp *Checkpoint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cp = cp
+	return nil
+}
+
+// fixedFingerprint returns a StepCheckpoint.Fingerprint reporting fingerprint.
+func fixedFingerprint(fingerprint string) func() (string, error) {
+	return func() (string, error) { return fingerprint, nil }
+}
+
+func TestCheckpoint_FingerprintsLazily(t *testing.T) {
+	var calls sync.Map
+	build := func() *Graph {
+		g := NewGraph()
+		for _, name := range []string{"a", "b"} {
+			require.NoError(t, g.AddStep(&Step{
+				Name:   name,
+				Action: noop,
+				Checkpoint: &StepCheckpoint{Fingerprint: func() (string, error) {
+					count, _ := calls.LoadOrStore(name, new(atomic.Int32))
+					count.(*atomic.Int32).Add(1)
+					return "f" + name, nil
+				}},
+			}))
+		}
+		return g
+	}
+	callCount := func(name string) int32 {
+		count, ok := calls.Load(name)
+		if !ok {
+			return 0
+		}
+		return count.(*atomic.Int32).Load()
+	}
+
+	require.NoError(t, Run(t.Context(), build(), RunOptions{}))
+	assert.Zero(t, callCount("a"), "steps are not fingerprinted without a checkpoint")
+
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"a": {Fingerprint: "fa"},
+	}}}
+	require.NoError(t, Run(t.Context(), build(), RunOptions{Checkpoint: store, Resume: true}))
+	assert.Equal(t, int32(1), callCount("a"), "a resumed step is fingerprinted once")
+	assert.Equal(t, int32(1), callCount("b"), "a step that runs is fingerprinted once")
+	assert.Equal(t, "fb", store.cp.Steps["b"].Fingerprint)
+}
+
+func TestCheckpoint_FingerprintErrorDisablesResume(t *testing.T) {
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"a": {Fingerprint: "fa"},
+	}}}
+
+	var ran atomic.Bool
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "a",
+		Action: func(context.Context) error { ran.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{
+			Fingerprint: func() (string, error) { return "", errors.New("unreadable") },
+		},
+	}))
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{Checkpoint: store, Resume: true}))
+	assert.True(t, ran.Load())
+	assert.NotContains(t, store.cp.Steps, "a")
+}
+
+func TestCheckpoint_RecordsSuccessfulSteps(t *testing.T) {
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "a",
+		Action:     noop,
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fa")},
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "b",
+		DependsOn:  []string{"a"},
+		Action:     func(context.Context) error { return errors.New("boom") },
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fb")},
+	}))
+	require.NoError(t, g.AddStep(&Step{Name: "c", Action: noop}))
+
+	store := &memCheckpointStore{}
+	result := RunWithResult(t.Context(), g, RunOptions{Checkpoint: store})
+	require.Error(t, result.Error)
+
+	require.NotNil(t, store.cp)
+	require.Contains(t, store.cp.Steps, "a")
+	assert.Equal(t, "fa", store.cp.Steps["a"].Fingerprint)
+	assert.NotContains(t, store.cp.Steps, "b", "failed steps must not be recorded")
+	assert.NotContains(t, store.cp.Steps, "c", "steps without Checkpoint are never recorded")
+}
+
+func TestCheckpoint_ResumeSkipsMatchingSteps(t *testing.T) {
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"a": {Fingerprint: "fa"},
+		"b": {Fingerprint: "stale"},
+	}}}
+
+	var ranA, ranB atomic.Bool
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "a",
+		Action:     func(context.Context) error { ranA.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fa")},
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "b",
+		DependsOn:  []string{"a"},
+		Action:     func(context.Context) error { ranB.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fb")},
+	}))
+
+	result := RunWithResult(t.Context(), g, RunOptions{Checkpoint: store, Resume: true})
+	require.NoError(t, result.Error)
+
+	assert.False(t, ranA.Load(), "a matches its checkpoint and must be resumed")
+	assert.True(t, ranB.Load(), "b has a changed fingerprint and must run")
+
+	timings := map[string]StepTiming{}
+	for _, st := range result.Steps {
+		timings[st.Name] = st
+	}
+	assert.True(t, timings["a"].Resumed)
+	assert.Equal(t, StepDone, timings["a"].Status)
+	assert.False(t, timings["b"].Resumed)
+	assert.Equal(t, "fb", store.cp.Steps["b"].Fingerprint)
+}
+
+func TestCheckpoint_ResumeIgnoredWithoutFlag(t *testing.T) {
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"a": {Fingerprint: "fa"},
+	}}}
+
+	var ran atomic.Bool
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "a",
+		Action:     func(context.Context) error { ran.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fa")},
+	}))
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{Checkpoint: store}))
+	assert.True(t, ran.Load())
+}
+
+func TestCheckpoint_GroupResumedAllOrNothing(t *testing.T) {
+	// package + deploy of svc share a group; only package completed last time.
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"package-svc": {Fingerprint: "h"},
+	}}}
+
+	var ranPackage atomic.Bool
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "package-svc",
+		Action:     func(context.Context) error { ranPackage.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("h"), Group: "svc"},
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "deploy-svc",
+		DependsOn:  []string{"package-svc"},
+		Action:     noop,
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("h"), Group: "svc"},
+	}))
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{Checkpoint: store, Resume: true}))
+	assert.True(t, ranPackage.Load(), "package must re-run because deploy in the same group was not recorded")
+	assert.Len(t, store.cp.Steps, 2)
+}
+
+func TestCheckpoint_OutputRoundTrip(t *testing.T) {
+	store := &memCheckpointStore{}
+	build := func(restored *string, ran *atomic.Bool) *Graph {
+		g := NewGraph()
+		require.NoError(t, g.AddStep(&Step{
+			Name:   "deploy",
+			Action: func(context.Context) error { ran.Store(true); return nil },
+			Checkpoint: &StepCheckpoint{
+				Fingerprint: fixedFingerprint("f"),
+				Output: func() (json.RawMessage, error) {
+					return json.Marshal("https://app.example.com")
+				},
+				Restore: func(output json.RawMessage) error {
+					return json.Unmarshal(output, restored)
+				},
+			},
+		}))
+		return g
+	}
+
+	var first string
+	var firstRan atomic.Bool
+	require.NoError(t, Run(t.Context(), build(&first, &firstRan), RunOptions{Checkpoint: store}))
+	assert.True(t, firstRan.Load())
+	assert.Empty(t, first)
+
+	var second string
+	var secondRan atomic.Bool
+	require.NoError(t, Run(t.Context(), build(&second, &secondRan), RunOptions{Checkpoint: store, Resume: true}))
+	assert.False(t, secondRan.Load())
+	assert.Equal(t, "https://app.example.com", second)
+}
+
+func TestCheckpoint_RestoreFailureReRunsStep(t *testing.T) {
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"a": {Fingerprint: "fa", Output: json.RawMessage(`"x"`)},
+	}}}
+
+	var ran atomic.Bool
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "a",
+		Action: func(context.Context) error { ran.Store(true); return nil },
+		Checkpoint: &StepCheckpoint{
+			Fingerprint: fixedFingerprint("fa"),
+			Restore:     func(json.RawMessage) error { return errors.New("corrupt") },
+		},
+	}))
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{Checkpoint: store, Resume: true}))
+	assert.True(t, ran.Load())
+}
+
+func TestCheckpoint_FailFastKeepsCarriedEntries(t *testing.T) {
+	store := &memCheckpointStore{cp: &Checkpoint{Steps: map[string]CheckpointEntry{
+		"late": {Fingerprint: "fl"},
+	}}}
+
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "fails",
+		Action: func(context.Context) error { return errors.New("boom") },
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name:       "late",
+		DependsOn:  []string{"fails"},
+		Action:     noop,
+		Checkpoint: &StepCheckpoint{Fingerprint: fixedFingerprint("fl")},
+	}))
+
+	require.Error(t, Run(t.Context(), g, RunOptions{Checkpoint: store, Resume: true}))
+	assert.Contains(t, store.cp.Steps, "late",
+		"a resumable step that never got to run must stay recorded")
+}
@@ -0,0 +1,152 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportFormat names a textual representation of a graph produced by [Graph.Export].
+type ExportFormat string
+
+const (
+	ExportDOT     ExportFormat = "dot"     // ExportDOT renders the graph as a Graphviz digraph.
+	ExportMermaid ExportFormat = "mermaid" // ExportMermaid renders the graph as a Mermaid flowchart.
+	ExportJSON    ExportFormat = "json"    // ExportJSON renders the graph as a JSON document.
+)
+
+// ExportFormats returns the supported export formats.
+func ExportFormats() []ExportFormat {
+	return []ExportFormat{ExportDOT, ExportMermaid, ExportJSON}
+}
+
+// PlannedStep describes a step as it would be scheduled, without executing it.
+type PlannedStep struct {
+	Name      string   `json:"name"`
+	DependsOn []string `json:"dependsOn"`
+	Tags      []string `json:"tags"`
+
+	// Priority is the step's transitive dependent count (see [Graph.Priority]).
+	Priority int `json:"priority"`
+
+	// Rank is the 1-based position of the step in the scheduler's
+	// critical-path order: when several steps are ready at once, lower ranks
+	// are started first.
+	Rank int `json:"rank"`
+}
+
+// Plan validates the graph and returns its steps in insertion order, annotated
+// with their scheduling priority and rank.
+func (g *Graph) Plan() ([]PlannedStep, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	rank := make(map[string]int, len(g.order))
+	for i, name := range g.priorityOrder() {
+		rank[name] = i + 1
+	}
+
+	plan := make([]PlannedStep, len(g.order))
+	for i, name := range g.order {
+		s := g.steps[name]
+		plan[i] = PlannedStep{
+			Name:      s.Name,
+			DependsOn: nonNil(s.DependsOn),
+			Tags:      nonNil(s.Tags),
+			Priority:  g.priority[name],
+			Rank:      rank[name],
+		}
+	}
+	return plan, nil
+}
+
+// Export validates the graph and writes it to w in the given format. Edges
+// point from a dependency to the step that depends on it, matching the order
+// in which the scheduler runs them.
+func (g *Graph) Export(w io.Writer, format ExportFormat) error {
+	plan, err := g.Plan()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case ExportDOT:
+		return writeDOT(w, plan)
+	case ExportMermaid:
+		return writeMermaid(w, plan)
+	case ExportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			Steps []PlannedStep `json:"steps"`
+		}{Steps: plan})
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func writeDOT(w io.Writer, plan []PlannedStep) error {
+	var sb strings.Builder
+	sb.WriteString("digraph exegraph {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, s := range plan {
+		fmt.Fprintf(&sb, "  %q [label=%q];\n", s.Name, strings.Join(stepLabel(s), "\n"))
+	}
+	for _, s := range plan {
+		for _, dep := range s.DependsOn {
+			fmt.Fprintf(&sb, "  %q -> %q;\n", dep, s.Name)
+		}
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func writeMermaid(w io.Writer, plan []PlannedStep) error {
+	// Step names may contain characters Mermaid does not accept in node IDs,
+	// so nodes get positional IDs and carry the name in their label.
+	ids := make(map[string]string, len(plan))
+	for i, s := range plan {
+		ids[s.Name] = fmt.Sprintf("s%d", i)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("flowchart LR\n")
+	for _, s := range plan {
+		label := strings.ReplaceAll(strings.Join(stepLabel(s), "<br/>"), `"`, "#quot;")
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[s.Name], label)
+	}
+	for _, s := range plan {
+		for _, dep := range s.DependsOn {
+			fmt.Fprintf(&sb, "  %s --> %s\n", ids[dep], ids[s.Name])
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// stepLabel returns the lines used to label a step's node in diagram formats.
+func stepLabel(s PlannedStep) []string {
+	lines := []string{s.Name}
+	if len(s.Tags) > 0 {
+		lines = append(lines, "tags: "+strings.Join(s.Tags, ", "))
+	}
+	return append(lines, fmt.Sprintf("priority: %d (rank %d)", s.Priority, s.Rank))
+}
+
+// nonNil returns s, or an empty slice when s is nil, so JSON output always
+// renders lists as arrays.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,94 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exportTestGraph builds: provision → deploy-api, provision → deploy-web, package-api → deploy-api.
+func exportTestGraph(t *testing.T) *Graph {
+	t.Helper()
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{Name: "provision", Tags: []string{"provision"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{Name: "package-api", Tags: []string{"package"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{
+		Name: "deploy-api", DependsOn: []string{"provision", "package-api"}, Action: noop,
+	}))
+	require.NoError(t, g.AddStep(&Step{Name: "deploy-web", DependsOn: []string{"provision"}, Action: noop}))
+	return g
+}
+
+func TestPlan(t *testing.T) {
+	plan, err := exportTestGraph(t).Plan()
+	require.NoError(t, err)
+	require.Len(t, plan, 4)
+
+	byName := map[string]PlannedStep{}
+	for _, s := range plan {
+		byName[s.Name] = s
+	}
+	assert.Equal(t, "provision", plan[0].Name, "plan keeps insertion order")
+	assert.Equal(t, 2, byName["provision"].Priority)
+	assert.Equal(t, 1, byName["provision"].Rank)
+	assert.Equal(t, 1, byName["package-api"].Priority)
+	assert.Equal(t, 2, byName["package-api"].Rank)
+	assert.Equal(t, 0, byName["deploy-web"].Priority)
+	assert.Equal(t, 4, byName["deploy-web"].Rank)
+	assert.Equal(t, []string{}, byName["deploy-web"].Tags)
+}
+
+func TestPlan_InvalidGraph(t *testing.T) {
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{Name: "a", DependsOn: []string{"missing"}, Action: noop}))
+	_, err := g.Plan()
+	require.Error(t, err)
+}
+
+func TestExport_DOT(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportTestGraph(t).Export(&buf, ExportDOT))
+
+	out := buf.String()
+	assert.Contains(t, out, "digraph exegraph {")
+	assert.Contains(t, out, `"provision" [label="provision\ntags: provision\npriority: 2 (rank 1)"];`)
+	assert.Contains(t, out, `"package-api" -> "deploy-api";`)
+	assert.Contains(t, out, `"provision" -> "deploy-web";`)
+}
+
+func TestExport_Mermaid(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportTestGraph(t).Export(&buf, ExportMermaid))
+
+	out := buf.String()
+	assert.Contains(t, out, "flowchart LR\n")
+	assert.Contains(t, out, `s0["provision<br/>tags: provision<br/>priority: 2 (rank 1)"]`)
+	assert.Contains(t, out, "s0 --> s2\n")
+	assert.Contains(t, out, "s1 --> s2\n")
+	assert.Contains(t, out, "s0 --> s3\n")
+}
+
+func TestExport_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, exportTestGraph(t).Export(&buf, ExportJSON))
+
+	var doc struct {
+		Steps []PlannedStep `json:"steps"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Steps, 4)
+	assert.Equal(t, []string{"provision", "package-api"}, doc.Steps[2].DependsOn)
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	err := exportTestGraph(t).Export(&buf, ExportFormat("svg"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported export format")
+}
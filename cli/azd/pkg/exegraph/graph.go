@@ -163,7 +163,8 @@ func (g *Graph) computePriority() {
 	// steps, switch to a topological-order DP (future optimization).
 	for _, name := range g.order {
 		visited := make(map[string]bool)
-		stack := dependents[name]
+		// Clone so appends below never write into dependents' backing arrays.
+		stack := slices.Clone(dependents[name])
 		for len(stack) > 0 {
 			cur := stack[len(stack)-1]
 			stack = stack[:len(stack)-1]
@@ -154,6 +154,20 @@ func TestPriority_Diamond(t *testing.T) {
 	assert.Equal(t, 0, g.Priority("d"))
 }
 
+func TestPriority_SharedDependentsNotCorrupted(t *testing.T) {
+	// root → r → {x, y}, y → z. r is inserted (and so walked) before root;
+	// walking r must not clobber r's dependent list, which root's walk reuses.
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{Name: "r", DependsOn: []string{"root"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{Name: "x", DependsOn: []string{"r"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{Name: "y", DependsOn: []string{"r"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{Name: "z", DependsOn: []string{"y"}, Action: noop}))
+	require.NoError(t, g.AddStep(&Step{Name: "root", Action: noop}))
+
+	assert.Equal(t, 3, g.Priority("r"))
+	assert.Equal(t, 4, g.Priority("root"))
+}
+
 func TestPriorityOrder_CriticalPathFirst(t *testing.T) {
 	// Simulate a provision→deploy graph:
 	//   provision-0 (3 deps) → publish-a (1 dep), publish-b (1 dep)
@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// pool is a named counting semaphore bounding how many holders may be active
+// at once. Slots are acquired by the coordinator when a worker is free to
+// start a step that carries the pool's tag, or by a running step's Action via
+// [AcquirePool].
+type pool struct {
+	slots chan struct{}
+}
+
+// pools maps pool name to its semaphore. Only pools with a positive limit are
+// present; every other name is unlimited.
+type pools map[string]*pool
+
+// newPools creates the semaphores for the configured limits, ignoring
+// non-positive ones.
+func newPools(limits map[string]int) pools {
+	p := make(pools, len(limits))
+	for name, limit := range limits {
+		if limit > 0 {
+			p[name] = &pool{slots: make(chan struct{}, limit)}
+		}
+	}
+	return p
+}
+
+// tryAcquire claims one slot in the pool of every tag of s without blocking.
+// It is all-or-nothing: when any pool is full, slots already claimed are
+// returned and false is reported.
+func (p pools) tryAcquire(s *Step) bool {
+	if len(p) == 0 {
+		return true
+	}
+
+	var claimed []*pool
+	for _, tag := range uniqueTags(s) {
+		tp, ok := p[tag]
+		if !ok {
+			continue
+		}
+		select {
+		case tp.slots <- struct{}{}:
+			claimed = append(claimed, tp)
+		default:
+			for _, c := range claimed {
+				<-c.slots
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// release returns the slots claimed by tryAcquire for s.
+func (p pools) release(s *Step) {
+	if len(p) == 0 {
+		return
+	}
+	for _, tag := range uniqueTags(s) {
+		if tp, ok := p[tag]; ok {
+			<-tp.slots
+		}
+	}
+}
+
+// uniqueTags returns the step's tags without duplicates, so a step listing a
+// tag twice never needs two slots of a pool with a limit of one.
+func uniqueTags(s *Step) []string {
+	if len(s.Tags) < 2 {
+		return s.Tags
+	}
+	tags := slices.Clone(s.Tags)
+	slices.Sort(tags)
+	return slices.Compact(tags)
+}
+
+type poolsContextKey struct{}
+
+// AcquirePool blocks until a slot in the named pool (see [RunOptions.Pools])
+// is free and returns a function that releases it. It lets a step bound only
+// part of its work — e.g. a container build inside a deploy step — instead of
+// occupying the slot for the whole step as a tag would. A step must not
+// acquire a pool named by one of its own tags: it already holds that slot.
+//
+// When ctx does not come from a running graph, or the pool has no limit,
+// AcquirePool returns immediately with a no-op release. It returns ctx's
+// error if ctx is canceled while waiting.
+func AcquirePool(ctx context.Context, name string) (release func(), err error) {
+	p, _ := ctx.Value(poolsContextKey{}).(pools)
+	tp, ok := p[name]
+	if !ok {
+		return func() {}, nil
+	}
+
+	select {
+	case tp.slots <- struct{}{}:
+		var once sync.Once
+		return func() { once.Do(func() { <-tp.slots }) }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// contextWithPools makes p available to [AcquirePool] for steps run with ctx.
+func contextWithPools(ctx context.Context, p pools) context.Context {
+	if len(p) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, poolsContextKey{}, p)
+}
@@ -0,0 +1,240 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// peakTracker records the highest number of concurrent holders observed.
+type peakTracker struct {
+	cur, peak atomic.Int32
+}
+
+func (p *peakTracker) enter() {
+	cur := p.cur.Add(1)
+	for {
+		old := p.peak.Load()
+		if cur <= old || p.peak.CompareAndSwap(old, cur) {
+			return
+		}
+	}
+}
+
+func (p *peakTracker) exit() { p.cur.Add(-1) }
+
+func TestRun_PoolLimitsTaggedSteps(t *testing.T) {
+	g := NewGraph()
+	var builds, all peakTracker
+	for i := range 6 {
+		require.NoError(t, g.AddStep(&Step{
+			Name: fmt.Sprintf("build-%d", i),
+			Tags: []string{"build"},
+			Action: func(context.Context) error {
+				builds.enter()
+				all.enter()
+				time.Sleep(20 * time.Millisecond)
+				all.exit()
+				builds.exit()
+				return nil
+			},
+		}))
+	}
+	for i := range 4 {
+		require.NoError(t, g.AddStep(&Step{
+			Name: fmt.Sprintf("poll-%d", i),
+			Tags: []string{"provision"},
+			Action: func(context.Context) error {
+				all.enter()
+				time.Sleep(20 * time.Millisecond)
+				all.exit()
+				return nil
+			},
+		}))
+	}
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{MaxConcurrency: 10, Pools: map[string]int{"build": 2}}))
+	assert.LessOrEqual(t, builds.peak.Load(), int32(2), "build pool must cap concurrent builds at 2")
+	assert.Greater(t, all.peak.Load(), int32(2), "untagged work must not be held back by the build pool")
+}
+
+func TestRun_PoolWaitingStepsDoNotHoldWorkers(t *testing.T) {
+	// With two workers and a "deploy" pool of 1, the second deploy step must
+	// wait without occupying the worker that "other" needs to unblock the first.
+	g := NewGraph()
+	release := make(chan struct{})
+	var otherRan atomic.Bool
+	require.NoError(t, g.AddStep(&Step{
+		Name: "deploy-a", Tags: []string{"deploy"},
+		Action: func(context.Context) error { <-release; return nil },
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name: "deploy-b", Tags: []string{"deploy"}, Action: noop,
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name: "other",
+		Action: func(context.Context) error {
+			otherRan.Store(true)
+			close(release)
+			return nil
+		},
+	}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(t.Context(), g, RunOptions{MaxConcurrency: 2, Pools: map[string]int{"deploy": 1}})
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		assert.True(t, otherRan.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler deadlocked: a step waiting on a pool held a worker")
+	}
+}
+
+func TestRun_PoolNestedAcquireWithOneWorker(t *testing.T) {
+	// With a single worker, "build" must not claim the "image" slot while it
+	// is queued behind "deploy", whose Action acquires that slot itself.
+	g := NewGraph()
+	var built atomic.Bool
+	require.NoError(t, g.AddStep(&Step{
+		Name: "deploy",
+		Action: func(ctx context.Context) error {
+			release, err := AcquirePool(ctx, "image")
+			if err != nil {
+				return err
+			}
+			release()
+			return nil
+		},
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name: "build", Tags: []string{"image"},
+		Action: func(context.Context) error { built.Store(true); return nil },
+	}))
+	// Give "deploy" the higher priority so it is started first.
+	require.NoError(t, g.AddStep(&Step{Name: "after", DependsOn: []string{"deploy"}, Action: noop}))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- Run(t.Context(), g, RunOptions{MaxConcurrency: 1, Pools: map[string]int{"image": 1}})
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+		assert.True(t, built.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler deadlocked: a queued step held the pool slot a running step waited on")
+	}
+}
+
+func TestRun_PoolDuplicateTagNeedsOneSlot(t *testing.T) {
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{Name: "a", Tags: []string{"build", "build"}, Action: noop}))
+	require.NoError(t, Run(t.Context(), g, RunOptions{Pools: map[string]int{"build": 1}}))
+}
+
+func TestRun_PoolNonPositiveLimitIsUnlimited(t *testing.T) {
+	g := NewGraph()
+	var peak peakTracker
+	release := make(chan struct{})
+	for i := range 3 {
+		require.NoError(t, g.AddStep(&Step{
+			Name: fmt.Sprintf("s%d", i),
+			Tags: []string{"build"},
+			Action: func(context.Context) error {
+				peak.enter()
+				if peak.cur.Load() == 3 {
+					close(release)
+				}
+				<-release
+				peak.exit()
+				return nil
+			},
+		}))
+	}
+	require.NoError(t, Run(t.Context(), g, RunOptions{MaxConcurrency: 3, Pools: map[string]int{"build": 0}}))
+	assert.Equal(t, int32(3), peak.peak.Load())
+}
+
+func TestRun_PoolFailFastSkipsWaitingSteps(t *testing.T) {
+	g := NewGraph()
+	var ranWaiting atomic.Bool
+	require.NoError(t, g.AddStep(&Step{
+		Name: "fails", Tags: []string{"deploy"},
+		Action: func(context.Context) error { return errors.New("boom") },
+	}))
+	require.NoError(t, g.AddStep(&Step{
+		Name: "waits", Tags: []string{"deploy"},
+		Action: func(context.Context) error { ranWaiting.Store(true); return nil },
+	}))
+	// Give "fails" the higher priority so it claims the only slot first.
+	require.NoError(t, g.AddStep(&Step{Name: "after", DependsOn: []string{"fails"}, Action: noop}))
+
+	result := RunWithResult(t.Context(), g, RunOptions{Pools: map[string]int{"deploy": 1}})
+	require.Error(t, result.Error)
+	assert.False(t, ranWaiting.Load())
+
+	statuses := map[string]StepStatus{}
+	for _, st := range result.Steps {
+		statuses[st.Name] = st.Status
+	}
+	assert.Equal(t, StepSkipped, statuses["waits"], "a step left waiting on a pool is reported as skipped")
+}
+
+func TestAcquirePool(t *testing.T) {
+	g := NewGraph()
+	var peak peakTracker
+	for i := range 4 {
+		require.NoError(t, g.AddStep(&Step{
+			Name: fmt.Sprintf("deploy-%d", i),
+			Tags: []string{"deploy"},
+			Action: func(ctx context.Context) error {
+				release, err := AcquirePool(ctx, "image")
+				if err != nil {
+					return err
+				}
+				peak.enter()
+				time.Sleep(10 * time.Millisecond)
+				peak.exit()
+				release()
+				release() // releasing twice is harmless
+				return nil
+			},
+		}))
+	}
+
+	require.NoError(t, Run(t.Context(), g, RunOptions{MaxConcurrency: 4, Pools: map[string]int{"image": 1}}))
+	assert.Equal(t, int32(1), peak.peak.Load())
+}
+
+func TestAcquirePool_Unconfigured(t *testing.T) {
+	release, err := AcquirePool(t.Context(), "image")
+	require.NoError(t, err)
+	release()
+}
+
+func TestAcquirePool_ContextCanceled(t *testing.T) {
+	p := newPools(map[string]int{"image": 1})
+	ctx := contextWithPools(t.Context(), p)
+	release, err := AcquirePool(ctx, "image")
+	require.NoError(t, err)
+	defer release()
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = AcquirePool(canceled, "image")
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// defaultRetryDelay is the wait before the first retry when
+// [RetryPolicy.InitialDelay] is not set.
+const defaultRetryDelay = time.Second
+
+// RetryPolicy re-runs a step's Action when it fails with a retryable error.
+// The wait between attempts starts at InitialDelay and doubles after every
+// retry, capped at MaxDelay.
+//
+// A step is never retried once its context is done: cancellation, FailFast
+// tear-down and an expired [RunOptions.StepTimeout] all end the step with the
+// error of its last attempt. Errors marking the step as skipped
+// ([StepSkippedError]) are not retried either.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of times Action may run, including the
+	// first. Values below 2 disable retries.
+	MaxAttempts int
+
+	// InitialDelay is the wait before the first retry. Zero means one second.
+	InitialDelay time.Duration
+
+	// MaxDelay caps the wait between attempts. Zero means no cap.
+	MaxDelay time.Duration
+
+	// Retryable reports whether err is worth another attempt. Nil retries
+	// every error.
+	Retryable func(err error) bool
+}
+
+// retry reports whether a step that failed with err on the given 1-based
+// attempt should run again, and how long to wait first. It is safe to call on
+// a nil policy.
+func (p *RetryPolicy) retry(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil || IsStepSkipped(err) {
+		return 0, false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+	if p.Retryable != nil && !p.Retryable(err) {
+		return 0, false
+	}
+	return p.delay(attempt), true
+}
+
+// delay returns the wait after the given 1-based attempt failed.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.InitialDelay
+	if d <= 0 {
+		d = defaultRetryDelay
+	}
+	for range attempt - 1 {
+		if (p.MaxDelay > 0 && d >= p.MaxDelay) || d > math.MaxInt64/2 {
+			break
+		}
+		d *= 2
+	}
+	if p.MaxDelay > 0 {
+		d = min(d, p.MaxDelay)
+	}
+	return d
+}
+
+// sleep waits for d, returning false early if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package exegraph
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("429 too many requests")
+
+// flakyAction fails with err for the first n calls and succeeds afterwards.
+func flakyAction(n int32, err error, calls *atomic.Int32) StepFunc {
+	return func(context.Context) error {
+		if calls.Add(1) <= n {
+			return err
+		}
+		return nil
+	}
+}
+
+func stepTiming(t *testing.T, result *RunResult, name string) StepTiming {
+	t.Helper()
+	for _, st := range result.Steps {
+		if st.Name == name {
+			return st
+		}
+	}
+	t.Fatalf("no timing recorded for step %q", name)
+	return StepTiming{}
+}
+
+func TestRun_RetrySucceedsAfterTransientFailures(t *testing.T) {
+	var calls atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "deploy",
+		Action: flakyAction(2, errTransient, &calls),
+		Retry:  &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+	}))
+	require.NoError(t, g.AddStep(&Step{Name: "after", DependsOn: []string{"deploy"}, Action: noop}))
+
+	result := RunWithResult(t.Context(), g, RunOptions{})
+	require.NoError(t, result.Error)
+	assert.Equal(t, int32(3), calls.Load())
+	assert.Equal(t, 3, stepTiming(t, result, "deploy").Attempts)
+	assert.Equal(t, 1, stepTiming(t, result, "after").Attempts)
+}
+
+func TestRun_RetryGivesUpAfterMaxAttempts(t *testing.T) {
+	var calls atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "deploy",
+		Action: flakyAction(10, errTransient, &calls),
+		Retry:  &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+	}))
+
+	result := RunWithResult(t.Context(), g, RunOptions{})
+	require.ErrorIs(t, result.Error, errTransient)
+	assert.Equal(t, int32(3), calls.Load())
+
+	st := stepTiming(t, result, "deploy")
+	assert.Equal(t, StepFailed, st.Status)
+	assert.Equal(t, 3, st.Attempts)
+}
+
+func TestRun_RetrySkipsNonRetryableErrors(t *testing.T) {
+	permanent := errors.New("invalid template")
+	var calls atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name:   "deploy",
+		Action: flakyAction(10, permanent, &calls),
+		Retry: &RetryPolicy{
+			MaxAttempts:  5,
+			InitialDelay: time.Millisecond,
+			Retryable:    func(err error) bool { return errors.Is(err, errTransient) },
+		},
+	}))
+
+	result := RunWithResult(t.Context(), g, RunOptions{})
+	require.ErrorIs(t, result.Error, permanent)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, 1, stepTiming(t, result, "deploy").Attempts)
+}
+
+func TestRun_RetryStopsWhenCanceledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	var calls atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name: "deploy",
+		Action: func(context.Context) error {
+			calls.Add(1)
+			cancel()
+			return errTransient
+		},
+		Retry: &RetryPolicy{MaxAttempts: 5, InitialDelay: time.Hour},
+	}))
+
+	done := make(chan *RunResult, 1)
+	go func() { done <- RunWithResult(ctx, g, RunOptions{}) }()
+
+	select {
+	case result := <-done:
+		require.ErrorIs(t, result.Error, errTransient)
+		assert.Equal(t, int32(1), calls.Load())
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry backoff ignored context cancellation")
+	}
+}
+
+func TestRun_RetryDoesNotRetrySkippedSteps(t *testing.T) {
+	var calls atomic.Int32
+	g := NewGraph()
+	require.NoError(t, g.AddStep(&Step{
+		Name: "optional",
+		Action: func(context.Context) error {
+			calls.Add(1)
+			return &StepSkippedError{StepName: "optional"}
+		},
+		Retry: &RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond},
+	}))
+
+	_ = Run(t.Context(), g, RunOptions{ErrorPolicy: ContinueOnError})
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := &RetryPolicy{InitialDelay: time.Second, MaxDelay: 5 * time.Second}
+	assert.Equal(t, time.Second, p.delay(1))
+	assert.Equal(t, 2*time.Second, p.delay(2))
+	assert.Equal(t, 4*time.Second, p.delay(3))
+	assert.Equal(t, 5*time.Second, p.delay(4))
+	assert.Equal(t, 5*time.Second, p.delay(60), "large attempt counts must not overflow")
+
+	assert.Equal(t, defaultRetryDelay, (&RetryPolicy{}).delay(1))
+	assert.Equal(t, 8*defaultRetryDelay, (&RetryPolicy{}).delay(4), "no cap without MaxDelay")
+}
@@ -6,6 +6,7 @@ package exegraph
 import (
 	"cmp"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -55,6 +56,16 @@ type RunOptions struct {
 	// ErrorPolicy determines behavior on step failure.
 	ErrorPolicy ErrorPolicy
 
+	// Pools bounds concurrency per named pool, on top of MaxConcurrency. A step
+	// is dispatched only when every pool named by one of its [Step.Tags] has a
+	// free slot, and holds those slots until it completes; e.g.
+	// {"build": 2, "provision": 4} runs at most two "build"-tagged steps at
+	// once. Steps may also hold a slot for part of their work through
+	// [AcquirePool]. Ready steps waiting on a full pool do not occupy a worker,
+	// so unrelated steps keep running. Names without an entry, or with a
+	// non-positive limit, are unlimited.
+	Pools map[string]int
+
 	// StepTimeout imposes a per-step deadline. When positive, each step's
 	// context is wrapped with context.WithTimeout before execution. If the
 	// step does not complete within the duration the context expires with
@@ -70,6 +81,17 @@ type RunOptions struct {
 	// OnStepDone is called (if non-nil) when a step finishes, with a nil error on success.
 	// It is invoked from worker goroutines and must be safe for concurrent use.
 	OnStepDone func(stepName string, err error)
+
+	// Checkpoint, when non-nil, records every successful step that declares a
+	// [Step.Checkpoint] and persists the record via the store after each
+	// completion, so progress survives a failed or interrupted run.
+	Checkpoint CheckpointStore
+
+	// Resume, when true, loads the previous checkpoint from Checkpoint before
+	// execution. Steps whose recorded fingerprint still matches are marked
+	// done without executing their Action (see [StepTiming.Resumed]).
+	// Ignored when Checkpoint is nil.
+	Resume bool
 }
 
 // Run executes all steps in the graph respecting dependency order, with bounded
@@ -147,10 +169,50 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 	failed := make(map[string]bool, n)
 	var allErrors []error
 
+	// Checkpoint state. `prior` and `fingerprints` are read-only once workers
+	// start; `current` is only mutated by the coordinator goroutine below.
+	// Entries for steps that will be resumed are carried forward up front so
+	// that an early FailFast in this run does not discard progress recorded by
+	// an earlier one.
+	var prior *Checkpoint
+	resumed := map[string]bool{}
+	fingerprints := map[string]string{}
+	current := &Checkpoint{Steps: map[string]CheckpointEntry{}}
+	if opts.Checkpoint != nil && opts.Resume {
+		loaded, err := opts.Checkpoint.Load(ctx)
+		if err != nil {
+			log.Printf("exegraph: ignoring unreadable checkpoint: %v", err)
+		} else if loaded != nil {
+			prior = loaded
+			resumed, fingerprints = resumeSet(g, prior)
+			for name := range resumed {
+				current.Steps[name] = prior.Steps[name]
+			}
+		}
+	}
+	saveCheckpoint := func() {
+		if opts.Checkpoint == nil {
+			return
+		}
+		if err := opts.Checkpoint.Save(ctx, current.clone()); err != nil {
+			log.Printf("exegraph: saving checkpoint: %v", err)
+		}
+	}
+
 	// Derive a cancellable context for FailFast tear-down.
 	runCtx, runCancel := context.WithCancel(ctx)
 	defer runCancel()
 
+	// Named concurrency pools. The coordinator claims a step's tag slots
+	// when a worker is free to start it and returns them when its completion
+	// arrives; steps that cannot claim their slots, or find no free worker,
+	// wait in `pending` without holding a worker or a slot. A slot is thus
+	// only ever held by a running step, so a step's Action may wait on
+	// [AcquirePool] without waiting on a queued step that cannot start.
+	stepPools := newPools(opts.Pools)
+	runCtx = contextWithPools(runCtx, stepPools)
+	var pending []string
+
 	// Worker pool size: when MaxConcurrency is explicitly set (>0), honor it
 	// as the upper bound (still clamped by the step count). Otherwise default
 	// to GOMAXPROCS*2 to avoid unbounded goroutine creation for the large
@@ -165,6 +227,17 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 		err   error
 		start time.Time
 		end   time.Time
+		// resumed is true when the step was satisfied from the prior
+		// checkpoint rather than executed.
+		resumed bool
+		// attempts is the number of times the step's Action ran.
+		attempts int
+		// output is the value captured by StepCheckpoint.Output after a
+		// successful execution (nil when the step is not checkpointed).
+		output json.RawMessage
+		// fingerprint is the step's fingerprint, computed before its Action
+		// ran ("" when the step is not checkpointed).
+		fingerprint string
 		// schedulerCanceled captures whether runCtx was already canceled at
 		// the moment the step finished executing. This is observed by the
 		// worker (not by the event loop) so a subsequent FailFast tear-down
@@ -198,9 +271,31 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 					continue
 				}
 				step := g.steps[name]
+				if resumed[name] && restoreStep(step, prior.Steps[name]) {
+					safeNotifyStart(opts, name)
+					safeNotifyDone(opts, name, nil)
+					now := time.Now()
+					completions <- stepCompletion{name: name, start: now, end: now, resumed: true}
+					continue
+				}
+				// Fingerprint the inputs before the Action can change them.
+				var fingerprint string
+				if opts.Checkpoint != nil && step.Checkpoint != nil {
+					var ok bool
+					if fingerprint, ok = fingerprints[name]; !ok {
+						fingerprint = step.Checkpoint.fingerprint(name)
+					}
+				}
 				start := time.Now()
-				err := runStep(runCtx, step, opts)
+				attempts, err := runStep(runCtx, step, opts)
 				end := time.Now()
+				var output json.RawMessage
+				if err == nil && opts.Checkpoint != nil && step.Checkpoint != nil && step.Checkpoint.Output != nil {
+					if output, err = step.Checkpoint.Output(); err != nil {
+						log.Printf("exegraph: capturing checkpoint output for %q: %v", name, err)
+						output, err = nil, nil
+					}
+				}
 				// Snapshot runCtx state immediately after the step's Action returns.
 				// This is the canonical "was this cancellation from the scheduler?"
 				// signal — doing it later in the event loop risks racing with a
@@ -208,8 +303,8 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 				// which would incorrectly re-classify a genuine per-step timeout
 				// or action failure as a scheduler cancellation.
 				completions <- stepCompletion{
-					name: name, err: err, start: start, end: end,
-					schedulerCanceled: runCtx.Err() != nil,
+					name: name, err: err, start: start, end: end, output: output,
+					fingerprint: fingerprint, attempts: attempts, schedulerCanceled: runCtx.Err() != nil,
 				}
 			}
 		})
@@ -217,24 +312,47 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 
 	runStart := time.Now()
 
+	inflight := 0
+
+	// dispatchReady queues pending steps in priority order while a worker is
+	// free to start them and their pool slots can be claimed, and leaves the
+	// rest pending until a completion frees a worker or slots.
+	dispatchReady := func() {
+		if len(pending) > 1 {
+			slices.SortStableFunc(pending, func(a, b string) int {
+				return cmp.Compare(g.Priority(b), g.Priority(a))
+			})
+		}
+		waiting := pending[:0]
+		for _, name := range pending {
+			if inflight >= numWorkers || !stepPools.tryAcquire(g.steps[name]) {
+				waiting = append(waiting, name)
+				continue
+			}
+			workQueue <- name
+			inflight++
+		}
+		pending = waiting
+	}
+
 	// Seed ready queue with zero in-degree steps, sorted by transitive
 	// dependent count descending (critical-path heuristic). Steps with more
 	// downstream dependents start first, reducing overall wall-clock time
 	// when parallelism is bounded.
-	inflight := 0
 	priorityOrder := g.priorityOrder()
 	for _, name := range priorityOrder {
 		if inDegree[name] == 0 {
-			workQueue <- name
-			inflight++
+			pending = append(pending, name)
 		}
 	}
+	dispatchReady()
 
 	// Event loop: process completions as they arrive. Each completion may
 	// unblock successors whose in-degree drops to zero.
 	for inflight > 0 {
 		comp := <-completions
 		inflight--
+		stepPools.release(g.steps[comp.name])
 
 		status, isRealFailure := classifyStepResult(comp.err, comp.schedulerCanceled)
 		timingMu.Lock()
@@ -246,9 +364,24 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 			Duration: comp.end.Sub(comp.start),
 			Tags:     g.steps[comp.name].Tags,
 			Err:      comp.err,
+			Resumed:  comp.resumed,
+			Attempts: comp.attempts,
 		})
 		timingMu.Unlock()
 
+		if isRealFailure {
+			// A carried-forward entry whose Restore failed and whose re-run
+			// then failed must not be resumed next time.
+			delete(current.Steps, comp.name)
+		} else if comp.err == nil && !comp.resumed && comp.fingerprint != "" {
+			current.Steps[comp.name] = CheckpointEntry{
+				Fingerprint: comp.fingerprint,
+				Output:      comp.output,
+				CompletedAt: comp.end,
+			}
+			saveCheckpoint()
+		}
+
 		if comp.err != nil {
 			if isRealFailure {
 				failed[comp.name] = true
@@ -261,10 +394,22 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 				for inflight > 0 {
 					r := <-completions
 					inflight--
+					stepPools.release(g.steps[r.name])
 					drainStatus, drainIsReal := classifyStepResult(r.err, r.schedulerCanceled)
 					if drainIsReal {
 						allErrors = append(allErrors, r.err)
 					}
+					// A peer that finished successfully while the run was being
+					// torn down still counts as progress worth resuming from.
+					if drainIsReal {
+						delete(current.Steps, r.name)
+					} else if r.err == nil && !r.resumed && r.fingerprint != "" {
+						current.Steps[r.name] = CheckpointEntry{
+							Fingerprint: r.fingerprint,
+							Output:      r.output,
+							CompletedAt: r.end,
+						}
+					}
 					timingMu.Lock()
 					result.Steps = append(result.Steps, StepTiming{
 						Name:     r.name,
@@ -274,6 +419,8 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 						Duration: r.end.Sub(r.start),
 						Tags:     g.steps[r.name].Tags,
 						Err:      r.err,
+						Resumed:  r.resumed,
+						Attempts: r.attempts,
 					})
 					timingMu.Unlock()
 				}
@@ -331,18 +478,12 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 			}
 		}
 
-		// Sort newly ready steps by priority (critical-path first).
-		// Stable sort so ties are deterministic across runs (tests rely on
-		// this, and users benefit from reproducible scheduling order).
-		if len(readyBatch) > 1 {
-			slices.SortStableFunc(readyBatch, func(a, b string) int {
-				return cmp.Compare(g.Priority(b), g.Priority(a))
-			})
-		}
-		for _, name := range readyBatch {
-			workQueue <- name
-			inflight++
-		}
+		// Queue newly ready steps, together with any still waiting on a pool,
+		// by priority (critical-path first). The sort is stable so ties are
+		// deterministic across runs (tests rely on this, and users benefit
+		// from reproducible scheduling order).
+		pending = append(pending, readyBatch...)
+		dispatchReady()
 	}
 
 	close(workQueue)
@@ -350,6 +491,10 @@ func execute(ctx context.Context, g *Graph, opts RunOptions) *RunResult {
 
 	result.TotalDuration = time.Since(runStart)
 
+	// Persist the final checkpoint once more so entries recorded during a
+	// FailFast drain (which skips the per-completion save) are not lost.
+	saveCheckpoint()
+
 	// FailFast / parent-cancel completeness: synthesize StepSkipped timings
 	// for any steps that never appeared in result.Steps. Without this, a
 	// FailFast tear-down leaves the post-break unqueued downstream steps
@@ -438,7 +583,7 @@ func setStepSpanAttributes(span tracing.Span, step *Step) {
 	}
 }
 
-func runStep(ctx context.Context, step *Step, opts RunOptions) (stepErr error) {
+func runStep(ctx context.Context, step *Step, opts RunOptions) (attempts int, stepErr error) {
 	ctx, span := tracing.Start(ctx, events.ExeGraphStepEvent)
 
 	setStepSpanAttributes(span, step)
@@ -455,16 +600,42 @@ func runStep(ctx context.Context, step *Step, opts RunOptions) (stepErr error) {
 			log.Printf("exegraph: recovered panic in step %q (this is likely an internal bug): %v", step.Name, r)
 			stepErr = fmt.Errorf("step %q panicked: %v\n%s", step.Name, r, debug.Stack())
 		}
+		span.SetAttributes(fields.ExeGraphStepAttemptsKey.Int(attempts))
 		span.EndWithStatus(stepErr)
 		safeNotifyDone(opts, step.Name, stepErr)
 	}()
 
 	safeNotifyStart(opts, step.Name)
 
-	if err := step.Action(ctx); err != nil {
-		return fmt.Errorf("step %q failed: %w", step.Name, err)
+	for {
+		attempts++
+		err := step.Action(ctx)
+		if err == nil {
+			return attempts, nil
+		}
+		delay, ok := step.Retry.retry(ctx, attempts, err)
+		if !ok {
+			return attempts, fmt.Errorf("step %q failed: %w", step.Name, err)
+		}
+		log.Printf("exegraph: step %q failed on attempt %d, retrying in %s: %v", step.Name, attempts, delay, err)
+		if !sleep(ctx, delay) {
+			return attempts, fmt.Errorf("step %q failed: %w", step.Name, err)
+		}
+	}
+}
+
+// restoreStep replays a recorded checkpoint output through the step's Restore
+// hook. It reports false when restoration fails, in which case the caller
+// executes the step normally.
+func restoreStep(step *Step, entry CheckpointEntry) bool {
+	if step.Checkpoint.Restore == nil {
+		return true
+	}
+	if err := step.Checkpoint.Restore(entry.Output); err != nil {
+		log.Printf("exegraph: restoring checkpoint for %q failed, re-running step: %v", step.Name, err)
+		return false
 	}
-	return nil
+	return true
 }
 
 // hasFailedDep checks if any of a step's dependencies are in the failed set.
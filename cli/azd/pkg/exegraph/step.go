@@ -77,6 +77,13 @@ type Step struct {
 
 	// Action is the function to execute when all dependencies are satisfied.
 	Action StepFunc
+
+	// Checkpoint opts the step into checkpoint/resume (see [RunOptions.Checkpoint]).
+	// Nil means the step always executes.
+	Checkpoint *StepCheckpoint
+
+	// Retry re-runs Action on retryable failures. Nil means a single attempt.
+	Retry *RetryPolicy
 }
 
 // StepTiming captures wall-clock timing for a single step.
@@ -88,6 +95,14 @@ type StepTiming struct {
 	Duration time.Duration
 	Tags     []string
 	Err      error
+
+	// Resumed is true when the step was satisfied from a prior checkpoint
+	// instead of executing its Action.
+	Resumed bool
+
+	// Attempts is the number of times the step's Action ran: more than one
+	// when its [RetryPolicy] retried it, zero when it was skipped or resumed.
+	Attempts int
 }
 
 // RunResult captures the outcome of a graph execution including per-step timing.
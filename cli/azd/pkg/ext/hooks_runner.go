@@ -125,6 +125,38 @@ func (h *HooksRunner) RunHooks(
 	return nil
 }
 
+// RunHook validates and invokes a single hook configuration under the given name, independently of
+// the hooks registered with the runner. It is used to run hook-style scripts outside of a command
+// lifecycle, such as the script steps of a workflow.
+func (h *HooksRunner) RunHook(
+	ctx context.Context,
+	name string,
+	hookType string,
+	hookConfig *HookConfig,
+	options *tools.ExecutionContext,
+) error {
+	hooks, err := h.hooksManager.GetAll(map[string][]*HookConfig{name: {hookConfig}})
+	if err != nil {
+		return err
+	}
+
+	for _, hook := range hooks {
+		if err := h.envManager.Reload(ctx, h.env); err != nil {
+			return fmt.Errorf("reloading environment before running hook: %w", err)
+		}
+
+		if err := h.execHook(ctx, hook, hookType, options); err != nil {
+			return err
+		}
+
+		if err := h.envManager.Reload(ctx, h.env); err != nil {
+			return fmt.Errorf("reloading environment after running hook: %w", err)
+		}
+	}
+
+	return nil
+}
+
 // setHookSpanAttributes records the hook name and scope on span. Built-in
 // lifecycle hook names are emitted raw; user- or extension-defined names are
 // hashed via HookNameAttribute to avoid leaking identifiers in telemetry.
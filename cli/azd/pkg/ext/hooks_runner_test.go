@@ -287,6 +287,40 @@ func Test_Hooks_Execute(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("RunHook", func(t *testing.T) {
+		ranHook := false
+
+		mockContext := mocks.NewMockContext(t.Context())
+		registerHookExecutors(mockContext)
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.Contains(command, "precommand.sh")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ranHook = true
+			require.Equal(t, cwd, args.Cwd)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		hooksManager := NewHooksManager(HooksManagerOptions{Cwd: cwd, ProjectDir: cwd}, mockContext.CommandRunner)
+		runner := NewHooksRunner(
+			hooksManager,
+			mockContext.CommandRunner,
+			envManager,
+			mockContext.Console,
+			cwd,
+			nil,
+			env,
+			mockContext.Container,
+		)
+
+		// The hook is not registered with the runner and its name is not a lifecycle name.
+		hook := &HookConfig{Shell: string(language.HookKindBash), Run: "scripts/precommand.sh"}
+		err := runner.RunHook(*mockContext.Context, "release step", "workflow", hook, nil)
+
+		require.NoError(t, err)
+		require.True(t, ranHook)
+		require.Equal(t, "release step", hook.Name)
+	})
 }
 
 // Test_Hooks_Validation verifies that hook configuration validation
@@ -28,7 +28,7 @@ const BundleRegistryFileName = "registry.json"
 // be fully portable: the registry.json author does not need to know the final
 // extraction location, and the installer's local-path artifact handling works
 // unchanged because it receives absolute paths.
-func newBundleSource(name string, location string) (Source, error) {
+func newBundleSource(name string, location string, verifier *signatureVerifier) (Source, error) {
 	bundleDir, registryPath, err := resolveBundlePaths(location)
 	if err != nil {
 		return nil, err
@@ -39,6 +39,10 @@ func newBundleSource(name string, location string) (Source, error) {
 		return nil, fmt.Errorf("failed reading bundle registry '%s': %w", registryPath, err)
 	}
 
+	if err := verifyRegistryFile(verifier, registryPath, registryBytes); err != nil {
+		return nil, err
+	}
+
 	var registry *Registry
 	if err := json.Unmarshal(registryBytes, &registry); err != nil {
 		return nil, fmt.Errorf("unable to unmarshal bundle registry '%s': %w", registryPath, err)
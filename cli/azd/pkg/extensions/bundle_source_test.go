@@ -50,7 +50,7 @@ func TestNewBundleSource_AnchorsRelativeArtifacts(t *testing.T) {
 	}
 	writeBundleRegistry(t, bundleDir, registry)
 
-	source, err := newBundleSource("bundle", bundleDir)
+	source, err := newBundleSource("bundle", bundleDir, &signatureVerifier{})
 	require.NoError(t, err)
 
 	exts, err := source.ListExtensions(t.Context())
@@ -88,7 +88,7 @@ func TestNewBundleSource_LocationAsRegistryFile(t *testing.T) {
 	}
 	registryPath := writeBundleRegistry(t, bundleDir, registry)
 
-	source, err := newBundleSource("bundle", registryPath)
+	source, err := newBundleSource("bundle", registryPath, &signatureVerifier{})
 	require.NoError(t, err)
 
 	exts, err := source.ListExtensions(t.Context())
@@ -116,7 +116,7 @@ func TestNewBundleSource_RejectsPathTraversal(t *testing.T) {
 	}
 	writeBundleRegistry(t, bundleDir, registry)
 
-	_, err := newBundleSource("bundle", bundleDir)
+	_, err := newBundleSource("bundle", bundleDir, &signatureVerifier{})
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "outside the bundle directory")
 }
@@ -124,7 +124,7 @@ func TestNewBundleSource_RejectsPathTraversal(t *testing.T) {
 func TestNewBundleSource_MissingRegistry(t *testing.T) {
 	t.Parallel()
 
-	_, err := newBundleSource("bundle", t.TempDir())
+	_, err := newBundleSource("bundle", t.TempDir(), &signatureVerifier{})
 	require.Error(t, err)
 }
 
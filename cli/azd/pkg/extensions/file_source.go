@@ -4,6 +4,7 @@
 package extensions
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,7 +14,7 @@ import (
 )
 
 // newFileSource creates a new file base registry source.
-func newFileSource(name string, path string) (Source, error) {
+func newFileSource(name string, path string, verifier *signatureVerifier) (Source, error) {
 	absolutePath, err := getAbsolutePath(path)
 	if err != nil {
 		return nil, fmt.Errorf("failed converting path '%s' to absolute path, %w", path, err)
@@ -24,6 +25,10 @@ func newFileSource(name string, path string) (Source, error) {
 		return nil, fmt.Errorf("failed reading file '%s', %w", path, err)
 	}
 
+	if err := verifyRegistryFile(verifier, absolutePath, registryBytes); err != nil {
+		return nil, err
+	}
+
 	return newJsonSource(name, string(registryBytes))
 }
 
@@ -52,3 +57,17 @@ func getAbsolutePath(filePath string) (string, error) {
 
 	return osutil.ResolveContainedPath(roots, filePath)
 }
+
+// verifyRegistryFile verifies the registry read from path against the detached signature stored next to it.
+func verifyRegistryFile(verifier *signatureVerifier, path string, registryBytes []byte) error {
+	if !verifier.required {
+		return nil
+	}
+
+	signature, err := os.ReadFile(path + registrySignatureSuffix)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed reading registry signature '%s', %w", path+registrySignatureSuffix, err)
+	}
+
+	return verifier.verify(registryBytes, string(signature), fmt.Sprintf("registry '%s'", path))
+}
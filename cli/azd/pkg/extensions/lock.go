@@ -0,0 +1,280 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// LockFileName is the name of the file, next to azure.yaml, that records the extensions resolved for a project.
+const LockFileName = "azd-extensions.lock"
+
+// ErrLockMismatch is returned when a locked install cannot install exactly what the lock file records.
+var ErrLockMismatch = errors.New("extension does not match the lock file")
+
+// LockFile records the exact extensions resolved for a project, so that every install of the project uses the same
+// versions and artifacts.
+type LockFile struct {
+	// Extensions maps the id of each locked extension, including the dependencies of other extensions, to its
+	// resolution.
+	Extensions map[string]*LockedExtension `json:"extensions"`
+}
+
+// LockedExtension is the resolution of a locked extension.
+type LockedExtension struct {
+	// Version is the exact resolved version
+	Version string `json:"version"`
+	// Source is the name of the extension source the version was resolved from
+	Source string `json:"source"`
+	// Artifacts maps each platform (os & architecture) to its artifact
+	Artifacts map[string]LockedArtifact `json:"artifacts,omitempty"`
+}
+
+// LockedArtifact is the artifact of a locked extension for a platform.
+type LockedArtifact struct {
+	// URL is the location of the artifact
+	URL string `json:"url"`
+	// Checksum is the checksum of the artifact
+	Checksum ExtensionChecksum `json:"checksum"`
+}
+
+// NewLockFile creates an empty lock file.
+func NewLockFile() *LockFile {
+	return &LockFile{
+		Extensions: map[string]*LockedExtension{},
+	}
+}
+
+// LoadLockFile loads the lock file at path. The returned error wraps os.ErrNotExist when the file does not exist.
+func LoadLockFile(path string) (*LockFile, error) {
+	//nolint:gosec // G304: path is the lock file of the current project
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading extension lock file: %w", err)
+	}
+
+	lock := NewLockFile()
+	if err := json.Unmarshal(contents, lock); err != nil {
+		return nil, fmt.Errorf("parsing extension lock file %s: %w", path, err)
+	}
+
+	if lock.Extensions == nil {
+		lock.Extensions = map[string]*LockedExtension{}
+	}
+
+	return lock, nil
+}
+
+// Save writes the lock file to path.
+func (l *LockFile) Save(path string) error {
+	contents, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling extension lock file: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(contents, '\n'), osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing extension lock file: %w", err)
+	}
+
+	return nil
+}
+
+// Lock records version of extension, replacing any previous resolution of the extension.
+func (l *LockFile) Lock(extension *ExtensionMetadata, version *ExtensionVersion) {
+	locked := &LockedExtension{
+		Version: version.Version,
+		Source:  extension.Source,
+	}
+
+	if len(version.Artifacts) > 0 {
+		locked.Artifacts = make(map[string]LockedArtifact, len(version.Artifacts))
+		for platform, artifact := range version.Artifacts {
+			locked.Artifacts[platform] = LockedArtifact{
+				URL:      artifact.URL,
+				Checksum: artifact.Checksum,
+			}
+		}
+	}
+
+	l.Extensions[extension.Id] = locked
+}
+
+// resolve returns the locked version of extension, after checking that it satisfies versionPreference and that the
+// source still publishes it with the locked artifacts.
+func (l *LockFile) resolve(extension *ExtensionMetadata, versionPreference string) (*ExtensionVersion, error) {
+	locked, has := l.Extensions[extension.Id]
+	if !has {
+		return nil, fmt.Errorf("%w: %s is not locked", ErrLockMismatch, extension.Id)
+	}
+
+	if !strings.EqualFold(locked.Source, extension.Source) {
+		return nil, fmt.Errorf(
+			"%w: %s is locked to source '%s' but resolved from source '%s'",
+			ErrLockMismatch, extension.Id, locked.Source, extension.Source)
+	}
+
+	if !matchesVersionConstraint(versionPreference, locked.Version) {
+		return nil, fmt.Errorf(
+			"%w: %s is locked to version %s, which does not satisfy constraint %q",
+			ErrLockMismatch, extension.Id, locked.Version, versionPreference)
+	}
+
+	version := findExactVersion(extension.Versions, locked.Version)
+	if version == nil {
+		return nil, fmt.Errorf(
+			"%w: version %s of %s is no longer published by source '%s'",
+			ErrLockMismatch, locked.Version, extension.Id, extension.Source)
+	}
+
+	platforms := slices.Sorted(maps.Keys(locked.Artifacts))
+	published := slices.Sorted(maps.Keys(version.Artifacts))
+	if !slices.Equal(platforms, published) {
+		return nil, fmt.Errorf(
+			"%w: %s %s is locked for platforms [%s] but published for platforms [%s]",
+			ErrLockMismatch, extension.Id, locked.Version,
+			strings.Join(platforms, ", "), strings.Join(published, ", "))
+	}
+
+	// the artifacts the source publishes without a checksum are validated against the checksum computed when they
+	// were locked, so the returned version is a copy that carries it
+	resolved := *version
+	resolved.Artifacts = maps.Clone(version.Artifacts)
+	for _, platform := range platforms {
+		lockedArtifact := locked.Artifacts[platform]
+		artifact := version.Artifacts[platform]
+
+		if artifact.Checksum.Value == "" && lockedArtifact.Checksum.Value != "" && lockedArtifact.URL == artifact.URL {
+			artifact.Checksum = lockedArtifact.Checksum
+			resolved.Artifacts[platform] = artifact
+			continue
+		}
+
+		if lockedArtifact.URL != artifact.URL ||
+			!strings.EqualFold(lockedArtifact.Checksum.Algorithm, artifact.Checksum.Algorithm) ||
+			!strings.EqualFold(lockedArtifact.Checksum.Value, artifact.Checksum.Value) {
+			return nil, fmt.Errorf(
+				"%w: the %s artifact of %s %s has changed since it was locked",
+				ErrLockMismatch, platform, extension.Id, locked.Version)
+		}
+	}
+
+	return &resolved, nil
+}
+
+// checkLockedDependency checks that the installed dependency of an extension installed with lock is at its locked
+// version. Installing the extension does not move an installed dependency, which is installed with the lock on its own.
+func checkLockedDependency(lock *LockFile, installed *Extension) error {
+	locked, has := lock.Extensions[installed.Id]
+	if !has {
+		return fmt.Errorf("%w: dependency %s is not locked", ErrLockMismatch, installed.Id)
+	}
+
+	if installed.Version != locked.Version {
+		return fmt.Errorf(
+			"%w: installed dependency %s version %s is not the locked version %s, "+
+				"run 'azd extension install --locked %s' to install the locked version",
+			ErrLockMismatch, installed.Id, installed.Version, locked.Version, installed.Id)
+	}
+
+	return nil
+}
+
+// findExactVersion returns the published version that is exactly version, or nil.
+func findExactVersion(versions []ExtensionVersion, version string) *ExtensionVersion {
+	for i := range versions {
+		if strings.EqualFold(versions[i].Version, version) {
+			return &versions[i]
+		}
+	}
+
+	return nil
+}
+
+// LockInstalled records the installed version of the extension id, and of the extensions it depends on, in lock.
+func (m *Manager) LockInstalled(ctx context.Context, lock *LockFile, id string) error {
+	return m.lockInstalled(ctx, lock, id, map[string]struct{}{})
+}
+
+func (m *Manager) lockInstalled(ctx context.Context, lock *LockFile, id string, visited map[string]struct{}) error {
+	if _, seen := visited[id]; seen {
+		return nil
+	}
+	visited[id] = struct{}{}
+
+	installed, err := m.GetInstalled(FilterOptions{Id: id})
+	if err != nil {
+		return fmt.Errorf("failed to get installed extension %s: %w", id, err)
+	}
+
+	// Extensions installed from a self-contained bundle have no registry to install them from again.
+	if installed.Source == BundleSourceName {
+		log.Printf("not locking extension %s, which was installed from a bundle", id)
+		return nil
+	}
+
+	matches, err := m.FindExtensions(ctx, &FilterOptions{Id: id, Source: installed.Source})
+	if err != nil {
+		return fmt.Errorf("failed to find extension %s: %w", id, err)
+	}
+
+	if len(matches) == 0 {
+		return fmt.Errorf("extension %s was not found in source '%s'", id, installed.Source)
+	}
+
+	version := findExactVersion(matches[0].Versions, installed.Version)
+	if version == nil {
+		return fmt.Errorf(
+			"version %s of extension %s was not found in source '%s'", installed.Version, id, installed.Source)
+	}
+
+	lock.Lock(matches[0], version)
+	if err := m.checksumLockedArtifacts(ctx, id, lock.Extensions[id]); err != nil {
+		return err
+	}
+
+	for _, dependency := range version.Dependencies {
+		if err := m.lockInstalled(ctx, lock, dependency.Id, visited); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checksumLockedArtifacts computes the checksum of the artifacts of locked that the source publishes without one, so
+// that installing them from the lock detects an artifact replaced at the same location.
+func (m *Manager) checksumLockedArtifacts(ctx context.Context, id string, locked *LockedExtension) error {
+	for _, platform := range slices.Sorted(maps.Keys(locked.Artifacts)) {
+		artifact := locked.Artifacts[platform]
+		if artifact.Checksum.Value != "" {
+			continue
+		}
+
+		tempFilePath, err := m.downloadArtifact(ctx, artifact.URL)
+		if err != nil {
+			return fmt.Errorf("failed to download the %s artifact of %s to lock its checksum: %w", platform, id, err)
+		}
+
+		value, err := computeChecksum(tempFilePath, "sha256")
+		removeDownloadedArtifact(tempFilePath)
+		if err != nil {
+			return err
+		}
+
+		artifact.Checksum = ExtensionChecksum{Algorithm: "sha256", Value: value}
+		locked.Artifacts[platform] = artifact
+	}
+
+	return nil
+}
@@ -0,0 +1,253 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+var lockTestRegistry = Registry{
+	Extensions: []*ExtensionMetadata{
+		{
+			Id:        "test.pack",
+			Namespace: "pack",
+			Versions: []ExtensionVersion{
+				{
+					Version:      "1.0.0",
+					Dependencies: []ExtensionDependency{{Id: "test.child", Version: ">=1.0.0"}},
+				},
+				{
+					Version:      "2.0.0",
+					Dependencies: []ExtensionDependency{{Id: "test.child", Version: ">=1.0.0"}},
+				},
+			},
+		},
+		{
+			Id:        "test.child",
+			Namespace: "child",
+			Versions: []ExtensionVersion{
+				{Version: "1.0.0", Artifacts: sampleArtifacts},
+				{Version: "2.0.0", Artifacts: sampleArtifacts},
+			},
+		},
+	},
+}
+
+func newLockTestManager(t *testing.T) (*mocks.MockContext, *Manager) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.String() == extensionRegistryUrl
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, lockTestRegistry)
+	})
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.String(), "https://aka.ms/azd/extensions/registry/")
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, []byte("test data"))
+	})
+
+	userConfigManager := config.NewUserConfigManager(mockContext.ConfigManager)
+	sourceManager := NewSourceManager(mockContext.Container, userConfigManager, mockContext.HttpClient)
+	lazyRunner := lazy.NewLazy(func() (*Runner, error) {
+		return NewRunner(mockContext.CommandRunner), nil
+	})
+	manager, err := NewManager(userConfigManager, sourceManager, lazyRunner, mockContext.HttpClient)
+	require.NoError(t, err)
+
+	return mockContext, manager
+}
+
+func findLockTestExtension(t *testing.T, mockContext *mocks.MockContext, manager *Manager, id string) *ExtensionMetadata {
+	matches, err := manager.FindExtensions(*mockContext.Context, &FilterOptions{Id: id})
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+
+	return matches[0]
+}
+
+// newTestLockFile locks test.pack and test.child to version 1.0.0.
+func newTestLockFile(t *testing.T, mockContext *mocks.MockContext, manager *Manager) *LockFile {
+	lock := NewLockFile()
+	for _, id := range []string{"test.pack", "test.child"} {
+		extension := findLockTestExtension(t, mockContext, manager, id)
+		lock.Lock(extension, findExactVersion(extension.Versions, "1.0.0"))
+		require.NoError(t, manager.checksumLockedArtifacts(*mockContext.Context, id, lock.Extensions[id]))
+	}
+
+	return lock
+}
+
+func Test_LockFile_SaveAndLoad(t *testing.T) {
+	mockContext, manager := newLockTestManager(t)
+	lock := newTestLockFile(t, mockContext, manager)
+
+	lockPath := filepath.Join(t.TempDir(), LockFileName)
+	require.NoError(t, lock.Save(lockPath))
+
+	loaded, err := LoadLockFile(lockPath)
+	require.NoError(t, err)
+	require.Equal(t, lock, loaded)
+	require.Equal(t, "1.0.0", loaded.Extensions["test.child"].Version)
+	require.Equal(t, sampleArtifacts["linux"].URL, loaded.Extensions["test.child"].Artifacts["linux"].URL)
+
+	// the registry publishes the artifacts without a checksum, so the checksum of the downloaded artifact is locked
+	artifactContents, err := json.Marshal([]byte("test data"))
+	require.NoError(t, err)
+	testDataChecksum := sha256.Sum256(artifactContents)
+	require.Equal(t, ExtensionChecksum{Algorithm: "sha256", Value: hex.EncodeToString(testDataChecksum[:])},
+		loaded.Extensions["test.child"].Artifacts["linux"].Checksum)
+
+	_, err = LoadLockFile(filepath.Join(t.TempDir(), LockFileName))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_Install_Locked(t *testing.T) {
+	mockContext, manager := newLockTestManager(t)
+	lock := newTestLockFile(t, mockContext, manager)
+
+	pack := findLockTestExtension(t, mockContext, manager, "test.pack")
+	version, err := manager.InstallWithOptions(*mockContext.Context, pack, InstallOptions{Lock: lock})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", version.Version)
+
+	// the dependency is installed at its locked version, not at the best satisfying one
+	child, err := manager.GetInstalled(FilterOptions{Id: "test.child"})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", child.Version)
+
+	// locking the installed extensions records the same resolution
+	installedLock := NewLockFile()
+	require.NoError(t, manager.LockInstalled(*mockContext.Context, installedLock, "test.pack"))
+	require.Equal(t, lock, installedLock)
+}
+
+func Test_Install_LockMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(lock *LockFile)
+	}{
+		{
+			name:   "NotLocked",
+			modify: func(lock *LockFile) { delete(lock.Extensions, "test.child") },
+		},
+		{
+			name:   "VersionNotPublished",
+			modify: func(lock *LockFile) { lock.Extensions["test.child"].Version = "0.9.0" },
+		},
+		{
+			name:   "SourceChanged",
+			modify: func(lock *LockFile) { lock.Extensions["test.child"].Source = "other" },
+		},
+		{
+			name: "ArtifactChanged",
+			modify: func(lock *LockFile) {
+				artifact := lock.Extensions["test.child"].Artifacts["linux"]
+				artifact.URL = "https://aka.ms/azd/extensions/registry/test.extension/azd-ext-test-linux-arm64"
+				lock.Extensions["test.child"].Artifacts["linux"] = artifact
+			},
+		},
+		{
+			name:   "PlatformRemoved",
+			modify: func(lock *LockFile) { delete(lock.Extensions["test.child"].Artifacts, "darwin") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext, manager := newLockTestManager(t)
+			lock := newTestLockFile(t, mockContext, manager)
+			tt.modify(lock)
+
+			pack := findLockTestExtension(t, mockContext, manager, "test.pack")
+			_, err := manager.InstallWithOptions(*mockContext.Context, pack, InstallOptions{Lock: lock})
+			require.ErrorIs(t, err, ErrLockMismatch)
+		})
+	}
+}
+
+func Test_Install_LockedArtifactReplaced(t *testing.T) {
+	mockContext, manager := newLockTestManager(t)
+	lock := newTestLockFile(t, mockContext, manager)
+
+	// the source publishes the artifact without a checksum, the one computed when it was locked must still match
+	for _, platform := range []string{"linux", "darwin", "windows"} {
+		artifact := lock.Extensions["test.child"].Artifacts[platform]
+		artifact.Checksum = ExtensionChecksum{Algorithm: "sha256", Value: "abc123"}
+		lock.Extensions["test.child"].Artifacts[platform] = artifact
+	}
+
+	pack := findLockTestExtension(t, mockContext, manager, "test.pack")
+	_, err := manager.InstallWithOptions(*mockContext.Context, pack, InstallOptions{Lock: lock})
+	require.ErrorContains(t, err, "checksum mismatch")
+
+	_, err = manager.GetInstalled(FilterOptions{Id: "test.child"})
+	require.ErrorIs(t, err, ErrInstalledExtensionNotFound)
+}
+
+func Test_Install_LockedDependencyInstalledAtOtherVersion(t *testing.T) {
+	mockContext, manager := newLockTestManager(t)
+
+	child := findLockTestExtension(t, mockContext, manager, "test.child")
+	_, err := manager.Install(*mockContext.Context, child, "2.0.0")
+	require.NoError(t, err)
+
+	// the installed dependency satisfies the constraint of test.pack, but it is not the locked version
+	lock := newTestLockFile(t, mockContext, manager)
+	pack := findLockTestExtension(t, mockContext, manager, "test.pack")
+	_, err = manager.InstallWithOptions(*mockContext.Context, pack, InstallOptions{Lock: lock})
+	require.ErrorIs(t, err, ErrLockMismatch)
+	require.ErrorContains(t, err, "not the locked version 1.0.0")
+
+	_, err = manager.GetInstalled(FilterOptions{Id: "test.pack"})
+	require.ErrorIs(t, err, ErrInstalledExtensionNotFound)
+
+	// once the dependency is at its locked version, the extension installs
+	childLock := &LockFile{Extensions: map[string]*LockedExtension{"test.child": lock.Extensions["test.child"]}}
+	_, _, err = manager.Upgrade(*mockContext.Context, child, UpgradeOptions{Lock: childLock})
+	require.NoError(t, err)
+
+	_, err = manager.InstallWithOptions(*mockContext.Context, pack, InstallOptions{Lock: lock})
+	require.NoError(t, err)
+}
+
+func Test_Upgrade_Locked(t *testing.T) {
+	mockContext, manager := newLockTestManager(t)
+
+	pack := findLockTestExtension(t, mockContext, manager, "test.pack")
+	_, err := manager.Install(*mockContext.Context, pack, "")
+	require.NoError(t, err)
+
+	child, err := manager.GetInstalled(FilterOptions{Id: "test.child"})
+	require.NoError(t, err)
+	require.Equal(t, "2.0.0", child.Version)
+
+	// a locked upgrade moves the extension and its dependency to their locked versions, even when it downgrades them
+	lock := newTestLockFile(t, mockContext, manager)
+	version, dependencyUpgrades, err := manager.Upgrade(*mockContext.Context, pack, UpgradeOptions{
+		UpgradeDependencies: true,
+		Lock:                lock,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", version.Version)
+	require.Len(t, dependencyUpgrades, 1)
+	require.Equal(t, UpgradeStatusUpgraded, dependencyUpgrades[0].Status)
+
+	child, err = manager.GetInstalled(FilterOptions{Id: "test.child"})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", child.Version)
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/internal/tracing/fields"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
@@ -240,6 +241,21 @@ func resolveExtensionVersion(
 	)
 }
 
+// resolveInstallVersion selects the version of extension to install: its locked version when lock is set, otherwise
+// the best published version that satisfies versionPreference and is compatible with azdVersion.
+func resolveInstallVersion(
+	extension *ExtensionMetadata,
+	versionPreference string,
+	azdVersion *semver.Version,
+	lock *LockFile,
+) (*ExtensionVersion, error) {
+	if lock != nil {
+		return lock.resolve(extension, versionPreference)
+	}
+
+	return resolveExtensionVersion(extension, versionPreference, azdVersion)
+}
+
 // createExtensionFilter creates a comprehensive filter that checks ALL criteria with AND logic
 func createExtensionFilter(options *FilterOptions) extensionFilterPredicate {
 	return func(extension *ExtensionMetadata) bool {
@@ -520,6 +536,9 @@ type InstallOptions struct {
 	VersionPreference string
 	// AzdVersion limits selected extension versions to those compatible with azd.
 	AzdVersion *semver.Version
+	// Lock, when set, installs the extension and its dependencies exactly as locked instead of resolving the best
+	// satisfying versions, and fails when the source no longer matches the lock.
+	Lock *LockFile
 }
 
 // InstallWithOptions installs an extension using the supplied options.
@@ -569,7 +588,7 @@ func (m *Manager) installInternal(
 	}
 
 	// Resolve to the latest published version that satisfies the preference.
-	selectedVersion, err := resolveExtensionVersion(extension, opts.VersionPreference, opts.AzdVersion)
+	selectedVersion, err := resolveInstallVersion(extension, opts.VersionPreference, opts.AzdVersion, opts.Lock)
 	if err != nil {
 		return nil, err
 	}
@@ -589,6 +608,15 @@ func (m *Manager) installInternal(
 		for _, dependency := range selectedVersion.Dependencies {
 			installedDependency, err := m.GetInstalled(FilterOptions{Id: dependency.Id})
 			if err == nil && installedDependency != nil {
+				if opts.Lock != nil {
+					if !skipDependencyValidation {
+						if err := checkLockedDependency(opts.Lock, installedDependency); err != nil {
+							return nil, err
+						}
+					}
+					continue
+				}
+
 				if !skipDependencyValidation &&
 					dependency.Version != "" &&
 					!matchesVersionConstraint(dependency.Version, installedDependency.Version) {
@@ -629,6 +657,7 @@ func (m *Manager) installInternal(
 			dependencyOpts := InstallOptions{
 				VersionPreference: dependency.Version,
 				AzdVersion:        opts.AzdVersion,
+				Lock:              opts.Lock,
 			}
 			if _, err := m.installInternal(ctx, dependencyMetadata, dependencyOpts, false, visited); err != nil {
 				if !errors.Is(err, ErrExtensionInstalled) {
@@ -657,13 +686,24 @@ func (m *Manager) installInternal(
 		}
 
 		// Clean up the temp file after all scenarios
-		defer os.Remove(tempFilePath)
+		defer removeDownloadedArtifact(tempFilePath)
 
 		// Step 5: Validate the checksum if provided
 		if err := validateChecksum(tempFilePath, artifact.Checksum); err != nil {
 			return nil, fmt.Errorf("checksum validation failed: %w", err)
 		}
 
+		// Verify the signature against the trusted public keys of the source
+		verifier, err := m.artifactSignatureVerifier(ctx, extension.Source)
+		if err != nil {
+			return nil, err
+		}
+
+		subject := fmt.Sprintf("artifact '%s' of extension %s", artifact.URL, extension.Id)
+		if err := verifier.verifyFile(tempFilePath, artifact.Signature, subject); err != nil {
+			return nil, err
+		}
+
 		userConfigDir, err := config.GetUserConfigDir()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get user config directory: %w", err)
@@ -827,6 +867,8 @@ type UpgradeOptions struct {
 	UpgradeDependencies bool
 	// AzdVersion limits selected extension versions to those compatible with azd.
 	AzdVersion *semver.Version
+	// Lock, when set, moves the extension and its dependencies to their locked versions.
+	Lock *LockFile
 }
 
 // DefaultUpgradeOptions returns UpgradeOptions with dependency upgrades enabled.
@@ -863,7 +905,7 @@ func (m *Manager) ReconcileDependencies(
 		return nil, nil, fmt.Errorf("extension metadata cannot be nil")
 	}
 
-	selectedVersion, err := resolveExtensionVersion(extension, opts.VersionPreference, opts.AzdVersion)
+	selectedVersion, err := resolveInstallVersion(extension, opts.VersionPreference, opts.AzdVersion, opts.Lock)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -894,6 +936,7 @@ func (m *Manager) upgradeInternal(
 	extensionVersion, err := m.installInternal(ctx, extension, InstallOptions{
 		VersionPreference: opts.VersionPreference,
 		AzdVersion:        opts.AzdVersion,
+		Lock:              opts.Lock,
 	}, true, map[string]struct{}{})
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to install extension: %w", err)
@@ -972,7 +1015,24 @@ func (m *Manager) evaluateDependencyChanges(
 			continue
 		}
 
-		bestVersion := bestSatisfyingVersionForAzd(dep.Version, childMetadata.Versions, opts.AzdVersion)
+		var bestVersion *ExtensionVersion
+		if opts.Lock != nil {
+			// A locked upgrade moves the dependency to its locked version, whichever it is.
+			lockedVersion, lockErr := opts.Lock.resolve(childMetadata, dep.Version)
+			if lockErr != nil {
+				results = append(results, UpgradeResult{
+					ExtensionId: dep.Id,
+					Status:      UpgradeStatusFailed,
+					FromVersion: installed.Version,
+					FromSource:  installed.Source,
+					Error:       lockErr,
+				})
+				continue
+			}
+			bestVersion = lockedVersion
+		} else {
+			bestVersion = bestSatisfyingVersionForAzd(dep.Version, childMetadata.Versions, opts.AzdVersion)
+		}
 		if bestVersion == nil {
 			// If no published version matches, keep a compatible installed version.
 			if matchesVersionConstraint(dep.Version, installed.Version) {
@@ -998,7 +1058,7 @@ func (m *Manager) evaluateDependencyChanges(
 
 		// Refuse to silently downgrade: a user (or sibling pack) may have moved the dependency
 		// past this pack's declared range deliberately.
-		if isDowngrade(installed.Version, bestVersion.Version) {
+		if opts.Lock == nil && isDowngrade(installed.Version, bestVersion.Version) {
 			if matchesVersionConstraint(dep.Version, installed.Version) {
 				// Installed is newer but still satisfies the constraint — keep it, no-op.
 				continue
@@ -1055,6 +1115,7 @@ func (m *Manager) evaluateDependencyChanges(
 			VersionPreference:   dep.Version,
 			UpgradeDependencies: opts.UpgradeDependencies,
 			AzdVersion:          opts.AzdVersion,
+			Lock:                opts.Lock,
 		}
 
 		childVersion, nested, upErr := m.upgradeInternal(childCtx, childMetadata, childOpts, visited)
@@ -1145,6 +1206,9 @@ func (m *Manager) downloadArtifact(ctx context.Context, artifactUrl string) (str
 	if strings.HasPrefix(artifactUrl, "http://") || strings.HasPrefix(artifactUrl, "https://") {
 		return m.downloadFromRemote(ctx, artifactUrl)
 	}
+	if oci.IsReference(artifactUrl) {
+		return m.downloadFromOci(ctx, artifactUrl)
+	}
 	return m.copyFromLocalPath(artifactUrl)
 }
 
@@ -1300,6 +1364,20 @@ func (tm *Manager) createSourcesFromConfig(
 	return sources, nil
 }
 
+// artifactSignatureVerifier returns the verifier of the artifact signatures of the extension source named sourceName.
+func (m *Manager) artifactSignatureVerifier(ctx context.Context, sourceName string) (*signatureVerifier, error) {
+	sourceConfig, err := m.sourceManager.Get(ctx, sourceName)
+	if errors.Is(err, ErrSourceNotFound) {
+		// Sources that are not part of the user configuration, such as the ones given on the command line, have no
+		// trusted public keys.
+		sourceConfig = &SourceConfig{Name: sourceName}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return m.sourceManager.signatureVerifier(sourceConfig)
+}
+
 // validateChecksum validates the file at the given path against the expected checksum using the specified algorithm.
 func validateChecksum(filePath string, checksum ExtensionChecksum) error {
 	// Check if checksum or required fields are nil
@@ -1308,40 +1386,48 @@ func validateChecksum(filePath string, checksum ExtensionChecksum) error {
 		return nil
 	}
 
+	computedChecksum, err := computeChecksum(filePath, checksum.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	// Compare the computed checksum with the expected checksum
+	if computedChecksum != checksum.Value {
+		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum.Value, computedChecksum)
+	}
+
+	return nil
+}
+
+// computeChecksum returns the hexadecimal checksum of the file at the given path with the specified algorithm.
+func computeChecksum(filePath string, algorithm string) (string, error) {
 	var hashAlgo hash.Hash
 
 	// Select the hashing algorithm based on the input
-	switch checksum.Algorithm {
+	switch algorithm {
 	case "sha256":
 		hashAlgo = sha256.New()
 	case "sha512":
 		hashAlgo = sha512.New()
 	default:
-		return fmt.Errorf("unsupported checksum algorithm: %s", checksum.Algorithm)
+		return "", fmt.Errorf("unsupported checksum algorithm: %s", algorithm)
 	}
 
 	// Open the file for reading
 	//nolint:gosec // G703: filePath from extension install
 	file, err := os.Open(filePath)
 	if err != nil {
-		return fmt.Errorf("failed to open file for checksum validation: %w", err)
+		return "", fmt.Errorf("failed to open file for checksum validation: %w", err)
 	}
 	defer file.Close()
 
 	// Compute the checksum
 	if _, err := io.Copy(hashAlgo, file); err != nil {
-		return fmt.Errorf("failed to compute checksum: %w", err)
+		return "", fmt.Errorf("failed to compute checksum: %w", err)
 	}
 
 	// Convert the computed checksum to a hexadecimal string
-	computedChecksum := hex.EncodeToString(hashAlgo.Sum(nil))
-
-	// Compare the computed checksum with the expected checksum
-	if computedChecksum != checksum.Value {
-		return fmt.Errorf("checksum mismatch: expected %s, got %s", checksum.Value, computedChecksum)
-	}
-
-	return nil
+	return hex.EncodeToString(hashAlgo.Sum(nil)), nil
 }
 
 // Helper function to copy a file to the target directory
@@ -0,0 +1,130 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+const (
+	// RegistryMediaType is the media type of the layer holding the registry of an OCI extension source. Registries
+	// pushed as the only layer of their artifact may use any media type.
+	RegistryMediaType = "application/vnd.microsoft.azd.extension.registry.v1+json"
+
+	// SignatureAnnotation is the annotation of the registry layer of an OCI extension source that holds the detached
+	// signature of the registry.
+	SignatureAnnotation = "com.microsoft.azd.signature"
+)
+
+// newOciSource creates a new extension source from the registry pushed as an OCI artifact at location.
+func newOciSource(
+	ctx context.Context,
+	name string,
+	location string,
+	client *oci.Client,
+	verifier *signatureVerifier,
+) (Source, error) {
+	ref, err := oci.ParseReference(location)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := client.FetchManifest(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := manifest.Layer(RegistryMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("finding the extension registry in %s: %w", ref, err)
+	}
+
+	var registry bytes.Buffer
+	if err := client.FetchBlob(ctx, ref, layer, &registry); err != nil {
+		return nil, err
+	}
+
+	if err := verifier.verify(
+		registry.Bytes(), layer.Annotations[SignatureAnnotation], fmt.Sprintf("registry '%s'", location),
+	); err != nil {
+		return nil, err
+	}
+
+	return newJsonSource(name, registry.String())
+}
+
+// ociDownloadDirPrefix prefixes the temporary directories that each hold a single artifact downloaded from an OCI
+// registry.
+const ociDownloadDirPrefix = "azd-ext-oci-"
+
+// downloadFromOci downloads the extension artifact pushed as an OCI artifact at location to a file of its own
+// temporary directory, named after the title of the artifact layer so that archives and entry points are recognized.
+func (m *Manager) downloadFromOci(ctx context.Context, location string) (string, error) {
+	ref, err := oci.ParseReference(location)
+	if err != nil {
+		return "", err
+	}
+
+	client := m.sourceManager.ociClient()
+
+	manifest, err := client.FetchManifest(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to download artifact: %w", err)
+	}
+
+	layer, err := manifest.Layer()
+	if err != nil {
+		return "", fmt.Errorf("finding the extension artifact in %s: %w", ref, err)
+	}
+
+	filename := layer.Title()
+	if filename == "" {
+		filename = path.Base(ref.Repository)
+	}
+
+	// the title is chosen by whoever pushed the artifact, and must only name a file
+	if filename == "." || filename == ".." || strings.ContainsAny(filename, `/\`) || filepath.Base(filename) != filename {
+		return "", fmt.Errorf("invalid file name '%s' of the extension artifact in %s", filename, ref)
+	}
+
+	tempDir, err := os.MkdirTemp("", ociDownloadDirPrefix+"*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	tempFilePath := filepath.Join(tempDir, filename)
+	//nolint:gosec // G304: the file name was validated above and the directory was just created
+	tempFile, err := os.OpenFile(tempFilePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, osutil.PermissionFileOwnerOnly)
+	if err != nil {
+		_ = os.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer tempFile.Close()
+
+	if err := client.FetchBlob(ctx, ref, layer, tempFile); err != nil {
+		_ = os.RemoveAll(tempDir)
+		return "", fmt.Errorf("failed to download artifact: %w", err)
+	}
+
+	return tempFilePath, nil
+}
+
+// removeDownloadedArtifact removes the temporary file of a downloaded artifact, along with the temporary directory of
+// an artifact downloaded from an OCI registry.
+func removeDownloadedArtifact(tempFilePath string) {
+	_ = os.Remove(tempFilePath)
+
+	if dir := filepath.Dir(tempFilePath); strings.HasPrefix(filepath.Base(dir), ociDownloadDirPrefix) {
+		_ = os.Remove(dir)
+	}
+}
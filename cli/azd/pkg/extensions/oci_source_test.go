@@ -0,0 +1,200 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockoci"
+	"github.com/stretchr/testify/require"
+)
+
+func newOciTestManager(t *testing.T) (*mocks.MockContext, *SourceManager, *Manager) {
+	t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	mockContext := mocks.NewMockContext(t.Context())
+	userConfigManager := config.NewUserConfigManager(mockContext.ConfigManager)
+	sourceManager := NewSourceManager(mockContext.Container, userConfigManager, http.DefaultClient)
+	lazyRunner := lazy.NewLazy(func() (*Runner, error) {
+		return NewRunner(mockContext.CommandRunner), nil
+	})
+	manager, err := NewManager(userConfigManager, sourceManager, lazyRunner, http.DefaultClient)
+	require.NoError(t, err)
+
+	return mockContext, sourceManager, manager
+}
+
+func Test_OciSource(t *testing.T) {
+	publicKey, privateKey := newTestSigningKey(t)
+
+	registry, err := json.Marshal(testRegistry)
+	require.NoError(t, err)
+
+	ociRegistry := mockoci.NewRegistry(t)
+	signedRef := ociRegistry.Push(t, "azd/extensions", "signed", mockoci.Layer{
+		MediaType:   RegistryMediaType,
+		Content:     registry,
+		Annotations: map[string]string{SignatureAnnotation: sign(privateKey, registry)},
+	})
+	unsignedRef := ociRegistry.Push(t, "azd/extensions", "unsigned", mockoci.Layer{
+		MediaType: RegistryMediaType,
+		Content:   registry,
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		mockContext, sourceManager, _ := newOciTestManager(t)
+
+		source, err := sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+			Name:     "oci",
+			Type:     SourceKindOci,
+			Location: unsignedRef,
+		})
+		require.NoError(t, err)
+
+		extensions, err := source.ListExtensions(*mockContext.Context)
+		require.NoError(t, err)
+		require.Len(t, extensions, len(testRegistry.Extensions))
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		mockContext, sourceManager, _ := newOciTestManager(t)
+
+		_, err := sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+			Name:       "oci",
+			Type:       SourceKindOci,
+			Location:   signedRef,
+			PublicKeys: []string{publicKey},
+		})
+		require.NoError(t, err)
+
+		_, err = sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+			Name:       "oci",
+			Type:       SourceKindOci,
+			Location:   unsignedRef,
+			PublicKeys: []string{publicKey},
+		})
+		require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockContext, sourceManager, _ := newOciTestManager(t)
+
+		_, err := sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+			Name:     "oci",
+			Type:     SourceKindOci,
+			Location: "oci://" + ociRegistry.Host + "/azd/extensions:missing",
+		})
+		require.ErrorIs(t, err, oci.ErrNotFound)
+	})
+}
+
+func Test_Install_FromOciSource(t *testing.T) {
+	mockContext, sourceManager, manager := newOciTestManager(t)
+	publicKey, privateKey := newTestSigningKey(t)
+	ociRegistry := mockoci.NewRegistry(t)
+	ociRegistry.RequireAuth("user", "secret")
+
+	dockerConfig, err := json.Marshal(map[string]any{
+		"auths": map[string]any{
+			ociRegistry.Host: map[string]string{"username": "user", "password": "secret"},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(os.Getenv("DOCKER_CONFIG"), "config.json"), dockerConfig, 0600))
+
+	artifactRef := ociRegistry.Push(t, "azd/extensions/test.oci", "1.0.0", mockoci.Layer{
+		MediaType: "application/octet-stream",
+		Title:     "azd-ext-oci",
+		Content:   []byte("test data"),
+	})
+
+	artifacts := map[string]ExtensionArtifact{}
+	for platform := range sampleArtifacts {
+		artifacts[platform] = ExtensionArtifact{
+			URL:                artifactRef,
+			Signature:          sign(privateKey, []byte("test data")),
+			AdditionalMetadata: map[string]any{"entryPoint": "azd-ext-oci"},
+		}
+	}
+
+	registry, err := json.Marshal(Registry{
+		Extensions: []*ExtensionMetadata{
+			{
+				Id:          "test.oci",
+				Namespace:   "oci",
+				DisplayName: "OCI Extension",
+				Versions:    []ExtensionVersion{{Version: "1.0.0", Artifacts: artifacts}},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	registryRef := ociRegistry.Push(t, "azd/extensions", "latest", mockoci.Layer{
+		MediaType:   RegistryMediaType,
+		Content:     registry,
+		Annotations: map[string]string{SignatureAnnotation: sign(privateKey, registry)},
+	})
+
+	require.NoError(t, sourceManager.Add(*mockContext.Context, "oci", &SourceConfig{
+		Type:       SourceKindOci,
+		Location:   registryRef,
+		PublicKeys: []string{publicKey},
+	}))
+
+	extensions, err := manager.FindExtensions(*mockContext.Context, &FilterOptions{Id: "test.oci"})
+	require.NoError(t, err)
+	require.Len(t, extensions, 1)
+
+	_, err = manager.Install(*mockContext.Context, extensions[0], "")
+	require.NoError(t, err)
+
+	installed, err := manager.GetInstalled(FilterOptions{Id: "test.oci"})
+	require.NoError(t, err)
+	require.Equal(t, "1.0.0", installed.Version)
+}
+
+func Test_DownloadFromOci(t *testing.T) {
+	_, _, manager := newOciTestManager(t)
+	ociRegistry := mockoci.NewRegistry(t)
+
+	artifactRef := ociRegistry.Push(t, "azd/extensions/test.oci", "1.0.0", mockoci.Layer{
+		MediaType: "application/octet-stream",
+		Title:     "azd-ext-oci.tar.gz",
+		Content:   []byte("test data"),
+	})
+
+	tempFilePath, err := manager.downloadFromOci(t.Context(), artifactRef)
+	require.NoError(t, err)
+	require.Equal(t, "azd-ext-oci.tar.gz", filepath.Base(tempFilePath))
+	require.NotEqual(t, os.TempDir(), filepath.Dir(tempFilePath), "each download has a directory of its own")
+
+	contents, err := os.ReadFile(tempFilePath)
+	require.NoError(t, err)
+	require.Equal(t, "test data", string(contents))
+
+	removeDownloadedArtifact(tempFilePath)
+	require.NoDirExists(t, filepath.Dir(tempFilePath))
+
+	for _, title := range []string{"..", "../azd-ext-oci", "bin/azd-ext-oci", `bin\azd-ext-oci`} {
+		t.Run(title, func(t *testing.T) {
+			artifactRef := ociRegistry.Push(t, "azd/extensions/test.oci", "2.0.0", mockoci.Layer{
+				MediaType: "application/octet-stream",
+				Title:     title,
+				Content:   []byte("test data"),
+			})
+
+			_, err := manager.downloadFromOci(t.Context(), artifactRef)
+			require.ErrorContains(t, err, "invalid file name")
+		})
+	}
+}
@@ -146,6 +146,9 @@ type ExtensionArtifact struct {
 	URL string `json:"url"`
 	// Checksum is the checksum of the artifact
 	Checksum ExtensionChecksum `json:"checksum"`
+	// Signature is the base64 encoded detached ed25519 signature of the artifact, verified against the trusted public
+	// keys of the extension source
+	Signature string `json:"signature,omitempty"`
 	// AdditionalMetadata is a map of additional metadata for the artifact
 	AdditionalMetadata map[string]any `json:"-"`
 }
@@ -199,6 +202,7 @@ func (c *ExtensionArtifact) UnmarshalJSON(data []byte) error {
 	// Remove known fields from the temp map
 	delete(temp, "url")
 	delete(temp, "checksum")
+	delete(temp, "signature")
 
 	// Convert the remaining fields to Extras
 	c.AdditionalMetadata = map[string]any{}
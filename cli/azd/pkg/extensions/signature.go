@@ -0,0 +1,172 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+)
+
+const (
+	// requireSignaturesConfigKey is the user configuration key that sets the signaturePolicy of extension sources.
+	requireSignaturesConfigKey = "extension.requireSignatures"
+
+	// registrySignatureSuffix is appended to the location of a registry to find its detached signature.
+	registrySignatureSuffix = ".sig"
+)
+
+// signaturePolicy is the value of extension.requireSignatures, which decides the extension sources whose content
+// must be signed by one of their trusted public keys. Sources with trusted public keys always require signatures.
+type signaturePolicy string
+
+const (
+	// signaturePolicyThirdParty, the default, requires the artifacts of every source but the default azd source to
+	// be signed, so that no third-party binary runs unverified.
+	signaturePolicyThirdParty signaturePolicy = ""
+	// signaturePolicyAll requires the registries and the artifacts of every source to be signed.
+	signaturePolicyAll signaturePolicy = "true"
+	// signaturePolicyNone only requires signatures from the sources with trusted public keys.
+	signaturePolicyNone signaturePolicy = "false"
+)
+
+// ErrSignatureVerificationFailed is returned when a registry or an extension artifact is not signed by one of the
+// trusted public keys of its source, or is not signed while signatures are required.
+var ErrSignatureVerificationFailed = errors.New("extension signature verification failed")
+
+// signatureVerifier verifies the detached ed25519 signatures of the registry and the artifacts of an extension source.
+type signatureVerifier struct {
+	source string
+	keys   []ed25519.PublicKey
+	// required is set when an unsigned registry must be rejected, which is the case when the source has trusted
+	// public keys or when signatures are required for every source.
+	required bool
+	// artifactsRequired is set when unsigned artifacts must be rejected, which is also the case for the sources other
+	// than the default azd source unless signatures are not required.
+	artifactsRequired bool
+}
+
+// newSignatureVerifier creates the signatureVerifier of the extension source described by sourceConfig.
+func newSignatureVerifier(sourceConfig *SourceConfig, policy signaturePolicy) (*signatureVerifier, error) {
+	required := policy == signaturePolicyAll || len(sourceConfig.PublicKeys) > 0
+	verifier := &signatureVerifier{
+		source:   sourceConfig.Name,
+		required: required,
+		artifactsRequired: required ||
+			(policy == signaturePolicyThirdParty && !strings.EqualFold(sourceConfig.Location, extensionRegistryUrl)),
+	}
+
+	for _, encoded := range sourceConfig.PublicKeys {
+		key, err := ParsePublicKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key for extension source '%s': %w", sourceConfig.Name, err)
+		}
+
+		verifier.keys = append(verifier.keys, key)
+	}
+
+	return verifier, nil
+}
+
+// ParsePublicKey parses a base64 encoded ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("public key is not base64 encoded: %w", err)
+	}
+
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be a %d bytes ed25519 key, got %d bytes", ed25519.PublicKeySize, len(key))
+	}
+
+	return ed25519.PublicKey(key), nil
+}
+
+// loadSignaturePolicy returns the signaturePolicy set by extension.requireSignatures in the user configuration.
+func loadSignaturePolicy(userConfig config.Config) signaturePolicy {
+	value, has := userConfig.GetString(requireSignaturesConfigKey)
+	if !has {
+		return signaturePolicyThirdParty
+	}
+
+	required, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("ignoring invalid value '%s' for %s: %v", value, requireSignaturesConfigKey, err)
+		return signaturePolicyThirdParty
+	}
+
+	if required {
+		return signaturePolicyAll
+	}
+
+	return signaturePolicyNone
+}
+
+// verify verifies the base64 encoded detached signature of the registry data, where subject describes data in errors.
+func (v *signatureVerifier) verify(data []byte, signature string, subject string) error {
+	return v.verifyData(data, signature, subject, v.required)
+}
+
+// verifyData verifies the base64 encoded detached signature of data. When signatures are not required, an empty
+// signature is accepted, and so is any signature when the source has no trusted public keys to verify it with.
+func (v *signatureVerifier) verifyData(data []byte, signature string, subject string, required bool) error {
+	if signature == "" {
+		if !required {
+			log.Printf("%s is not signed, skipping signature verification", subject)
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s is not signed", ErrSignatureVerificationFailed, subject)
+	}
+
+	if len(v.keys) == 0 {
+		if !required {
+			log.Printf(
+				"%s is signed but extension source '%s' has no trusted public keys, skipping signature verification",
+				subject, v.source)
+			return nil
+		}
+
+		return fmt.Errorf(
+			"%w: %s is signed but extension source '%s' has no trusted public keys",
+			ErrSignatureVerificationFailed, subject, v.source)
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("%w: signature of %s is not base64 encoded", ErrSignatureVerificationFailed, subject)
+	}
+
+	for _, key := range v.keys {
+		if ed25519.Verify(key, data, decoded) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"%w: %s is not signed by a trusted public key of extension source '%s'",
+		ErrSignatureVerificationFailed, subject, v.source)
+}
+
+// verifyFile verifies the base64 encoded detached signature of the extension artifact at path.
+func (v *signatureVerifier) verifyFile(path string, signature string, subject string) error {
+	if !v.artifactsRequired && (signature == "" || len(v.keys) == 0) {
+		return v.verifyData(nil, signature, subject, false)
+	}
+
+	//nolint:gosec // G304: path is the downloaded extension artifact
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s for signature verification: %w", subject, err)
+	}
+
+	return v.verifyData(data, signature, subject, v.artifactsRequired)
+}
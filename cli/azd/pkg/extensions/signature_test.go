@@ -0,0 +1,318 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package extensions
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/lazy"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const signedRegistryUrl = "https://example.com/extensions/registry.json"
+
+func newTestSigningKey(t *testing.T) (string, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	return base64.StdEncoding.EncodeToString(publicKey), privateKey
+}
+
+func sign(privateKey ed25519.PrivateKey, data []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data))
+}
+
+func Test_SignatureVerifier(t *testing.T) {
+	trustedKey, trustedPrivateKey := newTestSigningKey(t)
+	_, untrustedPrivateKey := newTestSigningKey(t)
+	data := []byte("test data")
+
+	tests := []struct {
+		name       string
+		publicKeys []string
+		policy     signaturePolicy
+		signature  string
+		wantErr    bool
+	}{
+		{name: "UnsignedWithoutKeys"},
+		{name: "UnsignedWithKeys", publicKeys: []string{trustedKey}, wantErr: true},
+		{name: "UnsignedRequired", policy: signaturePolicyAll, wantErr: true},
+		{name: "Signed", publicKeys: []string{trustedKey}, signature: sign(trustedPrivateKey, data)},
+		{
+			name:       "SignedByUntrustedKey",
+			publicKeys: []string{trustedKey},
+			signature:  sign(untrustedPrivateKey, data),
+			wantErr:    true,
+		},
+		{name: "SignedWithoutKeys", signature: sign(trustedPrivateKey, data)},
+		{
+			name:      "SignedWithoutKeysRequired",
+			policy:    signaturePolicyAll,
+			signature: sign(trustedPrivateKey, data),
+			wantErr:   true,
+		},
+		{name: "InvalidSignature", publicKeys: []string{trustedKey}, signature: "not base64!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := newSignatureVerifier(
+				&SourceConfig{Name: "test", PublicKeys: tt.publicKeys}, tt.policy)
+			require.NoError(t, err)
+
+			err = verifier.verify(data, tt.signature, "test data")
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("InvalidPublicKey", func(t *testing.T) {
+		_, err := newSignatureVerifier(
+			&SourceConfig{Name: "test", PublicKeys: []string{"dGVzdA=="}}, signaturePolicyThirdParty)
+		require.Error(t, err)
+	})
+}
+
+func Test_SignatureVerifier_Artifacts(t *testing.T) {
+	_, privateKey := newTestSigningKey(t)
+
+	artifactPath := filepath.Join(t.TempDir(), "artifact")
+	require.NoError(t, os.WriteFile(artifactPath, []byte("test data"), 0600))
+
+	tests := []struct {
+		name      string
+		location  string
+		policy    signaturePolicy
+		signature string
+		wantErr   bool
+	}{
+		{name: "DefaultSource", location: extensionRegistryUrl},
+		{name: "DefaultSourceRequired", location: extensionRegistryUrl, policy: signaturePolicyAll, wantErr: true},
+		{name: "ThirdPartySource", location: signedRegistryUrl, wantErr: true},
+		{
+			name:      "ThirdPartySourceSignedWithoutKeys",
+			location:  signedRegistryUrl,
+			signature: sign(privateKey, []byte("test data")),
+			wantErr:   true,
+		},
+		{name: "ThirdPartySourceNotRequired", location: signedRegistryUrl, policy: signaturePolicyNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := newSignatureVerifier(&SourceConfig{Name: "test", Location: tt.location}, tt.policy)
+			require.NoError(t, err)
+
+			err = verifier.verifyFile(artifactPath, tt.signature, "test artifact")
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UrlSource_Signature(t *testing.T) {
+	publicKey, privateKey := newTestSigningKey(t)
+	_, untrustedPrivateKey := newTestSigningKey(t)
+
+	registry, err := json.Marshal(testRegistry)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{name: "Signed", signature: sign(privateKey, registry)},
+		{name: "Unsigned", wantErr: true},
+		{name: "SignedByUntrustedKey", signature: sign(untrustedPrivateKey, registry), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			mockSignedRegistry(mockContext, registry, tt.signature)
+
+			sourceManager := NewSourceManager(
+				mockContext.Container, config.NewUserConfigManager(mockContext.ConfigManager), mockContext.HttpClient)
+
+			_, err := sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+				Name:       "signed",
+				Type:       SourceKindUrl,
+				Location:   signedRegistryUrl,
+				PublicKeys: []string{publicKey},
+			})
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_UrlSource_SignedWithoutPublicKeys(t *testing.T) {
+	_, privateKey := newTestSigningKey(t)
+
+	registry, err := json.Marshal(testRegistry)
+	require.NoError(t, err)
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockSignedRegistry(mockContext, registry, sign(privateKey, registry))
+
+	sourceManager := NewSourceManager(
+		mockContext.Container, config.NewUserConfigManager(mockContext.ConfigManager), mockContext.HttpClient)
+
+	// a source that starts signing its registry keeps working for users who have not configured its public keys
+	_, err = sourceManager.CreateSource(*mockContext.Context, &SourceConfig{
+		Name:     "signed",
+		Type:     SourceKindUrl,
+		Location: signedRegistryUrl,
+	})
+	require.NoError(t, err)
+}
+
+func Test_FileSource_RequireSignatures(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	userConfigManager := config.NewUserConfigManager(mockContext.ConfigManager)
+	sourceManager := NewSourceManager(mockContext.Container, userConfigManager, mockContext.HttpClient)
+
+	registryPath := filepath.Join(t.TempDir(), "registry.json")
+	registry, err := json.Marshal(testRegistry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(registryPath, registry, 0600))
+
+	sourceConfig := &SourceConfig{Name: "local", Type: SourceKindFile, Location: registryPath}
+
+	_, err = sourceManager.CreateSource(*mockContext.Context, sourceConfig)
+	require.NoError(t, err)
+
+	userConfig, err := userConfigManager.Load()
+	require.NoError(t, err)
+	require.NoError(t, userConfig.Set(requireSignaturesConfigKey, "true"))
+	require.NoError(t, userConfigManager.Save(userConfig))
+
+	_, err = sourceManager.CreateSource(*mockContext.Context, sourceConfig)
+	require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+
+	publicKey, privateKey := newTestSigningKey(t)
+	sourceConfig.PublicKeys = []string{publicKey}
+	require.NoError(t, os.WriteFile(registryPath+registrySignatureSuffix, []byte(sign(privateKey, registry)), 0600))
+
+	_, err = sourceManager.CreateSource(*mockContext.Context, sourceConfig)
+	require.NoError(t, err)
+}
+
+func Test_Install_VerifiesArtifactSignature(t *testing.T) {
+	publicKey, privateKey := newTestSigningKey(t)
+	_, untrustedPrivateKey := newTestSigningKey(t)
+
+	// the artifacts served by createRegistryMocks
+	artifact, err := json.Marshal([]byte("test data"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{name: "Signed", signature: sign(privateKey, artifact)},
+		{name: "Unsigned", wantErr: true},
+		{name: "SignedByUntrustedKey", signature: sign(untrustedPrivateKey, artifact), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AZD_CONFIG_DIR", t.TempDir())
+
+			artifacts := maps.Clone(sampleArtifacts)
+			for platform, artifact := range artifacts {
+				artifact.Signature = tt.signature
+				artifacts[platform] = artifact
+			}
+
+			registry, err := json.Marshal(Registry{
+				Extensions: []*ExtensionMetadata{
+					{
+						Id:          "test.signed",
+						Namespace:   "signed",
+						DisplayName: "Signed Extension",
+						Versions:    []ExtensionVersion{{Version: "1.0.0", Artifacts: artifacts}},
+					},
+				},
+			})
+			require.NoError(t, err)
+
+			mockContext := mocks.NewMockContext(t.Context())
+			createRegistryMocks(mockContext)
+			mockSignedRegistry(mockContext, registry, sign(privateKey, registry))
+
+			userConfigManager := config.NewUserConfigManager(mockContext.ConfigManager)
+			sourceManager := NewSourceManager(mockContext.Container, userConfigManager, mockContext.HttpClient)
+			require.NoError(t, sourceManager.Add(*mockContext.Context, "signed", &SourceConfig{
+				Type:       SourceKindUrl,
+				Location:   signedRegistryUrl,
+				PublicKeys: []string{publicKey},
+			}))
+
+			lazyRunner := lazy.NewLazy(func() (*Runner, error) {
+				return NewRunner(mockContext.CommandRunner), nil
+			})
+			manager, err := NewManager(userConfigManager, sourceManager, lazyRunner, mockContext.HttpClient)
+			require.NoError(t, err)
+
+			extensions, err := manager.FindExtensions(*mockContext.Context, &FilterOptions{Id: "test.signed"})
+			require.NoError(t, err)
+			require.Len(t, extensions, 1)
+
+			_, err = manager.Install(*mockContext.Context, extensions[0], "")
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrSignatureVerificationFailed)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+// mockSignedRegistry serves registry at signedRegistryUrl, along with its signature when it is not empty.
+func mockSignedRegistry(mockContext *mocks.MockContext, registry []byte, signature string) {
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.String() == signedRegistryUrl
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, json.RawMessage(registry))
+	})
+
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return request.URL.String() == signedRegistryUrl+registrySignatureSuffix
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		if signature == "" {
+			return mocks.CreateEmptyHttpResponse(request, http.StatusNotFound)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Request:    request,
+			Body:       io.NopCloser(strings.NewReader(signature)),
+		}, nil
+	})
+}
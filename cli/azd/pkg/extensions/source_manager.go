@@ -8,12 +8,16 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"slices"
 	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 )
 
 // SourceKind represents the type of extension source.
@@ -26,6 +30,9 @@ const (
 	// portable .zip. It behaves like a file source but anchors relative
 	// artifact paths to the extracted bundle directory.
 	SourceKindBundle SourceKind = "bundle"
+	// SourceKindOci is a registry pushed as an OCI artifact to a container registry, e.g.
+	// oci://contoso.azurecr.io/azd/extensions:latest. Its extension artifacts may be OCI artifacts as well.
+	SourceKindOci SourceKind = "oci"
 
 	baseConfigKey      string = "extension.sources"
 	installedConfigKey string = "extension.installed"
@@ -49,6 +56,9 @@ type SourceConfig struct {
 	Name     string     `json:"name,omitempty"`
 	Type     SourceKind `json:"type,omitempty"`
 	Location string     `json:"location,omitempty"`
+	// PublicKeys are the base64 encoded ed25519 public keys trusted to sign the registry and the extension artifacts
+	// of the source. When set, unsigned or badly signed content from the source is rejected.
+	PublicKeys []string `json:"publicKeys,omitempty"`
 }
 
 // SourceManager manages extension sources.
@@ -56,6 +66,9 @@ type SourceManager struct {
 	serviceLocator ioc.ServiceLocator
 	configManager  config.UserConfigManager
 	transport      policy.Transporter
+
+	ociClientOnce     sync.Once
+	ociClientInstance *oci.Client
 }
 
 func NewSourceManager(
@@ -205,13 +218,20 @@ func (sm *SourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 		return nil, errors.New("extension source location is required")
 	}
 
+	verifier, err := sm.signatureVerifier(config)
+	if err != nil {
+		return nil, err
+	}
+
 	switch config.Type {
 	case SourceKindFile:
-		source, err = newFileSource(config.Name, config.Location)
+		source, err = newFileSource(config.Name, config.Location, verifier)
 	case SourceKindBundle:
-		source, err = newBundleSource(config.Name, config.Location)
+		source, err = newBundleSource(config.Name, config.Location, verifier)
 	case SourceKindUrl:
-		source, err = newUrlSource(ctx, config.Name, config.Location, sm.transport)
+		source, err = newUrlSource(ctx, config.Name, config.Location, sm.transport, verifier)
+	case SourceKindOci:
+		source, err = newOciSource(ctx, config.Name, config.Location, sm.ociClient(), verifier)
 	default:
 		err = sm.serviceLocator.ResolveNamed(string(config.Type), &source)
 		if err != nil {
@@ -226,6 +246,35 @@ func (sm *SourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 	return source, nil
 }
 
+// signatureVerifier returns the verifier of the signatures of the registry and the artifacts of the source described
+// by sourceConfig, honoring the extension.requireSignatures user configuration.
+func (sm *SourceManager) signatureVerifier(sourceConfig *SourceConfig) (*signatureVerifier, error) {
+	userConfig, err := sm.configManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("unable to load user configuration: %w", err)
+	}
+
+	return newSignatureVerifier(sourceConfig, loadSignaturePolicy(userConfig))
+}
+
+// ociClient returns the client pulling the registries and the artifacts of OCI extension sources, which
+// authenticates with the credentials of the Docker configuration.
+func (sm *SourceManager) ociClient() *oci.Client {
+	sm.ociClientOnce.Do(func() {
+		var commandRunner exec.CommandRunner
+		if sm.serviceLocator != nil {
+			if err := sm.serviceLocator.Resolve(&commandRunner); err != nil {
+				log.Printf("docker credential helpers are not available: %v", err)
+				commandRunner = nil
+			}
+		}
+
+		sm.ociClientInstance = oci.NewClient(sm.transport, oci.NewDockerCredentialStore(commandRunner))
+	})
+
+	return sm.ociClientInstance
+}
+
 // addInternal adds a new extension source to the user configuration.
 func (sm *SourceManager) addInternal(source *SourceConfig) error {
 	config, err := sm.configManager.Load()
@@ -5,20 +5,51 @@ package extensions
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 )
 
 // newUrlSource creates a new URL extension source.
-func newUrlSource(ctx context.Context, name string, url string, transport policy.Transporter) (Source, error) {
+func newUrlSource(
+	ctx context.Context,
+	name string,
+	url string,
+	transport policy.Transporter,
+	verifier *signatureVerifier,
+) (Source, error) {
 	pipeline := runtime.NewPipeline("azd-extensions", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
 		Transport: transport,
 	})
 
+	json, err := fetchUrl(ctx, pipeline, url)
+	if err != nil {
+		return nil, err
+	}
+
+	if verifier.required {
+		signature, err := fetchUrl(ctx, pipeline, url+registrySignatureSuffix)
+		if respErr, ok := errors.AsType[*azcore.ResponseError](err); ok && respErr.StatusCode == http.StatusNotFound {
+			signature = nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if err := verifier.verify(json, string(signature), fmt.Sprintf("registry '%s'", url)); err != nil {
+			return nil, err
+		}
+	}
+
+	return newJsonSource(name, string(json))
+}
+
+// fetchUrl returns the body of the response to a GET request to url.
+func fetchUrl(ctx context.Context, pipeline runtime.Pipeline, url string) ([]byte, error) {
 	req, err := runtime.NewRequest(ctx, http.MethodGet, url)
 	if err != nil {
 		return nil, err
@@ -33,10 +64,10 @@ func newUrlSource(ctx context.Context, name string, url string, transport policy
 		return nil, runtime.NewResponseError(resp)
 	}
 
-	json, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed reading response body for template source '%s', %w", url, err)
 	}
 
-	return newJsonSource(name, string(json))
+	return body, nil
 }
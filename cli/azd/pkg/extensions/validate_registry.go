@@ -4,6 +4,8 @@
 package extensions
 
 import (
+	"crypto/ed25519"
+	"encoding/base64"
 	"fmt"
 	"regexp"
 	"slices"
@@ -296,6 +298,11 @@ func validateVersion(result *ExtensionValidationResult, index int, ver *Extensio
 					"(supported: %s)", artifactPrefix, artifact.Checksum.Algorithm,
 					strings.Join(validChecksumAlgorithms, ", ")))
 			}
+
+			if artifact.Signature != "" && !isValidSignature(artifact.Signature) {
+				result.addError(fmt.Sprintf("%s: signature must be a base64 encoded %d bytes ed25519 signature",
+					artifactPrefix, ed25519.SignatureSize))
+			}
 		}
 	}
 }
@@ -323,6 +330,11 @@ func isValidPlatform(platform string) bool {
 	return slices.Contains(ValidPlatforms, platform)
 }
 
+func isValidSignature(signature string) bool {
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	return err == nil && len(decoded) == ed25519.SignatureSize
+}
+
 func isValidChecksumAlgorithm(alg string) bool {
 	return slices.Contains(validChecksumAlgorithms, alg)
 }
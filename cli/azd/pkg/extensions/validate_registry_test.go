@@ -410,6 +410,36 @@ func TestValidateExtension_ChecksumAlgorithmValidation(t *testing.T) {
 		}
 		require.True(t, found, "expected unsupported algorithm error")
 	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		ext := &ExtensionMetadata{
+			Id:          "pub.ext",
+			DisplayName: "Test",
+			Description: "Test",
+			Versions: []ExtensionVersion{
+				{
+					Version: "1.0.0",
+					Artifacts: map[string]ExtensionArtifact{
+						"linux/amd64": {
+							URL:       "https://example.com/ext",
+							Checksum:  ExtensionChecksum{Algorithm: "sha256", Value: "abc123"},
+							Signature: "dGVzdA==",
+						},
+					},
+				},
+			},
+		}
+
+		result := validateExtension(ext, false)
+		require.False(t, result.Valid)
+		found := false
+		for _, issue := range result.Issues {
+			if strings.Contains(issue.Message, "signature must be") {
+				found = true
+			}
+		}
+		require.True(t, found, "expected invalid signature error")
+	})
 }
 
 func TestValidateExtension_RequireArtifactsOrDependencies(t *testing.T) {
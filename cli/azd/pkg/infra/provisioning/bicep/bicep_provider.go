@@ -1068,6 +1068,14 @@ func (p *BicepProvider) Preview(ctx context.Context) (*provisioning.DeployPrevie
 		return nil, err
 	}
 
+	return p.preview(ctx, planned)
+}
+
+// preview runs the what-if operation for the planned deployment.
+func (p *BicepProvider) preview(
+	ctx context.Context,
+	planned *compileBicepResult,
+) (*provisioning.DeployPreviewResult, error) {
 	p.console.ShowSpinner(ctx, "Generating infrastructure preview", input.Step)
 
 	deployment, err := p.generateDeploymentObject(planned)
@@ -1108,7 +1116,13 @@ func (p *BicepProvider) Preview(ctx context.Context) (*provisioning.DeployPrevie
 	}
 
 	var changes []*provisioning.DeploymentPreviewChange
+	billableChanges := []*provisioning.BillableChange{}
 	for _, change := range deployPreviewResult.Properties.Changes {
+		if billable := billableChange(
+			provisioning.ChangeType(*change.ChangeType), change.Before, change.After); billable != nil {
+			billableChanges = append(billableChanges, billable)
+		}
+
 		// Use After state if available (e.g., Create, Modify), otherwise use Before state (e.g., Delete).
 		// ARM returns nil for After when a resource is being deleted and nil for Before when created.
 		var resourceState map[string]any
@@ -1152,6 +1166,7 @@ func (p *BicepProvider) Preview(ctx context.Context) (*provisioning.DeployPrevie
 				Changes: changes,
 			},
 		},
+		BillableChanges: billableChanges,
 	}, nil
 }
 
@@ -2612,6 +2627,16 @@ func (p *BicepProvider) validatePreflight(
 		Fn:     p.checkReservedResourceNames,
 	})
 
+	// Register the checks declared by the policy files of the project, if any.
+	policyChecks, err := loadPolicyChecks(p.projectPath)
+	if err != nil {
+		p.setPreflightOutcome(span, preflightOutcomeError, nil)
+		return false, fmt.Errorf("loading preflight policies: %w", err)
+	}
+	for _, check := range policyChecks {
+		localPreflight.AddCheck(check)
+	}
+
 	valCtx, results, err := localPreflight.validate(ctx, p.console, armTemplate, armParameters)
 	if err != nil {
 		p.setPreflightOutcome(span, preflightOutcomeError, nil)
@@ -2677,10 +2702,16 @@ func (p *BicepProvider) validatePreflight(
 		p.setPreflightOutcome(span, preflightOutcomeSkipped, nil)
 		// No rules actually executed; record an empty slice for telemetry.
 		span.SetAttributes(fields.PreflightRulesKey.StringSlice([]string{}))
+		// Report the policy rules of the project that were not evaluated.
+		results = policyChecksSkipped(localPreflight.checks)
 	} else {
 		ruleIDs := make([]string, len(localPreflight.checks))
 		for i, check := range localPreflight.checks {
 			ruleIDs[i] = check.RuleID
+			// Policy rule IDs are chosen by users, only their hash is recorded.
+			if policyID, has := strings.CutPrefix(check.RuleID, policyRuleIDPrefix); has {
+				ruleIDs[i] = policyRuleIDPrefix + fields.CaseInsensitiveHash(policyID)
+			}
 		}
 		span.SetAttributes(fields.PreflightRulesKey.StringSlice(ruleIDs))
 	}
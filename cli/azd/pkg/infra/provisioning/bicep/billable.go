@@ -0,0 +1,66 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// billableChange returns the billable change of a what-if change, from the resource states before and after the
+// change. It returns nil for the changes to the resources not managed by the template.
+//
+// Resources are priced from the what-if states rather than from the resources of the compiled template: the SKUs,
+// capacities and locations of the compiled template are mostly expressions over the parameters, such as
+// [parameters('sku')], which only the deployment engine evaluates, and the template has no state of the deployed
+// resources to compute the delta from. The what-if states have the shape of the resources of the template, with
+// their expressions evaluated.
+func billableChange(changeType provisioning.ChangeType, before any, after any) *provisioning.BillableChange {
+	switch changeType {
+	case provisioning.ChangeTypeIgnore, provisioning.ChangeTypeUnsupported:
+		return nil
+	}
+
+	change := &provisioning.BillableChange{
+		Before: billableResource(before),
+		After:  billableResource(after),
+	}
+	if change.Before == nil && change.After == nil {
+		return nil
+	}
+
+	return change
+}
+
+// billableResource reads the properties that determine the price of a resource from its what-if state, which has the
+// shape of a resource of an ARM template with its expressions evaluated.
+func billableResource(state any) *provisioning.BillableResource {
+	if state == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil
+	}
+
+	var resource armTemplateResource
+	if err := json.Unmarshal(data, &resource); err != nil || resource.Type == "" {
+		return nil
+	}
+
+	billable := &provisioning.BillableResource{
+		Type:     resource.Type,
+		Name:     resource.Name,
+		Location: resource.Location,
+	}
+	if sku, ok := resource.SKU.Value(); ok {
+		billable.Sku = sku.Name
+		billable.Tier = sku.Tier
+		billable.Capacity = sku.Capacity
+	}
+
+	return billable
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBillableChange(t *testing.T) {
+	plan := func(skuName string) map[string]any {
+		return map[string]any{
+			"type":     "Microsoft.Web/serverfarms",
+			"name":     "plan-api",
+			"location": "eastus",
+			"sku":      map[string]any{"name": skuName, "tier": "PremiumV3", "capacity": 2},
+			"properties": map[string]any{
+				"reserved": true,
+			},
+		}
+	}
+
+	t.Run("Modify", func(t *testing.T) {
+		change := billableChange(provisioning.ChangeTypeModify, plan("B1"), plan("P1v3"))
+		require.Equal(t, &provisioning.BillableChange{
+			Before: &provisioning.BillableResource{
+				Type: "Microsoft.Web/serverfarms", Name: "plan-api", Location: "eastus",
+				Sku: "B1", Tier: "PremiumV3", Capacity: to.Ptr(2),
+			},
+			After: &provisioning.BillableResource{
+				Type: "Microsoft.Web/serverfarms", Name: "plan-api", Location: "eastus",
+				Sku: "P1v3", Tier: "PremiumV3", Capacity: to.Ptr(2),
+			},
+		}, change)
+	})
+
+	t.Run("CreateWithoutSku", func(t *testing.T) {
+		change := billableChange(provisioning.ChangeTypeCreate, nil, map[string]any{
+			"type":     "Microsoft.KeyVault/vaults",
+			"name":     "kv",
+			"location": "eastus",
+		})
+		require.Nil(t, change.Before)
+		require.Equal(t, &provisioning.BillableResource{
+			Type: "Microsoft.KeyVault/vaults", Name: "kv", Location: "eastus",
+		}, change.After)
+	})
+
+	t.Run("Ignore", func(t *testing.T) {
+		require.Nil(t, billableChange(provisioning.ChangeTypeIgnore, plan("B1"), nil))
+	})
+
+	t.Run("NoState", func(t *testing.T) {
+		require.Nil(t, billableChange(provisioning.ChangeTypeUnsupported, nil, nil))
+		require.Nil(t, billableChange(provisioning.ChangeTypeDelete, map[string]any{"name": "untyped"}, nil))
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+// Drift runs the what-if operation for the template and parameters of the last successful deployment. As long as
+// they did not change, every change reported by what-if was made outside of provisioning.
+func (p *BicepProvider) Drift(ctx context.Context) (*provisioning.DriftResult, error) {
+	planned, err := p.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scope, err := p.scopeForTemplate(planned.Template)
+	if err != nil {
+		return nil, fmt.Errorf("computing deployment scope: %w", err)
+	}
+
+	p.console.ShowSpinner(ctx, "Comparing deployment state", input.Step)
+	lastDeployment, err := p.latestDeploymentResult(ctx, scope)
+	if errors.Is(err, infra.ErrDeploymentsNotFound) ||
+		(err == nil && lastDeployment.ProvisioningState != azapi.DeploymentProvisioningStateSucceeded) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err:        fmt.Errorf("no successful deployment found for environment '%s'", p.env.Name()),
+			Suggestion: "Run 'azd provision' to deploy the infrastructure before checking it for drift.",
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("finding the last deployment: %w", err)
+	}
+
+	templateHash, err := p.deploymentManager.CalculateTemplateHash(
+		ctx, p.env.GetSubscriptionId(), planned.RawArmTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("can't get hash from current template: %w", err)
+	}
+
+	paramsHash, err := parametersHash(planned.Template.Parameters, planned.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("hashing parameters: %w", err)
+	}
+
+	if !prevDeploymentEqualToCurrent(lastDeployment, templateHash, paramsHash) {
+		return nil, &internal.ErrorWithSuggestion{
+			Err: fmt.Errorf("%w: deployment '%s' used a different template or different parameters",
+				provisioning.ErrDriftBaselineChanged, lastDeployment.Name),
+			Suggestion: "Run 'azd provision --preview' to review the changes, and 'azd provision' to apply them " +
+				"before checking for drift.",
+		}
+	}
+
+	previewResult, err := p.preview(ctx, planned)
+	if err != nil {
+		return nil, err
+	}
+
+	driftResult := &provisioning.DriftResult{
+		Changes: []*provisioning.DeploymentPreviewChange{},
+	}
+	for _, change := range previewResult.Preview.Properties.Changes {
+		if isDriftChange(change.ChangeType) {
+			driftResult.Changes = append(driftResult.Changes, change)
+		}
+	}
+
+	return driftResult, nil
+}
+
+// isDriftChange reports whether a what-if change reveals drift. Deploy changes are left out, as what-if reports them
+// for the resources whose changes it can't predict.
+func isDriftChange(changeType provisioning.ChangeType) bool {
+	switch changeType {
+	case provisioning.ChangeTypeCreate, provisioning.ChangeTypeDelete, provisioning.ChangeTypeModify:
+		return true
+	default:
+		return false
+	}
+}
@@ -469,7 +469,7 @@ func (l *localArmPreflight) validate(
 	// The snapshot contains the fully resolved deployment graph with expressions evaluated,
 	// conditions applied, and copy loops expanded.
 	// If the snapshot fails (e.g., older Bicep binary without snapshot support), skip local
-	// preflight rather than blocking the deployment.
+	// preflight rather than blocking the deployment. The caller reports the policy rules that were not evaluated.
 	data, err := l.bicepCli.Snapshot(ctx, bicepParamFile, snapshotOpts)
 	if err != nil {
 		log.Printf("local preflight: skipping checks, bicep snapshot unavailable: %v", err)
@@ -0,0 +1,367 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/braydonk/yaml"
+)
+
+// policiesDirectory is the directory of the project, relative to its root, holding the policy files evaluated by
+// local preflight. Each *.yaml (or *.yml) file declares a list of rules.
+var policiesDirectory = filepath.Join(".azure", "policies")
+
+const (
+	// policyRuleIDPrefix prefixes the rule id of the preflight checks compiled from policy rules.
+	policyRuleIDPrefix = "local_policy."
+	// policyViolationDiagnosticID is the diagnostic id of the findings of policy rules. The ids of the rules are not used,
+	// as they are chosen by users.
+	policyViolationDiagnosticID = "local_policy_violation"
+	// policySkippedDiagnosticID is the diagnostic id of the warning reporting that the policy rules were not evaluated.
+	policySkippedDiagnosticID = "local_policy_skipped"
+)
+
+// policyFile is the content of a policy file, e.g.:
+//
+//	rules:
+//	  - id: storage-no-public-blob
+//	    message: Storage accounts must disable public blob access.
+//	    severity: error
+//	    resourceType: Microsoft.Storage/storageAccounts
+//	    require:
+//	      - path: properties.allowBlobPublicAccess
+//	        equals: false
+type policyFile struct {
+	Rules []policyRule `yaml:"rules"`
+}
+
+// policyRule is a rule that the resources of a deployment must satisfy.
+type policyRule struct {
+	// Id uniquely identifies the rule across all the policy files.
+	Id string `yaml:"id"`
+	// Message describes the rule, and is reported for each resource that violates it.
+	Message string `yaml:"message"`
+	// Severity is either "error", which blocks the deployment, or "warning" (the default).
+	Severity string `yaml:"severity,omitempty"`
+	// Suggestion is an optional recommendation for resolving a violation.
+	Suggestion string `yaml:"suggestion,omitempty"`
+	// ResourceType restricts the rule to the resources of this type. The rule applies to all resources when empty.
+	ResourceType string `yaml:"resourceType,omitempty"`
+	// Require lists the conditions that a resource must all satisfy.
+	Require []policyCondition `yaml:"require"`
+}
+
+// policyCondition is a condition on a value of a resource, at a dotted path of its ARM representation, such as
+// properties.minimumTlsVersion or tags.costCenter. Exactly one operator must be set.
+type policyCondition struct {
+	Path      string `yaml:"path"`
+	Exists    *bool  `yaml:"exists,omitempty"`
+	Equals    any    `yaml:"equals,omitempty"`
+	NotEquals any    `yaml:"notEquals,omitempty"`
+	In        []any  `yaml:"in,omitempty"`
+	Pattern   string `yaml:"pattern,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// loadPolicyChecks compiles the rules of the policy files of the project into preflight checks. It returns no checks
+// when the project has no policy files.
+func loadPolicyChecks(projectPath string) ([]PreflightCheck, error) {
+	var policyFiles []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(projectPath, policiesDirectory, pattern))
+		if err != nil {
+			return nil, err
+		}
+		policyFiles = append(policyFiles, matches...)
+	}
+	slices.Sort(policyFiles)
+
+	var checks []PreflightCheck
+	seenIds := map[string]string{}
+	for _, policyFilePath := range policyFiles {
+		//nolint:gosec // G304: policy files are read from the project directory
+		content, err := os.ReadFile(policyFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("reading policy file: %w", err)
+		}
+
+		var policy policyFile
+		if err := yaml.Unmarshal(content, &policy); err != nil {
+			return nil, fmt.Errorf("parsing policy file %s: %w", policyFilePath, err)
+		}
+
+		for i := range policy.Rules {
+			rule := &policy.Rules[i]
+			if err := rule.compile(); err != nil {
+				return nil, fmt.Errorf("policy file %s: rule %d: %w", policyFilePath, i+1, err)
+			}
+
+			if otherFile, has := seenIds[rule.Id]; has {
+				return nil, fmt.Errorf(
+					"policy file %s: rule '%s' is already declared in %s", policyFilePath, rule.Id, otherFile)
+			}
+			seenIds[rule.Id] = policyFilePath
+
+			checks = append(checks, PreflightCheck{
+				RuleID: policyRuleIDPrefix + rule.Id,
+				Fn: func(_ context.Context, valCtx *validationContext) ([]PreflightCheckResult, error) {
+					return rule.evaluate(valCtx.SnapshotResources)
+				},
+			})
+		}
+	}
+
+	return checks, nil
+}
+
+// policyChecksSkipped returns the warning reporting that the policy rules registered among checks were not evaluated,
+// as happens when the bicep snapshot is unavailable, or nil when no policy rule is registered. Policy rules may gate
+// deployments, so they are never skipped silently.
+func policyChecksSkipped(checks []PreflightCheck) []PreflightCheckResult {
+	var ruleIds []string
+	for _, check := range checks {
+		if ruleId, has := strings.CutPrefix(check.RuleID, policyRuleIDPrefix); has {
+			ruleIds = append(ruleIds, ruleId)
+		}
+	}
+
+	if len(ruleIds) == 0 {
+		return nil
+	}
+
+	return []PreflightCheckResult{
+		{
+			Severity:     PreflightCheckWarning,
+			DiagnosticID: policySkippedDiagnosticID,
+			Message: fmt.Sprintf(
+				"The policy rules of %s were not evaluated because the Bicep CLI could not produce a snapshot of "+
+					"the deployment: %s.", policiesDirectory, strings.Join(ruleIds, ", ")),
+			Suggestion: "Upgrade the Bicep CLI to a version supporting 'bicep snapshot', and run with --debug to see " +
+				"why the snapshot failed.",
+		},
+	}
+}
+
+// compile validates the rule and prepares its conditions for evaluation.
+func (r *policyRule) compile() error {
+	if r.Id == "" {
+		return errors.New("'id' is required")
+	}
+
+	if r.Message == "" {
+		return fmt.Errorf("rule '%s': 'message' is required", r.Id)
+	}
+
+	switch r.Severity {
+	case "", "warning", "error":
+	default:
+		return fmt.Errorf("rule '%s': invalid severity '%s', expected 'error' or 'warning'", r.Id, r.Severity)
+	}
+
+	if len(r.Require) == 0 {
+		return fmt.Errorf("rule '%s': 'require' must list at least one condition", r.Id)
+	}
+
+	for i := range r.Require {
+		if err := r.Require[i].compile(); err != nil {
+			return fmt.Errorf("rule '%s': condition %d: %w", r.Id, i+1, err)
+		}
+	}
+
+	return nil
+}
+
+// evaluate returns a finding for each resource of the type of the rule that does not satisfy all of its conditions.
+func (r *policyRule) evaluate(resources []armTemplateResource) ([]PreflightCheckResult, error) {
+	severity := PreflightCheckWarning
+	if r.Severity == "error" {
+		severity = PreflightCheckError
+	}
+
+	var results []PreflightCheckResult
+	for _, resource := range resources {
+		if r.ResourceType != "" && !strings.EqualFold(resource.Type, r.ResourceType) {
+			continue
+		}
+
+		values, err := policyValues(resource)
+		if err != nil {
+			return nil, fmt.Errorf("reading resource '%s': %w", resource.Name, err)
+		}
+
+		var violations []string
+		for _, condition := range r.Require {
+			if !condition.holds(values) {
+				violations = append(violations, condition.String())
+			}
+		}
+
+		if len(violations) > 0 {
+			results = append(results, PreflightCheckResult{
+				Severity:     severity,
+				DiagnosticID: policyViolationDiagnosticID,
+				Message: fmt.Sprintf("Policy '%s': %s\nResource '%s' (%s): %s.",
+					r.Id, r.Message, resource.Name, resource.Type, strings.Join(violations, ", ")),
+				Suggestion: r.Suggestion,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// policyValues returns the ARM representation of the resource as generic JSON values.
+func policyValues(resource armTemplateResource) (map[string]any, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// compile validates the condition, and normalizes its values to the types of JSON values.
+func (c *policyCondition) compile() error {
+	if c.Path == "" {
+		return errors.New("'path' is required")
+	}
+
+	operators := 0
+	for _, set := range []bool{c.Exists != nil, c.Equals != nil, c.NotEquals != nil, c.In != nil, c.Pattern != ""} {
+		if set {
+			operators++
+		}
+	}
+	if operators != 1 {
+		return fmt.Errorf(
+			"'%s': exactly one of 'exists', 'equals', 'notEquals', 'in' or 'pattern' must be set", c.Path)
+	}
+
+	var err error
+	if c.Equals, err = jsonValue(c.Equals); err != nil {
+		return err
+	}
+	if c.NotEquals, err = jsonValue(c.NotEquals); err != nil {
+		return err
+	}
+	for i := range c.In {
+		if c.In[i], err = jsonValue(c.In[i]); err != nil {
+			return err
+		}
+	}
+
+	if c.Pattern != "" {
+		if c.pattern, err = regexp.Compile(c.Pattern); err != nil {
+			return fmt.Errorf("'%s': invalid pattern: %w", c.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// jsonValue converts a value decoded from YAML to the equivalent value decoded from JSON, so that both compare equal.
+func jsonValue(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %v: %w", value, err)
+	}
+
+	var result any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// holds reports whether the resource values satisfy the condition. Only exists and notEquals hold for missing
+// values.
+func (c *policyCondition) holds(values map[string]any) bool {
+	value, found := lookupPath(values, c.Path)
+
+	switch {
+	case c.Exists != nil:
+		return found == *c.Exists
+	case c.NotEquals != nil:
+		return !found || !reflect.DeepEqual(value, c.NotEquals)
+	case !found:
+		return false
+	case c.Equals != nil:
+		return reflect.DeepEqual(value, c.Equals)
+	case c.In != nil:
+		return slices.ContainsFunc(c.In, func(allowed any) bool { return reflect.DeepEqual(value, allowed) })
+	case c.pattern != nil:
+		s, ok := value.(string)
+		return ok && c.pattern.MatchString(s)
+	default:
+		return true
+	}
+}
+
+// String describes the condition as it is expected to hold.
+func (c *policyCondition) String() string {
+	switch {
+	case c.Exists != nil && *c.Exists:
+		return fmt.Sprintf("%s must be set", c.Path)
+	case c.Exists != nil:
+		return fmt.Sprintf("%s must not be set", c.Path)
+	case c.NotEquals != nil:
+		return fmt.Sprintf("%s must not be %s", c.Path, policyValueText(c.NotEquals))
+	case c.Equals != nil:
+		return fmt.Sprintf("%s must be %s", c.Path, policyValueText(c.Equals))
+	case c.In != nil:
+		allowed := make([]string, len(c.In))
+		for i, value := range c.In {
+			allowed[i] = policyValueText(value)
+		}
+		return fmt.Sprintf("%s must be one of %s", c.Path, strings.Join(allowed, ", "))
+	default:
+		return fmt.Sprintf("%s must match '%s'", c.Path, c.Pattern)
+	}
+}
+
+func policyValueText(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// lookupPath returns the value at the dotted path, e.g. properties.networkAcls.defaultAction.
+func lookupPath(values map[string]any, path string) (any, bool) {
+	var current any = values
+	for segment := range strings.SplitSeq(path, ".") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		if current, ok = object[segment]; !ok {
+			return nil, false
+		}
+	}
+
+	return current, current != nil
+}
@@ -0,0 +1,264 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package bicep
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicyFile(t *testing.T, projectPath string, name string, content string) {
+	t.Helper()
+
+	dir := filepath.Join(projectPath, policiesDirectory)
+	require.NoError(t, os.MkdirAll(dir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+}
+
+func TestLoadPolicyChecks(t *testing.T) {
+	t.Run("NoPolicies", func(t *testing.T) {
+		checks, err := loadPolicyChecks(t.TempDir())
+		require.NoError(t, err)
+		require.Empty(t, checks)
+	})
+
+	t.Run("Rules", func(t *testing.T) {
+		projectPath := t.TempDir()
+		writePolicyFile(t, projectPath, "storage.yaml", `
+rules:
+  - id: storage-no-public-blob
+    message: Storage accounts must disable public blob access.
+    severity: error
+    resourceType: Microsoft.Storage/storageAccounts
+    require:
+      - path: properties.allowBlobPublicAccess
+        equals: false
+`)
+		writePolicyFile(t, projectPath, "tags.yml", `
+rules:
+  - id: cost-center
+    message: Resources must be tagged with a cost center.
+    require:
+      - path: tags.costCenter
+        exists: true
+`)
+
+		checks, err := loadPolicyChecks(projectPath)
+		require.NoError(t, err)
+		require.Len(t, checks, 2)
+		require.Equal(t, "local_policy.storage-no-public-blob", checks[0].RuleID)
+		require.Equal(t, "local_policy.cost-center", checks[1].RuleID)
+	})
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "InvalidYaml",
+			content: "rules: [",
+			wantErr: "parsing policy file",
+		},
+		{
+			name: "MissingId",
+			content: `
+rules:
+  - message: m
+    require:
+      - path: location
+        exists: true
+`,
+			wantErr: "'id' is required",
+		},
+		{
+			name: "InvalidSeverity",
+			content: `
+rules:
+  - id: r
+    message: m
+    severity: critical
+    require:
+      - path: location
+        exists: true
+`,
+			wantErr: "invalid severity 'critical'",
+		},
+		{
+			name: "NoConditions",
+			content: `
+rules:
+  - id: r
+    message: m
+`,
+			wantErr: "'require' must list at least one condition",
+		},
+		{
+			name: "SeveralOperators",
+			content: `
+rules:
+  - id: r
+    message: m
+    require:
+      - path: location
+        exists: true
+        equals: eastus
+`,
+			wantErr: "exactly one of",
+		},
+		{
+			name: "InvalidPattern",
+			content: `
+rules:
+  - id: r
+    message: m
+    require:
+      - path: name
+        pattern: "("
+`,
+			wantErr: "invalid pattern",
+		},
+		{
+			name: "DuplicateId",
+			content: `
+rules:
+  - id: r
+    message: m
+    require:
+      - path: location
+        exists: true
+  - id: r
+    message: m
+    require:
+      - path: location
+        exists: true
+`,
+			wantErr: "rule 'r' is already declared",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectPath := t.TempDir()
+			writePolicyFile(t, projectPath, "policy.yaml", tt.content)
+
+			_, err := loadPolicyChecks(projectPath)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPolicyChecks(t *testing.T) {
+	projectPath := t.TempDir()
+	writePolicyFile(t, projectPath, "policy.yaml", `
+rules:
+  - id: storage-hardening
+    message: Storage accounts must be hardened.
+    severity: error
+    suggestion: Set allowBlobPublicAccess to false and minimumTlsVersion to TLS1_2.
+    resourceType: microsoft.storage/storageaccounts
+    require:
+      - path: properties.allowBlobPublicAccess
+        equals: false
+      - path: properties.minimumTlsVersion
+        in: [TLS1_2, TLS1_3]
+  - id: naming
+    message: Resource names must be lowercase.
+    require:
+      - path: name
+        pattern: ^[a-z0-9-]+$
+  - id: no-premium
+    message: Premium SKUs are not allowed.
+    require:
+      - path: sku.tier
+        notEquals: Premium
+`)
+
+	checks, err := loadPolicyChecks(projectPath)
+	require.NoError(t, err)
+	require.Len(t, checks, 3)
+
+	valCtx := &validationContext{
+		SnapshotResources: []armTemplateResource{
+			{
+				Type:       "Microsoft.Storage/storageAccounts",
+				Name:       "stcompliant",
+				SKU:        armField[armTemplateSKU]{raw: json.RawMessage(`{"name":"Standard_LRS","tier":"Standard"}`)},
+				Properties: json.RawMessage(`{"allowBlobPublicAccess":false,"minimumTlsVersion":"TLS1_2"}`),
+			},
+			{
+				Type:       "Microsoft.Storage/storageAccounts",
+				Name:       "stpublic",
+				Properties: json.RawMessage(`{"allowBlobPublicAccess":true}`),
+			},
+			{
+				Type: "Microsoft.Web/sites",
+				Name: "WebApp",
+				SKU:  armField[armTemplateSKU]{raw: json.RawMessage(`{"name":"P1v3","tier":"Premium"}`)},
+			},
+		},
+	}
+
+	t.Run("ResourceType", func(t *testing.T) {
+		results, err := checks[0].Fn(t.Context(), valCtx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, PreflightCheckError, results[0].Severity)
+		require.Equal(t, policyViolationDiagnosticID, results[0].DiagnosticID)
+		require.Equal(t,
+			"Policy 'storage-hardening': Storage accounts must be hardened.\n"+
+				"Resource 'stpublic' (Microsoft.Storage/storageAccounts): "+
+				"properties.allowBlobPublicAccess must be false, "+
+				"properties.minimumTlsVersion must be one of \"TLS1_2\", \"TLS1_3\".",
+			results[0].Message)
+		require.Equal(t,
+			"Set allowBlobPublicAccess to false and minimumTlsVersion to TLS1_2.", results[0].Suggestion)
+	})
+
+	t.Run("Pattern", func(t *testing.T) {
+		results, err := checks[1].Fn(t.Context(), valCtx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Equal(t, PreflightCheckWarning, results[0].Severity)
+		require.Contains(t, results[0].Message, "Resource 'WebApp' (Microsoft.Web/sites)")
+		require.Contains(t, results[0].Message, "name must match '^[a-z0-9-]+$'")
+	})
+
+	t.Run("NotEquals", func(t *testing.T) {
+		results, err := checks[2].Fn(t.Context(), valCtx)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.Contains(t, results[0].Message, "sku.tier must not be \"Premium\"")
+	})
+}
+
+func TestPolicyChecksSkipped(t *testing.T) {
+	builtInCheck := PreflightCheck{RuleID: "role_assignment_permissions"}
+	require.Nil(t, policyChecksSkipped([]PreflightCheck{builtInCheck}))
+
+	projectPath := t.TempDir()
+	writePolicyFile(t, projectPath, "policy.yaml", `
+rules:
+  - id: storage-no-public-blob
+    message: Storage accounts must disable public blob access.
+    severity: error
+    resourceType: Microsoft.Storage/storageAccounts
+    require:
+      - path: properties.allowBlobPublicAccess
+        equals: false
+`)
+
+	checks, err := loadPolicyChecks(projectPath)
+	require.NoError(t, err)
+
+	results := policyChecksSkipped(append([]PreflightCheck{builtInCheck}, checks...))
+	require.Len(t, results, 1)
+	require.Equal(t, PreflightCheckWarning, results[0].Severity)
+	require.Equal(t, policySkippedDiagnosticID, results[0].DiagnosticID)
+	require.Contains(t, results[0].Message, "storage-no-public-blob")
+}
@@ -0,0 +1,24 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+// BillableResource describes a resource by the properties that determine its price.
+type BillableResource struct {
+	// Type is the Azure resource type, e.g. Microsoft.Web/serverfarms.
+	Type     string
+	Name     string
+	Location string
+	// Sku is the name of the SKU of the resource, e.g. P1v3.
+	Sku  string
+	Tier string
+	// Capacity is the number of units of the SKU, e.g. the instances of an App Service plan. Nil when not set.
+	Capacity *int
+}
+
+// BillableChange is the change of a billable resource. Before is nil for the resources to be created, and After is nil
+// for the resources to be deleted.
+type BillableChange struct {
+	Before *BillableResource
+	After  *BillableResource
+}
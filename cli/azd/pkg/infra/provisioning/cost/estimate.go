@@ -0,0 +1,111 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"math"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// Estimate is the estimated monthly cost of the resources of a deployment, before and after its changes. It only
+// includes the fixed charges of the resources: usage-based charges, such as storage or requests, are left out.
+type Estimate struct {
+	// Currency is the ISO code of the currency of the costs, e.g. USD.
+	Currency string
+	// MonthlyCost is the estimated monthly cost of the resources after the changes.
+	MonthlyCost float64
+	// PreviousMonthlyCost is the estimated monthly cost of the resources before the changes.
+	PreviousMonthlyCost float64
+	// Resources are the priced resources.
+	Resources []ResourceEstimate
+	// UnpricedResources is the number of resources without a known price, which are left out of the estimate.
+	UnpricedResources int
+}
+
+// Delta is the change of the estimated monthly cost.
+func (e *Estimate) Delta() float64 {
+	return e.MonthlyCost - e.PreviousMonthlyCost
+}
+
+// ResourceEstimate is the estimated monthly cost of a resource, before and after its change.
+type ResourceEstimate struct {
+	Type     string
+	Name     string
+	Sku      string
+	Location string
+	// MonthlyCost is the estimated monthly cost of the resource after the change, 0 when it is deleted.
+	MonthlyCost float64
+	// PreviousMonthlyCost is the estimated monthly cost of the resource before the change, 0 when it is created.
+	PreviousMonthlyCost float64
+}
+
+// EstimateCost prices the resources of the changes before and after the changes.
+func EstimateCost(sheet PriceSheet, changes []*provisioning.BillableChange) *Estimate {
+	estimate := &Estimate{
+		Currency:  sheet.Currency(),
+		Resources: []ResourceEstimate{},
+	}
+
+	for _, change := range changes {
+		resource := change.After
+		if resource == nil {
+			resource = change.Before
+		}
+		if resource == nil {
+			continue
+		}
+
+		resourceEstimate := ResourceEstimate{
+			Type:     resource.Type,
+			Name:     resource.Name,
+			Sku:      resource.Sku,
+			Location: resource.Location,
+		}
+
+		priced := true
+		if change.Before != nil {
+			resourceEstimate.PreviousMonthlyCost, priced = sheet.MonthlyCost(*change.Before)
+		}
+		if priced && change.After != nil {
+			resourceEstimate.MonthlyCost, priced = sheet.MonthlyCost(*change.After)
+		}
+
+		if !priced {
+			estimate.UnpricedResources++
+			continue
+		}
+
+		resourceEstimate.MonthlyCost = roundCents(resourceEstimate.MonthlyCost)
+		resourceEstimate.PreviousMonthlyCost = roundCents(resourceEstimate.PreviousMonthlyCost)
+
+		estimate.MonthlyCost += resourceEstimate.MonthlyCost
+		estimate.PreviousMonthlyCost += resourceEstimate.PreviousMonthlyCost
+		estimate.Resources = append(estimate.Resources, resourceEstimate)
+	}
+
+	estimate.MonthlyCost = roundCents(estimate.MonthlyCost)
+	estimate.PreviousMonthlyCost = roundCents(estimate.PreviousMonthlyCost)
+
+	return estimate
+}
+
+func roundCents(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// BillableResources returns the resources of the changes, before and after the changes.
+func BillableResources(changes []*provisioning.BillableChange) []provisioning.BillableResource {
+	var resources []provisioning.BillableResource
+	for _, change := range changes {
+		if change.Before != nil {
+			resources = append(resources, *change.Before)
+		}
+		if change.After != nil {
+			resources = append(resources, *change.After)
+		}
+	}
+
+	return resources
+}
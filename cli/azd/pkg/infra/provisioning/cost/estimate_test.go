@@ -0,0 +1,57 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateCost(t *testing.T) {
+	sheet := &StaticPriceSheet{
+		CurrencyCode: "USD",
+		Prices: []Price{
+			{ResourceType: "Microsoft.Web/serverfarms", Sku: "B1", MonthlyPrice: 13.14, PerCapacity: true},
+			{ResourceType: "Microsoft.Web/serverfarms", Sku: "P1v3", MonthlyPrice: 113.15, PerCapacity: true},
+			{ResourceType: "Microsoft.ContainerRegistry/registries", Sku: "Basic", MonthlyPrice: 5.07},
+			{ResourceType: "Microsoft.KeyVault/vaults", Sku: "*", MonthlyPrice: 0},
+		},
+	}
+
+	plan := func(sku string, capacity int) *provisioning.BillableResource {
+		return &provisioning.BillableResource{
+			Type: "Microsoft.Web/serverfarms", Name: "plan", Location: "eastus", Sku: sku, Capacity: to.Ptr(capacity)}
+	}
+
+	estimate := EstimateCost(sheet, []*provisioning.BillableChange{
+		// Scaled up and out.
+		{Before: plan("B1", 1), After: plan("P1v3", 2)},
+		// Created.
+		{After: &provisioning.BillableResource{
+			Type: "Microsoft.ContainerRegistry/registries", Name: "cr", Location: "eastus", Sku: "Basic"}},
+		// Deleted.
+		{Before: &provisioning.BillableResource{Type: "Microsoft.KeyVault/vaults", Name: "kv", Sku: "standard"}},
+		// Unpriced.
+		{
+			Before: &provisioning.BillableResource{Type: "Microsoft.Network/virtualNetworks", Name: "vnet"},
+			After:  &provisioning.BillableResource{Type: "Microsoft.Network/virtualNetworks", Name: "vnet"},
+		},
+	})
+
+	require.Equal(t, "USD", estimate.Currency)
+	require.Equal(t, 231.37, estimate.MonthlyCost)
+	require.Equal(t, 13.14, estimate.PreviousMonthlyCost)
+	require.InDelta(t, 218.23, estimate.Delta(), 0.001)
+	require.Equal(t, 1, estimate.UnpricedResources)
+	require.Equal(t, []ResourceEstimate{
+		{Type: "Microsoft.Web/serverfarms", Name: "plan", Sku: "P1v3", Location: "eastus",
+			MonthlyCost: 226.3, PreviousMonthlyCost: 13.14},
+		{Type: "Microsoft.ContainerRegistry/registries", Name: "cr", Sku: "Basic", Location: "eastus",
+			MonthlyCost: 5.07},
+		{Type: "Microsoft.KeyVault/vaults", Name: "kv", Sku: "standard"},
+	}, estimate.Resources)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/resources"
+)
+
+// PriceSheet prices resources by their SKU, capacity and location.
+type PriceSheet interface {
+	// Currency is the ISO code of the currency of the prices, e.g. USD.
+	Currency() string
+	// MonthlyCost returns the monthly cost of the resource, or false when its price is unknown.
+	MonthlyCost(resource provisioning.BillableResource) (float64, bool)
+}
+
+// Price is the monthly price of the resources of a type and SKU.
+type Price struct {
+	// ResourceType is the Azure resource type, e.g. Microsoft.Web/serverfarms.
+	ResourceType string `json:"resourceType"`
+	// Sku is the name of the SKU, or * for all the SKUs of the resource type.
+	Sku string `json:"sku"`
+	// Capacity restricts the price to the resources with this capacity, for the resources priced by the size of their
+	// SKU, e.g. C1 for Azure Cache for Redis.
+	Capacity *int `json:"capacity,omitempty"`
+	// Location is the Azure location of the price, in its short form. Empty for the prices of all locations.
+	Location string `json:"location,omitempty"`
+	// MonthlyPrice is the price of one month of the resource.
+	MonthlyPrice float64 `json:"monthlyPrice"`
+	// PerCapacity is true when the price is for each unit of the capacity of the resource, e.g. the instances of an
+	// App Service plan.
+	PerCapacity bool `json:"perCapacity,omitempty"`
+	// Meter identifies the price in the Azure Retail Prices API, to refresh it for the location of the resources.
+	Meter *Meter `json:"meter,omitempty"`
+	// RetrievedOn is when the price was retrieved from the Azure Retail Prices API.
+	RetrievedOn time.Time `json:"retrievedOn,omitzero"`
+}
+
+// Meter identifies the prices of a resource in the Azure Retail Prices API.
+type Meter struct {
+	ServiceName string `json:"serviceName"`
+	ProductName string `json:"productName,omitempty"`
+	SkuName     string `json:"skuName"`
+	MeterName   string `json:"meterName,omitempty"`
+}
+
+// StaticPriceSheet is a price sheet with a fixed list of prices.
+type StaticPriceSheet struct {
+	CurrencyCode string  `json:"currency"`
+	Prices       []Price `json:"prices"`
+}
+
+// NewPriceSheet reads a price sheet from its JSON representation.
+func NewPriceSheet(data []byte) (*StaticPriceSheet, error) {
+	var sheet StaticPriceSheet
+	if err := json.Unmarshal(data, &sheet); err != nil {
+		return nil, fmt.Errorf("reading price sheet: %w", err)
+	}
+
+	return &sheet, nil
+}
+
+// BundledPriceSheet returns the price sheet bundled with azd, with the list prices of the common SKUs of the resources
+// used by azd templates, in USD.
+func BundledPriceSheet() (*StaticPriceSheet, error) {
+	return NewPriceSheet(resources.PriceSheet)
+}
+
+func (s *StaticPriceSheet) Currency() string {
+	return s.CurrencyCode
+}
+
+func (s *StaticPriceSheet) MonthlyCost(resource provisioning.BillableResource) (float64, bool) {
+	price, ok := s.Lookup(resource)
+	if !ok {
+		return 0, false
+	}
+
+	if price.PerCapacity && resource.Capacity != nil && *resource.Capacity > 0 {
+		return price.MonthlyPrice * float64(*resource.Capacity), true
+	}
+
+	return price.MonthlyPrice, true
+}
+
+// Lookup returns the price that best matches the resource. Prices for its SKU are preferred over the prices for all
+// the SKUs, and prices for its location over the prices for all locations.
+func (s *StaticPriceSheet) Lookup(resource provisioning.BillableResource) (*Price, bool) {
+	var best *Price
+	bestScore := -1
+	for i := range s.Prices {
+		price := &s.Prices[i]
+		score, ok := price.match(resource)
+		if ok && score > bestScore {
+			best = price
+			bestScore = score
+		}
+	}
+
+	return best, best != nil
+}
+
+// match reports whether the price applies to the resource, with a score that is higher for more specific prices.
+func (p *Price) match(resource provisioning.BillableResource) (int, bool) {
+	if !strings.EqualFold(p.ResourceType, resource.Type) {
+		return 0, false
+	}
+
+	score := 0
+	switch {
+	case strings.EqualFold(p.Sku, resource.Sku):
+		score += 2
+	case p.Sku != "*":
+		return 0, false
+	}
+
+	if p.Capacity != nil && (resource.Capacity == nil || *resource.Capacity != *p.Capacity) {
+		return 0, false
+	}
+
+	if p.Location != "" {
+		if normalizeLocation(p.Location) != normalizeLocation(resource.Location) {
+			return 0, false
+		}
+		score++
+	}
+
+	return score, true
+}
+
+// normalizeLocation returns the short form of a location, e.g. eastus for East US.
+func normalizeLocation(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
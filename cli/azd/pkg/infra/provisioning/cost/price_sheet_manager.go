@@ -0,0 +1,288 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/azure/azure-dev/cli/azd/pkg/config"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+const (
+	// retailPricesEndpoint is the endpoint of the Azure Retail Prices API, which doesn't require authentication.
+	retailPricesEndpoint = "https://prices.azure.com/api/retail/prices"
+	// priceCacheTTL is how long the prices retrieved from the Azure Retail Prices API are used before being refreshed.
+	priceCacheTTL = 7 * 24 * time.Hour
+	// refreshTimeout bounds the time spent refreshing prices, so that estimating costs offline is not delayed.
+	refreshTimeout = 10 * time.Second
+	// hoursPerMonth is the number of hours in a month used by Azure pricing.
+	hoursPerMonth = 730
+)
+
+// errRetailPriceNotFound is returned when the Azure Retail Prices API has no price for a meter in a location.
+var errRetailPriceNotFound = errors.New("retail price not found")
+
+// PriceSheetManager provides the price sheet used to estimate costs. It starts from the price sheet bundled with azd,
+// and refreshes the prices for the locations of the resources from the Azure Retail Prices API when online. Refreshed
+// prices are cached in the azd config directory, so that later estimates work offline.
+type PriceSheetManager struct {
+	transport policy.Transporter
+	// cachePath overrides the path of the cache file, for tests.
+	cachePath string
+}
+
+// NewPriceSheetManager creates a new PriceSheetManager.
+func NewPriceSheetManager(transport policy.Transporter) *PriceSheetManager {
+	return &PriceSheetManager{
+		transport: transport,
+	}
+}
+
+// PriceSheet returns the price sheet for the resources, refreshing their prices first when they are missing from the
+// cache or expired. Failing to refresh prices is not an error: the cached or bundled prices are used instead.
+func (m *PriceSheetManager) PriceSheet(
+	ctx context.Context,
+	resources []provisioning.BillableResource,
+) (PriceSheet, error) {
+	bundled, err := BundledPriceSheet()
+	if err != nil {
+		return nil, err
+	}
+
+	cachePath, err := m.cacheFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := readPriceCache(cachePath)
+	if m.refresh(ctx, bundled, cached, resources) {
+		if err := writePriceCache(cachePath, cached); err != nil {
+			log.Printf("failed to write price cache: %v", err)
+		}
+	}
+
+	// Cached prices are specific to a location, and so are preferred to the bundled ones.
+	return &StaticPriceSheet{
+		CurrencyCode: bundled.CurrencyCode,
+		Prices:       append(cached.Prices, bundled.Prices...),
+	}, nil
+}
+
+// refresh retrieves the prices of the resources missing from the cache, or expired, and reports whether the cache
+// changed. It stops at the first request that fails, as azd is likely offline.
+func (m *PriceSheetManager) refresh(
+	ctx context.Context,
+	bundled *StaticPriceSheet,
+	cached *StaticPriceSheet,
+	resources []provisioning.BillableResource,
+) bool {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	changed := false
+	for _, resource := range resources {
+		if resource.Location == "" {
+			continue
+		}
+
+		price, ok := bundled.Lookup(resource)
+		if !ok || price.Meter == nil {
+			continue
+		}
+
+		location := normalizeLocation(resource.Location)
+		index := cachedPriceIndex(cached, price, location)
+		if index >= 0 && time.Since(cached.Prices[index].RetrievedOn) < priceCacheTTL {
+			continue
+		}
+
+		monthlyPrice, err := m.retailMonthlyPrice(ctx, price.Meter, location)
+		if errors.Is(err, errRetailPriceNotFound) {
+			log.Printf("no retail price for %s %s in %s", price.ResourceType, price.Sku, location)
+			continue
+		} else if err != nil {
+			log.Printf("failed to refresh prices, using cached prices: %v", err)
+			return changed
+		}
+
+		refreshed := *price
+		refreshed.Location = location
+		refreshed.MonthlyPrice = monthlyPrice
+		refreshed.RetrievedOn = time.Now().UTC()
+		if index >= 0 {
+			cached.Prices[index] = refreshed
+		} else {
+			cached.Prices = append(cached.Prices, refreshed)
+		}
+		changed = true
+	}
+
+	return changed
+}
+
+// cachedPriceIndex returns the index of the cached price for the same resources as price in the location, or -1.
+func cachedPriceIndex(cached *StaticPriceSheet, price *Price, location string) int {
+	for i, candidate := range cached.Prices {
+		if strings.EqualFold(candidate.ResourceType, price.ResourceType) &&
+			strings.EqualFold(candidate.Sku, price.Sku) &&
+			equalCapacity(candidate.Capacity, price.Capacity) &&
+			candidate.Location == location {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func equalCapacity(a *int, b *int) bool {
+	return (a == nil && b == nil) || (a != nil && b != nil && *a == *b)
+}
+
+// retailPriceResponse is the response of the Azure Retail Prices API.
+type retailPriceResponse struct {
+	Items []retailPriceItem `json:"Items"`
+}
+
+type retailPriceItem struct {
+	RetailPrice      float64 `json:"retailPrice"`
+	UnitOfMeasure    string  `json:"unitOfMeasure"`
+	TierMinimumUnits float64 `json:"tierMinimumUnits"`
+}
+
+// retailMonthlyPrice returns the monthly price of a meter in a location from the Azure Retail Prices API.
+func (m *PriceSheetManager) retailMonthlyPrice(ctx context.Context, meter *Meter, location string) (float64, error) {
+	filters := []string{
+		fmt.Sprintf("serviceName eq '%s'", meter.ServiceName),
+		fmt.Sprintf("armRegionName eq '%s'", location),
+		fmt.Sprintf("skuName eq '%s'", meter.SkuName),
+		"priceType eq 'Consumption'",
+	}
+	if meter.ProductName != "" {
+		filters = append(filters, fmt.Sprintf("productName eq '%s'", meter.ProductName))
+	}
+	if meter.MeterName != "" {
+		filters = append(filters, fmt.Sprintf("meterName eq '%s'", meter.MeterName))
+	}
+
+	query := url.Values{}
+	query.Set("currencyCode", "USD")
+	query.Set("$filter", strings.Join(filters, " and "))
+
+	pipeline := runtime.NewPipeline("azd-prices", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: m.transport,
+	})
+
+	req, err := runtime.NewRequest(ctx, http.MethodGet, retailPricesEndpoint+"?"+query.Encode())
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := pipeline.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("requesting retail prices: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, runtime.NewResponseError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("reading retail prices: %w", err)
+	}
+
+	var prices retailPriceResponse
+	if err := json.Unmarshal(body, &prices); err != nil {
+		return 0, fmt.Errorf("reading retail prices: %w", err)
+	}
+
+	for _, item := range prices.Items {
+		if item.TierMinimumUnits != 0 {
+			continue
+		}
+
+		if unitsPerMonth, ok := unitsPerMonth(item.UnitOfMeasure); ok {
+			return item.RetailPrice * unitsPerMonth, nil
+		}
+	}
+
+	return 0, errRetailPriceNotFound
+}
+
+// unitsPerMonth returns the number of units of measure of a retail price in a month.
+func unitsPerMonth(unitOfMeasure string) (float64, bool) {
+	switch strings.ReplaceAll(unitOfMeasure, "/", " ") {
+	case "1 Hour":
+		return hoursPerMonth, true
+	case "1 Day":
+		return hoursPerMonth / 24.0, true
+	case "1 Month":
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+func (m *PriceSheetManager) cacheFilePath() (string, error) {
+	if m.cachePath != "" {
+		return m.cachePath, nil
+	}
+
+	configDir, err := config.GetUserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	return filepath.Join(configDir, "cache", "prices.json"), nil
+}
+
+// readPriceCache returns the cached prices, or an empty price sheet when the cache is missing or corrupt.
+func readPriceCache(cachePath string) *StaticPriceSheet {
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.Printf("failed to read price cache: %v", err)
+		}
+		return &StaticPriceSheet{}
+	}
+
+	sheet, err := NewPriceSheet(data)
+	if err != nil {
+		log.Printf("failed to read price cache %s: %v", cachePath, err)
+		return &StaticPriceSheet{}
+	}
+
+	return sheet
+}
+
+func writePriceCache(cachePath string, sheet *StaticPriceSheet) error {
+	sheet.CurrencyCode = "USD"
+	data, err := json.MarshalIndent(sheet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), osutil.PermissionDirectoryOwnerOnly); err != nil {
+		return err
+	}
+
+	return os.WriteFile(cachePath, data, osutil.PermissionFile)
+}
@@ -0,0 +1,124 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceSheetManager_Refresh(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	requests := 0
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.String(), retailPricesEndpoint)
+	}).RespondFn(func(request *http.Request) (*http.Response, error) {
+		requests++
+		filter := request.URL.Query().Get("$filter")
+		require.Contains(t, filter, "serviceName eq 'Azure App Service'")
+		require.Contains(t, filter, "armRegionName eq 'westeurope'")
+		require.Contains(t, filter, "skuName eq 'P1 v3'")
+
+		return mocks.CreateHttpResponseWithBody(request, http.StatusOK, map[string]any{
+			"Items": []map[string]any{
+				{"retailPrice": 0.2, "unitOfMeasure": "1 Hour", "tierMinimumUnits": 0},
+			},
+		})
+	})
+
+	manager := NewPriceSheetManager(mockContext.HttpClient)
+	manager.cachePath = filepath.Join(t.TempDir(), "cache", "prices.json")
+
+	plan := provisioning.BillableResource{
+		Type: "Microsoft.Web/serverfarms", Location: "West Europe", Sku: "P1v3", Capacity: to.Ptr(2)}
+
+	sheet, err := manager.PriceSheet(t.Context(), []provisioning.BillableResource{plan})
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+
+	monthlyCost, ok := sheet.MonthlyCost(plan)
+	require.True(t, ok)
+	require.InDelta(t, 2*0.2*hoursPerMonth, monthlyCost, 0.001)
+
+	// The refreshed price is cached.
+	cached := readPriceCache(manager.cachePath)
+	require.Len(t, cached.Prices, 1)
+	require.Equal(t, "westeurope", cached.Prices[0].Location)
+
+	_, err = manager.PriceSheet(t.Context(), []provisioning.BillableResource{plan})
+	require.NoError(t, err)
+	require.Equal(t, 1, requests)
+}
+
+func TestPriceSheetManager_Offline(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.HttpClient.When(func(request *http.Request) bool {
+		return strings.HasPrefix(request.URL.String(), retailPricesEndpoint)
+	}).SetNonRetriableError(errors.New("no such host"))
+
+	manager := NewPriceSheetManager(mockContext.HttpClient)
+	manager.cachePath = filepath.Join(t.TempDir(), "prices.json")
+
+	plan := provisioning.BillableResource{Type: "Microsoft.Web/serverfarms", Location: "eastus", Sku: "B1"}
+
+	t.Run("Bundled", func(t *testing.T) {
+		sheet, err := manager.PriceSheet(t.Context(), []provisioning.BillableResource{plan})
+		require.NoError(t, err)
+
+		bundled, err := BundledPriceSheet()
+		require.NoError(t, err)
+		want, _ := bundled.MonthlyCost(plan)
+
+		monthlyCost, ok := sheet.MonthlyCost(plan)
+		require.True(t, ok)
+		require.Equal(t, want, monthlyCost)
+
+		_, err = os.Stat(manager.cachePath)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("ExpiredCache", func(t *testing.T) {
+		require.NoError(t, writePriceCache(manager.cachePath, &StaticPriceSheet{
+			Prices: []Price{{
+				ResourceType: "Microsoft.Web/serverfarms",
+				Sku:          "B1",
+				Location:     "eastus",
+				MonthlyPrice: 20,
+				RetrievedOn:  time.Now().Add(-2 * priceCacheTTL),
+			}},
+		}))
+
+		sheet, err := manager.PriceSheet(t.Context(), []provisioning.BillableResource{plan})
+		require.NoError(t, err)
+
+		monthlyCost, ok := sheet.MonthlyCost(plan)
+		require.True(t, ok)
+		require.Equal(t, 20.0, monthlyCost)
+	})
+}
+
+func Test_unitsPerMonth(t *testing.T) {
+	for unit, want := range map[string]float64{
+		"1 Hour":  hoursPerMonth,
+		"1/Day":   hoursPerMonth / 24.0,
+		"1/Month": 1,
+	} {
+		got, ok := unitsPerMonth(unit)
+		require.True(t, ok, unit)
+		require.Equal(t, want, got, unit)
+	}
+
+	_, ok := unitsPerMonth("1M")
+	require.False(t, ok)
+}
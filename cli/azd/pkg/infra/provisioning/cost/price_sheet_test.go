@@ -0,0 +1,95 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package cost
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundledPriceSheet(t *testing.T) {
+	sheet, err := BundledPriceSheet()
+	require.NoError(t, err)
+	require.Equal(t, "USD", sheet.Currency())
+	require.NotEmpty(t, sheet.Prices)
+
+	for _, price := range sheet.Prices {
+		require.NotEmpty(t, price.ResourceType)
+		require.NotEmpty(t, price.Sku, "price for %s has no SKU", price.ResourceType)
+		require.Empty(t, price.Location, "bundled prices apply to all locations")
+		require.GreaterOrEqual(t, price.MonthlyPrice, 0.0)
+	}
+}
+
+func TestStaticPriceSheet_MonthlyCost(t *testing.T) {
+	sheet := &StaticPriceSheet{
+		CurrencyCode: "USD",
+		Prices: []Price{
+			{ResourceType: "Microsoft.KeyVault/vaults", Sku: "*", MonthlyPrice: 0},
+			{ResourceType: "Microsoft.Web/serverfarms", Sku: "P1v3", MonthlyPrice: 100, PerCapacity: true},
+			{ResourceType: "Microsoft.Web/serverfarms", Sku: "P1v3", Location: "westeurope", MonthlyPrice: 120,
+				PerCapacity: true},
+			{ResourceType: "Microsoft.Cache/redis", Sku: "Basic", Capacity: to.Ptr(0), MonthlyPrice: 16},
+			{ResourceType: "Microsoft.Cache/redis", Sku: "Basic", Capacity: to.Ptr(1), MonthlyPrice: 40},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		resource provisioning.BillableResource
+		want     float64
+		wantOk   bool
+	}{
+		{
+			name:     "AllSkus",
+			resource: provisioning.BillableResource{Type: "Microsoft.KeyVault/vaults", Sku: "standard"},
+			want:     0,
+			wantOk:   true,
+		},
+		{
+			name: "PerCapacity",
+			resource: provisioning.BillableResource{
+				Type: "microsoft.web/serverfarms", Sku: "p1v3", Location: "eastus", Capacity: to.Ptr(3)},
+			want:   300,
+			wantOk: true,
+		},
+		{
+			name: "Location",
+			resource: provisioning.BillableResource{
+				Type: "Microsoft.Web/serverfarms", Sku: "P1v3", Location: "West Europe"},
+			want:   120,
+			wantOk: true,
+		},
+		{
+			name: "Capacity",
+			resource: provisioning.BillableResource{
+				Type: "Microsoft.Cache/redis", Sku: "Basic", Capacity: to.Ptr(1)},
+			want:   40,
+			wantOk: true,
+		},
+		{
+			name:     "UnknownCapacity",
+			resource: provisioning.BillableResource{Type: "Microsoft.Cache/redis", Sku: "Basic"},
+		},
+		{
+			name:     "UnknownSku",
+			resource: provisioning.BillableResource{Type: "Microsoft.Web/serverfarms", Sku: "B1"},
+		},
+		{
+			name:     "UnknownType",
+			resource: provisioning.BillableResource{Type: "Microsoft.Network/virtualNetworks"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := sheet.MonthlyCost(tt.resource)
+			require.Equal(t, tt.wantOk, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrDriftNotSupported is returned when the provider of the infrastructure can't detect drift.
+	ErrDriftNotSupported = errors.New("drift detection is not supported by the provider")
+	// ErrDriftBaselineChanged is returned when the infrastructure changed since its last successful deployment, so that
+	// drift can't be told apart from the intended changes.
+	ErrDriftBaselineChanged = errors.New("the infrastructure changed since the last successful deployment")
+	// ErrDriftDetected is returned when deployed resources differ from their last successful deployment.
+	ErrDriftDetected = errors.New("drift detected")
+)
+
+// DriftDetector is implemented by the providers able to compare the deployed resources with their last successful
+// deployment, without applying any change.
+type DriftDetector interface {
+	// Drift returns the changes made to the deployed resources outside of provisioning.
+	Drift(ctx context.Context) (*DriftResult, error)
+}
+
+// DriftResult lists the resources that differ from their last successful deployment.
+type DriftResult struct {
+	// Changes are the changes that provisioning again would apply to bring the resources back to their last deployed
+	// configuration.
+	Changes []*DeploymentPreviewChange
+}
@@ -8,8 +8,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/alpha"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
@@ -285,6 +287,7 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 			Status:     deployResult.Preview.Status,
 			Properties: &DeploymentPreviewProperties{},
 		},
+		BillableChanges: deployResult.BillableChanges,
 	}
 
 	for index, result := range deployResult.Preview.Properties.Changes {
@@ -304,6 +307,31 @@ func (m *Manager) Preview(ctx context.Context) (*DeployPreviewResult, error) {
 	return &filteredResult, nil
 }
 
+// Drift returns the changes made to the deployed resources since their last successful deployment. Resource types
+// known to azd are replaced by their display name.
+func (m *Manager) Drift(ctx context.Context) (*DriftResult, error) {
+	detector, ok := m.provider.(DriftDetector)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrDriftNotSupported, m.provider.Name())
+	}
+
+	driftResult, err := detector.Drift(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("detecting drift: %w", err)
+	}
+
+	for _, change := range driftResult.Changes {
+		if displayName := azapi.GetResourceTypeDisplayName(azapi.AzureResourceType(change.ResourceType)); displayName != "" {
+			change.ResourceType = displayName
+		}
+	}
+
+	// make sure any spinner is stopped
+	m.console.StopSpinner(ctx, "", input.StepDone)
+
+	return driftResult, nil
+}
+
 // Destroys the Azure infrastructure for the specified project
 func (m *Manager) Destroy(ctx context.Context, options DestroyOptions) (*DestroyResult, error) {
 	destroyResult, err := m.provider.Destroy(ctx, options)
@@ -332,27 +360,50 @@ func UpdateEnvironment(
 	envManager environment.Manager,
 ) error {
 	if len(outputs) > 0 {
-		for key, param := range outputs {
-			// Complex types marshalled as JSON strings, simple types marshalled as simple strings
-			if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
-				bytes, err := json.Marshal(param.Value)
-				if err != nil {
-					return fmt.Errorf("invalid value for output parameter '%s' (%s): %w", key, string(param.Type), err)
-				}
-				env.DotenvSet(key, string(bytes))
-			} else {
-				env.DotenvSet(key, fmt.Sprintf("%v", param.Value))
-			}
+		values, err := OutputValues(outputs)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range values {
+			env.DotenvSet(key, value)
 		}
 
-		if err := envManager.Save(ctx, env); err != nil {
+		// The outputs describe resources that were already deployed, so they are saved even when they do not
+		// match the schema of the environment.
+		err = envManager.SaveWithOptions(ctx, env, &environment.SaveOptions{WarnOnSchemaViolation: true})
+		if err != nil {
 			return fmt.Errorf("writing environment: %w", err)
 		}
+
+		// Recorded so the outputs can be told apart from other values, e.g. by 'azd env copy --no-outputs'.
+		if err := AddOutputKeys(env, envManager, slices.Collect(maps.Keys(values))...); err != nil {
+			return fmt.Errorf("recording output keys: %w", err)
+		}
 	}
 
 	return nil
 }
 
+// OutputValues returns the values deployment outputs are stored as in the environment.
+func OutputValues(outputs map[string]OutputParameter) (map[string]string, error) {
+	values := make(map[string]string, len(outputs))
+	for key, param := range outputs {
+		// Complex types marshalled as JSON strings, simple types marshalled as simple strings
+		if param.Type == ParameterTypeArray || param.Type == ParameterTypeObject {
+			bytes, err := json.Marshal(param.Value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for output parameter '%s' (%s): %w", key, string(param.Type), err)
+			}
+			values[key] = string(bytes)
+		} else {
+			values[key] = fmt.Sprintf("%v", param.Value)
+		}
+	}
+
+	return values, nil
+}
+
 type EnsureSubscriptionAndLocationOptions struct {
 	// LocationFilterPredicate is a function to filter the locations being displayed if prompting the user for the location.
 	LocationFiler prompt.LocationFilterPredicate
@@ -503,12 +554,6 @@ func NewManager(
 }
 
 func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
-	var err error
-	m.options.Provider, err = ParseProvider(m.options.Provider)
-	if err != nil {
-		return nil, err
-	}
-
 	if alphaFeatureId, isAlphaFeature := alpha.IsFeatureKey(string(m.options.Provider)); isAlphaFeature {
 		if !m.alphaFeatureManager.IsEnabled(alphaFeatureId) {
 			return nil, fmt.Errorf("provider '%s' is alpha feature and it is not enabled. Run `%s` to enable it.",
@@ -531,8 +576,15 @@ func (m *Manager) newProvider(ctx context.Context) (Provider, error) {
 	}
 
 	var provider Provider
-	err = m.serviceLocator.ResolveNamed(string(providerKey), &provider)
+	err := m.serviceLocator.ResolveNamed(string(providerKey), &provider)
 	if err != nil {
+		// providers contributed by extensions are only known once they are registered
+		if !isBuiltInProvider(m.options.Provider) {
+			return nil, fmt.Errorf(
+				"%w: '%s', supported providers are '%s', '%s' and '%s'",
+				ErrUnknownProvider, m.options.Provider, Bicep, Terraform, Pulumi)
+		}
+
 		return nil, fmt.Errorf("failed resolving IaC provider '%s': %w", providerKey, err)
 	}
 
@@ -94,6 +94,35 @@ func TestManagerPreview(t *testing.T) {
 	require.Nil(t, err)
 }
 
+func TestManagerDriftNotSupported(t *testing.T) {
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+	})
+
+	mockContext := mocks.NewMockContext(t.Context())
+	registerContainerDependencies(mockContext, env)
+
+	envManager := &mockenv.MockEnvManager{}
+	mgr := provisioning.NewManager(
+		mockContext.Container,
+		defaultProvider,
+		envManager,
+		env,
+		mockContext.Console,
+		mockContext.AlphaFeaturesManager,
+		nil,
+		cloud.AzurePublic(),
+	)
+	err := mgr.Initialize(*mockContext.Context, "", provisioning.Options{Provider: "test"})
+	require.NoError(t, err)
+
+	driftResult, err := mgr.Drift(*mockContext.Context)
+
+	require.Nil(t, driftResult)
+	require.ErrorIs(t, err, provisioning.ErrDriftNotSupported)
+}
+
 func TestManagerGetState(t *testing.T) {
 	env := environment.NewWithValues("test-env", map[string]string{
 		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
@@ -338,6 +367,37 @@ func requirePromptRequiredError(
 	return promptErr
 }
 
+func TestManagerUnknownProvider(t *testing.T) {
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_SUBSCRIPTION_ID": "SUBSCRIPTION_ID",
+		"AZURE_LOCATION":        "eastus2",
+	})
+
+	mockContext := mocks.NewMockContext(t.Context())
+	registerContainerDependencies(mockContext, env)
+	// Providers contributed by extensions are registered under their own names.
+	mockContext.Container.MustRegisterNamedTransient("custom-ext", test.NewTestProvider)
+
+	newManager := func() *provisioning.Manager {
+		return provisioning.NewManager(
+			mockContext.Container,
+			defaultProvider,
+			&mockenv.MockEnvManager{},
+			env,
+			mockContext.Console,
+			mockContext.AlphaFeaturesManager,
+			nil,
+			cloud.AzurePublic(),
+		)
+	}
+
+	err := newManager().Initialize(*mockContext.Context, "", provisioning.Options{Provider: "custom-ext"})
+	require.NoError(t, err)
+
+	err = newManager().Initialize(*mockContext.Context, "", provisioning.Options{Provider: "bicpe"})
+	require.ErrorIs(t, err, provisioning.ErrUnknownProvider)
+}
+
 func registerContainerDependencies(mockContext *mocks.MockContext, env *environment.Environment) {
 	envManager := &mockenv.MockEnvManager{}
 	envManager.On("Save", *mockContext.Context, env).Return(nil)
@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// outputKeysFileName is the name of the file, stored next to the .env file of an environment, listing the .env keys
+// that were set from provisioning outputs. It is provisioning state rather than user configuration, so it is kept
+// out of config.json.
+const outputKeysFileName = ".outputs.json"
+
+// outputKeysPath returns the path of the output keys file of env.
+func outputKeysPath(env *environment.Environment, envManager environment.Manager) string {
+	return filepath.Join(filepath.Dir(envManager.EnvPath(env)), outputKeysFileName)
+}
+
+// OutputKeys returns the .env keys of env that were set from provisioning outputs, sorted. Environments provisioned
+// before outputs were recorded have none.
+func OutputKeys(env *environment.Environment, envManager environment.Manager) ([]string, error) {
+	data, err := os.ReadFile(outputKeysPath(env, envManager))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading output keys: %w", err)
+	}
+
+	var keys []string
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parsing output keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// AddOutputKeys records that the given .env keys of env were set from provisioning outputs.
+func AddOutputKeys(env *environment.Environment, envManager environment.Manager, keys ...string) error {
+	all, err := OutputKeys(env, envManager)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if !slices.Contains(all, key) {
+			all = append(all, key)
+		}
+	}
+
+	return SetOutputKeys(env, envManager, all)
+}
+
+// SetOutputKeys replaces the .env keys of env recorded as set from provisioning outputs. No file is written when
+// there are none.
+func SetOutputKeys(env *environment.Environment, envManager environment.Manager, keys []string) error {
+	path := outputKeysPath(env, envManager)
+	if len(keys) == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("removing output keys: %w", err)
+		}
+		return nil
+	}
+
+	keys = slices.Sorted(slices.Values(keys))
+	data, err := json.MarshalIndent(keys, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling output keys: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), osutil.PermissionDirectory); err != nil {
+		return fmt.Errorf("creating environment directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, osutil.PermissionFile); err != nil {
+		return fmt.Errorf("writing output keys: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package provisioning
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateEnvironmentRecordsOutputKeys(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	env := environment.NewWithValues("test-env", map[string]string{"AZURE_LOCATION": "eastus2"})
+
+	envRoot := t.TempDir()
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("SaveWithOptions", *mockContext.Context, env, mock.Anything).Return(nil)
+	envManager.On("EnvPath", env).Return(filepath.Join(envRoot, environment.DotEnvFileName))
+
+	keys, err := OutputKeys(env, envManager)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+
+	err = UpdateEnvironment(*mockContext.Context, map[string]OutputParameter{
+		"API_URL": {Type: ParameterTypeString, Value: "https://api"},
+	}, env, envManager)
+	require.NoError(t, err)
+
+	err = UpdateEnvironment(*mockContext.Context, map[string]OutputParameter{
+		"WEB_URL": {Type: ParameterTypeString, Value: "https://web"},
+	}, env, envManager)
+	require.NoError(t, err)
+
+	keys, err = OutputKeys(env, envManager)
+	require.NoError(t, err)
+	require.Equal(t, []string{"API_URL", "WEB_URL"}, keys)
+
+	// The keys are provisioning state, not configuration of the environment.
+	_, has := env.Config.Get("infra")
+	require.False(t, has)
+
+	require.NoError(t, SetOutputKeys(env, envManager, nil))
+	_, err = os.Stat(filepath.Join(envRoot, outputKeysFileName))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
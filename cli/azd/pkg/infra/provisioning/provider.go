@@ -231,6 +231,10 @@ type DeployResult struct {
 // applying the changes.
 type DeployPreviewResult struct {
 	Preview *DeploymentPreview
+	// BillableChanges are the changes of the resources, described by the properties that determine their price. Nil
+	// when the provider can't describe them. Bicep describes them from the what-if resource states, and Terraform
+	// from the resource changes of the plan.
+	BillableChanges []*BillableChange
 }
 
 type DestroyResult struct {
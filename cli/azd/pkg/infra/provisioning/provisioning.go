@@ -4,6 +4,7 @@
 package provisioning
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
@@ -50,8 +51,22 @@ func NewEnvRefreshResultFromState(state *State) contracts.EnvRefreshResult {
 	return result
 }
 
+// ErrUnknownProvider is returned when provisioning with a provider that is neither built into azd nor registered by an
+// extension.
+var ErrUnknownProvider = errors.New("unknown IaC provider")
+
 // ParseProvider returns the specified IaC provider unchanged.
 // Defaulting for NotSpecified is handled later during provider creation.
 func ParseProvider(kind ProviderKind) (ProviderKind, error) {
 	return kind, nil
 }
+
+// isBuiltInProvider returns whether kind is one of the IaC providers built into azd.
+func isBuiltInProvider(kind ProviderKind) bool {
+	switch kind {
+	case NotSpecified, Bicep, Arm, Terraform, Pulumi, Test:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,830 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
+	"github.com/braydonk/yaml"
+	"github.com/drone/envsubst"
+)
+
+const (
+	// passphraseEnvVarName is the variable holding the passphrase that encrypts the secrets of stacks kept in
+	// self-managed backends.
+	passphraseEnvVarName = "PULUMI_CONFIG_PASSPHRASE"
+	// passphraseFileEnvVarName is the variable holding the path of a file with that passphrase.
+	passphraseFileEnvVarName = "PULUMI_CONFIG_PASSPHRASE_FILE"
+	// passphraseConfigKey is the environment config key referencing the passphrase generated for the environment,
+	// which is kept in the user vault rather than in the .env file passed to hooks and child processes.
+	passphraseConfigKey = "infra.pulumi.passphrase"
+)
+
+var (
+	defaultOptions = provisioning.Options{
+		Module: "main",
+		Path:   "infra",
+	}
+)
+
+// PulumiProvider exposes infrastructure provisioning using Pulumi projects. Each azd environment maps to the
+// Pulumi stack of the same name.
+type PulumiProvider struct {
+	envManager   environment.Manager
+	env          *environment.Environment
+	prompters    prompt.Prompter
+	console      input.Console
+	cli          *pulumi.Cli
+	curPrincipal provisioning.CurrentPrincipalIdProvider
+	projectPath  string
+	options      provisioning.Options
+	// localBackend is set when the state of the stack is kept in the local backend managed by azd.
+	localBackend bool
+}
+
+// Name gets the name of the infra provider
+func (p *PulumiProvider) Name() string {
+	return "Pulumi"
+}
+
+func (p *PulumiProvider) RequiredExternalTools() []tools.ExternalTool {
+	return []tools.ExternalTool{p.cli}
+}
+
+// NewPulumiProvider creates a new instance of a Pulumi Infra provider
+func NewPulumiProvider(
+	cli *pulumi.Cli,
+	envManager environment.Manager,
+	env *environment.Environment,
+	console input.Console,
+	curPrincipal provisioning.CurrentPrincipalIdProvider,
+	prompters prompt.Prompter,
+) provisioning.Provider {
+	return &PulumiProvider{
+		envManager:   envManager,
+		env:          env,
+		console:      console,
+		cli:          cli,
+		curPrincipal: curPrincipal,
+		prompters:    prompters,
+	}
+}
+
+func (p *PulumiProvider) Initialize(ctx context.Context, projectPath string, options provisioning.Options) error {
+	infraOptions, err := options.GetWithDefaults(defaultOptions)
+	if err != nil {
+		return fmt.Errorf("merging pulumi provider options: %w", err)
+	}
+
+	p.projectPath = projectPath
+	p.options = infraOptions
+
+	requiredTools := p.RequiredExternalTools()
+	if err := tools.EnsureInstalled(ctx, requiredTools...); err != nil {
+		return err
+	}
+
+	if err := p.EnsureEnv(ctx); err != nil {
+		return err
+	}
+
+	project, err := p.readProject()
+	if err != nil {
+		return err
+	}
+
+	envVars := []string{
+		// Required when using service principal login
+		fmt.Sprintf("ARM_TENANT_ID=%s", os.Getenv("ARM_TENANT_ID")),
+		fmt.Sprintf("ARM_SUBSCRIPTION_ID=%s", p.env.GetSubscriptionId()),
+		fmt.Sprintf("ARM_CLIENT_ID=%s", os.Getenv("ARM_CLIENT_ID")),
+		fmt.Sprintf("ARM_CLIENT_SECRET=%s", os.Getenv("ARM_CLIENT_SECRET")),
+		// Default location of the azure-native provider
+		fmt.Sprintf("ARM_LOCATION=%s", p.env.GetLocation()),
+		"PULUMI_SKIP_UPDATE_CHECK=true",
+	}
+
+	// Unless the project or the user chose a backend, the state of the stack is kept next to the environment, like
+	// the local state of terraform.
+	p.localBackend = project.Backend.URL == "" && os.Getenv("PULUMI_BACKEND_URL") == ""
+	if p.localBackend {
+		envVars = append(envVars, fmt.Sprintf("PULUMI_BACKEND_URL=%s", p.localBackendUrl()))
+	}
+
+	passphrase, err := p.passphrase(ctx)
+	if err != nil {
+		return err
+	}
+	if passphrase != "" {
+		envVars = append(envVars, fmt.Sprintf("%s=%s", passphraseEnvVarName, passphrase))
+	}
+
+	p.cli.SetEnv(envVars)
+	return nil
+}
+
+// passphrase returns the passphrase encrypting the secrets of the stack, when azd provides it rather than the user.
+// Stacks of the local backend use a passphrase generated for the environment and stored as a secret of its config.
+// Stacks of other self-managed backends require the user to configure one.
+func (p *PulumiProvider) passphrase(ctx context.Context) (string, error) {
+	if _, has := os.LookupEnv(passphraseEnvVarName); has || os.Getenv(passphraseFileEnvVarName) != "" {
+		return "", nil
+	}
+
+	if passphrase, has := p.env.Dotenv()[passphraseEnvVarName]; has {
+		return passphrase, nil
+	}
+
+	if passphrase, has := p.env.Config.GetString(passphraseConfigKey); has {
+		return passphrase, nil
+	}
+
+	if !p.localBackend {
+		return "", nil
+	}
+
+	// Stacks created before the passphrase was generated encrypt their secrets with an empty passphrase, which
+	// must be kept to read them.
+	if _, err := os.Stat(filepath.Join(p.localBackendPath(), "stacks")); err == nil {
+		p.console.MessageUxItem(ctx, &ux.WarningMessage{
+			Description: fmt.Sprintf(
+				"The secrets of Pulumi stack '%s' are encrypted with an empty passphrase. Set %s and run "+
+					"'pulumi stack change-secrets-provider passphrase' in %s to protect them.",
+				p.stackName(), passphraseEnvVarName, p.modulePath()),
+		})
+		return "", nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("generating pulumi passphrase: %w", err)
+	}
+
+	passphrase := base64.StdEncoding.EncodeToString(key)
+	if err := p.env.Config.SetSecret(passphraseConfigKey, passphrase); err != nil {
+		return "", fmt.Errorf("storing pulumi passphrase: %w", err)
+	}
+	if err := p.envManager.Save(ctx, p.env); err != nil {
+		return "", fmt.Errorf("saving pulumi passphrase: %w", err)
+	}
+
+	return passphrase, nil
+}
+
+// EnsureEnv ensures that the environment is in a provision-ready state with required values set, prompting the user if
+// values are unset.
+//
+// An environment is considered to be in a provision-ready state if it contains both an AZURE_SUBSCRIPTION_ID and
+// AZURE_LOCATION value.
+func (p *PulumiProvider) EnsureEnv(ctx context.Context) error {
+	return provisioning.EnsureSubscriptionAndLocation(
+		ctx,
+		p.envManager,
+		p.env,
+		p.prompters,
+		provisioning.EnsureSubscriptionAndLocationOptions{},
+	)
+}
+
+// Deploy the infrastructure of the stack through pulumi up
+func (p *PulumiProvider) Deploy(ctx context.Context) (*provisioning.DeployResult, error) {
+	deployment, err := p.prepareStack(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	modulePath := p.modulePath()
+
+	// pulumi writes its progress directly to the console, so no spinner must be running.
+	p.console.StopSpinner(ctx, "", input.Step)
+	_, err = p.cli.Up(ctx, modulePath, p.stackName())
+	if err != nil {
+		return nil, fmt.Errorf("deploying pulumi stack: %w", err)
+	}
+
+	outputs, err := p.createOutputParameters(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs: %w", err)
+	}
+
+	deployment.Outputs = outputs
+	return &provisioning.DeployResult{
+		Deployment: deployment,
+	}, nil
+}
+
+// Preview the changes of the stack through pulumi preview
+func (p *PulumiProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
+	if _, err := p.prepareStack(ctx); err != nil {
+		return nil, err
+	}
+
+	runResult, err := p.cli.Preview(ctx, p.modulePath(), p.stackName())
+	if err != nil {
+		return nil, err
+	}
+
+	var preview pulumiPreviewOutput
+	if err := json.Unmarshal([]byte(runResult), &preview); err != nil {
+		return nil, fmt.Errorf("reading pulumi preview: %w", err)
+	}
+
+	return &provisioning.DeployPreviewResult{
+		Preview: &provisioning.DeploymentPreview{
+			Status: "done",
+			Properties: &provisioning.DeploymentPreviewProperties{
+				Changes: convertPreviewSteps(preview.Steps),
+			},
+		},
+	}, nil
+}
+
+// Destroys the resources of the stack through pulumi destroy
+func (p *PulumiProvider) Destroy(
+	ctx context.Context,
+	options provisioning.DestroyOptions,
+) (*provisioning.DestroyResult, error) {
+	if _, err := p.prepareStack(ctx); err != nil {
+		return nil, err
+	}
+
+	modulePath := p.modulePath()
+
+	outputs, err := p.createOutputParameters(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs: %w", err)
+	}
+
+	p.console.Message(ctx, "Deleting pulumi stack resources...")
+	// pulumi doesn't use the `p.console`, we must ensure no spinner is running before calling Destroy
+	// as it could be an interactive operation if it needs confirmation
+	p.console.StopSpinner(ctx, "", input.Step)
+	runResult, err := p.cli.Destroy(ctx, modulePath, p.stackName(), options.Force())
+	if err != nil {
+		return nil, fmt.Errorf("template Destroy failed: %s, err: %w", runResult, err)
+	}
+
+	return &provisioning.DestroyResult{
+		InvalidatedEnvKeys: slices.Collect(maps.Keys(outputs)),
+	}, nil
+}
+
+func (p *PulumiProvider) State(
+	ctx context.Context,
+	options *provisioning.StateOptions,
+) (*provisioning.StateResult, error) {
+	modulePath := p.modulePath()
+
+	p.console.Message(ctx, "Retrieving pulumi stack state...")
+	if err := p.selectStack(ctx); err != nil {
+		return nil, err
+	}
+
+	outputs, err := p.createOutputParameters(ctx, modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi stack outputs: %w", err)
+	}
+
+	exported, err := p.cli.StackExport(ctx, modulePath, p.stackName())
+	if err != nil {
+		return nil, fmt.Errorf("fetching pulumi state failed: %w", err)
+	}
+
+	var checkpoint pulumiCheckpoint
+	if err := json.Unmarshal([]byte(exported), &checkpoint); err != nil {
+		return nil, fmt.Errorf("reading pulumi state: %w", err)
+	}
+
+	return &provisioning.StateResult{
+		State: &provisioning.State{
+			Outputs:   outputs,
+			Resources: collectAzureResources(checkpoint.Deployment.Resources),
+		},
+	}, nil
+}
+
+// Parameters returns the stack configuration values azd sets from the parameters file of the module.
+func (p *PulumiProvider) Parameters(ctx context.Context) ([]provisioning.Parameter, error) {
+	values, err := p.loadParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := p.readProject()
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make([]provisioning.Parameter, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		parameters = append(parameters, provisioning.Parameter{
+			Name:               key,
+			Secret:             project.isSecret(key),
+			Value:              values[key].value,
+			EnvVarMapping:      values[key].envVarMapping,
+			UsingEnvVarMapping: len(values[key].envVarMapping) > 0,
+		})
+	}
+
+	return parameters, nil
+}
+
+// PlannedOutputs returns the outputs declared in Pulumi.yaml. Only projects using the YAML runtime declare their
+// outputs there; the outputs of the other runtimes are only known once deployed.
+func (p *PulumiProvider) PlannedOutputs(ctx context.Context) ([]provisioning.PlannedOutput, error) {
+	project, err := p.readProject()
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []provisioning.PlannedOutput
+	for _, key := range slices.Sorted(maps.Keys(project.Outputs)) {
+		outputs = append(outputs, provisioning.PlannedOutput{
+			Name: key,
+		})
+	}
+
+	return outputs, nil
+}
+
+// prepareStack selects the stack of the environment and sets its configuration from the parameters file. It returns
+// the deployment holding the configuration values.
+func (p *PulumiProvider) prepareStack(ctx context.Context) (*provisioning.Deployment, error) {
+	if err := p.selectStack(ctx); err != nil {
+		return nil, err
+	}
+
+	values, err := p.loadParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	project, err := p.readProject()
+	if err != nil {
+		return nil, err
+	}
+
+	deployment := &provisioning.Deployment{
+		Parameters: map[string]provisioning.InputParameter{},
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		value, err := configValue(values[key].value)
+		if err != nil {
+			return nil, fmt.Errorf("parameter '%s': %w", key, err)
+		}
+
+		err = p.cli.SetConfig(ctx, p.modulePath(), p.stackName(), key, value, project.isSecret(key))
+		if err != nil {
+			return nil, err
+		}
+
+		deployment.Parameters[key] = provisioning.InputParameter{
+			Type:  string(parameterType(values[key].value)),
+			Value: values[key].value,
+		}
+	}
+
+	return deployment, nil
+}
+
+// selectStack selects the stack of the environment, creating it on first use.
+func (p *PulumiProvider) selectStack(ctx context.Context) error {
+	if p.localBackend {
+		if err := os.MkdirAll(p.localBackendPath(), osutil.PermissionDirectory); err != nil {
+			return fmt.Errorf("creating pulumi state directory: %w", err)
+		}
+	}
+
+	return p.cli.SelectStack(ctx, p.modulePath(), p.stackName())
+}
+
+// Creates a normalized view of the stack outputs.
+func (p *PulumiProvider) createOutputParameters(
+	ctx context.Context,
+	modulePath string,
+) (map[string]provisioning.OutputParameter, error) {
+	runResult, err := p.cli.StackOutput(ctx, modulePath, p.stackName())
+	if err != nil {
+		return nil, err
+	}
+
+	var outputMap map[string]any
+	if err := json.Unmarshal([]byte(runResult), &outputMap); err != nil {
+		return nil, err
+	}
+
+	outputParameters := make(map[string]provisioning.OutputParameter)
+	for key, value := range outputMap {
+		if value == nil {
+			// omit null
+			continue
+		}
+
+		outputParameters[key] = provisioning.OutputParameter{
+			Type:  parameterType(value),
+			Value: value,
+		}
+	}
+
+	return outputParameters, nil
+}
+
+// parameterType returns the parameter type of a value decoded from JSON.
+func parameterType(value any) provisioning.ParameterType {
+	switch value.(type) {
+	case bool:
+		return provisioning.ParameterTypeBoolean
+	case float64:
+		return provisioning.ParameterTypeNumber
+	case []any:
+		return provisioning.ParameterTypeArray
+	case map[string]any:
+		return provisioning.ParameterTypeObject
+	default:
+		return provisioning.ParameterTypeString
+	}
+}
+
+// configValue returns value in the form `pulumi config set` expects. Values other than strings are passed as JSON.
+func configValue(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(raw), nil
+}
+
+// parameterValue is a value of the parameters file, along with the environment variables it references.
+type parameterValue struct {
+	value         any
+	envVarMapping []string
+}
+
+// loadParameters reads the parameters file of the module, replacing environment variable references in its string
+// values. A missing parameters file means the stack takes no configuration from azd.
+func (p *PulumiProvider) loadParameters(ctx context.Context) (map[string]parameterValue, error) {
+	parametersFilePath := p.parametersTemplateFilePath()
+	log.Printf("Reading parameters template file from: %s", parametersFilePath)
+
+	parametersBytes, err := os.ReadFile(parametersFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading parameter file template: %w", err)
+	}
+
+	var parameters map[string]any
+	if err := json.Unmarshal(parametersBytes, &parameters); err != nil {
+		return nil, fmt.Errorf("error unmarshalling template parameters: %w", err)
+	}
+
+	principalId := ""
+	values := make(map[string]parameterValue, len(parameters))
+	for key, param := range parameters {
+		template, ok := param.(string)
+		if !ok {
+			values[key] = parameterValue{value: param}
+			continue
+		}
+
+		var envVarMapping []string
+		var mappingErr error
+		replaced, err := envsubst.Eval(template, func(name string) string {
+			if !slices.Contains(envVarMapping, name) {
+				envVarMapping = append(envVarMapping, name)
+			}
+
+			if name == environment.PrincipalIdEnvVarName {
+				if principalId == "" {
+					principalId, mappingErr = p.curPrincipal.CurrentPrincipalId(ctx)
+				}
+				return principalId
+			}
+
+			return p.env.Getenv(name)
+		})
+		if mappingErr != nil {
+			return nil, fmt.Errorf("fetching current principal id: %w", mappingErr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("substituting parameter '%s': %w", key, err)
+		}
+
+		values[key] = parameterValue{value: replaced, envVarMapping: envVarMapping}
+	}
+
+	return values, nil
+}
+
+// pulumiProject is the part of Pulumi.yaml azd relies on.
+// see https://www.pulumi.com/docs/iac/concepts/projects/project-file/
+type pulumiProject struct {
+	Name    string `yaml:"name"`
+	Backend struct {
+		URL string `yaml:"url"`
+	} `yaml:"backend"`
+	// Config values are either a default value, or a declaration with a type, default and secret flag.
+	Config map[string]any `yaml:"config"`
+	// Outputs are only declared by projects using the YAML runtime.
+	Outputs map[string]any `yaml:"outputs"`
+}
+
+// isSecret reports whether Pulumi.yaml declares the config key as secret.
+func (project *pulumiProject) isSecret(key string) bool {
+	declaration, ok := project.Config[key].(map[string]any)
+	if !ok {
+		return false
+	}
+
+	secret, _ := declaration["secret"].(bool)
+	return secret
+}
+
+func (p *PulumiProvider) readProject() (*pulumiProject, error) {
+	projectFilePath := filepath.Join(p.modulePath(), "Pulumi.yaml")
+	projectBytes, err := os.ReadFile(projectFilePath)
+	if errors.Is(err, os.ErrNotExist) {
+		// Pulumi.yml is also accepted by pulumi.
+		projectFilePath = filepath.Join(p.modulePath(), "Pulumi.yml")
+		projectBytes, err = os.ReadFile(projectFilePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading pulumi project file: %w", err)
+	}
+
+	var project pulumiProject
+	if err := yaml.Unmarshal(projectBytes, &project); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", projectFilePath, err)
+	}
+
+	return &project, nil
+}
+
+// pulumiPreviewOutput is a model type for the output of `pulumi preview --json`.
+type pulumiPreviewOutput struct {
+	Steps []pulumiPreviewStep `json:"steps"`
+}
+
+// pulumiPreviewStep is a model type for a step of `pulumi preview --json`, i.e. the operation on one resource.
+type pulumiPreviewStep struct {
+	Op           string                        `json:"op"`
+	URN          string                        `json:"urn"`
+	OldState     *pulumiResource               `json:"oldState"`
+	NewState     *pulumiResource               `json:"newState"`
+	DiffReasons  []string                      `json:"diffReasons"`
+	DetailedDiff map[string]pulumiPropertyDiff `json:"detailedDiff"`
+}
+
+// pulumiPropertyDiff is a model type for the change of one property of a resource. Kind is one of add, delete or
+// update, possibly suffixed by -replace.
+type pulumiPropertyDiff struct {
+	Kind string `json:"kind"`
+}
+
+// pulumiCheckpoint is a model type for the output of `pulumi stack export`.
+type pulumiCheckpoint struct {
+	Deployment struct {
+		Resources []pulumiResource `json:"resources"`
+	} `json:"deployment"`
+}
+
+// pulumiResource is a model type for a resource in the state of a stack. For the azure-native provider, ID is the
+// Azure resource id.
+type pulumiResource struct {
+	URN     string         `json:"urn"`
+	Custom  bool           `json:"custom"`
+	ID      string         `json:"id"`
+	Type    string         `json:"type"`
+	Inputs  map[string]any `json:"inputs"`
+	Outputs map[string]any `json:"outputs"`
+}
+
+// convertPreviewSteps converts the steps of a pulumi preview to the changes shared by all provider implementations.
+// The steps of pulumi internal resources, like the stack and providers, are omitted.
+func convertPreviewSteps(steps []pulumiPreviewStep) []*provisioning.DeploymentPreviewChange {
+	changes := []*provisioning.DeploymentPreviewChange{}
+	for _, step := range steps {
+		changeType, has := pulumiChangeTypes[step.Op]
+		if !has {
+			// the create-replacement and delete-replaced steps are part of a replace step.
+			continue
+		}
+
+		resource := step.NewState
+		if resource == nil {
+			resource = step.OldState
+		}
+		if resource == nil || strings.HasPrefix(resource.Type, "pulumi:") {
+			continue
+		}
+
+		change := &provisioning.DeploymentPreviewChange{
+			ChangeType:   changeType,
+			ResourceType: armResourceType(*resource, step.OldState),
+			Name:         resourceName(step.URN),
+		}
+		if step.OldState != nil {
+			change.ResourceId = provisioning.Resource{Id: step.OldState.ID}
+			change.Before = step.OldState.Outputs
+		}
+		if step.NewState != nil {
+			change.After = step.NewState.Inputs
+		}
+
+		for _, path := range slices.Sorted(maps.Keys(step.DetailedDiff)) {
+			change.Delta = append(change.Delta, provisioning.DeploymentPreviewPropertyChange{
+				ChangeType: propertyChangeType(step.DetailedDiff[path].Kind),
+				Path:       path,
+			})
+		}
+		if len(step.DetailedDiff) == 0 {
+			for _, path := range step.DiffReasons {
+				change.Delta = append(change.Delta, provisioning.DeploymentPreviewPropertyChange{
+					ChangeType: provisioning.PropertyChangeTypeModify,
+					Path:       path,
+				})
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+var pulumiChangeTypes = map[string]provisioning.ChangeType{
+	"create":  provisioning.ChangeTypeCreate,
+	"update":  provisioning.ChangeTypeModify,
+	"replace": provisioning.ChangeTypeModify,
+	"delete":  provisioning.ChangeTypeDelete,
+	"same":    provisioning.ChangeTypeNoChange,
+	"read":    provisioning.ChangeTypeIgnore,
+	"refresh": provisioning.ChangeTypeIgnore,
+	"import":  provisioning.ChangeTypeCreate,
+}
+
+func propertyChangeType(kind string) provisioning.PropertyChangeType {
+	switch strings.TrimSuffix(kind, "-replace") {
+	case "add":
+		return provisioning.PropertyChangeTypeCreate
+	case "delete":
+		return provisioning.PropertyChangeTypeDelete
+	default:
+		return provisioning.PropertyChangeTypeModify
+	}
+}
+
+// resourceName returns the name of a resource from its URN, urn:pulumi:<stack>::<project>::<type>::<name>, or an empty
+// string when urn is not a resource URN.
+func resourceName(urn string) string {
+	index := strings.LastIndex(urn, "::")
+	if index < 0 {
+		return ""
+	}
+
+	return urn[index+len("::"):]
+}
+
+// pulumiResourceTypes maps azure-native resource type tokens to the Azure resource types azd knows about.
+var pulumiResourceTypes = map[string]azapi.AzureResourceType{
+	"app:ContainerApp":                            azapi.AzureResourceTypeContainerApp,
+	"app:Job":                                     azapi.AzureResourceTypeContainerAppJob,
+	"app:ManagedEnvironment":                      azapi.AzureResourceTypeContainerAppEnvironment,
+	"apimanagement:ApiManagementService":          azapi.AzureResourceTypeApim,
+	"appconfiguration:ConfigurationStore":         azapi.AzureResourceTypeAppConfig,
+	"insights:Component":                          azapi.AzureResourceTypeAppInsightComponent,
+	"authorization:RoleAssignment":                azapi.AzureResourceTypeRoleAssignment,
+	"cache:Redis":                                 azapi.AzureResourceTypeCacheForRedis,
+	"cognitiveservices:Account":                   azapi.AzureResourceTypeCognitiveServiceAccount,
+	"cognitiveservices:Deployment":                azapi.AzureResourceTypeCognitiveServiceAccountDeployment,
+	"containerregistry:Registry":                  azapi.AzureResourceTypeContainerRegistry,
+	"containerservice:ManagedCluster":             azapi.AzureResourceTypeManagedCluster,
+	"dbformysql:Server":                           azapi.AzureResourceTypeMySqlServer,
+	"dbforpostgresql:Server":                      azapi.AzureResourceTypePostgreSqlServer,
+	"documentdb:DatabaseAccount":                  azapi.AzureResourceTypeCosmosDb,
+	"eventhub:Namespace":                          azapi.AzureResourceTypeEventHubsNamespace,
+	"keyvault:Vault":                              azapi.AzureResourceTypeKeyVault,
+	"network:PrivateEndpoint":                     azapi.AzureResourceTypePrivateEndpoint,
+	"network:VirtualNetwork":                      azapi.AzureResourceTypeVirtualNetwork,
+	"operationalinsights:Workspace":               azapi.AzureResourceTypeLogAnalyticsWorkspace,
+	"resources:ResourceGroup":                     azapi.AzureResourceTypeResourceGroup,
+	"search:Service":                              azapi.AzureResourceTypeSearchService,
+	"servicebus:Namespace":                        azapi.AzureResourceTypeServiceBusNamespace,
+	"sql:Server":                                  azapi.AzureResourceTypeSqlServer,
+	"storage:StorageAccount":                      azapi.AzureResourceTypeStorageAccount,
+	"web:AppServicePlan":                          azapi.AzureResourceTypeServicePlan,
+	"web:StaticSite":                              azapi.AzureResourceTypeStaticWebSite,
+	"web:WebApp":                                  azapi.AzureResourceTypeWebSite,
+	"web:WebAppSlot":                              azapi.AzureResourceTypeWebSiteSlot,
+	"machinelearningservices:Workspace":           azapi.AzureResourceTypeMachineLearningWorkspace,
+	"machinelearningservices:OnlineEndpoint":      azapi.AzureResourceTypeMachineLearningEndpoint,
+	"machinelearningservices:WorkspaceConnection": azapi.AzureResourceTypeMachineLearningConnection,
+}
+
+// armResourceType returns the Azure resource type of a resource. Existing resources carry their Azure resource id;
+// new ones are only known by their azure-native type token, e.g. azure-native:storage:StorageAccount or the
+// versioned azure-native:storage/v20230101:StorageAccount.
+func armResourceType(resource pulumiResource, oldState *pulumiResource) string {
+	if oldState != nil {
+		if id, err := arm.ParseResourceID(oldState.ID); err == nil {
+			return id.ResourceType.String()
+		}
+	}
+
+	provider, token, found := strings.Cut(resource.Type, ":")
+	if !found || provider != "azure-native" {
+		return resource.Type
+	}
+
+	module, typeName, found := strings.Cut(token, ":")
+	if !found {
+		return resource.Type
+	}
+	module, _, _ = strings.Cut(module, "/")
+
+	if resourceType, has := pulumiResourceTypes[module+":"+typeName]; has {
+		return string(resourceType)
+	}
+
+	return resource.Type
+}
+
+// collectAzureResources returns the Azure resources of a stack, i.e. the custom resources whose id is an Azure
+// resource id.
+func collectAzureResources(resources []pulumiResource) []provisioning.Resource {
+	azureResources := []provisioning.Resource{}
+	for _, resource := range resources {
+		if !resource.Custom || strings.HasPrefix(resource.Type, "pulumi:") {
+			continue
+		}
+
+		if _, err := arm.ParseResourceID(resource.ID); err != nil {
+			continue
+		}
+
+		azureResources = append(azureResources, provisioning.Resource{
+			Id: resource.ID,
+		})
+	}
+
+	return azureResources
+}
+
+// The stack of the environment, which has the name of the environment.
+func (p *PulumiProvider) stackName() string {
+	return p.env.Name()
+}
+
+// Gets the folder path to the pulumi project
+func (p *PulumiProvider) modulePath() string {
+	infraPath := p.options.Path
+	if strings.TrimSpace(infraPath) == "" {
+		infraPath = "infra"
+	}
+
+	return filepath.Join(p.projectPath, infraPath)
+}
+
+// Gets the path to the project parameters file path
+func (p *PulumiProvider) parametersTemplateFilePath() string {
+	parametersFilename := fmt.Sprintf("%s.parameters.json", p.options.Module)
+	return filepath.Join(p.modulePath(), parametersFilename)
+}
+
+// Gets the path to the staging .azure directory holding the local backend of the stack
+func (p *PulumiProvider) localBackendPath() string {
+	return filepath.Join(p.projectPath, ".azure", p.env.Name(), p.options.Path, ".pulumi")
+}
+
+// Gets the URL of the local backend, e.g. file:///home/user/project/.azure/dev/infra/.pulumi
+func (p *PulumiProvider) localBackendUrl() string {
+	return "file://" + filepath.ToSlash(p.localBackendPath())
+}
@@ -0,0 +1,476 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	_ "embed"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/account"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/prompt"
+	pulumiTools "github.com/azure/azure-dev/cli/azd/pkg/tools/pulumi"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockaccount"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockenv"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPulumiDeploy(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	config := prepareConfigMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	ranUp := false
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && args.Args[0] == "up"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ranUp = true
+		require.Contains(t, args.Args, "--yes")
+		require.Equal(t, "test-env", args.Args[slices.Index(args.Args, "--stack")+1])
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	deployResult, err := infraProvider.Deploy(*mockContext.Context)
+	require.NoError(t, err)
+	require.True(t, ranUp)
+
+	require.Equal(t, map[string]configSet{
+		"location":    {value: "westus2"},
+		"dbPassword":  {value: "p@ss", secret: true},
+		"principalId": {value: "11111111-1111-1111-1111-111111111111"},
+		"replicas":    {value: "2"},
+	}, config)
+
+	require.Equal(t, provisioning.InputParameter{
+		Type:  string(provisioning.ParameterTypeString),
+		Value: "westus2",
+	}, deployResult.Deployment.Parameters["location"])
+	require.Equal(t, string(provisioning.ParameterTypeNumber), deployResult.Deployment.Parameters["replicas"].Type)
+	require.Equal(t, provisioning.OutputParameter{
+		Type:  provisioning.ParameterTypeString,
+		Value: "rg-test-env",
+	}, deployResult.Deployment.Outputs["AZURE_RESOURCE_GROUP"])
+	require.Equal(t, provisioning.ParameterTypeNumber, deployResult.Deployment.Outputs["REPLICAS"].Type)
+	require.NotContains(t, deployResult.Deployment.Outputs, "UNSET")
+}
+
+func TestPulumiPreview(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	prepareConfigMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && args.Args[0] == "preview"
+	}).Respond(exec.NewRunResult(0, pulumiPreviewMockOutput, ""))
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	previewResult, err := infraProvider.Preview(*mockContext.Context)
+	require.NoError(t, err)
+
+	changes := previewResult.Preview.Properties.Changes
+	require.Len(t, changes, 2)
+
+	require.Equal(t, provisioning.ChangeTypeCreate, changes[0].ChangeType)
+	require.Equal(t, string(azapi.AzureResourceTypeStorageAccount), changes[0].ResourceType)
+	require.Equal(t, "sttodo", changes[0].Name)
+
+	require.Equal(t, provisioning.ChangeTypeModify, changes[1].ChangeType)
+	require.Equal(t, string(azapi.AzureResourceTypeResourceGroup), changes[1].ResourceType)
+	require.Equal(t, "rg", changes[1].Name)
+	require.Equal(t,
+		"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env", changes[1].ResourceId.Id)
+	require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+		{ChangeType: provisioning.PropertyChangeTypeCreate, Path: "tags"},
+	}, changes[1].Delta)
+}
+
+func TestPulumiDestroy(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	prepareConfigMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	var destroyArgs []string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && args.Args[0] == "destroy"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		destroyArgs = args.Args
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	destroyResult, err := infraProvider.Destroy(*mockContext.Context, provisioning.NewDestroyOptions(true, false))
+	require.NoError(t, err)
+
+	require.Contains(t, destroyArgs, "--yes")
+	require.ElementsMatch(t, []string{"AZURE_RESOURCE_GROUP", "REPLICAS"}, destroyResult.InvalidatedEnvKeys)
+}
+
+func TestPulumiState(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	prepareOutputMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack export")
+	}).Respond(exec.NewRunResult(0, pulumiExportMockOutput, ""))
+
+	infraProvider := createPulumiProvider(t, mockContext)
+	stateResult, err := infraProvider.State(*mockContext.Context, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, "rg-test-env", stateResult.State.Outputs["AZURE_RESOURCE_GROUP"].Value)
+	require.Equal(t, []provisioning.Resource{
+		{Id: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env"},
+	}, stateResult.State.Resources)
+}
+
+func TestPulumiParametersAndPlannedOutputs(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+
+	infraProvider := createPulumiProvider(t, mockContext)
+
+	parameters, err := infraProvider.Parameters(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, []provisioning.Parameter{
+		{
+			Name:               "dbPassword",
+			Secret:             true,
+			Value:              "p@ss",
+			EnvVarMapping:      []string{"DB_PASSWORD"},
+			UsingEnvVarMapping: true,
+		},
+		{
+			Name:               "location",
+			Value:              "westus2",
+			EnvVarMapping:      []string{"AZURE_LOCATION"},
+			UsingEnvVarMapping: true,
+		},
+		{
+			Name:               "principalId",
+			Value:              "11111111-1111-1111-1111-111111111111",
+			EnvVarMapping:      []string{"AZURE_PRINCIPAL_ID"},
+			UsingEnvVarMapping: true,
+		},
+		{
+			Name:  "replicas",
+			Value: float64(2),
+		},
+	}, parameters)
+
+	outputs, err := infraProvider.PlannedOutputs(*mockContext.Context)
+	require.NoError(t, err)
+	require.Equal(t, []provisioning.PlannedOutput{
+		{Name: "AZURE_PRINCIPAL_ID"},
+		{Name: "AZURE_RESOURCE_GROUP"},
+	}, outputs)
+}
+
+func TestPulumiBackend(t *testing.T) {
+	t.Run("Local", func(t *testing.T) {
+		t.Setenv("PULUMI_BACKEND_URL", "")
+		mockContext := mocks.NewMockContext(t.Context())
+		prepareGenericMocks(mockContext.CommandRunner)
+
+		var selectEnv []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			selectEnv = args.Env
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		infraProvider := createPulumiProvider(t, mockContext)
+		require.NoError(t, infraProvider.selectStack(*mockContext.Context))
+
+		backendPath := filepath.Join(infraProvider.projectPath, ".azure", "test-env", "infra", ".pulumi")
+		require.DirExists(t, backendPath)
+		require.Contains(t, selectEnv, "PULUMI_BACKEND_URL=file://"+filepath.ToSlash(backendPath))
+	})
+
+	t.Run("Project", func(t *testing.T) {
+		t.Setenv("PULUMI_BACKEND_URL", "")
+		mockContext := mocks.NewMockContext(t.Context())
+		prepareGenericMocks(mockContext.CommandRunner)
+
+		var selectEnv []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			selectEnv = args.Env
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		projectDir := t.TempDir()
+		writeProject(t, projectDir, "name: todo\nruntime: yaml\nbackend:\n  url: azblob://state\n")
+
+		infraProvider := initializePulumiProvider(t, mockContext, projectDir)
+		require.NoError(t, infraProvider.selectStack(*mockContext.Context))
+
+		require.NoDirExists(t, filepath.Join(projectDir, ".azure"))
+		require.False(t, slices.ContainsFunc(selectEnv, func(v string) bool {
+			return strings.HasPrefix(v, "PULUMI_BACKEND_URL=")
+		}))
+	})
+}
+
+func TestPulumiPassphrase(t *testing.T) {
+	passphraseEnv := func(provider *PulumiProvider, mockContext *mocks.MockContext) []string {
+		var selectEnv []string
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			selectEnv = args.Env
+			return exec.NewRunResult(0, "", ""), nil
+		})
+		require.NoError(t, provider.selectStack(*mockContext.Context))
+
+		var passphrases []string
+		for _, value := range selectEnv {
+			if strings.HasPrefix(value, passphraseEnvVarName+"=") {
+				passphrases = append(passphrases, value)
+			}
+		}
+		return passphrases
+	}
+
+	t.Run("Generated", func(t *testing.T) {
+		t.Setenv("PULUMI_BACKEND_URL", "")
+		unsetEnv(t, passphraseEnvVarName)
+		mockContext := mocks.NewMockContext(t.Context())
+		prepareGenericMocks(mockContext.CommandRunner)
+
+		infraProvider := createPulumiProvider(t, mockContext)
+		require.NotContains(t, infraProvider.env.Dotenv(), passphraseEnvVarName)
+		passphrase, has := infraProvider.env.Config.GetString(passphraseConfigKey)
+		require.True(t, has)
+		require.NotEmpty(t, passphrase)
+		// The config only holds a reference to the passphrase kept in the user vault.
+		pulumiConfig := infraProvider.env.Config.Raw()["infra"].(map[string]any)["pulumi"].(map[string]any)
+		require.Regexp(t, "^vault://", pulumiConfig["passphrase"])
+		require.Equal(t, []string{passphraseEnvVarName + "=" + passphrase}, passphraseEnv(infraProvider, mockContext))
+	})
+
+	t.Run("ExistingStack", func(t *testing.T) {
+		t.Setenv("PULUMI_BACKEND_URL", "")
+		unsetEnv(t, passphraseEnvVarName)
+		mockContext := mocks.NewMockContext(t.Context())
+		prepareGenericMocks(mockContext.CommandRunner)
+
+		// A stack created with an empty passphrase keeps using it.
+		projectDir := t.TempDir()
+		writeProject(t, projectDir, pulumiProjectFile)
+		require.NoError(t, os.MkdirAll(
+			filepath.Join(projectDir, ".azure", "test-env", "infra", ".pulumi", "stacks"), 0755))
+
+		infraProvider := initializePulumiProvider(t, mockContext, projectDir)
+		_, has := infraProvider.env.Config.GetString(passphraseConfigKey)
+		require.False(t, has)
+		require.Empty(t, passphraseEnv(infraProvider, mockContext))
+		require.True(t, slices.ContainsFunc(mockContext.Console.Output(), func(message string) bool {
+			return strings.Contains(message, "encrypted with an empty passphrase")
+		}))
+	})
+
+	t.Run("Configured", func(t *testing.T) {
+		t.Setenv("PULUMI_BACKEND_URL", "")
+		t.Setenv(passphraseEnvVarName, "user passphrase")
+		mockContext := mocks.NewMockContext(t.Context())
+		prepareGenericMocks(mockContext.CommandRunner)
+
+		infraProvider := createPulumiProvider(t, mockContext)
+		_, has := infraProvider.env.Config.GetString(passphraseConfigKey)
+		require.False(t, has)
+		require.Empty(t, passphraseEnv(infraProvider, mockContext))
+	})
+}
+
+// unsetEnv unsets the environment variable key for the duration of the test.
+func unsetEnv(t *testing.T, key string) {
+	t.Setenv(key, "")
+	require.NoError(t, os.Unsetenv(key))
+}
+
+func Test_resourceName(t *testing.T) {
+	require.Equal(t, "app", resourceName("urn:pulumi:dev::todo::azure-native:app:ContainerApp::app"))
+	require.Empty(t, resourceName("app"))
+}
+
+func Test_armResourceType(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource pulumiResource
+		oldState *pulumiResource
+		want     string
+	}{
+		{
+			name:     "Token",
+			resource: pulumiResource{Type: "azure-native:app:ContainerApp"},
+			want:     string(azapi.AzureResourceTypeContainerApp),
+		},
+		{
+			name:     "VersionedToken",
+			resource: pulumiResource{Type: "azure-native:keyvault/v20230701:Vault"},
+			want:     string(azapi.AzureResourceTypeKeyVault),
+		},
+		{
+			name:     "ResourceId",
+			resource: pulumiResource{Type: "azure-native:web:WebAppSiteContainer"},
+			oldState: &pulumiResource{
+				ID: "/subscriptions/sub/resourceGroups/rg/providers/Microsoft.Web/sites/app",
+			},
+			want: string(azapi.AzureResourceTypeWebSite),
+		},
+		{
+			name:     "Unknown",
+			resource: pulumiResource{Type: "random:index/randomString:RandomString"},
+			want:     "random:index/randomString:RandomString",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, armResourceType(tt.resource, tt.oldState))
+		})
+	}
+}
+
+//go:embed testdata/Pulumi.yaml
+var pulumiProjectFile string
+
+//go:embed testdata/main.parameters.json
+var pulumiParametersFile string
+
+//go:embed testdata/pulumi_preview_mock.json
+var pulumiPreviewMockOutput string
+
+//go:embed testdata/pulumi_export_mock.json
+var pulumiExportMockOutput string
+
+func writeProject(t *testing.T, projectDir string, project string) {
+	infraDir := filepath.Join(projectDir, "infra")
+	require.NoError(t, os.MkdirAll(infraDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(infraDir, "Pulumi.yaml"), []byte(project), 0600))
+	require.NoError(t,
+		os.WriteFile(filepath.Join(infraDir, "main.parameters.json"), []byte(pulumiParametersFile), 0600))
+}
+
+func createPulumiProvider(t *testing.T, mockContext *mocks.MockContext) *PulumiProvider {
+	projectDir := t.TempDir()
+	writeProject(t, projectDir, pulumiProjectFile)
+
+	return initializePulumiProvider(t, mockContext, projectDir)
+}
+
+func initializePulumiProvider(t *testing.T, mockContext *mocks.MockContext, projectDir string) *PulumiProvider {
+	options := provisioning.Options{
+		Provider: provisioning.Pulumi,
+	}
+
+	env := environment.NewWithValues("test-env", map[string]string{
+		"AZURE_LOCATION":        "westus2",
+		"AZURE_SUBSCRIPTION_ID": "00000000-0000-0000-0000-000000000000",
+		"DB_PASSWORD":           "p@ss",
+	})
+
+	resourceService := azapi.NewResourceService(mockContext.SubscriptionCredentialProvider, mockContext.ArmClientOptions)
+	accountManager := &mockaccount.MockAccountManager{
+		Subscriptions: []account.Subscription{
+			{
+				Id:   "00000000-0000-0000-0000-000000000000",
+				Name: "test",
+			},
+		},
+		Locations: []account.Location{
+			{
+				Name:                "location",
+				DisplayName:         "Test Location",
+				RegionalDisplayName: "(US) Test Location",
+			},
+		},
+	}
+
+	envManager := &mockenv.MockEnvManager{}
+	envManager.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	provider := NewPulumiProvider(
+		pulumiTools.NewCli(mockContext.CommandRunner),
+		envManager,
+		env,
+		mockContext.Console,
+		&mockCurrentPrincipal{},
+		prompt.NewDefaultPrompter(env, mockContext.Console, accountManager, nil, resourceService, cloud.AzurePublic()),
+	)
+
+	err := provider.Initialize(*mockContext.Context, projectDir, options)
+	require.NoError(t, err)
+
+	return provider.(*PulumiProvider)
+}
+
+func prepareGenericMocks(commandRunner *mockexec.MockCommandRunner) {
+	commandRunner.MockToolInPath("pulumi", nil)
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return strings.Contains(command, "pulumi version")
+	}).Respond(exec.NewRunResult(0, "v3.130.0\n", ""))
+
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack select")
+	}).Respond(exec.NewRunResult(0, "", ""))
+}
+
+// configSet records a `pulumi config set` call.
+type configSet struct {
+	value  string
+	secret bool
+}
+
+func prepareConfigMocks(commandRunner *mockexec.MockCommandRunner) map[string]configSet {
+	config := map[string]configSet{}
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "config set")
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		value, err := io.ReadAll(args.StdIn)
+		if err != nil {
+			return exec.RunResult{}, err
+		}
+
+		config[args.Args[2]] = configSet{value: string(value), secret: slices.Contains(args.Args, "--secret")}
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	return config
+}
+
+func prepareOutputMocks(commandRunner *mockexec.MockCommandRunner) {
+	commandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi" && strings.Contains(command, "stack output")
+	}).Respond(exec.NewRunResult(0, `{"AZURE_RESOURCE_GROUP": "rg-test-env", "REPLICAS": 2, "UNSET": null}`, ""))
+}
+
+type mockCurrentPrincipal struct{}
+
+func (m *mockCurrentPrincipal) CurrentPrincipalId(_ context.Context) (string, error) {
+	return "11111111-1111-1111-1111-111111111111", nil
+}
+
+func (m *mockCurrentPrincipal) CurrentPrincipalType(_ context.Context) (provisioning.PrincipalType, error) {
+	return provisioning.UserType, nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+// convertBillableChanges returns the billable changes of the managed resources of a terraform plan, including the
+// resources left unchanged.
+func convertBillableChanges(resourceChanges []terraformResourceChange) []*provisioning.BillableChange {
+	changes := []*provisioning.BillableChange{}
+	for _, resourceChange := range resourceChanges {
+		if resourceChange.Mode != terraformModeManaged || slices.Contains(resourceChange.Change.Actions, "read") {
+			continue
+		}
+
+		before, _ := resourceChange.Change.Before.(map[string]any)
+		after, _ := resourceChange.Change.After.(map[string]any)
+		resourceType := armResourceType(resourceChange.Type, before, after)
+
+		change := &provisioning.BillableChange{
+			Before: billableResource(resourceType, before),
+			After:  billableResource(resourceType, after),
+		}
+		if change.Before != nil || change.After != nil {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// billableResource reads the properties that determine the price of a resource from the values of its terraform
+// attributes, mapping the attributes of the azurerm provider to the SKU of the Azure resource.
+func billableResource(resourceType string, values map[string]any) *provisioning.BillableResource {
+	if values == nil {
+		return nil
+	}
+
+	resource := &provisioning.BillableResource{
+		Type:     resourceType,
+		Name:     stringAttribute(values, "name"),
+		Location: stringAttribute(values, "location"),
+		Sku:      stringAttribute(values, "sku_name", "sku", "sku_size", "sku_tier"),
+		Tier:     stringAttribute(values, "sku_tier", "tier"),
+	}
+
+	for _, name := range []string{"worker_count", "capacity"} {
+		if capacity, ok := values[name].(float64); ok {
+			resource.Capacity = to.Ptr(int(capacity))
+			break
+		}
+	}
+
+	switch {
+	case values["account_tier"] != nil && values["account_replication_type"] != nil:
+		// Storage accounts, e.g. Standard_LRS.
+		resource.Sku = stringAttribute(values, "account_tier") + "_" + stringAttribute(values, "account_replication_type")
+	case strings.HasSuffix(resourceType, "/flexibleServers"):
+		// Database flexible servers prefix the SKU with its tier, e.g. B_Standard_B1ms.
+		for prefix, tier := range flexibleServerTiers {
+			if sku, has := strings.CutPrefix(resource.Sku, prefix); has {
+				resource.Sku = sku
+				resource.Tier = tier
+				break
+			}
+		}
+	case strings.EqualFold(resourceType, "Microsoft.ApiManagement/service"):
+		// API Management suffixes the SKU with its capacity, e.g. Developer_1.
+		if sku, capacity, has := strings.Cut(resource.Sku, "_"); has {
+			if units, err := strconv.Atoi(capacity); err == nil {
+				resource.Sku = sku
+				resource.Capacity = &units
+			}
+		}
+	}
+
+	return resource
+}
+
+// flexibleServerTiers maps the prefixes of the SKUs of database flexible servers to their tier.
+var flexibleServerTiers = map[string]string{
+	"B_":  "Burstable",
+	"GP_": "GeneralPurpose",
+	"MO_": "MemoryOptimized",
+}
+
+// stringAttribute returns the value of the first of the attributes set to a string.
+func stringAttribute(values map[string]any, names ...string) string {
+	for _, name := range names {
+		if value, ok := values[name].(string); ok && value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_convertBillableChanges(t *testing.T) {
+	var plan terraformPlanOutput
+	require.NoError(t, json.Unmarshal([]byte(terraformPlanMockOutput), &plan))
+
+	changes := convertBillableChanges(plan.ResourceChanges)
+
+	// The data source is omitted.
+	require.Len(t, changes, 4)
+	require.Equal(t, &provisioning.BillableResource{
+		Type:     string(azapi.AzureResourceTypeResourceGroup),
+		Name:     "rg-test-env",
+		Location: "westus2",
+	}, changes[0].After)
+	require.Nil(t, changes[1].Before)
+	require.Equal(t, "Microsoft.App/containerApps", changes[3].Before.Type)
+	require.Nil(t, changes[3].After)
+}
+
+func Test_billableResource(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType azapi.AzureResourceType
+		values       map[string]any
+		want         *provisioning.BillableResource
+	}{
+		{
+			name:         "ServicePlan",
+			resourceType: azapi.AzureResourceTypeServicePlan,
+			values: map[string]any{
+				"name": "plan-api", "location": "eastus", "sku_name": "P1v3", "worker_count": float64(3),
+			},
+			want: &provisioning.BillableResource{
+				Type: string(azapi.AzureResourceTypeServicePlan), Name: "plan-api", Location: "eastus",
+				Sku: "P1v3", Capacity: to.Ptr(3),
+			},
+		},
+		{
+			name:         "StorageAccount",
+			resourceType: azapi.AzureResourceTypeStorageAccount,
+			values: map[string]any{
+				"name": "st", "location": "eastus", "account_tier": "Standard", "account_replication_type": "LRS",
+			},
+			want: &provisioning.BillableResource{
+				Type: string(azapi.AzureResourceTypeStorageAccount), Name: "st", Location: "eastus",
+				Sku: "Standard_LRS",
+			},
+		},
+		{
+			name:         "FlexibleServer",
+			resourceType: azapi.AzureResourceTypePostgreSqlServer,
+			values:       map[string]any{"name": "psql", "location": "eastus", "sku_name": "B_Standard_B1ms"},
+			want: &provisioning.BillableResource{
+				Type: string(azapi.AzureResourceTypePostgreSqlServer), Name: "psql", Location: "eastus",
+				Sku: "Standard_B1ms", Tier: "Burstable",
+			},
+		},
+		{
+			name:         "ApiManagement",
+			resourceType: azapi.AzureResourceTypeApim,
+			values:       map[string]any{"name": "apim", "location": "eastus", "sku_name": "Developer_1"},
+			want: &provisioning.BillableResource{
+				Type: string(azapi.AzureResourceTypeApim), Name: "apim", Location: "eastus",
+				Sku: "Developer", Capacity: to.Ptr(1),
+			},
+		},
+		{
+			name:         "Redis",
+			resourceType: azapi.AzureResourceTypeCacheForRedis,
+			values: map[string]any{
+				"name": "redis", "location": "eastus", "sku_name": "Basic", "family": "C", "capacity": float64(0),
+			},
+			want: &provisioning.BillableResource{
+				Type: string(azapi.AzureResourceTypeCacheForRedis), Name: "redis", Location: "eastus",
+				Sku: "Basic", Capacity: to.Ptr(0),
+			},
+		},
+		{
+			name:         "Nil",
+			resourceType: azapi.AzureResourceTypeKeyVault,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, billableResource(string(tt.resourceType), tt.values))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/pkg/input"
+)
+
+// Drift runs a refresh-only plan with the parameters of the last deployment, which reports the changes made to the
+// resources outside of terraform without considering the changes to the configuration.
+func (t *TerraformProvider) Drift(ctx context.Context) (*provisioning.DriftResult, error) {
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return nil, fmt.Errorf("reading backend config: %w", err)
+	}
+
+	t.console.ShowSpinner(ctx, "Detecting drift", input.Step)
+
+	initRes, err := t.init(ctx, isRemoteBackendConfig)
+	if err != nil {
+		return nil, fmt.Errorf("terraform init failed: %s , err: %w", initRes, err)
+	}
+
+	// The parameters file is written on each deployment, and only created here when there is none yet.
+	if err := t.ensureParametersFile(ctx); err != nil {
+		return nil, err
+	}
+
+	modulePath := t.modulePath()
+	planFilePath := t.driftPlanFilePath()
+	hasDrift, err := t.cli.PlanRefreshOnly(ctx, modulePath, planFilePath, t.createPlanArgs(isRemoteBackendConfig)...)
+	if err != nil {
+		return nil, fmt.Errorf("terraform plan failed: %w", err)
+	}
+
+	driftResult := &provisioning.DriftResult{
+		Changes: []*provisioning.DeploymentPreviewChange{},
+	}
+	if !hasDrift {
+		return driftResult, nil
+	}
+
+	runResult, err := t.cli.Show(ctx, modulePath, planFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("showing terraform plan: %w", err)
+	}
+
+	var planOutput terraformPlanOutput
+	if err := json.Unmarshal([]byte(runResult), &planOutput); err != nil {
+		return nil, fmt.Errorf("reading terraform plan: %w", err)
+	}
+
+	restoringChanges := make([]terraformResourceChange, len(planOutput.ResourceDrift))
+	for i, resourceDrift := range planOutput.ResourceDrift {
+		restoringChanges[i] = restoringChange(resourceDrift)
+	}
+
+	driftResult.Changes = convertResourceChanges(restoringChanges)
+	return driftResult, nil
+}
+
+// restoringChange reverses a drift, which goes from the last applied state to the remote object, into the change that
+// applying the configuration again makes: a resource deleted outside of terraform is created again.
+func restoringChange(resourceDrift terraformResourceChange) terraformResourceChange {
+	actions := make([]string, len(resourceDrift.Change.Actions))
+	for i, action := range resourceDrift.Change.Actions {
+		switch action {
+		case "delete":
+			actions[i] = "create"
+		case "create":
+			actions[i] = "delete"
+		default:
+			actions[i] = action
+		}
+	}
+
+	restoring := resourceDrift
+	restoring.Change = terraformChange{
+		Actions:         actions,
+		Before:          resourceDrift.Change.After,
+		After:           resourceDrift.Change.Before,
+		BeforeSensitive: resourceDrift.Change.AfterSensitive,
+		AfterSensitive:  resourceDrift.Change.BeforeSensitive,
+	}
+	return restoring
+}
+
+// Gets the path to the staging .azure refresh-only plan file, kept apart from the plan file applied on deployment.
+func (t *TerraformProvider) driftPlanFilePath() string {
+	planFilename := fmt.Sprintf("%s.drift.tfplan", t.options.Module)
+	return filepath.Join(t.projectPath, ".azure", t.env.Name(), t.options.Path, planFilename)
+}
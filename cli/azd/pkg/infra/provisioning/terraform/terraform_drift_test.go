@@ -0,0 +1,67 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/terraform_drift_mock.json
+var terraformDriftMockOutput string
+
+func TestTerraformDrift(t *testing.T) {
+	skipIfTerraformNotInstalled(t)
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "-refresh-only")
+	}).Respond(exec.NewRunResult(2, "", ""))
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "show") && strings.Contains(command, ".drift.tfplan")
+	}).Respond(exec.NewRunResult(0, terraformDriftMockOutput, ""))
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	driftResult, err := infraProvider.Drift(*mockContext.Context)
+
+	require.NoError(t, err)
+	require.Len(t, driftResult.Changes, 2)
+}
+
+func Test_restoringChange(t *testing.T) {
+	var planOutput terraformPlanOutput
+	require.NoError(t, json.Unmarshal([]byte(terraformDriftMockOutput), &planOutput))
+
+	restoringChanges := make([]terraformResourceChange, len(planOutput.ResourceDrift))
+	for i, resourceDrift := range planOutput.ResourceDrift {
+		restoringChanges[i] = restoringChange(resourceDrift)
+	}
+	changes := convertResourceChanges(restoringChanges)
+	require.Len(t, changes, 2)
+
+	t.Run("Modified", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeModify, changes[0].ChangeType)
+		require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+			{ChangeType: provisioning.PropertyChangeTypeDelete, Path: "tags.owner", Before: "portal"},
+		}, changes[0].Delta)
+	})
+
+	t.Run("Deleted", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeCreate, changes[1].ChangeType)
+		require.Equal(t, string(azapi.AzureResourceTypeKeyVault), changes[1].ResourceType)
+		require.Equal(t, "kv-test-env", changes[1].Name)
+		require.Equal(t, planOutput.ResourceDrift[1].Change.Before.(map[string]any)["id"], changes[1].ResourceId.Id)
+	})
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// ImportsFileName is the name of the file holding the import blocks written by [TerraformProvider.WriteImports].
+const ImportsFileName = "imports.tf"
+
+// ResourceImport is an existing Azure resource matched to the address of a resource of the terraform configuration.
+type ResourceImport struct {
+	Address    string `json:"address"`
+	ResourceId string `json:"resourceId"`
+}
+
+// ImportPlan lists the existing Azure resources to import into the terraform state.
+type ImportPlan struct {
+	Imports []ResourceImport `json:"imports"`
+	// Unmatched are the resource ids that no resource of the configuration matches.
+	Unmatched []string `json:"unmatched"`
+}
+
+// PlanImports matches existing Azure resources to the resources the terraform configuration would create. A
+// resource matches when it has the same Azure resource type and name and, when the configuration sets one, the same
+// resource group.
+func (t *TerraformProvider) PlanImports(ctx context.Context, resourceIds []string) (*ImportPlan, error) {
+	_, deploymentDetails, err := t.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	runResult, err := t.cli.Show(ctx, t.modulePath(), deploymentDetails.PlanFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("showing terraform plan: %w", err)
+	}
+
+	var planOutput terraformPlanOutput
+	if err := json.Unmarshal([]byte(runResult), &planOutput); err != nil {
+		return nil, fmt.Errorf("reading terraform plan: %w", err)
+	}
+
+	return matchImports(planOutput.ResourceChanges, resourceIds), nil
+}
+
+// matchImports matches each resource id to the first resource to be created with the same type and name.
+func matchImports(resourceChanges []terraformResourceChange, resourceIds []string) *ImportPlan {
+	plan := &ImportPlan{
+		Imports:   []ResourceImport{},
+		Unmatched: []string{},
+	}
+
+	matched := map[string]bool{}
+	for _, resourceId := range resourceIds {
+		id, err := arm.ParseResourceID(resourceId)
+		if err != nil {
+			log.Printf("ignoring resource '%s' with an invalid id: %v", resourceId, err)
+			plan.Unmatched = append(plan.Unmatched, resourceId)
+			continue
+		}
+
+		index := slices.IndexFunc(resourceChanges, func(change terraformResourceChange) bool {
+			if change.Mode != terraformModeManaged || matched[change.Address] ||
+				!slices.Equal(change.Change.Actions, []string{"create"}) {
+				return false
+			}
+
+			after, _ := change.Change.After.(map[string]any)
+			name, _ := after["name"].(string)
+			if !strings.EqualFold(name, id.Name) ||
+				!strings.EqualFold(armResourceType(change.Type, nil, after), id.ResourceType.String()) {
+				return false
+			}
+
+			resourceGroup, has := after["resource_group_name"].(string)
+			return !has || strings.EqualFold(resourceGroup, id.ResourceGroupName)
+		})
+		if index < 0 {
+			plan.Unmatched = append(plan.Unmatched, resourceId)
+			continue
+		}
+
+		matched[resourceChanges[index].Address] = true
+		plan.Imports = append(plan.Imports, ResourceImport{
+			Address:    resourceChanges[index].Address,
+			ResourceId: resourceId,
+		})
+	}
+
+	return plan
+}
+
+// WriteImports writes the import blocks of the plan to imports.tf in the module directory, for the next terraform
+// apply to import the resources. It returns the path of the file.
+func (t *TerraformProvider) WriteImports(plan *ImportPlan) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("# Generated by azd infra import. The resources are imported into the terraform state the next\n")
+	sb.WriteString("# time the infrastructure is provisioned, after which this file can be removed.\n")
+	for _, resourceImport := range plan.Imports {
+		sb.WriteString("\nimport {\n")
+		sb.WriteString(fmt.Sprintf("  to = %s\n", resourceImport.Address))
+		sb.WriteString(fmt.Sprintf("  id = %s\n", strconv.Quote(resourceImport.ResourceId)))
+		sb.WriteString("}\n")
+	}
+
+	importsFilePath := t.ImportsFilePath()
+	log.Printf("Writing import blocks to: %s", importsFilePath)
+	if err := os.WriteFile(importsFilePath, []byte(sb.String()), osutil.PermissionFile); err != nil {
+		return "", fmt.Errorf("writing %s: %w", ImportsFileName, err)
+	}
+
+	return importsFilePath, nil
+}
+
+// ImportsFilePath returns the path of the file [TerraformProvider.WriteImports] writes.
+func (t *TerraformProvider) ImportsFilePath() string {
+	return filepath.Join(t.modulePath(), ImportsFileName)
+}
+
+// Import imports the resources of the plan into the terraform state through terraform import.
+func (t *TerraformProvider) Import(ctx context.Context, plan *ImportPlan) error {
+	isRemoteBackendConfig, err := t.isRemoteBackendConfig()
+	if err != nil {
+		return fmt.Errorf("reading backend config: %w", err)
+	}
+
+	if err := t.ensureParametersFile(ctx); err != nil {
+		return err
+	}
+
+	args := []string{fmt.Sprintf("-var-file=%s", t.parametersFilePath())}
+	if !isRemoteBackendConfig {
+		args = append(args, fmt.Sprintf("-state=%s", t.localStateFilePath()))
+	}
+
+	for _, resourceImport := range plan.Imports {
+		_, err := t.cli.Import(ctx, t.modulePath(), resourceImport.Address, resourceImport.ResourceId, args...)
+		if err != nil {
+			return fmt.Errorf("importing %s: %w", resourceImport.Address, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_matchImports(t *testing.T) {
+	resourceChanges := []terraformResourceChange{
+		{
+			Address: "azurerm_resource_group.rg",
+			Mode:    terraformModeManaged,
+			Type:    "azurerm_resource_group",
+			Change: terraformChange{
+				Actions: []string{"create"},
+				After:   map[string]any{"name": "rg-test-env"},
+			},
+		},
+		{
+			Address: "azurerm_storage_account.st",
+			Mode:    terraformModeManaged,
+			Type:    "azurerm_storage_account",
+			Change: terraformChange{
+				Actions: []string{"create"},
+				After:   map[string]any{"name": "sttestenv", "resource_group_name": "rg-test-env"},
+			},
+		},
+		{
+			Address: "azurerm_storage_account.other",
+			Mode:    terraformModeManaged,
+			Type:    "azurerm_storage_account",
+			Change: terraformChange{
+				Actions: []string{"create"},
+				After:   map[string]any{"name": "stother", "resource_group_name": "rg-other"},
+			},
+		},
+		{
+			Address: "azapi_resource.app",
+			Mode:    terraformModeManaged,
+			Type:    "azapi_resource",
+			Change: terraformChange{
+				Actions: []string{"create"},
+				After:   map[string]any{"name": "ca-api", "type": "Microsoft.App/containerApps@2024-03-01"},
+			},
+		},
+		{
+			Address: "azurerm_key_vault.kv",
+			Mode:    terraformModeManaged,
+			Type:    "azurerm_key_vault",
+			Change: terraformChange{
+				Actions: []string{"no-op"},
+				After:   map[string]any{"name": "kv-test-env"},
+			},
+		},
+	}
+
+	resourceGroupId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env"
+	storageId := resourceGroupId + "/providers/Microsoft.Storage/storageAccounts/STTESTENV"
+	otherStorageId := resourceGroupId + "/providers/Microsoft.Storage/storageAccounts/stother"
+	appId := resourceGroupId + "/providers/Microsoft.App/containerApps/ca-api"
+	keyVaultId := resourceGroupId + "/providers/Microsoft.KeyVault/vaults/kv-test-env"
+
+	plan := matchImports(
+		resourceChanges, []string{resourceGroupId, storageId, otherStorageId, appId, keyVaultId, "not-an-id"})
+
+	require.Equal(t, []ResourceImport{
+		{Address: "azurerm_resource_group.rg", ResourceId: resourceGroupId},
+		{Address: "azurerm_storage_account.st", ResourceId: storageId},
+		{Address: "azapi_resource.app", ResourceId: appId},
+	}, plan.Imports)
+	require.Equal(t, []string{otherStorageId, keyVaultId, "not-an-id"}, plan.Unmatched)
+}
+
+func Test_matchImports_PlanFromShow(t *testing.T) {
+	var planOutput terraformPlanOutput
+	require.NoError(t, json.Unmarshal([]byte(terraformPlanMockOutput), &planOutput))
+
+	storageId := "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env" +
+		"/providers/Microsoft.Storage/storageAccounts/sttestenv"
+
+	plan := matchImports(planOutput.ResourceChanges, []string{storageId})
+	require.Equal(t, []ResourceImport{{Address: "azurerm_storage_account.st", ResourceId: storageId}}, plan.Imports)
+	require.Empty(t, plan.Unmatched)
+}
+
+func TestTerraformWriteImports(t *testing.T) {
+	projectPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(projectPath, "infra"), 0755))
+
+	provider := &TerraformProvider{
+		projectPath: projectPath,
+		options:     provisioning.Options{Path: "infra"},
+	}
+
+	importsFilePath, err := provider.WriteImports(&ImportPlan{
+		Imports: []ResourceImport{
+			{
+				Address:    "azurerm_resource_group.rg",
+				ResourceId: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env",
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(projectPath, "infra", ImportsFileName), importsFilePath)
+
+	contents, err := os.ReadFile(importsFilePath)
+	require.NoError(t, err)
+	require.Contains(t, string(contents), "import {\n"+
+		"  to = azurerm_resource_group.rg\n"+
+		"  id = \"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env\"\n"+
+		"}\n")
+}
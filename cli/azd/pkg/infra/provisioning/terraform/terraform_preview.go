@@ -0,0 +1,335 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+)
+
+const (
+	// terraformUnknownValue is displayed for the values only known once the plan is applied.
+	terraformUnknownValue = "(known after apply)"
+	// terraformSensitiveValue is displayed instead of sensitive values.
+	terraformSensitiveValue = "(sensitive value)"
+)
+
+// terraformPlanOutput is a model type for the output of `terraform show -json` for a plan file.
+// see https://developer.hashicorp.com/terraform/internals/json-format#plan-representation for more information on
+// the shape of the JSON data
+type terraformPlanOutput struct {
+	FormatVersion   string                    `json:"format_version"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+	// ResourceDrift are the changes made to the remote objects since they were last applied.
+	ResourceDrift []terraformResourceChange `json:"resource_drift"`
+}
+
+// terraformResourceChange is a model type for the planned change of one resource.
+type terraformResourceChange struct {
+	Address      string          `json:"address"`
+	Mode         string          `json:"mode"`
+	Type         string          `json:"type"`
+	Name         string          `json:"name"`
+	ProviderName string          `json:"provider_name"`
+	Change       terraformChange `json:"change"`
+}
+
+// terraformChange is a model type for the change-representation of a resource. AfterUnknown, BeforeSensitive and
+// AfterSensitive mirror the shape of the values, with true for the values that are unknown or sensitive, or are
+// true themselves when the whole value is.
+type terraformChange struct {
+	Actions         []string `json:"actions"`
+	Before          any      `json:"before"`
+	After           any      `json:"after"`
+	AfterUnknown    any      `json:"after_unknown"`
+	BeforeSensitive any      `json:"before_sensitive"`
+	AfterSensitive  any      `json:"after_sensitive"`
+}
+
+// convertResourceChanges converts the resource changes of a terraform plan to the changes shared by all provider
+// implementations. Data sources are omitted, as they are only read.
+func convertResourceChanges(resourceChanges []terraformResourceChange) []*provisioning.DeploymentPreviewChange {
+	changes := []*provisioning.DeploymentPreviewChange{}
+	for _, resourceChange := range resourceChanges {
+		if resourceChange.Mode != terraformModeManaged {
+			continue
+		}
+
+		before, _ := resourceChange.Change.Before.(map[string]any)
+		after, _ := resourceChange.Change.After.(map[string]any)
+
+		change := &provisioning.DeploymentPreviewChange{
+			ChangeType:   changeTypeForActions(resourceChange.Change.Actions),
+			ResourceType: armResourceType(resourceChange.Type, before, after),
+			Name:         resourceChange.Name,
+		}
+
+		if name, ok := after["name"].(string); ok && name != "" {
+			change.Name = name
+		} else if name, ok := before["name"].(string); ok && name != "" {
+			change.Name = name
+		}
+
+		if id, ok := before["id"].(string); ok {
+			change.ResourceId = provisioning.Resource{Id: id}
+		} else if id, ok := after["id"].(string); ok {
+			change.ResourceId = provisioning.Resource{Id: id}
+		}
+
+		if change.ChangeType == provisioning.ChangeTypeModify {
+			change.Delta = propertyChanges(
+				"",
+				resourceChange.Change.Before,
+				resourceChange.Change.After,
+				resourceChange.Change.AfterUnknown,
+				resourceChange.Change.BeforeSensitive,
+				resourceChange.Change.AfterSensitive,
+			)
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// changeTypeForActions maps the actions of a terraform change to a change type. Replacing a resource, by deleting
+// and creating it in either order, is reported as a modification.
+func changeTypeForActions(actions []string) provisioning.ChangeType {
+	switch {
+	case slices.Contains(actions, "create") && slices.Contains(actions, "delete"):
+		return provisioning.ChangeTypeModify
+	case slices.Contains(actions, "create"):
+		return provisioning.ChangeTypeCreate
+	case slices.Contains(actions, "delete"):
+		return provisioning.ChangeTypeDelete
+	case slices.Contains(actions, "update"):
+		return provisioning.ChangeTypeModify
+	case slices.Contains(actions, "read"):
+		return provisioning.ChangeTypeIgnore
+	default:
+		return provisioning.ChangeTypeNoChange
+	}
+}
+
+// propertyChanges returns the changes between the before and after values of the property at path. Nested objects
+// are compared property by property, with paths like tags.env; lists are compared as a whole. Sensitive values are
+// masked, including the ones nested in lists and blocks.
+func propertyChanges(
+	path string,
+	before any,
+	after any,
+	afterUnknown any,
+	beforeSensitive any,
+	afterSensitive any,
+) []provisioning.DeploymentPreviewPropertyChange {
+	beforeMap, beforeIsMap := before.(map[string]any)
+	afterMap, afterIsMap := after.(map[string]any)
+	unknownMap, _ := afterUnknown.(map[string]any)
+
+	if (beforeIsMap || before == nil) && (afterIsMap || after == nil) && (beforeIsMap || afterIsMap) &&
+		!isMarked(afterUnknown) && !isMarked(beforeSensitive) && !isMarked(afterSensitive) {
+		beforeSensitiveMap, _ := beforeSensitive.(map[string]any)
+		afterSensitiveMap, _ := afterSensitive.(map[string]any)
+
+		keys := slices.Concat(
+			slices.Collect(maps.Keys(beforeMap)),
+			slices.Collect(maps.Keys(afterMap)),
+			slices.Collect(maps.Keys(unknownMap)),
+		)
+		slices.Sort(keys)
+
+		var changes []provisioning.DeploymentPreviewPropertyChange
+		for _, key := range slices.Compact(keys) {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			changes = append(changes, propertyChanges(
+				childPath,
+				beforeMap[key],
+				afterMap[key],
+				unknownMap[key],
+				beforeSensitiveMap[key],
+				afterSensitiveMap[key],
+			)...)
+		}
+		return changes
+	}
+
+	unknown := isMarked(afterUnknown)
+	if !unknown && reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	change := provisioning.DeploymentPreviewPropertyChange{
+		Path:   path,
+		Before: before,
+		After:  after,
+	}
+
+	switch {
+	case unknown:
+		change.After = terraformUnknownValue
+		if before == nil {
+			change.ChangeType = provisioning.PropertyChangeTypeCreate
+		} else {
+			change.ChangeType = provisioning.PropertyChangeTypeModify
+		}
+	case before == nil:
+		change.ChangeType = provisioning.PropertyChangeTypeCreate
+	case after == nil:
+		change.ChangeType = provisioning.PropertyChangeTypeDelete
+	default:
+		change.ChangeType = provisioning.PropertyChangeTypeModify
+	}
+
+	change.Before = maskSensitive(change.Before, beforeSensitive)
+	if !unknown {
+		change.After = maskSensitive(change.After, afterSensitive)
+	}
+
+	return []provisioning.DeploymentPreviewPropertyChange{change}
+}
+
+// maskSensitive returns value with the parts marked by marker, a value of before_sensitive or after_sensitive,
+// replaced by terraformSensitiveValue. The marker mirrors the shape of value, where values without a marker are not
+// sensitive; any other marker than false masks the whole value it applies to.
+func maskSensitive(value any, marker any) any {
+	switch marker := marker.(type) {
+	case nil:
+		return value
+	case bool:
+		if marker {
+			return terraformSensitiveValue
+		}
+
+		return value
+	case map[string]any:
+		values, ok := value.(map[string]any)
+		if !ok {
+			if len(marker) == 0 || value == nil {
+				return value
+			}
+
+			return terraformSensitiveValue
+		}
+
+		masked := make(map[string]any, len(values))
+		for key, child := range values {
+			masked[key] = maskSensitive(child, marker[key])
+		}
+
+		return masked
+	case []any:
+		values, ok := value.([]any)
+		if !ok {
+			if len(marker) == 0 || value == nil {
+				return value
+			}
+
+			return terraformSensitiveValue
+		}
+
+		masked := make([]any, len(values))
+		for i, child := range values {
+			var childMarker any
+			if i < len(marker) {
+				childMarker = marker[i]
+			}
+
+			masked[i] = maskSensitive(child, childMarker)
+		}
+
+		return masked
+	default:
+		return terraformSensitiveValue
+	}
+}
+
+// isMarked reports whether a value of after_unknown, before_sensitive or after_sensitive marks the whole value.
+func isMarked(marker any) bool {
+	b, ok := marker.(bool)
+	return ok && b
+}
+
+// azurermResourceTypes maps azurerm resource types to the Azure resource types azd knows about.
+var azurermResourceTypes = map[string]azapi.AzureResourceType{
+	"azurerm_api_management":                             azapi.AzureResourceTypeApim,
+	"azurerm_app_configuration":                          azapi.AzureResourceTypeAppConfig,
+	"azurerm_application_insights":                       azapi.AzureResourceTypeAppInsightComponent,
+	"azurerm_automation_account":                         azapi.AzureResourceTypeAutomationAccount,
+	"azurerm_cognitive_account":                          azapi.AzureResourceTypeCognitiveServiceAccount,
+	"azurerm_cognitive_deployment":                       azapi.AzureResourceTypeCognitiveServiceAccountDeployment,
+	"azurerm_container_app":                              azapi.AzureResourceTypeContainerApp,
+	"azurerm_container_app_environment":                  azapi.AzureResourceTypeContainerAppEnvironment,
+	"azurerm_container_app_job":                          azapi.AzureResourceTypeContainerAppJob,
+	"azurerm_container_registry":                         azapi.AzureResourceTypeContainerRegistry,
+	"azurerm_cosmosdb_account":                           azapi.AzureResourceTypeCosmosDb,
+	"azurerm_eventhub_namespace":                         azapi.AzureResourceTypeEventHubsNamespace,
+	"azurerm_key_vault":                                  azapi.AzureResourceTypeKeyVault,
+	"azurerm_kubernetes_cluster":                         azapi.AzureResourceTypeManagedCluster,
+	"azurerm_linux_function_app":                         azapi.AzureResourceTypeWebSite,
+	"azurerm_linux_web_app":                              azapi.AzureResourceTypeWebSite,
+	"azurerm_log_analytics_workspace":                    azapi.AzureResourceTypeLogAnalyticsWorkspace,
+	"azurerm_mysql_flexible_server":                      azapi.AzureResourceTypeMySqlServer,
+	"azurerm_portal_dashboard":                           azapi.AzureResourceTypePortalDashboard,
+	"azurerm_postgresql_flexible_server":                 azapi.AzureResourceTypePostgreSqlServer,
+	"azurerm_private_endpoint":                           azapi.AzureResourceTypePrivateEndpoint,
+	"azurerm_redis_cache":                                azapi.AzureResourceTypeCacheForRedis,
+	"azurerm_resource_group":                             azapi.AzureResourceTypeResourceGroup,
+	"azurerm_role_assignment":                            azapi.AzureResourceTypeRoleAssignment,
+	"azurerm_search_service":                             azapi.AzureResourceTypeSearchService,
+	"azurerm_service_plan":                               azapi.AzureResourceTypeServicePlan,
+	"azurerm_servicebus_namespace":                       azapi.AzureResourceTypeServiceBusNamespace,
+	"azurerm_mssql_server":                               azapi.AzureResourceTypeSqlServer,
+	"azurerm_static_web_app":                             azapi.AzureResourceTypeStaticWebSite,
+	"azurerm_storage_account":                            azapi.AzureResourceTypeStorageAccount,
+	"azurerm_virtual_network":                            azapi.AzureResourceTypeVirtualNetwork,
+	"azurerm_windows_function_app":                       azapi.AzureResourceTypeWebSite,
+	"azurerm_windows_web_app":                            azapi.AzureResourceTypeWebSite,
+	"azurerm_linux_web_app_slot":                         azapi.AzureResourceTypeWebSiteSlot,
+	"azurerm_windows_web_app_slot":                       azapi.AzureResourceTypeWebSiteSlot,
+	"azurerm_machine_learning_workspace":                 azapi.AzureResourceTypeMachineLearningWorkspace,
+	"azurerm_kubernetes_cluster_node_pool":               azapi.AzureResourceTypeAgentPool,
+	"azurerm_dev_center":                                 azapi.AzureResourceTypeDevCenter,
+	"azurerm_dev_center_project":                         azapi.AzureResourceTypeDevCenterProject,
+	"azurerm_load_test":                                  azapi.AzureResourceTypeLoadTest,
+	"azurerm_cdn_frontdoor_profile":                      azapi.AzureResourceTypeCDNProfile,
+	"azurerm_redis_enterprise_cluster":                   azapi.AzureResourceTypeRedisEnterprise,
+	"azurerm_key_vault_managed_hardware_security_module": azapi.AzureResourceTypeManagedHSM,
+}
+
+// armResourceType returns the Azure resource type of a terraform resource. Existing resources carry their Azure
+// resource id, azapi resources declare their type, and other azurerm resources are looked up by their type.
+func armResourceType(terraformType string, before map[string]any, after map[string]any) string {
+	if id, ok := before["id"].(string); ok {
+		if resourceId, err := arm.ParseResourceID(id); err == nil {
+			return resourceId.ResourceType.String()
+		}
+	}
+
+	if strings.HasPrefix(terraformType, "azapi_") {
+		// e.g. Microsoft.App/containerApps@2024-03-01
+		for _, values := range []map[string]any{after, before} {
+			if typeAndVersion, ok := values["type"].(string); ok && typeAndVersion != "" {
+				resourceType, _, _ := strings.Cut(typeAndVersion, "@")
+				return resourceType
+			}
+		}
+	}
+
+	if resourceType, has := azurermResourceTypes[terraformType]; has {
+		return string(resourceType)
+	}
+
+	return terraformType
+}
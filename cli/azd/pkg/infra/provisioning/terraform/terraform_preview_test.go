@@ -0,0 +1,170 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package terraform
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/terraform_plan_mock.json
+var terraformPlanMockOutput string
+
+func TestTerraformPreview(t *testing.T) {
+	skipIfTerraformNotInstalled(t)
+	mockContext := mocks.NewMockContext(t.Context())
+	prepareGenericMocks(mockContext.CommandRunner)
+	preparePlanningMocks(mockContext.CommandRunner)
+
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "terraform" && strings.Contains(command, "show") && strings.Contains(command, ".tfplan")
+	}).Respond(exec.RunResult{
+		Stdout: terraformPlanMockOutput,
+		Stderr: "",
+	})
+
+	infraProvider := createTerraformProvider(t, mockContext)
+	previewResult, err := infraProvider.Preview(*mockContext.Context)
+
+	require.NoError(t, err)
+	require.Len(t, previewResult.Preview.Properties.Changes, 4)
+}
+
+func Test_convertResourceChanges(t *testing.T) {
+	var plan terraformPlanOutput
+	require.NoError(t, json.Unmarshal([]byte(terraformPlanMockOutput), &plan))
+
+	changes := convertResourceChanges(plan.ResourceChanges)
+	require.Len(t, changes, 4)
+
+	t.Run("Update", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeModify, changes[0].ChangeType)
+		require.Equal(t, string(azapi.AzureResourceTypeResourceGroup), changes[0].ResourceType)
+		require.Equal(t, "rg-test-env", changes[0].Name)
+		require.Equal(t,
+			"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg-test-env", changes[0].ResourceId.Id)
+		require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+			{ChangeType: provisioning.PropertyChangeTypeCreate, Path: "tags.owner", After: "platform"},
+		}, changes[0].Delta)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeCreate, changes[1].ChangeType)
+		require.Equal(t, string(azapi.AzureResourceTypeStorageAccount), changes[1].ResourceType)
+		require.Equal(t, "sttestenv", changes[1].Name)
+		require.Empty(t, changes[1].Delta)
+	})
+
+	t.Run("ReplaceMasksSensitiveValues", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeModify, changes[2].ChangeType)
+		require.Equal(t, "azurerm_key_vault_secret", changes[2].ResourceType)
+		require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+			{ChangeType: provisioning.PropertyChangeTypeModify, Path: "id",
+				Before: "https://kv-test-env.vault.azure.net/secrets/db/1", After: terraformUnknownValue},
+			{ChangeType: provisioning.PropertyChangeTypeModify, Path: "value",
+				Before: terraformSensitiveValue, After: terraformSensitiveValue},
+			{ChangeType: provisioning.PropertyChangeTypeModify, Path: "version",
+				Before: "1", After: terraformUnknownValue},
+		}, changes[2].Delta)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.Equal(t, provisioning.ChangeTypeDelete, changes[3].ChangeType)
+		require.Equal(t, string(azapi.AzureResourceTypeContainerApp), changes[3].ResourceType)
+		require.Equal(t, "ca-api", changes[3].Name)
+	})
+}
+
+func Test_convertResourceChanges_NestedSensitiveValues(t *testing.T) {
+	// a web app whose site_config block and connection_string list hold sensitive values
+	var resourceChanges []terraformResourceChange
+	require.NoError(t, json.Unmarshal([]byte(`[{
+		"address": "azurerm_linux_web_app.web",
+		"mode": "managed",
+		"type": "azurerm_linux_web_app",
+		"name": "web",
+		"change": {
+			"actions": ["update"],
+			"before": {
+				"name": "app-test-env",
+				"site_config": [{"always_on": false, "docker_registry_password": "old-registry-secret"}],
+				"connection_string": [
+					{"name": "db", "type": "SQLAzure", "value": "Server=db;Password=old-db-secret"}
+				],
+				"auth_settings": {"enabled": true, "client_secret": "old-client-secret"}
+			},
+			"after": {
+				"name": "app-test-env",
+				"site_config": [{"always_on": true, "docker_registry_password": "new-registry-secret"}],
+				"connection_string": [
+					{"name": "db", "type": "SQLAzure", "value": "Server=db;Password=new-db-secret"}
+				],
+				"auth_settings": {"enabled": true, "client_secret": "new-client-secret"}
+			},
+			"after_unknown": {},
+			"before_sensitive": {
+				"site_config": [{"docker_registry_password": true}],
+				"connection_string": [{"value": true}],
+				"auth_settings": {"client_secret": true}
+			},
+			"after_sensitive": {
+				"site_config": [{"docker_registry_password": true}],
+				"connection_string": true,
+				"auth_settings": {"client_secret": true}
+			}
+		}
+	}]`), &resourceChanges))
+
+	changes := convertResourceChanges(resourceChanges)
+	require.Len(t, changes, 1)
+
+	require.Equal(t, []provisioning.DeploymentPreviewPropertyChange{
+		{ChangeType: provisioning.PropertyChangeTypeModify, Path: "auth_settings.client_secret",
+			Before: terraformSensitiveValue, After: terraformSensitiveValue},
+		{ChangeType: provisioning.PropertyChangeTypeModify, Path: "connection_string",
+			Before: []any{map[string]any{"name": "db", "type": "SQLAzure", "value": terraformSensitiveValue}},
+			After:  terraformSensitiveValue},
+		{ChangeType: provisioning.PropertyChangeTypeModify, Path: "site_config",
+			Before: []any{map[string]any{"always_on": false, "docker_registry_password": terraformSensitiveValue}},
+			After:  []any{map[string]any{"always_on": true, "docker_registry_password": terraformSensitiveValue}}},
+	}, changes[0].Delta)
+
+	// the secrets appear neither in the console nor in the JSON output of the preview
+	output, err := json.Marshal(changes)
+	require.NoError(t, err)
+	for _, secret := range []string{"old-registry-secret", "new-registry-secret", "old-db-secret", "new-db-secret",
+		"old-client-secret", "new-client-secret"} {
+		require.NotContains(t, string(output), secret)
+	}
+}
+
+func Test_maskSensitive(t *testing.T) {
+	value := map[string]any{"list": []any{"a", "b"}, "plain": "c"}
+
+	require.Equal(t, value, maskSensitive(value, nil))
+	require.Equal(t, value, maskSensitive(value, false))
+	require.Equal(t, value, maskSensitive(value, map[string]any{}))
+	require.Equal(t, terraformSensitiveValue, maskSensitive(value, true))
+	require.Equal(t,
+		map[string]any{"list": []any{"a", terraformSensitiveValue}, "plain": "c"},
+		maskSensitive(value, map[string]any{"list": []any{false, true}}))
+	// a marker that does not match the shape of the value masks it
+	require.Equal(t, terraformSensitiveValue, maskSensitive("c", map[string]any{"plain": true}))
+	require.Equal(t, terraformSensitiveValue, maskSensitive("c", "unexpected"))
+}
+
+func Test_armResourceType(t *testing.T) {
+	require.Equal(t, string(azapi.AzureResourceTypeKeyVault), armResourceType("azurerm_key_vault", nil, nil))
+	require.Equal(t, string(azapi.AzureResourceTypeContainerApp), armResourceType("azapi_resource", nil,
+		map[string]any{"type": "Microsoft.App/containerApps@2024-03-01"}))
+	require.Equal(t, "random_string", armResourceType("random_string", nil, nil))
+}
@@ -219,19 +219,31 @@ func (t *TerraformProvider) Deploy(ctx context.Context) (*provisioning.DeployRes
 	}, nil
 }
 
+// Previews the changes of the plan, read from terraform show
 func (t *TerraformProvider) Preview(ctx context.Context) (*provisioning.DeployPreviewResult, error) {
-	// terraform uses plan() to display the what-if output
-	// no changes are added to the properties
-	_, _, err := t.plan(ctx)
+	_, deploymentDetails, err := t.plan(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	runResult, err := t.cli.Show(ctx, t.modulePath(), deploymentDetails.PlanFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("showing terraform plan: %w", err)
+	}
+
+	var planOutput terraformPlanOutput
+	if err := json.Unmarshal([]byte(runResult), &planOutput); err != nil {
+		return nil, fmt.Errorf("reading terraform plan: %w", err)
+	}
+
 	return &provisioning.DeployPreviewResult{
 		Preview: &provisioning.DeploymentPreview{
-			Status:     "done",
-			Properties: &provisioning.DeploymentPreviewProperties{},
+			Status: "done",
+			Properties: &provisioning.DeploymentPreviewProperties{
+				Changes: convertResourceChanges(planOutput.ResourceChanges),
+			},
 		},
+		BillableChanges: convertBillableChanges(planOutput.ResourceChanges),
 	}, nil
 }
 
@@ -0,0 +1,492 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oci
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+)
+
+const (
+	// MediaTypeImageManifest is the media type of OCI image manifests, which also describe OCI artifacts.
+	MediaTypeImageManifest = "application/vnd.oci.image.manifest.v1+json"
+	// AnnotationTitle is the annotation holding the file name of a layer, as set by 'oras push'.
+	AnnotationTitle = "org.opencontainers.image.title"
+
+	mediaTypeImageIndex     = "application/vnd.oci.image.index.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+
+	// maxManifestSize is the largest manifest accepted, as recommended by the distribution specification.
+	maxManifestSize = 4 * 1024 * 1024
+)
+
+// ErrNotFound is returned when a manifest or a blob does not exist in the registry.
+var ErrNotFound = errors.New("OCI artifact not found")
+
+// Descriptor describes content stored in a registry.
+type Descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Title returns the file name of the content, from its title annotation.
+func (d Descriptor) Title() string {
+	return d.Annotations[AnnotationTitle]
+}
+
+// Manifest is an OCI image manifest describing an artifact.
+type Manifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	ArtifactType  string            `json:"artifactType,omitempty"`
+	Config        Descriptor        `json:"config"`
+	Layers        []Descriptor      `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// Layer returns the layer with one of mediaTypes. When no layer has one of mediaTypes, the only layer of the
+// manifest is returned.
+func (m *Manifest) Layer(mediaTypes ...string) (Descriptor, error) {
+	for _, layer := range m.Layers {
+		if slices.Contains(mediaTypes, layer.MediaType) {
+			return layer, nil
+		}
+	}
+
+	if len(m.Layers) == 1 {
+		return m.Layers[0], nil
+	}
+
+	return Descriptor{}, fmt.Errorf(
+		"expected a single layer or a layer of media type %s, found %d layers",
+		strings.Join(mediaTypes, ", "), len(m.Layers))
+}
+
+// Client pulls artifacts from OCI distribution registries, authenticating with the credentials of a
+// CredentialStore.
+type Client struct {
+	pipeline    runtime.Pipeline
+	credentials CredentialStore
+
+	// authorizations caches the Authorization header of each registry repository.
+	authorizations   map[string]string
+	authorizationsMu sync.Mutex
+}
+
+// NewClient creates a new Client.
+func NewClient(transport policy.Transporter, credentials CredentialStore) *Client {
+	pipeline := runtime.NewPipeline("azd-oci", "1.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		Transport: transport,
+	})
+
+	return &Client{
+		pipeline:       pipeline,
+		credentials:    credentials,
+		authorizations: map[string]string{},
+	}
+}
+
+// FetchManifest fetches the manifest of the artifact ref.
+func (c *Client) FetchManifest(ctx context.Context, ref Reference) (*Manifest, error) {
+	manifestUrl := ref.baseUrl() + ref.Repository + "/manifests/" + ref.reference()
+	accept := strings.Join([]string{MediaTypeImageManifest, mediaTypeDockerManifest, mediaTypeImageIndex}, ", ")
+
+	resp, err := c.get(ctx, ref, manifestUrl, accept, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	contents, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest of %s: %w", ref, err)
+	}
+
+	if len(contents) > maxManifestSize {
+		return nil, fmt.Errorf("manifest of %s exceeds %d bytes", ref, maxManifestSize)
+	}
+
+	if ref.Digest != "" {
+		if err := verifyDigest(ref.Digest, contents); err != nil {
+			return nil, fmt.Errorf("manifest of %s: %w", ref, err)
+		}
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing manifest of %s: %w", ref, err)
+	}
+
+	mediaType := manifest.MediaType
+	if mediaType == "" {
+		mediaType = strings.TrimSpace(strings.Split(resp.Header.Get("Content-Type"), ";")[0])
+	}
+
+	if mediaType == mediaTypeImageIndex || mediaType == mediaTypeDockerList {
+		return nil, fmt.Errorf("%s is a multi-platform index, reference a single artifact instead", ref)
+	}
+
+	return &manifest, nil
+}
+
+// FetchBlob writes the content of the blob described by descriptor, in the repository of ref, to w. The content is
+// verified against the digest and the size of descriptor.
+func (c *Client) FetchBlob(ctx context.Context, ref Reference, descriptor Descriptor, w io.Writer) error {
+	verifier, err := newDigestVerifier(descriptor.Digest)
+	if err != nil {
+		return fmt.Errorf("blob of %s: %w", ref, err)
+	}
+
+	blobUrl := ref.baseUrl() + ref.Repository + "/blobs/" + descriptor.Digest
+
+	resp, err := c.get(ctx, ref, blobUrl, "", true)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	written, err := io.Copy(io.MultiWriter(w, verifier.hash), io.LimitReader(resp.Body, descriptor.Size+1))
+	if err != nil {
+		return fmt.Errorf("downloading blob %s of %s: %w", descriptor.Digest, ref, err)
+	}
+
+	if written != descriptor.Size {
+		return fmt.Errorf(
+			"blob %s of %s has %d bytes, expected %d bytes", descriptor.Digest, ref, written, descriptor.Size)
+	}
+
+	if err := verifier.verify(); err != nil {
+		return fmt.Errorf("blob of %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// get sends a GET request to requestUrl on behalf of ref, authenticating when the registry challenges the request.
+// The body of the returned response must be closed by the caller.
+func (c *Client) get(
+	ctx context.Context,
+	ref Reference,
+	requestUrl string,
+	accept string,
+	stream bool,
+) (*http.Response, error) {
+	key := ref.Registry + "/" + ref.Repository
+
+	c.authorizationsMu.Lock()
+	authorization := c.authorizations[key]
+	c.authorizationsMu.Unlock()
+
+	resp, err := c.send(ctx, requestUrl, accept, authorization, stream)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		authorization, err = c.authorize(ctx, ref, challenge)
+		if err != nil {
+			return nil, err
+		}
+
+		c.authorizationsMu.Lock()
+		c.authorizations[key] = authorization
+		c.authorizationsMu.Unlock()
+
+		resp, err = c.send(ctx, requestUrl, accept, authorization, stream)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, ref)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf(
+			"access to %s was denied, log in to the registry with 'docker login %s': %w",
+			ref, ref.Registry, runtime.NewResponseError(resp))
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("request failed for %s: %w", ref, runtime.NewResponseError(resp))
+	}
+}
+
+func (c *Client) send(
+	ctx context.Context,
+	requestUrl string,
+	accept string,
+	authorization string,
+	stream bool,
+) (*http.Response, error) {
+	req, err := runtime.NewRequest(ctx, http.MethodGet, requestUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	if accept != "" {
+		req.Raw().Header.Set("Accept", accept)
+	}
+
+	if authorization != "" {
+		req.Raw().Header.Set("Authorization", authorization)
+	}
+
+	if stream {
+		runtime.SkipBodyDownload(req)
+	}
+
+	resp, err := c.pipeline.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed for '%s': %w", requestUrl, err)
+	}
+
+	return resp, nil
+}
+
+// authorize returns the Authorization header answering the WWW-Authenticate challenge of the registry of ref.
+func (c *Client) authorize(ctx context.Context, ref Reference, challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	credential, err := c.credentials.Credential(ctx, ref.Registry)
+	if err != nil {
+		return "", fmt.Errorf("getting the credentials of registry %s: %w", ref.Registry, err)
+	}
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if credential.Password == "" {
+			return "", fmt.Errorf(
+				"registry %s requires credentials, log in with 'docker login %s'", ref.Registry, ref.Registry)
+		}
+
+		return "Basic " + basicAuth(credential.Username, credential.Password), nil
+	case "bearer":
+		token, err := c.fetchToken(ctx, ref, params, credential)
+		if err != nil {
+			return "", err
+		}
+
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("registry %s requested unsupported authentication '%s'", ref.Registry, challenge)
+	}
+}
+
+// fetchToken gets an access token from the token server of a Bearer challenge, following the token authentication
+// of the distribution specification.
+func (c *Client) fetchToken(
+	ctx context.Context,
+	ref Reference,
+	params map[string]string,
+	credential Credential,
+) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry %s did not specify where to get access tokens", ref.Registry)
+	}
+
+	if err := checkRealm(ref, realm); err != nil {
+		return "", err
+	}
+
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+
+	var req *policy.Request
+	var err error
+
+	if credential.IdentityToken != "" {
+		// Identity tokens are refresh tokens, exchanged with the OAuth2 token endpoint.
+		req, err = runtime.NewRequest(ctx, http.MethodPost, realm)
+		if err != nil {
+			return "", err
+		}
+
+		form := url.Values{
+			"grant_type":    {"refresh_token"},
+			"refresh_token": {credential.IdentityToken},
+			"service":       {params["service"]},
+			"scope":         {scope},
+		}
+		if err := req.SetBody(
+			streaming.NopCloser(strings.NewReader(form.Encode())), "application/x-www-form-urlencoded",
+		); err != nil {
+			return "", err
+		}
+	} else {
+		req, err = runtime.NewRequest(ctx, http.MethodGet, realm)
+		if err != nil {
+			return "", err
+		}
+
+		query := req.Raw().URL.Query()
+		if service := params["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", scope)
+		req.Raw().URL.RawQuery = query.Encode()
+
+		if credential.Password != "" {
+			req.Raw().Header.Set("Authorization", "Basic "+basicAuth(credential.Username, credential.Password))
+		}
+	}
+
+	resp, err := c.pipeline.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting an access token for %s: %w", ref, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf(
+			"requesting an access token for %s, log in to the registry with 'docker login %s': %w",
+			ref, ref.Registry, runtime.NewResponseError(resp))
+	}
+
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := runtime.UnmarshalAsJSON(resp, &tokenResponse); err != nil {
+		return "", fmt.Errorf("parsing the access token for %s: %w", ref, err)
+	}
+
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+
+	return "", fmt.Errorf("registry %s returned an empty access token", ref.Registry)
+}
+
+// checkRealm checks that the token server realm of the registry of ref can be sent credentials: it must use https,
+// unless both the registry and the token server are loopback hosts, which are reached over plain HTTP.
+func checkRealm(ref Reference, realm string) error {
+	realmUrl, err := url.Parse(realm)
+	if err != nil || realmUrl.Host == "" {
+		return fmt.Errorf("registry %s specified an invalid token server '%s'", ref.Registry, realm)
+	}
+
+	switch strings.ToLower(realmUrl.Scheme) {
+	case "https":
+		return nil
+	case "http":
+		if isLoopback(ref.Registry) && isLoopback(realmUrl.Host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"registry %s specified the token server '%s', which does not use https", ref.Registry, realmUrl.Redacted())
+}
+
+// parseChallenge parses a WWW-Authenticate challenge such as: Bearer realm="https://r/token",service="r".
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		name, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(value, `"`) {
+			// Quoted values, such as scopes, may contain commas.
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[name] = value[1:]
+				break
+			}
+			params[name] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+			params[name] = strings.TrimSpace(value)
+		}
+
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+
+	return scheme, params
+}
+
+func basicAuth(username string, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// digestVerifier verifies content against a digest in the form algorithm:hex.
+type digestVerifier struct {
+	digest string
+	hash   hash.Hash
+}
+
+func newDigestVerifier(digest string) (*digestVerifier, error) {
+	algorithm, _, _ := strings.Cut(digest, ":")
+
+	switch algorithm {
+	case "sha256":
+		return &digestVerifier{digest: digest, hash: sha256.New()}, nil
+	case "sha512":
+		return &digestVerifier{digest: digest, hash: sha512.New()}, nil
+	default:
+		return nil, fmt.Errorf("unsupported digest algorithm in '%s'", digest)
+	}
+}
+
+func (v *digestVerifier) verify() error {
+	algorithm, _, _ := strings.Cut(v.digest, ":")
+	actual := algorithm + ":" + hex.EncodeToString(v.hash.Sum(nil))
+
+	if actual != v.digest {
+		return fmt.Errorf("digest mismatch, expected %s but got %s", v.digest, actual)
+	}
+
+	return nil
+}
+
+func verifyDigest(digest string, content []byte) error {
+	verifier, err := newDigestVerifier(digest)
+	if err != nil {
+		return err
+	}
+
+	verifier.hash.Write(content)
+
+	return verifier.verify()
+}
@@ -0,0 +1,199 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oci_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockexec"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockoci"
+	"github.com/stretchr/testify/require"
+)
+
+const testMediaType = "application/vnd.contoso.test.v1+json"
+
+func pull(t *testing.T, client *oci.Client, location string) ([]byte, error) {
+	ref, err := oci.ParseReference(location)
+	require.NoError(t, err)
+
+	manifest, err := client.FetchManifest(t.Context(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	layer, err := manifest.Layer(testMediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	var content bytes.Buffer
+	if err := client.FetchBlob(t.Context(), ref, layer, &content); err != nil {
+		return nil, err
+	}
+
+	return content.Bytes(), nil
+}
+
+// writeDockerConfig writes a Docker configuration with contents, and points DOCKER_CONFIG to it.
+func writeDockerConfig(t *testing.T, contents string) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(contents), 0600))
+	t.Setenv("DOCKER_CONFIG", dir)
+}
+
+func Test_Client_Pull(t *testing.T) {
+	registry := mockoci.NewRegistry(t)
+	location := registry.Push(t, "azd/catalog", "1.0.0",
+		mockoci.Layer{MediaType: "text/plain", Title: "README.md", Content: []byte("readme")},
+		mockoci.Layer{MediaType: testMediaType, Title: "catalog.json", Content: []byte(`{"name":"catalog"}`)},
+	)
+
+	t.Run("Anonymous", func(t *testing.T) {
+		writeDockerConfig(t, `{}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		content, err := pull(t, client, location)
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"catalog"}`, string(content))
+	})
+
+	t.Run("ByDigest", func(t *testing.T) {
+		writeDockerConfig(t, `{}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		content, err := pull(t, client, strings.Replace(
+			location, ":1.0.0", "@"+registry.Digest("azd/catalog", "1.0.0"), 1))
+		require.NoError(t, err)
+		require.Equal(t, `{"name":"catalog"}`, string(content))
+	})
+
+	t.Run("Title", func(t *testing.T) {
+		writeDockerConfig(t, `{}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		ref, err := oci.ParseReference(location)
+		require.NoError(t, err)
+		manifest, err := client.FetchManifest(t.Context(), ref)
+		require.NoError(t, err)
+
+		layer, err := manifest.Layer(testMediaType)
+		require.NoError(t, err)
+		require.Equal(t, "catalog.json", layer.Title())
+
+		_, err = manifest.Layer("application/octet-stream")
+		require.Error(t, err)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		writeDockerConfig(t, `{}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		_, err := pull(t, client, strings.Replace(location, ":1.0.0", ":2.0.0", 1))
+		require.ErrorIs(t, err, oci.ErrNotFound)
+	})
+}
+
+func Test_Client_Auth(t *testing.T) {
+	registry := mockoci.NewRegistry(t)
+	location := registry.Push(t, "azd/catalog", "latest",
+		mockoci.Layer{MediaType: testMediaType, Content: []byte("catalog")})
+	registry.RequireAuth("user", "P@55w0rd")
+
+	auth := func(password string) string {
+		return base64.StdEncoding.EncodeToString([]byte("user:" + password))
+	}
+
+	t.Run("Anonymous", func(t *testing.T) {
+		writeDockerConfig(t, `{}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		_, err := pull(t, client, location)
+		require.ErrorContains(t, err, "docker login "+registry.Host)
+	})
+
+	t.Run("DockerConfig", func(t *testing.T) {
+		writeDockerConfig(t, `{"auths":{"http://`+registry.Host+`":{"auth":"`+auth("P@55w0rd")+`"}}}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		content, err := pull(t, client, location)
+		require.NoError(t, err)
+		require.Equal(t, "catalog", string(content))
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		writeDockerConfig(t, `{"auths":{"`+registry.Host+`":{"auth":"`+auth("wrong")+`"}}}`)
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(nil))
+
+		_, err := pull(t, client, location)
+		require.Error(t, err)
+	})
+
+	t.Run("CredentialHelper", func(t *testing.T) {
+		writeDockerConfig(t, `{"credsStore":"desktop","auths":{"`+registry.Host+`":{}}}`)
+
+		commandRunner := mockexec.NewMockCommandRunner()
+		commandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "docker-credential-desktop"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			var server bytes.Buffer
+			_, _ = server.ReadFrom(args.StdIn)
+			if server.String() != registry.Host {
+				return exec.NewRunResult(1, "", "credentials not found in native keychain"),
+					errors.New("exit code: 1")
+			}
+
+			return exec.NewRunResult(0, `{"Username":"user","Secret":"P@55w0rd"}`, ""), nil
+		})
+
+		client := oci.NewClient(http.DefaultClient, oci.NewDockerCredentialStore(commandRunner))
+
+		content, err := pull(t, client, location)
+		require.NoError(t, err)
+		require.Equal(t, "catalog", string(content))
+	})
+}
+
+// recordingTransport records the hosts of the requests it sends.
+type recordingTransport struct {
+	hosts []string
+}
+
+func (r *recordingTransport) Do(req *http.Request) (*http.Response, error) {
+	r.hosts = append(r.hosts, req.URL.Host)
+	return http.DefaultClient.Do(req)
+}
+
+func Test_Client_UntrustedRealm(t *testing.T) {
+	registry := mockoci.NewRegistry(t)
+	location := registry.Push(t, "azd/catalog", "latest",
+		mockoci.Layer{MediaType: testMediaType, Content: []byte("catalog")})
+	registry.RequireAuth("user", "P@55w0rd")
+	registry.SetRealm("http://attacker.example/token")
+
+	writeDockerConfig(t, `{"auths":{"`+registry.Host+`":{"username":"user","password":"P@55w0rd"}}}`)
+	transport := &recordingTransport{}
+	client := oci.NewClient(transport, oci.NewDockerCredentialStore(nil))
+
+	_, err := pull(t, client, location)
+	require.ErrorContains(t, err, "does not use https")
+	require.NotContains(t, transport.hosts, "attacker.example")
+}
+
+func Test_DockerCredentialStore_IdentityToken(t *testing.T) {
+	writeDockerConfig(t, `{"auths":{"contoso.azurecr.io":{"identitytoken":"refresh-token"}}}`)
+
+	credential, err := oci.NewDockerCredentialStore(nil).Credential(context.Background(), "contoso.azurecr.io")
+	require.NoError(t, err)
+	require.Equal(t, oci.Credential{IdentityToken: "refresh-token"}, credential)
+}
@@ -0,0 +1,186 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oci
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+)
+
+// identityTokenUsername is the user name credential helpers report for identity tokens, e.g. after 'az acr login'.
+const identityTokenUsername = "<token>"
+
+// Credential authenticates to a registry.
+type Credential struct {
+	Username string
+	Password string
+	// IdentityToken is a refresh token exchanged for access tokens, as stored by 'az acr login'.
+	IdentityToken string
+}
+
+// IsEmpty reports whether the credential has no secret, in which case the registry is accessed anonymously.
+func (c Credential) IsEmpty() bool {
+	return c.Password == "" && c.IdentityToken == ""
+}
+
+// CredentialStore provides the credentials of registries.
+type CredentialStore interface {
+	// Credential returns the credential of registry, or an empty credential to access the registry anonymously.
+	Credential(ctx context.Context, registry string) (Credential, error)
+}
+
+// DockerCredentialStore reads the credentials of registries from the Docker configuration, as written by
+// 'docker login' or 'az acr login', including the credentials kept by Docker credential helpers.
+type DockerCredentialStore struct {
+	commandRunner exec.CommandRunner
+}
+
+// NewDockerCredentialStore creates a DockerCredentialStore. Credential helpers are only used when commandRunner is
+// not nil.
+func NewDockerCredentialStore(commandRunner exec.CommandRunner) *DockerCredentialStore {
+	return &DockerCredentialStore{
+		commandRunner: commandRunner,
+	}
+}
+
+// dockerConfig is the subset of the Docker configuration file that holds registry credentials.
+type dockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+type dockerAuth struct {
+	Auth          string `json:"auth"`
+	Username      string `json:"username"`
+	Password      string `json:"password"`
+	IdentityToken string `json:"identitytoken"`
+}
+
+// Credential returns the credential of registry, looked up in the credential helper of the registry, then in the
+// default credential store, then in the credentials stored in the configuration file itself.
+func (s *DockerCredentialStore) Credential(ctx context.Context, registry string) (Credential, error) {
+	configPath := filepath.Join(dockerConfigDir(), "config.json")
+
+	//nolint:gosec // G304: path is the Docker configuration of the current user
+	contents, err := os.ReadFile(configPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return Credential{}, nil
+	} else if err != nil {
+		return Credential{}, fmt.Errorf("reading docker config: %w", err)
+	}
+
+	var config dockerConfig
+	if err := json.Unmarshal(contents, &config); err != nil {
+		return Credential{}, fmt.Errorf("parsing docker config %s: %w", configPath, err)
+	}
+
+	for _, helper := range []string{config.CredHelpers[registry], config.CredsStore} {
+		if helper == "" {
+			continue
+		}
+
+		credential, err := s.helperCredential(ctx, helper, registry)
+		if err != nil {
+			return Credential{}, err
+		}
+
+		if !credential.IsEmpty() {
+			return credential, nil
+		}
+	}
+
+	for server, auth := range config.Auths {
+		if normalizeServer(server) != registry {
+			continue
+		}
+
+		credential := Credential{
+			Username:      auth.Username,
+			Password:      auth.Password,
+			IdentityToken: auth.IdentityToken,
+		}
+
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return Credential{}, fmt.Errorf("decoding docker credentials of %s: %w", registry, err)
+			}
+
+			credential.Username, credential.Password, _ = strings.Cut(string(decoded), ":")
+		}
+
+		return credential, nil
+	}
+
+	return Credential{}, nil
+}
+
+// helperCredential gets the credential of registry from the docker-credential-<helper> credential helper. It returns
+// an empty credential when the helper does not know the registry.
+func (s *DockerCredentialStore) helperCredential(
+	ctx context.Context,
+	helper string,
+	registry string,
+) (Credential, error) {
+	if s.commandRunner == nil {
+		log.Printf("skipping docker credential helper '%s', which cannot be run", helper)
+		return Credential{}, nil
+	}
+
+	result, err := s.commandRunner.Run(
+		ctx, exec.NewRunArgs("docker-credential-"+helper, "get").WithStdIn(strings.NewReader(registry)))
+	if err != nil {
+		// Helpers fail with "credentials not found in native keychain" for unknown registries.
+		log.Printf("docker credential helper '%s' has no credentials for %s: %v", helper, registry, err)
+		return Credential{}, nil
+	}
+
+	var output struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal([]byte(result.Stdout), &output); err != nil {
+		return Credential{}, fmt.Errorf("parsing the output of docker credential helper '%s': %w", helper, err)
+	}
+
+	if output.Username == identityTokenUsername {
+		return Credential{IdentityToken: output.Secret}, nil
+	}
+
+	return Credential{Username: output.Username, Password: output.Secret}, nil
+}
+
+// normalizeServer returns the registry host of a server of the Docker configuration, which may be a URL such as
+// https://index.docker.io/v1/.
+func normalizeServer(server string) string {
+	server = strings.TrimPrefix(server, "https://")
+	server = strings.TrimPrefix(server, "http://")
+	server, _, _ = strings.Cut(server, "/")
+
+	return server
+}
+
+// dockerConfigDir returns the directory of the current Docker configuration.
+func dockerConfigDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".docker"
+	}
+
+	return filepath.Join(home, ".docker")
+}
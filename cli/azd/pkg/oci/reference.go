@@ -0,0 +1,133 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package oci pulls artifacts from OCI distribution registries, such as Azure Container Registry or Harbor.
+package oci
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+)
+
+// Scheme is the optional scheme of OCI artifact references, e.g. oci://contoso.azurecr.io/azd/extensions:1.0.0.
+const Scheme = "oci://"
+
+// defaultTag is the tag of references that specify neither a tag nor a digest.
+const defaultTag = "latest"
+
+var (
+	repositoryRegex = regexp.MustCompile(
+		`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagRegex    = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+	digestRegex = regexp.MustCompile(`^[a-z0-9]+(?:[+._-][a-z0-9]+)*:[a-zA-Z0-9=_-]+$`)
+)
+
+// Reference identifies an artifact in an OCI registry.
+type Reference struct {
+	// Registry is the host, and optional port, of the registry
+	Registry string
+	// Repository is the name of the repository within the registry
+	Repository string
+	// Tag is the tag of the artifact, empty when the artifact is referenced by digest
+	Tag string
+	// Digest is the digest of the manifest of the artifact, empty when the artifact is referenced by tag
+	Digest string
+}
+
+// IsReference reports whether location is an OCI artifact reference, i.e. starts with the oci:// scheme.
+func IsReference(location string) bool {
+	return strings.HasPrefix(strings.ToLower(location), Scheme)
+}
+
+// ParseReference parses an artifact reference in the form [oci://]registry/repository[:tag|@digest]. The tag
+// defaults to latest.
+func ParseReference(value string) (Reference, error) {
+	location := value
+	if IsReference(location) {
+		location = location[len(Scheme):]
+	}
+
+	registry, path, found := strings.Cut(location, "/")
+	if !found || registry == "" || path == "" {
+		return Reference{}, fmt.Errorf("invalid OCI reference '%s': expected registry/repository[:tag|@digest]", value)
+	}
+
+	ref := Reference{Registry: registry}
+
+	if repository, digest, found := strings.Cut(path, "@"); found {
+		if !digestRegex.MatchString(digest) {
+			return Reference{}, fmt.Errorf("invalid OCI reference '%s': invalid digest '%s'", value, digest)
+		}
+		ref.Digest = digest
+		path = repository
+	}
+
+	// A colon after the last slash separates the tag.
+	if index := strings.LastIndex(path, ":"); index > strings.LastIndex(path, "/") {
+		ref.Tag = path[index+1:]
+		path = path[:index]
+
+		if !tagRegex.MatchString(ref.Tag) {
+			return Reference{}, fmt.Errorf("invalid OCI reference '%s': invalid tag '%s'", value, ref.Tag)
+		}
+	}
+
+	if !repositoryRegex.MatchString(path) {
+		return Reference{}, fmt.Errorf("invalid OCI reference '%s': invalid repository '%s'", value, path)
+	}
+	ref.Repository = path
+
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+
+	return ref, nil
+}
+
+// String returns the reference in the form registry/repository[:tag][@digest].
+func (r Reference) String() string {
+	value := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		value += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		value += "@" + r.Digest
+	}
+
+	return value
+}
+
+// reference returns the digest of the reference when set, otherwise its tag, as expected by the manifests API.
+func (r Reference) reference() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+
+	return r.Tag
+}
+
+// baseUrl returns the URL of the distribution API of the registry. Loopback registries, such as a local registry
+// started with 'docker run -p 5000:5000 registry:2', are reached over plain HTTP like the Docker CLI does.
+func (r Reference) baseUrl() string {
+	if isLoopback(r.Registry) {
+		return "http://" + r.Registry + "/v2/"
+	}
+
+	return "https://" + r.Registry + "/v2/"
+}
+
+// isLoopback reports whether host, with an optional port, is localhost or a loopback IP address.
+func isLoopback(host string) bool {
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,119 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ParseReference(t *testing.T) {
+	digest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name     string
+		value    string
+		expected Reference
+		baseUrl  string
+		wantErr  bool
+	}{
+		{
+			name:     "Tag",
+			value:    "oci://contoso.azurecr.io/azd/extensions:1.0.0",
+			expected: Reference{Registry: "contoso.azurecr.io", Repository: "azd/extensions", Tag: "1.0.0"},
+			baseUrl:  "https://contoso.azurecr.io/v2/",
+		},
+		{
+			name:     "DefaultTag",
+			value:    "contoso.azurecr.io/azd/extensions",
+			expected: Reference{Registry: "contoso.azurecr.io", Repository: "azd/extensions", Tag: "latest"},
+			baseUrl:  "https://contoso.azurecr.io/v2/",
+		},
+		{
+			name:     "Digest",
+			value:    "oci://harbor.contoso.com:8443/azd/templates@" + digest,
+			expected: Reference{Registry: "harbor.contoso.com:8443", Repository: "azd/templates", Digest: digest},
+			baseUrl:  "https://harbor.contoso.com:8443/v2/",
+		},
+		{
+			name:     "TagAndDigest",
+			value:    "oci://contoso.azurecr.io/azd/templates:1.0@" + digest,
+			expected: Reference{Registry: "contoso.azurecr.io", Repository: "azd/templates", Tag: "1.0", Digest: digest},
+			baseUrl:  "https://contoso.azurecr.io/v2/",
+		},
+		{
+			name:     "LocalRegistry",
+			value:    "oci://localhost:5000/extensions:latest",
+			expected: Reference{Registry: "localhost:5000", Repository: "extensions", Tag: "latest"},
+			baseUrl:  "http://localhost:5000/v2/",
+		},
+		{
+			name:     "LoopbackRegistry",
+			value:    "oci://127.0.0.1:5000/extensions",
+			expected: Reference{Registry: "127.0.0.1:5000", Repository: "extensions", Tag: "latest"},
+			baseUrl:  "http://127.0.0.1:5000/v2/",
+		},
+		{name: "NoRepository", value: "oci://contoso.azurecr.io", wantErr: true},
+		{name: "UppercaseRepository", value: "oci://contoso.azurecr.io/Extensions", wantErr: true},
+		{name: "InvalidTag", value: "oci://contoso.azurecr.io/extensions:-1", wantErr: true},
+		{name: "InvalidDigest", value: "oci://contoso.azurecr.io/extensions@1234", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseReference(tt.value)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, ref)
+			require.Equal(t, tt.baseUrl, ref.baseUrl())
+		})
+	}
+}
+
+func Test_ParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(
+		`Bearer realm="https://contoso.azurecr.io/oauth2/token",service="contoso.azurecr.io",` +
+			`scope="repository:azd/extensions:pull,push"`)
+
+	require.Equal(t, "Bearer", scheme)
+	require.Equal(t, map[string]string{
+		"realm":   "https://contoso.azurecr.io/oauth2/token",
+		"service": "contoso.azurecr.io",
+		"scope":   "repository:azd/extensions:pull,push",
+	}, params)
+}
+
+func Test_CheckRealm(t *testing.T) {
+	tests := []struct {
+		name     string
+		registry string
+		realm    string
+		wantErr  bool
+	}{
+		{name: "Https", registry: "contoso.azurecr.io", realm: "https://contoso.azurecr.io/oauth2/token"},
+		{name: "HttpsOtherHost", registry: "docker.io", realm: "https://auth.docker.io/token"},
+		{name: "Http", registry: "contoso.azurecr.io", realm: "http://contoso.azurecr.io/oauth2/token", wantErr: true},
+		{name: "LoopbackHttp", registry: "localhost:5000", realm: "http://127.0.0.1:5000/token"},
+		{name: "LoopbackRegistryRemoteHttp", registry: "localhost:5000", realm: "http://example.com/token", wantErr: true},
+		{name: "RemoteRegistryLoopbackHttp", registry: "contoso.azurecr.io", realm: "http://localhost/token", wantErr: true},
+		{name: "OtherScheme", registry: "contoso.azurecr.io", realm: "ftp://contoso.azurecr.io/token", wantErr: true},
+		{name: "Relative", registry: "contoso.azurecr.io", realm: "/oauth2/token", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRealm(Reference{Registry: tt.registry, Repository: "azd/extensions"}, tt.realm)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
+	"github.com/fatih/color"
+)
+
+// CostEstimate defines a ux item for displaying the estimated monthly cost of a provision preview.
+type CostEstimate struct {
+	Currency            string
+	MonthlyCost         float64
+	PreviousMonthlyCost float64
+	Resources           []CostEstimateResource
+	// UnpricedResources is the number of resources left out of the estimate.
+	UnpricedResources int
+}
+
+// CostEstimateResource is the estimated monthly cost of a resource, before and after its change.
+type CostEstimateResource struct {
+	Type                string  `json:"type"`
+	Name                string  `json:"name"`
+	Sku                 string  `json:"sku,omitempty"`
+	Location            string  `json:"location,omitempty"`
+	MonthlyCost         float64 `json:"monthlyCost"`
+	PreviousMonthlyCost float64 `json:"previousMonthlyCost"`
+}
+
+func (ce *CostEstimate) ToString(currentIndentation string) string {
+	delta := ce.MonthlyCost - ce.PreviousMonthlyCost
+	title := fmt.Sprintf("%sEstimated monthly cost: %s (%s)",
+		currentIndentation, ce.amount(ce.MonthlyCost), ce.deltaText(delta))
+
+	// Only the resources with a cost are listed.
+	var resources []CostEstimateResource
+	var maxTypeLen int
+	for _, resource := range ce.Resources {
+		if resource.MonthlyCost == 0 && resource.PreviousMonthlyCost == 0 {
+			continue
+		}
+		resources = append(resources, resource)
+		maxTypeLen = max(maxTypeLen, len(resource.Type))
+	}
+
+	var lines []string
+	for _, resource := range resources {
+		details := []string{}
+		for _, detail := range []string{resource.Sku, resource.Location} {
+			if detail != "" {
+				details = append(details, detail)
+			}
+		}
+
+		name := resource.Name
+		if len(details) > 0 {
+			name = fmt.Sprintf("%s (%s)", resource.Name, strings.Join(details, ", "))
+		}
+
+		cost := ce.amount(resource.MonthlyCost)
+		if delta := resource.MonthlyCost - resource.PreviousMonthlyCost; delta != 0 {
+			cost = ce.deltaColor(delta)(
+				"%s => %s", ce.amount(resource.PreviousMonthlyCost), ce.amount(resource.MonthlyCost))
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s%s : %s : %s",
+			currentIndentation,
+			resource.Type,
+			strings.Repeat(" ", maxTypeLen-len(resource.Type)),
+			name,
+			cost,
+		))
+	}
+
+	note := "Usage-based charges are not included."
+	if ce.UnpricedResources > 0 {
+		note = fmt.Sprintf("Usage-based charges and %d resource(s) without a known price are not included.",
+			ce.UnpricedResources)
+	}
+	note = currentIndentation + output.WithGrayFormat(note)
+
+	if len(lines) == 0 {
+		return fmt.Sprintf("%s\n%s", title, note)
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s", title, strings.Join(lines, "\n"), note)
+}
+
+func (ce *CostEstimate) amount(value float64) string {
+	return fmt.Sprintf("%.2f %s", value, ce.Currency)
+}
+
+func (ce *CostEstimate) deltaText(delta float64) string {
+	if delta >= 0 {
+		return ce.deltaColor(delta)("+%s", ce.amount(delta))
+	}
+
+	return ce.deltaColor(delta)("-%s", ce.amount(-delta))
+}
+
+// deltaColor highlights cost increases in yellow and decreases in green.
+func (ce *CostEstimate) deltaColor(delta float64) func(string, ...any) string {
+	switch {
+	case delta > 0:
+		return color.YellowString
+	case delta < 0:
+		return color.GreenString
+	default:
+		return fmt.Sprintf
+	}
+}
+
+// costEstimateJson is the data of the JSON event of a cost estimate. It extends the console message with the costs, so
+// that consumers of the JSON output, such as pull request bots, don't have to parse the message.
+type costEstimateJson struct {
+	Message             string                 `json:"message"`
+	Currency            string                 `json:"currency"`
+	MonthlyCost         float64                `json:"monthlyCost"`
+	PreviousMonthlyCost float64                `json:"previousMonthlyCost"`
+	Delta               float64                `json:"delta"`
+	Resources           []CostEstimateResource `json:"resources"`
+	UnpricedResources   int                    `json:"unpricedResources"`
+}
+
+func (ce *CostEstimate) MarshalJSON() ([]byte, error) {
+	resources := ce.Resources
+	if resources == nil {
+		resources = []CostEstimateResource{}
+	}
+
+	event := output.EventForMessage(ce.ToString(""))
+	event.Data = costEstimateJson{
+		Message:             event.Data.(contracts.ConsoleMessage).Message,
+		Currency:            ce.Currency,
+		MonthlyCost:         ce.MonthlyCost,
+		PreviousMonthlyCost: ce.PreviousMonthlyCost,
+		Delta:               ce.MonthlyCost - ce.PreviousMonthlyCost,
+		Resources:           resources,
+		UnpricedResources:   ce.UnpricedResources,
+	}
+
+	return json.Marshal(event)
+}
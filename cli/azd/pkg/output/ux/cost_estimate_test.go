@@ -0,0 +1,79 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package ux
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/test/snapshot"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCostEstimate(t *testing.T) {
+	ce := &CostEstimate{
+		Currency:            "USD",
+		MonthlyCost:         231.37,
+		PreviousMonthlyCost: 13.14,
+		Resources: []CostEstimateResource{
+			{Type: "App Service plan", Name: "plan-api", Sku: "P1v3", Location: "eastus",
+				MonthlyCost: 226.3, PreviousMonthlyCost: 13.14},
+			{Type: "Container registry", Name: "cr", Sku: "Basic", Location: "eastus", MonthlyCost: 5.07},
+			{Type: "Key Vault", Name: "kv", Sku: "standard"},
+		},
+		UnpricedResources: 2,
+	}
+
+	output := ce.ToString("  ")
+	snapshot.SnapshotT(t, output)
+}
+
+func TestCostEstimateNoCost(t *testing.T) {
+	ce := &CostEstimate{
+		Currency:  "USD",
+		Resources: []CostEstimateResource{{Type: "Key Vault", Name: "kv"}},
+	}
+
+	require.Equal(t,
+		"Estimated monthly cost: 0.00 USD (+0.00 USD)\nUsage-based charges are not included.", ce.ToString(""))
+}
+
+func TestCostEstimateMarshalJSON(t *testing.T) {
+	ce := &CostEstimate{
+		Currency:            "USD",
+		MonthlyCost:         5.07,
+		PreviousMonthlyCost: 10,
+		Resources: []CostEstimateResource{
+			{Type: "Container registry", Name: "cr", Sku: "Basic", Location: "eastus",
+				MonthlyCost: 5.07, PreviousMonthlyCost: 10},
+		},
+		UnpricedResources: 1,
+	}
+
+	data, err := json.Marshal(ce)
+	require.NoError(t, err)
+
+	var parsed struct {
+		Type string `json:"type"`
+		Data struct {
+			Message             string                 `json:"message"`
+			Currency            string                 `json:"currency"`
+			MonthlyCost         float64                `json:"monthlyCost"`
+			PreviousMonthlyCost float64                `json:"previousMonthlyCost"`
+			Delta               float64                `json:"delta"`
+			Resources           []CostEstimateResource `json:"resources"`
+			UnpricedResources   int                    `json:"unpricedResources"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &parsed))
+	require.Equal(t, "consoleMessage", parsed.Type)
+	require.Contains(t, parsed.Data.Message, "Estimated monthly cost: 5.07 USD (-4.93 USD)")
+	require.NotContains(t, parsed.Data.Message, "\x1b[", "the message has no ANSI colors")
+	require.Equal(t, "USD", parsed.Data.Currency)
+	require.Equal(t, 5.07, parsed.Data.MonthlyCost)
+	require.Equal(t, 10.0, parsed.Data.PreviousMonthlyCost)
+	require.InDelta(t, -4.93, parsed.Data.Delta, 0.001)
+	require.Equal(t, ce.Resources, parsed.Data.Resources)
+	require.Equal(t, 1, parsed.Data.UnpricedResources)
+}
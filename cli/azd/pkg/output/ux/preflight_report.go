@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/contracts"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
@@ -104,12 +105,58 @@ func writeItem(
 	}
 }
 
+// preflightReportJson is the data of the JSON event of a preflight report. It extends the console message with the
+// findings, so that consumers of the JSON output don't have to parse the message.
+type preflightReportJson struct {
+	Message string                    `json:"message"`
+	Items   []preflightReportItemJson `json:"items"`
+}
+
+type preflightReportItemJson struct {
+	// Severity is either "warning" or "error".
+	Severity     string                    `json:"severity"`
+	DiagnosticID string                    `json:"diagnosticId,omitempty"`
+	Message      string                    `json:"message"`
+	Suggestion   string                    `json:"suggestion,omitempty"`
+	Links        []preflightReportLinkJson `json:"links,omitempty"`
+}
+
+type preflightReportLinkJson struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
+}
+
 func (r *PreflightReport) MarshalJSON() ([]byte, error) {
 	warnings, errors := r.partition()
 
-	return json.Marshal(output.EventForMessage(
+	event := output.EventForMessage(
 		fmt.Sprintf("preflight: %d warning(s), %d error(s)",
-			len(warnings), len(errors))))
+			len(warnings), len(errors)))
+
+	data := preflightReportJson{
+		Message: event.Data.(contracts.ConsoleMessage).Message,
+		Items:   []preflightReportItemJson{},
+	}
+	for _, item := range append(warnings, errors...) {
+		severity := "warning"
+		if item.IsError {
+			severity = "error"
+		}
+
+		itemJson := preflightReportItemJson{
+			Severity:     severity,
+			DiagnosticID: item.DiagnosticID,
+			Message:      item.Message,
+			Suggestion:   item.Suggestion,
+		}
+		for _, link := range item.Links {
+			itemJson.Links = append(itemJson.Links, preflightReportLinkJson{URL: link.URL, Title: link.Title})
+		}
+		data.Items = append(data.Items, itemJson)
+	}
+	event.Data = data
+
+	return json.Marshal(event)
 }
 
 // HasErrors returns true if the report contains at least one error-level item.
@@ -166,6 +166,52 @@ func TestPreflightReport_MarshalJSON_Envelope(t *testing.T) {
 	require.Contains(t, parsed.Data.Message, "1 error(s)")
 }
 
+func TestPreflightReport_MarshalJSON_Items(t *testing.T) {
+	report := &PreflightReport{
+		Items: []PreflightReportItem{
+			{IsError: true, DiagnosticID: "local_policy_violation", Message: "e1"},
+			{
+				IsError:      false,
+				DiagnosticID: "role_assignment_missing",
+				Message:      "w1",
+				Suggestion:   "fix it",
+				Links:        []PreflightReportLink{{URL: "https://aka.ms/azd", Title: "docs"}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(report)
+	require.NoError(t, err)
+
+	var parsed struct {
+		Data struct {
+			Items []struct {
+				Severity     string `json:"severity"`
+				DiagnosticID string `json:"diagnosticId"`
+				Message      string `json:"message"`
+				Suggestion   string `json:"suggestion"`
+				Links        []struct {
+					URL   string `json:"url"`
+					Title string `json:"title"`
+				} `json:"links"`
+			} `json:"items"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &parsed))
+
+	// Warnings are listed before errors, as in the console output.
+	require.Len(t, parsed.Data.Items, 2)
+	require.Equal(t, "warning", parsed.Data.Items[0].Severity)
+	require.Equal(t, "role_assignment_missing", parsed.Data.Items[0].DiagnosticID)
+	require.Equal(t, "w1", parsed.Data.Items[0].Message)
+	require.Equal(t, "fix it", parsed.Data.Items[0].Suggestion)
+	require.Len(t, parsed.Data.Items[0].Links, 1)
+	require.Equal(t, "https://aka.ms/azd", parsed.Data.Items[0].Links[0].URL)
+	require.Equal(t, "docs", parsed.Data.Items[0].Links[0].Title)
+	require.Equal(t, "error", parsed.Data.Items[1].Severity)
+	require.Equal(t, "local_policy_violation", parsed.Data.Items[1].DiagnosticID)
+}
+
 func TestPreflightReport_Indentation(t *testing.T) {
 	report := &PreflightReport{
 		Items: []PreflightReportItem{
@@ -6,31 +6,37 @@ package project
 import (
 	"context"
 	"strings"
-	"sync"
 )
 
+// ContainerBuildPool is the exegraph concurrency pool bounding concurrent
+// container image builds. Graph builders tag the package steps of
+// container-hosted services with it, and service targets that build images
+// during deploy hold a slot of it via [exegraph.AcquirePool]. Users size it
+// through the `concurrency` section of azure.yaml.
+const ContainerBuildPool = "build"
+
 type buildGateContextKey struct{}
 
-// ContextWithBuildGate returns a new context carrying the given mutex as a
-// build gate. Deploy targets that perform concurrent-unsafe build operations
-// (e.g. dotnet publish on Aspire projects that share <ProjectReference>
-// dependencies) should acquire this mutex for the duration of the build and
-// release it before proceeding to the Azure deployment portion.
+// ContextWithBuildGate returns a new context carrying the name of the exegraph
+// concurrency pool that gates a concurrent-unsafe build. Deploy targets that
+// perform such builds (e.g. dotnet publish on Aspire projects that share
+// <ProjectReference> dependencies) should hold a slot of this pool, via
+// [exegraph.AcquirePool], for the duration of the build and release it before
+// proceeding to the Azure deployment portion. The graph builder sizes the pool
+// to one slot by default.
 //
 // This is a FALLBACK mechanism. The preferred approach is to use
 // [dotnet.ContextWithArtifactsPath] to isolate intermediate outputs, which
 // allows full parallelism without serialization.
-func ContextWithBuildGate(ctx context.Context, mu *sync.Mutex) context.Context {
-	return context.WithValue(ctx, buildGateContextKey{}, mu)
+func ContextWithBuildGate(ctx context.Context, pool string) context.Context {
+	return context.WithValue(ctx, buildGateContextKey{}, pool)
 }
 
-// BuildGateFromContext retrieves the build gate mutex from the context, or nil
-// if none was set. Callers should check for nil before locking.
-func BuildGateFromContext(ctx context.Context) *sync.Mutex {
-	if mu, ok := ctx.Value(buildGateContextKey{}).(*sync.Mutex); ok {
-		return mu
-	}
-	return nil
+// BuildGateFromContext retrieves the build gate pool name from the context, or
+// "" if none was set.
+func BuildGateFromContext(ctx context.Context) string {
+	pool, _ := ctx.Value(buildGateContextKey{}).(string)
+	return pool
 }
 
 // sanitizeTempDirName replaces characters outside [A-Za-z0-9_-] with
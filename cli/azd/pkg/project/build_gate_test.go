@@ -5,21 +5,18 @@ package project
 
 import (
 	"context"
-	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestBuildGateRoundTrip(t *testing.T) {
-	mu := &sync.Mutex{}
-	ctx := ContextWithBuildGate(context.Background(), mu)
-	got := BuildGateFromContext(ctx)
-	require.Same(t, mu, got)
+	ctx := ContextWithBuildGate(context.Background(), "aspire")
+	require.Equal(t, "aspire", BuildGateFromContext(ctx))
 }
 
-func TestBuildGateFromContext_NilWhenAbsent(t *testing.T) {
-	require.Nil(t, BuildGateFromContext(context.Background()))
+func TestBuildGateFromContext_EmptyWhenAbsent(t *testing.T) {
+	require.Empty(t, BuildGateFromContext(context.Background()))
 }
 
 func TestSanitizeTempDirName(t *testing.T) {
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net"
 	"net/url"
@@ -36,6 +37,8 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 	"github.com/azure/azure-dev/cli/azd/pkg/output/ux"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/buildah"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/buildkit"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/dotnet"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/pack"
@@ -264,9 +267,36 @@ func (ch *ContainerHelper) RequiredExternalTools(ctx context.Context, serviceCon
 		return []tools.ExternalTool{ch.dotNetCli}
 	}
 
+	if builder := ch.daemonlessBuilder(serviceConfig); builder != nil {
+		return []tools.ExternalTool{builder}
+	}
+
 	return []tools.ExternalTool{ch.docker}
 }
 
+// buildPushTool is a daemonless builder, which pushes the image it builds to the registry instead of keeping it in
+// the local image store.
+type buildPushTool interface {
+	tools.ExternalTool
+	BuildAndPush(ctx context.Context, cwd string, options *docker.BuildPushOptions, buildProgress io.Writer) error
+}
+
+// daemonlessBuilder returns the builder configured for the service when it is a daemonless builder, and nil otherwise.
+func (ch *ContainerHelper) daemonlessBuilder(serviceConfig *ServiceConfig) buildPushTool {
+	if serviceConfig.Docker.RemoteBuild {
+		return nil
+	}
+
+	switch serviceConfig.Docker.Builder {
+	case DockerBuilderBuildah:
+		return buildah.NewCli(ch.commandRunner)
+	case DockerBuilderBuildKit:
+		return buildkit.NewCli(ch.commandRunner)
+	default:
+		return nil
+	}
+}
+
 // Login logs into the container registry specified by AZURE_CONTAINER_REGISTRY_ENDPOINT in the environment. On success,
 // it returns the name of the container registry that was logged into.
 func (ch *ContainerHelper) Login(
@@ -281,14 +311,19 @@ func (ch *ContainerHelper) Login(
 
 	// Only perform automatic login for ACR
 	// Other registries require manual login via external 'docker login' command
-	hostParts := strings.Split(registryName, ".")
-	if len(hostParts) == 1 || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix) {
+	if ch.isAzureContainerRegistry(registryName) {
 		return registryName, ch.containerRegistryService.Login(ctx, env.GetSubscriptionId(), registryName)
 	}
 
 	return registryName, nil
 }
 
+// isAzureContainerRegistry reports whether the registry is an Azure Container Registry, which azd authenticates to.
+func (ch *ContainerHelper) isAzureContainerRegistry(registryName string) bool {
+	hostParts := strings.Split(registryName, ".")
+	return len(hostParts) == 1 || strings.HasSuffix(registryName, ch.cloud.ContainerRegistryEndpointSuffix)
+}
+
 var defaultCredentialsRetryInitialDelay = 2 * time.Second
 
 func (ch *ContainerHelper) Credentials(
@@ -354,10 +389,16 @@ func (ch *ContainerHelper) Build(
 	env *environment.Environment,
 	progress *async.Progress[ServiceProgress],
 ) (*ServiceBuildResult, error) {
-	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) {
+	// Remote and daemonless builds build the image in the publish step, as they push it as part of the build
+	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) ||
+		serviceConfig.Docker.Builder.Daemonless() {
 		return &ServiceBuildResult{}, nil
 	}
 
+	if err := validateDockerBuilder(serviceConfig.Docker); err != nil {
+		return nil, err
+	}
+
 	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
 	resolveDockerPaths(serviceConfig, &dockerOptions)
 
@@ -542,7 +583,8 @@ func (ch *ContainerHelper) Package(
 	env *environment.Environment,
 	progress *async.Progress[ServiceProgress],
 ) (*ServicePackageResult, error) {
-	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) {
+	if serviceConfig.Docker.RemoteBuild || useDotnetPublishForDockerBuild(serviceConfig) ||
+		serviceConfig.Docker.Builder.Daemonless() {
 		return &ServicePackageResult{}, nil
 	}
 
@@ -626,6 +668,7 @@ func (ch *ContainerHelper) Publish(
 	defer func() { span.EndWithStatus(err) }()
 	span.SetAttributes(
 		attribute.Bool("container.remotebuild", serviceConfig.Docker.RemoteBuild),
+		attribute.String("container.builder", string(serviceConfig.Docker.Builder)),
 	)
 
 	var remoteImage string
@@ -656,6 +699,9 @@ func (ch *ContainerHelper) Publish(
 		}
 	} else if useDotnetPublishForDockerBuild(serviceConfig) {
 		remoteImage, err = ch.runDotnetPublish(ctx, serviceConfig, targetResource, env, progress)
+	} else if builder := ch.daemonlessBuilder(serviceConfig); builder != nil {
+		remoteImage, err = ch.runDaemonlessBuild(
+			ctx, builder, serviceConfig, targetResource, env, progress, imageOverride)
 	} else {
 		remoteImage, err = ch.publishLocalImage(ctx, serviceConfig, serviceContext, env, progress, imageOverride)
 	}
@@ -920,6 +966,182 @@ func (ch *ContainerHelper) runRemoteBuild(
 	return imageName, nil
 }
 
+// runDaemonlessBuild builds the image with a daemonless builder, which pushes it to the registry as part of the build.
+// It returns the full remote image name.
+func (ch *ContainerHelper) runDaemonlessBuild(
+	ctx context.Context,
+	builder buildPushTool,
+	serviceConfig *ServiceConfig,
+	target *environment.TargetResource,
+	env *environment.Environment,
+	progress *async.Progress[ServiceProgress],
+	imageOverride *imageOverride,
+) (string, error) {
+	dockerOptions := getDockerOptionsWithDefaults(serviceConfig.Docker)
+	resolveDockerPaths(serviceConfig, &dockerOptions)
+
+	if _, err := os.Stat(dockerOptions.Path); err != nil {
+		return "", fmt.Errorf("the %s builder requires a Dockerfile: %w", serviceConfig.Docker.Builder, err)
+	}
+
+	resolvedBuildArgs, err := resolveDockerBuildArgs(dockerOptions.BuildArgs, env)
+	if err != nil {
+		return "", err
+	}
+
+	resolvedBuildEnv, err := resolveDockerParameters(dockerOptions.BuildEnv, env)
+	if err != nil {
+		return "", err
+	}
+
+	buildArgs, err := dockerBuildArgsWithValues(resolvedBuildArgs, dockerBuildArgEnvResolver(env, resolvedBuildEnv))
+	if err != nil {
+		return "", err
+	}
+
+	cacheFrom, err := resolveDockerBuildArgs(dockerOptions.CacheFrom, env)
+	if err != nil {
+		return "", fmt.Errorf("resolving docker.cacheFrom: %w", err)
+	}
+
+	cacheTo, err := resolveDockerBuildArgs(dockerOptions.CacheTo, env)
+	if err != nil {
+		return "", fmt.Errorf("resolving docker.cacheTo: %w", err)
+	}
+
+	localImageTag, err := ch.LocalImageTag(ctx, serviceConfig, env)
+	if err != nil {
+		return "", err
+	}
+
+	imageName, err := ch.RemoteImageTag(ctx, serviceConfig, localImageTag, imageOverride, env)
+	if err != nil {
+		return "", err
+	}
+
+	buildOptions := &docker.BuildPushOptions{
+		Dockerfile:   dockerOptions.Path,
+		Context:      dockerOptions.Context,
+		Target:       dockerOptions.Target,
+		Network:      dockerOptions.Network,
+		BuildArgs:    buildArgs,
+		BuildSecrets: dockerOptions.BuildSecrets,
+		BuildEnv:     append(env.Environ(), resolvedBuildEnv...),
+		Image:        imageName,
+		CacheFrom:    cacheFrom,
+		CacheTo:      cacheTo,
+	}
+
+	for platform := range strings.SplitSeq(dockerOptions.Platform, ",") {
+		if platform = strings.TrimSpace(platform); platform != "" {
+			buildOptions.Platforms = append(buildOptions.Platforms, platform)
+		}
+	}
+
+	// Only provide credentials for ACR
+	// Other registries require a manual login, e.g. with 'buildah login' or 'docker login'
+	registryName, err := ch.RegistryName(ctx, serviceConfig, env)
+	if err != nil {
+		return "", err
+	}
+
+	isAcr := registryName != "" && ch.isAzureContainerRegistry(registryName)
+	if isAcr {
+		progress.SetProgress(NewServiceProgress("Fetching container registry credentials"))
+		credentials, err := ch.Credentials(ctx, serviceConfig, target, env)
+		if err != nil {
+			return "", fmt.Errorf("fetching container registry credentials: %w", err)
+		}
+
+		buildOptions.Credentials = &docker.RegistryCredentials{
+			Server:   credentials.LoginServer,
+			Username: credentials.Username,
+			Password: credentials.Password,
+		}
+	}
+
+	log.Printf(
+		"building image %s for service %s with %s, platforms: %s",
+		imageName,
+		serviceConfig.Name,
+		builder.Name(),
+		buildOptions.Platforms,
+	)
+
+	progress.SetProgress(NewServiceProgress(fmt.Sprintf("Building and pushing container image with %s", builder.Name())))
+	previewerWriter := ch.console.ShowPreviewer(ctx,
+		&input.ShowPreviewerOptions{
+			Prefix:       "  ",
+			MaxLineCount: 8,
+			Title:        fmt.Sprintf("%s Output", builder.Name()),
+		})
+	err = builder.BuildAndPush(ctx, serviceConfig.Path(), buildOptions, previewerWriter)
+	ch.console.StopPreviewer(ctx, false)
+	if err != nil {
+		if !isAcr {
+			return "", &internal.ErrorWithSuggestion{
+				Err: err,
+				Suggestion: fmt.Sprintf("When pushing to an external registry, ensure %s is authenticated to it "+
+					"and run 'azd deploy' again", builder.Name()),
+			}
+		}
+
+		return "", err
+	}
+
+	return imageName, nil
+}
+
+// validateDockerBuilder validates the options of a service that is built by the Docker builder.
+func validateDockerBuilder(options DockerProjectOptions) error {
+	if options.Builder != "" && options.Builder != DockerBuilderDocker {
+		return fmt.Errorf(
+			"unsupported docker.builder '%s', supported values are: %s, %s, %s",
+			options.Builder, DockerBuilderDocker, DockerBuilderBuildah, DockerBuilderBuildKit)
+	}
+
+	if len(options.CacheFrom) > 0 || len(options.CacheTo) > 0 {
+		return &internal.ErrorWithSuggestion{
+			Err: errors.New("docker.cacheFrom and docker.cacheTo are not supported by the docker builder"),
+			Suggestion: "Set 'builder: buildah' or 'builder: buildkit' under the 'docker:' section of the service " +
+				"in azure.yaml to use a registry build cache.",
+		}
+	}
+
+	return nil
+}
+
+// dockerBuildArgsWithValues returns the build args with the value of the args that do not specify one looked up
+// with lookupEnv.
+func dockerBuildArgsWithValues(buildArgs []string, lookupEnv func(string) (string, bool)) ([]string, error) {
+	result := make([]string, 0, len(buildArgs))
+	for i, arg := range buildArgs {
+		name, _, hasValue := strings.Cut(arg, "=")
+		if name == "" {
+			return nil, fmt.Errorf("docker build arg at index %d has an empty name", i)
+		}
+
+		if !hasValue {
+			value, ok := lookupEnv(name)
+			if !ok {
+				return nil, fmt.Errorf(
+					"resolving docker build arg %q: environment variable is not set; "+
+						"use %s=<value> or set environment variable %s",
+					name,
+					name,
+					name,
+				)
+			}
+
+			arg = name + "=" + value
+		}
+
+		result = append(result, arg)
+	}
+
+	return result, nil
+}
+
 func dockerBuildArgsToAcrArguments(
 	buildArgs []string,
 	lookupEnv func(string) (string, bool),
@@ -1082,6 +1304,16 @@ func (ch *ContainerHelper) packBuild(
 			}
 		}
 
+		// Commands of a Go module, e.g. cmd/api, are built from the root of the module, which holds go.mod.
+		if svc.Language == ServiceLanguageGo && buildContext != svcPath {
+			svcRelPath, err := filepath.Rel(buildContext, svcPath)
+			if err != nil {
+				return nil, fmt.Errorf("calculating relative context path: %w", err)
+			}
+
+			environ = append(environ, fmt.Sprintf("BP_GO_TARGETS=./%s", filepath.ToSlash(svcRelPath)))
+		}
+
 		if svc.OutputPath != "" && (svc.Language == ServiceLanguageTypeScript || svc.Language == ServiceLanguageJavaScript) {
 			inDockerOutputPath := path.Join("/workspace", svc.OutputPath)
 			// A dist folder has been set.
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/azure/azure-dev/cli/azd/internal"
 	"github.com/azure/azure-dev/cli/azd/pkg/async"
 	"github.com/azure/azure-dev/cli/azd/pkg/azapi"
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
@@ -1909,3 +1911,164 @@ func Test_containerAppTarget_RequiredExternalTools(t *testing.T) {
 	// with an empty ServiceConfig Docker section, we get docker
 	assert.NotNil(t, toolList)
 }
+
+func Test_ContainerHelper_Publish_DaemonlessBuild(t *testing.T) {
+	dockerConfigDir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dockerConfigDir)
+	require.NoError(t, os.WriteFile(
+		filepath.Join(dockerConfigDir, "config.json"), []byte(`{"credsStore": "desktop"}`), 0600))
+
+	mockContext := mocks.NewMockContext(t.Context())
+	env := environment.NewWithValues("dev", map[string]string{
+		"CACHE_REPO": "contoso.azurecr.io/cache",
+	})
+
+	mockContainerRegistryService := &mockContainerRegistryService{}
+	mockContainerRegistryService.On("Credentials", mock.Anything, "SUBSCRIPTION_ID", "contoso.azurecr.io").
+		Return(&azapi.DockerCredentials{
+			Username:    "00000000-0000-0000-0000-000000000000",
+			Password:    "REFRESH_TOKEN",
+			LoginServer: "contoso.azurecr.io",
+		}, nil)
+
+	ran := map[string]exec.RunArgs{}
+	var authConfig string
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "buildah"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran[strings.Join(args.Args[:2], " ")] = args
+
+		if idx := slices.Index(args.Args, "--authfile"); idx >= 0 && authConfig == "" {
+			contents, err := os.ReadFile(args.Args[idx+1])
+			require.NoError(t, err)
+			authConfig = string(contents)
+		}
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	containerHelper := NewContainerHelper(
+		clock.NewMock(),
+		mockContainerRegistryService,
+		nil,
+		mockContext.CommandRunner,
+		docker.NewCli(mockContext.CommandRunner),
+		dotnet.NewCli(mockContext.CommandRunner),
+		mockContext.Console,
+		cloud.AzurePublic(),
+	)
+
+	projectRoot := t.TempDir()
+	servicePath := filepath.Join(projectRoot, "src", "api")
+	require.NoError(t, os.MkdirAll(servicePath, osutil.PermissionDirectory))
+	require.NoError(t, os.WriteFile(filepath.Join(servicePath, "Dockerfile"), []byte("FROM scratch"), 0600))
+
+	serviceConfig := createTestServiceConfig("./src/api", ContainerAppTarget, ServiceLanguageTypeScript)
+	serviceConfig.Project.Path = projectRoot
+	serviceConfig.Docker.Registry = osutil.NewExpandableString("contoso.azurecr.io")
+	serviceConfig.Docker.Image = osutil.NewExpandableString("api")
+	serviceConfig.Docker.Tag = osutil.NewExpandableString("v1")
+	serviceConfig.Docker.Builder = DockerBuilderBuildah
+	serviceConfig.Docker.Platform = "linux/amd64, linux/arm64"
+	serviceConfig.Docker.BuildArgs = []osutil.ExpandableString{osutil.NewExpandableString("CACHE_REPO")}
+	serviceConfig.Docker.CacheFrom = []osutil.ExpandableString{osutil.NewExpandableString("${CACHE_REPO}")}
+	serviceConfig.Docker.CacheTo = []osutil.ExpandableString{osutil.NewExpandableString("${CACHE_REPO}")}
+
+	serviceContext := NewServiceContext()
+	buildResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServiceBuildResult, error) {
+		return containerHelper.Build(*mockContext.Context, serviceConfig, serviceContext, env, progress)
+	})
+	require.NoError(t, err)
+	require.Empty(t, buildResult.Artifacts)
+
+	packageResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePackageResult, error) {
+		return containerHelper.Package(*mockContext.Context, serviceConfig, serviceContext, env, progress)
+	})
+	require.NoError(t, err)
+	require.Empty(t, packageResult.Artifacts)
+
+	targetResource := environment.NewTargetResource(
+		"SUBSCRIPTION_ID",
+		"RESOURCE_GROUP",
+		"CONTAINER_APP",
+		"Microsoft.App/containerApps",
+	)
+
+	publishResult, err := logProgress(t, func(progress *async.Progress[ServiceProgress]) (*ServicePublishResult, error) {
+		return containerHelper.Publish(
+			*mockContext.Context, serviceConfig, serviceContext, targetResource, env, progress, nil)
+	})
+	require.NoError(t, err)
+
+	artifact, found := publishResult.Artifacts.FindFirst(WithKind(ArtifactKindContainer))
+	require.True(t, found)
+	require.Equal(t, "contoso.azurecr.io/api:v1", artifact.Location)
+
+	build := ran["build -f"]
+	require.Contains(t, build.Args, "linux/amd64,linux/arm64")
+	require.Contains(t, build.Args, "--manifest")
+	require.Contains(t, build.Args, "CACHE_REPO=contoso.azurecr.io/cache")
+	require.Contains(t, build.Args, "--cache-from")
+	require.Contains(t, build.Args, "--cache-to")
+	require.Equal(t, filepath.Join(servicePath, "Dockerfile"), build.Args[2])
+
+	push := ran["manifest push"]
+	require.Equal(t,
+		[]string{"contoso.azurecr.io/api:v1", "docker://contoso.azurecr.io/api:v1"},
+		push.Args[len(push.Args)-2:])
+	require.Contains(t, ran, "manifest rm")
+
+	require.Contains(t, authConfig, `"contoso.azurecr.io"`)
+	require.NotContains(t, authConfig, "credsStore")
+}
+
+func Test_ContainerHelper_DaemonlessBuilder(t *testing.T) {
+	ch := &ContainerHelper{}
+	sc := &ServiceConfig{
+		Name:     "api",
+		Language: ServiceLanguagePython,
+		Project:  &ProjectConfig{},
+	}
+
+	sc.Docker.Builder = DockerBuilderBuildKit
+	require.Equal(t, "BuildKit", ch.RequiredExternalTools(t.Context(), sc)[0].Name())
+
+	sc.Docker.Builder = DockerBuilderBuildah
+	require.Equal(t, "Buildah", ch.RequiredExternalTools(t.Context(), sc)[0].Name())
+
+	// remote builds take precedence over the builder
+	sc.Docker.RemoteBuild = true
+	require.Nil(t, ch.daemonlessBuilder(sc))
+	require.Empty(t, ch.RequiredExternalTools(t.Context(), sc))
+}
+
+func Test_validateDockerBuilder(t *testing.T) {
+	require.NoError(t, validateDockerBuilder(DockerProjectOptions{}))
+	require.NoError(t, validateDockerBuilder(DockerProjectOptions{Builder: DockerBuilderDocker}))
+
+	err := validateDockerBuilder(DockerProjectOptions{Builder: "kaniko"})
+	require.ErrorContains(t, err, "unsupported docker.builder 'kaniko'")
+
+	err = validateDockerBuilder(DockerProjectOptions{
+		CacheFrom: []osutil.ExpandableString{osutil.NewExpandableString("contoso.azurecr.io/cache")},
+	})
+	var errWithSuggestion *internal.ErrorWithSuggestion
+	require.ErrorAs(t, err, &errWithSuggestion)
+	require.Contains(t, errWithSuggestion.Suggestion, "builder: buildah")
+}
+
+func Test_dockerBuildArgsWithValues(t *testing.T) {
+	lookupEnv := func(name string) (string, bool) {
+		if name == "FROM_ENV" {
+			return "env-value", true
+		}
+		return "", false
+	}
+
+	args, err := dockerBuildArgsWithValues([]string{"EXPLICIT=value", "MULTI=a=b", "FROM_ENV"}, lookupEnv)
+	require.NoError(t, err)
+	require.Equal(t, []string{"EXPLICIT=value", "MULTI=a=b", "FROM_ENV=env-value"}, args)
+
+	_, err = dockerBuildArgsWithValues([]string{"MISSING"}, lookupEnv)
+	require.ErrorContains(t, err, `resolving docker build arg "MISSING"`)
+}
@@ -29,6 +29,12 @@ type DockerProjectOptions struct {
 	RemoteBuild bool                      `yaml:"remoteBuild,omitempty"  json:"remoteBuild,omitempty"`
 	Network     string                    `yaml:"network,omitempty"     json:"network,omitempty"`
 	BuildArgs   []osutil.ExpandableString `yaml:"buildArgs,omitempty"   json:"buildArgs,omitempty"`
+	// Builder is the tool that builds the image locally. Ignored when RemoteBuild is set.
+	Builder DockerBuilder `yaml:"builder,omitempty"     json:"builder,omitempty"`
+	// CacheFrom and CacheTo are the registry references the build cache is imported from and exported to.
+	// Only supported by the daemonless builders.
+	CacheFrom []osutil.ExpandableString `yaml:"cacheFrom,omitempty"   json:"cacheFrom,omitempty"`
+	CacheTo   []osutil.ExpandableString `yaml:"cacheTo,omitempty"     json:"cacheTo,omitempty"`
 	// not supported from azure.yaml directly yet. Adding it for Aspire to use it, initially.
 	// Aspire would pass the secret keys, which are env vars that azd will set just to run docker build.
 	BuildSecrets []string `yaml:"-"                     json:"-"`
@@ -40,6 +46,23 @@ type DockerProjectOptions struct {
 	InMemDockerfile []byte `yaml:"-"                     json:"-"`
 }
 
+// DockerBuilder is the tool that builds the container image of a service locally.
+type DockerBuilder string
+
+const (
+	// DockerBuilderDocker builds the image with the Docker (or Podman) daemon and pushes it in the publish step.
+	DockerBuilderDocker DockerBuilder = "docker"
+	// DockerBuilderBuildah builds and pushes the image with buildah, without a container daemon.
+	DockerBuilderBuildah DockerBuilder = "buildah"
+	// DockerBuilderBuildKit builds and pushes the image with buildctl, the client of a BuildKit daemon.
+	DockerBuilderBuildKit DockerBuilder = "buildkit"
+)
+
+// Daemonless reports whether the builder builds and pushes the image in a single step, without the Docker daemon.
+func (b DockerBuilder) Daemonless() bool {
+	return b == DockerBuilderBuildah || b == DockerBuilderBuildKit
+}
+
 type dockerProject struct {
 	env                 *environment.Environment
 	docker              *docker.Cli
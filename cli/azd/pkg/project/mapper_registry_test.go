@@ -1303,6 +1303,10 @@ func Test_createTypedResourceProps(t *testing.T) {
 		{"ContainerApp_empty", ResourceTypeHostContainerApp, nil, false, "ContainerAppProps"},
 		{"ContainerApp_json", ResourceTypeHostContainerApp,
 			mustJSON(t, ContainerAppProps{Port: 3000}), false, "ContainerAppProps"},
+		{"FunctionApp_empty", ResourceTypeHostFunctionApp, nil, false, "FunctionAppProps"},
+		{"FunctionApp_json", ResourceTypeHostFunctionApp,
+			mustJSON(t, FunctionAppProps{Runtime: FunctionAppRuntime{Stack: FunctionAppRuntimeStackPython}}),
+			false, "FunctionAppProps"},
 		{"Cosmos_empty", ResourceTypeDbCosmos, nil, false, "CosmosDBProps"},
 		{"Cosmos_json", ResourceTypeDbCosmos,
 			mustJSON(t, CosmosDBProps{Containers: []CosmosDBContainerProps{{Name: "c1"}}}), false, "CosmosDBProps"},
@@ -1343,6 +1347,7 @@ func Test_createTypedResourceProps_InvalidJSON(t *testing.T) {
 	types := []ResourceType{
 		ResourceTypeHostAppService,
 		ResourceTypeHostContainerApp,
+		ResourceTypeHostFunctionApp,
 		ResourceTypeDbCosmos,
 		ResourceTypeStorage,
 		ResourceTypeAiProject,
@@ -1377,6 +1382,7 @@ func Test_getResourceTypeKinds(t *testing.T) {
 		{"Cosmos", ResourceTypeDbCosmos, []string{"GlobalDocumentDB"}},
 		{"Mongo", ResourceTypeDbMongo, []string{"MongoDB"}},
 		{"AppService", ResourceTypeHostAppService, []string{"app", "app,linux"}},
+		{"FunctionApp", ResourceTypeHostFunctionApp, []string{"functionapp", "functionapp,linux"}},
 		{"Unknown", ResourceType("unknown"), []string{}},
 	}
 
@@ -79,6 +79,14 @@ func Parse(ctx context.Context, yamlContent string) (*ProjectConfig, error) {
 		return nil, err
 	}
 
+	if err := projectConfig.Environments.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing project %s: %w", projectConfig.Name, err)
+	}
+
+	if err := projectConfig.Env.Validate(); err != nil {
+		return nil, fmt.Errorf("parsing project %s: %w", projectConfig.Name, err)
+	}
+
 	var err error
 	projectConfig.Infra.Provider, err = provisioning.ParseProvider(projectConfig.Infra.Provider)
 	if err != nil {
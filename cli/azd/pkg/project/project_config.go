@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/azure/azure-dev/cli/azd/pkg/cloud"
+	"github.com/azure/azure-dev/cli/azd/pkg/environment"
 	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/infra/provisioning"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
@@ -37,9 +38,16 @@ type ProjectConfig struct {
 	State             *state.Config              `yaml:"state,omitempty"`
 	Platform          *platform.Config           `yaml:"platform,omitempty"`
 	Workflows         workflow.WorkflowMap       `yaml:"workflows,omitempty"`
+	Environments      environment.Templates      `yaml:"environments,omitempty"`
+	Env               environment.Schema         `yaml:"env,omitempty"`
 	Cloud             *cloud.Config              `yaml:"cloud,omitempty"`
 	Resources         map[string]*ResourceConfig `yaml:"resources,omitempty"`
 
+	// Concurrency limits how many execution graph steps carrying a given tag (e.g. "build",
+	// "provision", "deploy:aks") run at once during `azd up` and `azd deploy`. Tags without an
+	// entry, or with a non-positive limit, are unbounded.
+	Concurrency map[string]int `yaml:"concurrency,omitempty"`
+
 	// AdditionalProperties captures any unknown YAML fields for extension support
 	AdditionalProperties map[string]any `yaml:",inline"`
 
@@ -18,15 +18,21 @@ func AllResourceTypes() []ResourceType {
 		ResourceTypeDbMySql,
 		ResourceTypeDbMongo,
 		ResourceTypeDbCosmos,
+		ResourceTypeDbSqlServer,
 		ResourceTypeHostAppService,
 		ResourceTypeHostContainerApp,
+		ResourceTypeHostFunctionApp,
 		ResourceTypeOpenAiModel,
 		ResourceTypeMessagingEventHubs,
 		ResourceTypeMessagingServiceBus,
+		ResourceTypeMessagingEventGrid,
+		ResourceTypeMessagingSignalR,
+		ResourceTypeMessagingWebPubSub,
 		ResourceTypeStorage,
 		ResourceTypeAiProject,
 		ResourceTypeAiSearch,
 		ResourceTypeKeyVault,
+		ResourceTypeAppConfig,
 	}
 }
 
@@ -36,15 +42,21 @@ const (
 	ResourceTypeDbMySql             ResourceType = "db.mysql"
 	ResourceTypeDbMongo             ResourceType = "db.mongo"
 	ResourceTypeDbCosmos            ResourceType = "db.cosmos"
+	ResourceTypeDbSqlServer         ResourceType = "db.sqlserver"
 	ResourceTypeHostContainerApp    ResourceType = "host.containerapp"
 	ResourceTypeHostAppService      ResourceType = "host.appservice"
+	ResourceTypeHostFunctionApp     ResourceType = "host.functionapp"
 	ResourceTypeOpenAiModel         ResourceType = "ai.openai.model"
 	ResourceTypeMessagingEventHubs  ResourceType = "messaging.eventhubs"
 	ResourceTypeMessagingServiceBus ResourceType = "messaging.servicebus"
+	ResourceTypeMessagingEventGrid  ResourceType = "messaging.eventgrid"
+	ResourceTypeMessagingSignalR    ResourceType = "messaging.signalr"
+	ResourceTypeMessagingWebPubSub  ResourceType = "messaging.webpubsub"
 	ResourceTypeStorage             ResourceType = "storage"
 	ResourceTypeAiProject           ResourceType = "ai.project"
 	ResourceTypeAiSearch            ResourceType = "ai.search"
 	ResourceTypeKeyVault            ResourceType = "keyvault"
+	ResourceTypeAppConfig           ResourceType = "appconfig"
 )
 
 func (r ResourceType) String() string {
@@ -59,16 +71,26 @@ func (r ResourceType) String() string {
 		return "MongoDB"
 	case ResourceTypeDbCosmos:
 		return "CosmosDB"
+	case ResourceTypeDbSqlServer:
+		return "Azure SQL Database"
 	case ResourceTypeHostAppService:
 		return "App Service"
 	case ResourceTypeHostContainerApp:
 		return "Container App"
+	case ResourceTypeHostFunctionApp:
+		return "Function App"
 	case ResourceTypeOpenAiModel:
 		return "Open AI Model"
 	case ResourceTypeMessagingEventHubs:
 		return "Event Hubs"
 	case ResourceTypeMessagingServiceBus:
 		return "Service Bus"
+	case ResourceTypeMessagingEventGrid:
+		return "Event Grid"
+	case ResourceTypeMessagingSignalR:
+		return "SignalR"
+	case ResourceTypeMessagingWebPubSub:
+		return "Web PubSub"
 	case ResourceTypeStorage:
 		return "Storage Account"
 	case ResourceTypeAiProject:
@@ -77,6 +99,8 @@ func (r ResourceType) String() string {
 		return "AI Search"
 	case ResourceTypeKeyVault:
 		return "Key Vault"
+	case ResourceTypeAppConfig:
+		return "App Configuration"
 	}
 
 	return ""
@@ -90,7 +114,8 @@ func (r ResourceType) AzureResourceType() string {
 	// Alongside this, the resource type should be updated in the scaffold/resource_meta.go
 	// See notes there on how to easily obtain the resource type for new AVM modules.
 	switch r {
-	case ResourceTypeHostAppService:
+	case ResourceTypeHostAppService,
+		ResourceTypeHostFunctionApp:
 		return "Microsoft.Web/sites"
 	case ResourceTypeHostContainerApp:
 		return "Microsoft.App/containerApps"
@@ -100,6 +125,8 @@ func (r ResourceType) AzureResourceType() string {
 		return "Microsoft.DBforPostgreSQL/flexibleServers/databases"
 	case ResourceTypeDbMySql:
 		return "Microsoft.DBforMySQL/flexibleServers/databases"
+	case ResourceTypeDbSqlServer:
+		return "Microsoft.Sql/servers/databases"
 	case ResourceTypeDbMongo:
 		return "Microsoft.DocumentDB/databaseAccounts/mongodbDatabases"
 	case ResourceTypeOpenAiModel:
@@ -110,6 +137,12 @@ func (r ResourceType) AzureResourceType() string {
 		return "Microsoft.EventHub/namespaces"
 	case ResourceTypeMessagingServiceBus:
 		return "Microsoft.ServiceBus/namespaces"
+	case ResourceTypeMessagingEventGrid:
+		return "Microsoft.EventGrid/topics"
+	case ResourceTypeMessagingSignalR:
+		return "Microsoft.SignalRService/signalR"
+	case ResourceTypeMessagingWebPubSub:
+		return "Microsoft.SignalRService/webPubSub"
 	case ResourceTypeStorage:
 		return "Microsoft.Storage/storageAccounts"
 	case ResourceTypeKeyVault:
@@ -118,6 +151,8 @@ func (r ResourceType) AzureResourceType() string {
 		return "Microsoft.CognitiveServices/accounts/projects"
 	case ResourceTypeAiSearch:
 		return "Microsoft.Search/searchServices"
+	case ResourceTypeAppConfig:
+		return "Microsoft.AppConfiguration/configurationStores"
 	}
 
 	return ""
@@ -176,6 +211,8 @@ func (r *ResourceConfig) MarshalYAML() (any, error) {
 			errMarshal = marshalRawProps(raw.Props.(AppServiceProps))
 		case ResourceTypeHostContainerApp:
 			errMarshal = marshalRawProps(raw.Props.(ContainerAppProps))
+		case ResourceTypeHostFunctionApp:
+			errMarshal = marshalRawProps(raw.Props.(FunctionAppProps))
 		case ResourceTypeDbCosmos:
 			errMarshal = marshalRawProps(raw.Props.(CosmosDBProps))
 		case ResourceTypeMessagingEventHubs:
@@ -236,6 +273,12 @@ func (r *ResourceConfig) UnmarshalYAML(value *yaml.Node) error {
 			return err
 		}
 		raw.Props = cap
+	case ResourceTypeHostFunctionApp:
+		fap := FunctionAppProps{}
+		if err := unmarshalProps(&fap); err != nil {
+			return err
+		}
+		raw.Props = fap
 	case ResourceTypeDbCosmos:
 		cdp := CosmosDBProps{}
 		if err := unmarshalProps(&cdp); err != nil {
@@ -296,6 +339,28 @@ type AppServiceRuntime struct {
 	Version string                 `yaml:"version,omitempty"`
 }
 
+type FunctionAppProps struct {
+	Env     []ServiceEnvVar    `yaml:"env,omitempty"`
+	Runtime FunctionAppRuntime `yaml:"runtime,omitempty"`
+}
+
+type FunctionAppRuntimeStack string
+
+const (
+	FunctionAppRuntimeStackDotNetIsolated FunctionAppRuntimeStack = "dotnet-isolated"
+	FunctionAppRuntimeStackNode           FunctionAppRuntimeStack = "node"
+	FunctionAppRuntimeStackPython         FunctionAppRuntimeStack = "python"
+	FunctionAppRuntimeStackJava           FunctionAppRuntimeStack = "java"
+	// FunctionAppRuntimeStackCustom runs the app as a custom handler, e.g. a Go executable.
+	FunctionAppRuntimeStackCustom FunctionAppRuntimeStack = "custom"
+)
+
+// FunctionAppRuntime is the language runtime of a function app on the Flex Consumption plan.
+type FunctionAppRuntime struct {
+	Stack   FunctionAppRuntimeStack `yaml:"stack,omitempty"`
+	Version string                  `yaml:"version,omitempty"`
+}
+
 type ServiceEnvVar struct {
 	Name string `yaml:"name,omitempty"`
 
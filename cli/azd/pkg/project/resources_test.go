@@ -26,15 +26,21 @@ func Test_AllResourceTypes(t *testing.T) {
 		ResourceTypeDbMySql,
 		ResourceTypeDbMongo,
 		ResourceTypeDbCosmos,
+		ResourceTypeDbSqlServer,
 		ResourceTypeHostAppService,
 		ResourceTypeHostContainerApp,
+		ResourceTypeHostFunctionApp,
 		ResourceTypeOpenAiModel,
 		ResourceTypeMessagingEventHubs,
 		ResourceTypeMessagingServiceBus,
+		ResourceTypeMessagingEventGrid,
+		ResourceTypeMessagingSignalR,
+		ResourceTypeMessagingWebPubSub,
 		ResourceTypeStorage,
 		ResourceTypeAiProject,
 		ResourceTypeAiSearch,
 		ResourceTypeKeyVault,
+		ResourceTypeAppConfig,
 	}
 
 	require.Equal(t, expected, all)
@@ -84,6 +90,20 @@ func Test_ResourceType_String(t *testing.T) {
 		{"Foundry", ResourceTypeAiProject, "Foundry"},
 		{"AI Search", ResourceTypeAiSearch, "AI Search"},
 		{"Key Vault", ResourceTypeKeyVault, "Key Vault"},
+		{
+			"Azure SQL Database",
+			ResourceTypeDbSqlServer,
+			"Azure SQL Database",
+		},
+		{"Function App", ResourceTypeHostFunctionApp, "Function App"},
+		{"Event Grid", ResourceTypeMessagingEventGrid, "Event Grid"},
+		{"SignalR", ResourceTypeMessagingSignalR, "SignalR"},
+		{"Web PubSub", ResourceTypeMessagingWebPubSub, "Web PubSub"},
+		{
+			"App Configuration",
+			ResourceTypeAppConfig,
+			"App Configuration",
+		},
 		{
 			"unknown returns empty",
 			ResourceType("unknown.type"),
@@ -174,6 +194,36 @@ func Test_ResourceType_AzureResourceType(t *testing.T) {
 			ResourceTypeAiSearch,
 			"Microsoft.Search/searchServices",
 		},
+		{
+			"DbSqlServer",
+			ResourceTypeDbSqlServer,
+			"Microsoft.Sql/servers/databases",
+		},
+		{
+			"FunctionApp",
+			ResourceTypeHostFunctionApp,
+			"Microsoft.Web/sites",
+		},
+		{
+			"EventGrid",
+			ResourceTypeMessagingEventGrid,
+			"Microsoft.EventGrid/topics",
+		},
+		{
+			"SignalR",
+			ResourceTypeMessagingSignalR,
+			"Microsoft.SignalRService/signalR",
+		},
+		{
+			"WebPubSub",
+			ResourceTypeMessagingWebPubSub,
+			"Microsoft.SignalRService/webPubSub",
+		},
+		{
+			"AppConfig",
+			ResourceTypeAppConfig,
+			"Microsoft.AppConfiguration/configurationStores",
+		},
 		{
 			"unknown returns empty",
 			ResourceType("custom.thing"),
@@ -174,8 +174,8 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				existing.ResourceType = resourceMeta.ParentForEval
 			}
 
-			if res.Type == ResourceTypeKeyVault {
-				// For Key Vault, we grant read access to secrets by default
+			if res.Type == ResourceTypeKeyVault || res.Type == ResourceTypeAppConfig {
+				// For Key Vault and App Configuration, we grant read access to secrets and settings by default
 				existing.RoleAssignments = resourceMeta.RoleAssignments.Read
 			}
 
@@ -222,6 +222,10 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 			infraSpec.DbMySql = &scaffold.DatabaseMysql{
 				DatabaseName: res.Name,
 			}
+		case ResourceTypeDbSqlServer:
+			infraSpec.DbSqlServer = &scaffold.DatabaseSqlServer{
+				DatabaseName: res.Name,
+			}
 		case ResourceTypeHostAppService:
 			svcConfig, ok := projectConfig.Services[res.Name]
 			if !ok {
@@ -263,6 +267,24 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				return nil, err
 			}
 
+			infraSpec.Services = append(infraSpec.Services, svcSpec)
+		case ResourceTypeHostFunctionApp:
+			svcSpec := scaffold.ServiceSpec{
+				Name: res.Name,
+				Env:  map[string]string{},
+				Host: scaffold.FunctionAppKind,
+			}
+
+			err := mapFunctionApp(res, &svcSpec, &infraSpec)
+			if err != nil {
+				return nil, err
+			}
+
+			err = mapHostUses(res, &svcSpec, backendMapping, existingMap, projectConfig)
+			if err != nil {
+				return nil, err
+			}
+
 			infraSpec.Services = append(infraSpec.Services, svcSpec)
 		case ResourceTypeOpenAiModel:
 			props := res.Props.(AIModelProps)
@@ -298,6 +320,21 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 				Queues: props.Queues,
 				Topics: props.Topics,
 			}
+		case ResourceTypeMessagingEventGrid:
+			if infraSpec.EventGrid != nil {
+				return nil, fmt.Errorf("only one event grid resource is currently allowed")
+			}
+			infraSpec.EventGrid = &scaffold.EventGrid{Name: res.Name}
+		case ResourceTypeMessagingSignalR:
+			if infraSpec.SignalR != nil {
+				return nil, fmt.Errorf("only one signalr resource is currently allowed")
+			}
+			infraSpec.SignalR = &scaffold.SignalR{Name: res.Name}
+		case ResourceTypeMessagingWebPubSub:
+			if infraSpec.WebPubSub != nil {
+				return nil, fmt.Errorf("only one web pubsub resource is currently allowed")
+			}
+			infraSpec.WebPubSub = &scaffold.WebPubSub{Name: res.Name}
 		case ResourceTypeStorage:
 			if infraSpec.StorageAccount != nil {
 				return nil, fmt.Errorf("only one storage account resource is currently allowed")
@@ -335,6 +372,11 @@ func infraSpec(projectConfig *ProjectConfig) (*scaffold.InfraSpec, error) {
 			infraSpec.KeyVault = &scaffold.KeyVault{}
 		case ResourceTypeAiSearch:
 			infraSpec.AISearch = &scaffold.AISearch{}
+		case ResourceTypeAppConfig:
+			if infraSpec.AppConfig != nil {
+				return nil, fmt.Errorf("only one app configuration resource is currently allowed")
+			}
+			infraSpec.AppConfig = &scaffold.AppConfig{Name: res.Name}
 		}
 	}
 
@@ -388,6 +430,24 @@ func mapHostProps(
 	infraSpec *scaffold.InfraSpec,
 	port int,
 	env []ServiceEnvVar,
+) error {
+	if err := mapHostEnv(res, svcSpec, infraSpec, env); err != nil {
+		return err
+	}
+
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port value %d for host %s must be between 1 and 65535", port, res.Name)
+	}
+
+	svcSpec.Port = port
+	return nil
+}
+
+func mapHostEnv(
+	res *ResourceConfig,
+	svcSpec *scaffold.ServiceSpec,
+	infraSpec *scaffold.InfraSpec,
+	env []ServiceEnvVar,
 ) error {
 	for _, envVar := range env {
 		if len(envVar.Value) == 0 && len(envVar.Secret) == 0 {
@@ -419,11 +479,6 @@ func mapHostProps(
 		svcSpec.Env[envVar.Name] = evaluatedValue
 	}
 
-	if port < 1 || port > 65535 {
-		return fmt.Errorf("port value %d for host %s must be between 1 and 65535", port, res.Name)
-	}
-
-	svcSpec.Port = port
 	return nil
 }
 
@@ -471,6 +526,27 @@ func mapAppService(
 	return nil
 }
 
+// mapFunctionApp maps a function app host. Unlike other hosts, function apps are triggered by the Functions host
+// and do not listen on a port of their own.
+func mapFunctionApp(res *ResourceConfig, svcSpec *scaffold.ServiceSpec, infraSpec *scaffold.InfraSpec) error {
+	props := res.Props.(FunctionAppProps)
+
+	if len(props.Runtime.Stack) == 0 {
+		return fmt.Errorf("resources.%s.runtime.stack is required", res.Name)
+	}
+
+	if len(props.Runtime.Version) == 0 {
+		return fmt.Errorf("resources.%s.runtime.version is required", res.Name)
+	}
+
+	svcSpec.Runtime = &scaffold.RuntimeInfo{
+		Type:    string(props.Runtime.Stack),
+		Version: props.Runtime.Version,
+	}
+
+	return mapHostEnv(res, svcSpec, infraSpec, props.Env)
+}
+
 func mapHostUses(
 	res *ResourceConfig,
 	svcSpec *scaffold.ServiceSpec,
@@ -529,8 +605,11 @@ func mapHostUses(
 			svcSpec.DbMySql = &scaffold.DatabaseReference{DatabaseName: useRes.Name}
 		case ResourceTypeDbRedis:
 			svcSpec.DbRedis = &scaffold.DatabaseReference{DatabaseName: useRes.Name}
+		case ResourceTypeDbSqlServer:
+			svcSpec.DbSqlServer = &scaffold.DatabaseReference{DatabaseName: useRes.Name}
 		case ResourceTypeHostAppService,
-			ResourceTypeHostContainerApp:
+			ResourceTypeHostContainerApp,
+			ResourceTypeHostFunctionApp:
 			if svcSpec.Frontend == nil {
 				svcSpec.Frontend = &scaffold.Frontend{}
 			}
@@ -544,6 +623,12 @@ func mapHostUses(
 			svcSpec.EventHubs = &scaffold.EventHubs{}
 		case ResourceTypeMessagingServiceBus:
 			svcSpec.ServiceBus = &scaffold.ServiceBus{}
+		case ResourceTypeMessagingEventGrid:
+			svcSpec.EventGrid = &scaffold.EventGridReference{}
+		case ResourceTypeMessagingSignalR:
+			svcSpec.SignalR = &scaffold.SignalRReference{}
+		case ResourceTypeMessagingWebPubSub:
+			svcSpec.WebPubSub = &scaffold.WebPubSubReference{}
 		case ResourceTypeStorage:
 			svcSpec.StorageAccount = &scaffold.StorageReference{}
 		case ResourceTypeAiProject:
@@ -552,6 +637,8 @@ func mapHostUses(
 			svcSpec.AISearch = &scaffold.AISearchReference{}
 		case ResourceTypeKeyVault:
 			svcSpec.KeyVault = &scaffold.KeyVaultReference{}
+		case ResourceTypeAppConfig:
+			svcSpec.AppConfig = &scaffold.AppConfigReference{}
 		}
 	}
 
@@ -752,7 +839,7 @@ func genBicepParamsFromEnvSubst(
 // are automatically added to the project configuration. Returns an empty slice if none exist.
 func DependentResourcesOf(resource *ResourceConfig) []*ResourceConfig {
 	switch resource.Type {
-	case ResourceTypeDbMongo, ResourceTypeDbMySql, ResourceTypeDbPostgres, ResourceTypeDbRedis:
+	case ResourceTypeDbMongo, ResourceTypeDbMySql, ResourceTypeDbPostgres, ResourceTypeDbRedis, ResourceTypeDbSqlServer:
 		return []*ResourceConfig{{Name: "vault", Type: ResourceTypeKeyVault}}
 	default:
 		return nil
@@ -374,6 +374,40 @@ func Test_mapContainerApp(t *testing.T) {
 	assert.Equal(t, 8080, svcSpec.Port)
 }
 
+func Test_mapFunctionApp(t *testing.T) {
+	t.Run("Runtime", func(t *testing.T) {
+		res := &ResourceConfig{
+			Name: "func",
+			Props: FunctionAppProps{
+				Runtime: FunctionAppRuntime{Stack: FunctionAppRuntimeStackPython, Version: "3.12"},
+				Env:     []ServiceEnvVar{{Name: "KEY", Value: "val"}},
+			},
+		}
+		svcSpec := &scaffold.ServiceSpec{Env: map[string]string{}}
+		infraSpec := &scaffold.InfraSpec{}
+
+		err := mapFunctionApp(res, svcSpec, infraSpec)
+		require.NoError(t, err)
+		require.NotNil(t, svcSpec.Runtime)
+		assert.Equal(t, "python", svcSpec.Runtime.Type)
+		assert.Equal(t, "3.12", svcSpec.Runtime.Version)
+		assert.Equal(t, 0, svcSpec.Port)
+		assert.Equal(t, "'val'", svcSpec.Env["KEY"])
+	})
+
+	t.Run("MissingRuntime_returns_error", func(t *testing.T) {
+		res := &ResourceConfig{
+			Name:  "func",
+			Props: FunctionAppProps{Runtime: FunctionAppRuntime{Stack: FunctionAppRuntimeStackNode}},
+		}
+		svcSpec := &scaffold.ServiceSpec{Env: map[string]string{}}
+
+		err := mapFunctionApp(res, svcSpec, &scaffold.InfraSpec{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "runtime.version is required")
+	})
+}
+
 func Test_emitVariableExpression_PropertyExpr(t *testing.T) {
 	env := EmitEnv{
 		FuncMap:         scaffold.BaseEmitBicepFuncMap(),
@@ -72,6 +72,9 @@ type ServiceConfig struct {
 	// Whether to build the service remotely. Only applicable to function app services.
 	// When set to nil (unset), the default behavior based on language is used.
 	RemoteBuild *bool `yaml:"remoteBuild,omitempty"`
+	// Retry policy for the service's publish and deploy steps on transient failures.
+	// When nil, each step runs once.
+	Retry *ServiceRetryOptions `yaml:"retry,omitempty"`
 
 	// AdditionalProperties captures any unknown YAML fields for extension support
 	AdditionalProperties map[string]any `yaml:",inline"`
@@ -91,6 +94,17 @@ type configuredHookRegistration struct {
 	cancel    context.CancelFunc
 }
 
+// ServiceRetryOptions configures how azd retries a service's publish and deploy steps after a
+// transient failure, such as a throttled registry push or a conflicting ARM operation.
+type ServiceRetryOptions struct {
+	// The total number of attempts, including the first. Values below 2 disable retries.
+	MaxAttempts int `yaml:"maxAttempts,omitempty"`
+	// The wait before the first retry, in seconds. It doubles after each retry.
+	DelaySeconds int `yaml:"delaySeconds,omitempty"`
+	// The upper bound for the wait between attempts, in seconds. Zero means no bound.
+	MaxDelaySeconds int `yaml:"maxDelaySeconds,omitempty"`
+}
+
 type DotNetContainerAppOptions struct {
 	Manifest    *apphost.Manifest
 	AppHostPath string
@@ -207,7 +207,7 @@ func (t *aksTarget) Publish(
 	// Only publish the container image if a package output has been defined
 	// Empty package details is a valid scenario for any AKS deployment that does not build any containers
 	// Ex) Helm charts, or other manifests that reference external images
-	if serviceConfig.Docker.RemoteBuild || hasPackage {
+	if serviceConfig.Docker.RemoteBuild || serviceConfig.Docker.Builder.Daemonless() || hasPackage {
 		// Login, tag & push container image to ACR
 		publishResult, err := t.containerHelper.Publish(
 			ctx, serviceConfig, serviceContext, targetResource, t.env, progress, publishOptions)
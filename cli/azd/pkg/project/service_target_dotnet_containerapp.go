@@ -15,7 +15,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"sync"
 	"text/template"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
@@ -29,6 +28,7 @@ import (
 	"github.com/azure/azure-dev/cli/azd/pkg/containerapps"
 	"github.com/azure/azure-dev/cli/azd/pkg/cosmosdb"
 	"github.com/azure/azure-dev/cli/azd/pkg/environment"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
 	"github.com/azure/azure-dev/cli/azd/pkg/keyvault"
 	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
 	"github.com/azure/azure-dev/cli/azd/pkg/sqldb"
@@ -159,12 +159,15 @@ func (at *dotnetContainerAppTarget) Deploy(
 	// own artifacts directory (--artifacts-path), so intermediate build outputs
 	// are isolated and concurrent publishes cannot interfere with each other.
 	//
-	// If a build gate mutex is present in ctx, we first try to create a per-service
+	// If a build gate pool is present in ctx, we first try to create a per-service
 	// temp dir for --artifacts-path. If that fails (e.g. disk full, permissions),
-	// we fall back to acquiring the mutex during image preparation only.
+	// we fall back to holding a slot of the gate pool during image preparation only.
+	//
+	// Independently, image preparation holds a slot of the ContainerBuildPool so
+	// user-configured limits on concurrent container builds apply here too.
 	imageCtx := ctx
-	var buildGateMu *sync.Mutex // non-nil only in the mutex fallback path
-	if BuildGateFromContext(ctx) != nil {
+	var gatePool string // non-empty only in the serialization fallback path
+	if gate := BuildGateFromContext(ctx); gate != "" {
 		// Create a per-service temp directory for isolated intermediate outputs.
 		// Sanitize the service name to safe filesystem characters and keep the
 		// prefix short to avoid MAX_PATH issues on Windows when MSBuild nests
@@ -179,22 +182,29 @@ func (at *dotnetContainerAppTarget) Deploy(
 			}()
 			imageCtx = dotnet.ContextWithArtifactsPath(ctx, artifactsDir)
 		} else {
-			// Fallback: use the mutex to serialize image preparation only.
-			// We lock/unlock explicitly (not defer) so the Azure deployment
-			// portion that follows runs in parallel.
+			// Fallback: use the gate pool to serialize image preparation only.
+			// Slots are released explicitly (not deferred) so the Azure
+			// deployment portion that follows runs in parallel.
 			log.Printf("warning: failed to create artifacts temp dir for %s: %v; falling back to serial build",
 				serviceConfig.Name, mkdirErr)
-			buildGateMu = BuildGateFromContext(ctx)
+			gatePool = gate
 		}
 	}
 
-	if buildGateMu != nil {
-		buildGateMu.Lock()
+	// Acquire the gate before the shared build pool so concurrent deploy steps
+	// always take the two pools in the same order.
+	releaseGate, err := exegraph.AcquirePool(ctx, gatePool)
+	if err != nil {
+		return nil, err
 	}
-	imageResult, err := at.prepareContainerImage(imageCtx, serviceConfig, serviceContext, targetResource, progress)
-	if buildGateMu != nil {
-		buildGateMu.Unlock()
+	releaseBuild, err := exegraph.AcquirePool(ctx, ContainerBuildPool)
+	if err != nil {
+		releaseGate()
+		return nil, err
 	}
+	imageResult, err := at.prepareContainerImage(imageCtx, serviceConfig, serviceContext, targetResource, progress)
+	releaseBuild()
+	releaseGate()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,41 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package templates
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+)
+
+// CatalogMediaType is the media type of the layer holding the templates of an OCI template source. Catalogs pushed as
+// the only layer of their artifact may use any media type.
+const CatalogMediaType = "application/vnd.microsoft.azd.template.catalog.v1+json"
+
+// newOciTemplateSource creates a new template source from the catalog pushed as an OCI artifact at location.
+func newOciTemplateSource(ctx context.Context, name string, location string, client *oci.Client) (Source, error) {
+	ref, err := oci.ParseReference(location)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := client.FetchManifest(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("request failed for template source '%s', %w", location, err)
+	}
+
+	layer, err := manifest.Layer(CatalogMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("finding the template catalog in %s: %w", ref, err)
+	}
+
+	var catalog strings.Builder
+	if err := client.FetchBlob(ctx, ref, layer, &catalog); err != nil {
+		return nil, fmt.Errorf("request failed for template source '%s', %w", location, err)
+	}
+
+	return newJsonTemplateSource(name, catalog.String())
+}
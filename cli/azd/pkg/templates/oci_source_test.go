@@ -0,0 +1,52 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package templates
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/azure/azure-dev/cli/azd/test/mocks/mockoci"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewOciTemplateSource(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	catalog, err := json.Marshal(testTemplates)
+	require.NoError(t, err)
+
+	registry := mockoci.NewRegistry(t)
+	location := registry.Push(t, "azd/templates", "latest", mockoci.Layer{
+		MediaType: CatalogMediaType,
+		Content:   catalog,
+	})
+
+	mockContext := mocks.NewMockContext(t.Context())
+	sm := NewSourceManager(NewSourceOptions(), mockContext.Container, &mockUserConfigManager{}, http.DefaultClient)
+
+	source, err := sm.CreateSource(*mockContext.Context, &SourceConfig{
+		Key:      "catalog",
+		Name:     "catalog",
+		Type:     SourceKindOci,
+		Location: location,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "catalog", source.Name())
+
+	templates, err := source.ListTemplates(*mockContext.Context)
+	require.NoError(t, err)
+	require.Len(t, templates, len(testTemplates))
+
+	_, err = sm.CreateSource(*mockContext.Context, &SourceConfig{
+		Key:      "missing",
+		Name:     "missing",
+		Type:     SourceKindOci,
+		Location: "oci://" + registry.Host + "/azd/templates:missing",
+	})
+	require.ErrorIs(t, err, oci.ErrNotFound)
+}
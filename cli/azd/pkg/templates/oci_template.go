@@ -0,0 +1,104 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package templates
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/azure/azure-dev/cli/azd/pkg/rzip"
+)
+
+// TemplateMediaType is the media type of the layer holding the files of a template pushed as an OCI artifact, a
+// gzipped tarball of the template repository. Templates pushed as the only layer of their artifact may use any
+// media type.
+const TemplateMediaType = "application/vnd.microsoft.azd.template.v1.tar+gzip"
+
+// OciTemplatePuller pulls templates pushed as artifacts to an OCI registry, which lets catalogs of OCI template
+// sources reference templates with an oci:// repository path.
+type OciTemplatePuller struct {
+	serviceLocator ioc.ServiceLocator
+	transport      policy.Transporter
+
+	clientOnce     sync.Once
+	clientInstance *oci.Client
+}
+
+// NewOciTemplatePuller creates a new OciTemplatePuller.
+func NewOciTemplatePuller(serviceLocator ioc.ServiceLocator, transport policy.Transporter) *OciTemplatePuller {
+	return &OciTemplatePuller{
+		serviceLocator: serviceLocator,
+		transport:      transport,
+	}
+}
+
+// Pull extracts the files of the template pushed as an OCI artifact at location into destination.
+func (p *OciTemplatePuller) Pull(ctx context.Context, location string, destination string) error {
+	ref, err := oci.ParseReference(location)
+	if err != nil {
+		return err
+	}
+
+	client := p.client()
+
+	manifest, err := client.FetchManifest(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("fetching template: %w", err)
+	}
+
+	layer, err := manifest.Layer(TemplateMediaType)
+	if err != nil {
+		return fmt.Errorf("finding the template files in %s: %w", ref, err)
+	}
+
+	archive, err := os.CreateTemp("", "azd-template-oci-*.tar.gz")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	defer os.Remove(archive.Name())
+
+	err = client.FetchBlob(ctx, ref, layer, archive)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("fetching template: %w", err)
+	}
+
+	if err := rzip.ExtractTarGzToDirectory(archive.Name(), destination); err != nil {
+		return fmt.Errorf("extracting template files from %s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// client returns the client pulling the template artifacts.
+func (p *OciTemplatePuller) client() *oci.Client {
+	p.clientOnce.Do(func() {
+		p.clientInstance = newOciClient(p.serviceLocator, p.transport)
+	})
+
+	return p.clientInstance
+}
+
+// newOciClient creates a client pulling artifacts from OCI registries, which authenticates with the credentials of
+// the Docker configuration.
+func newOciClient(serviceLocator ioc.ServiceLocator, transport policy.Transporter) *oci.Client {
+	var commandRunner exec.CommandRunner
+	if serviceLocator != nil {
+		if err := serviceLocator.Resolve(&commandRunner); err != nil {
+			log.Printf("docker credential helpers are not available: %v", err)
+			commandRunner = nil
+		}
+	}
+
+	return oci.NewClient(transport, oci.NewDockerCredentialStore(commandRunner))
+}
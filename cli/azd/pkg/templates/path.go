@@ -8,13 +8,16 @@ import (
 	"log"
 	"net/url"
 	"os"
+	pathpkg "path"
 	"path/filepath"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
-// remoteURIPrefixes lists URI scheme prefixes that identify remote git repositories.
+// remoteURIPrefixes lists URI scheme prefixes that identify remote git repositories and templates pushed as OCI
+// artifacts.
 var remoteURIPrefixes = []string{
 	"git@",
 	"git://",
@@ -22,6 +25,7 @@ var remoteURIPrefixes = []string{
 	"file://",
 	"http://",
 	"https://",
+	oci.Scheme,
 }
 
 // isRemoteURI returns true if path starts with a known remote URI prefix.
@@ -35,7 +39,7 @@ func isRemoteURI(path string) bool {
 }
 
 // Absolute returns an absolute template path, given a possibly relative template path. An absolute path also corresponds to
-// a fully-qualified URI to a git repository.
+// a fully-qualified URI to a git repository or an oci:// reference to a template pushed as an OCI artifact.
 //
 // See Template.Path for more details.
 func Absolute(path string) (string, error) {
@@ -118,6 +122,7 @@ func LooksLikeLocalPath(path string) bool {
 //   - "https://github.com/Azure-Samples/todo-nodejs-mongo" → "todo-nodejs-mongo"
 //   - "https://github.com/Azure-Samples/todo-nodejs-mongo.git" → "todo-nodejs-mongo"
 //   - "../my-template" → "my-template"
+//   - "oci://contoso.azurecr.io/azd/templates/todo-nodejs-mongo:1.0.0" → "todo-nodejs-mongo"
 func DeriveDirectoryName(templatePath string) string {
 	path := strings.TrimSpace(templatePath)
 	path = strings.TrimRight(path, "/")
@@ -127,6 +132,13 @@ func DeriveDirectoryName(templatePath string) string {
 
 	var name string
 
+	// For OCI references, use the last segment of the repository, without the tag or digest
+	if oci.IsReference(path) {
+		if ref, err := oci.ParseReference(path); err == nil {
+			path = pathpkg.Base(ref.Repository)
+		}
+	}
+
 	// For remote URIs, extract the last path segment from the URL
 	if isRemoteURI(path) {
 		// Handle git@host:owner/repo format
@@ -314,6 +314,11 @@ func Test_DeriveDirectoryName(t *testing.T) {
 			input:    "",
 			expected: "new-project",
 		},
+		{
+			name:     "OciReference",
+			input:    "oci://contoso.azurecr.io/azd/templates/todo-nodejs-mongo:1.0.0",
+			expected: "todo-nodejs-mongo",
+		},
 	}
 
 	for _, tt := range tests {
@@ -336,6 +341,7 @@ func Test_IsLocalPath(t *testing.T) {
 		{"GitProtocolURI", "git://github.com/Azure-Samples/my-template.git", false},
 		{"SshURL", "ssh://git@github.com/Azure-Samples/my-template.git", false},
 		{"FileURL", "file:///home/user/my-template", false},
+		{"OciReference", "oci://contoso.azurecr.io/azd/templates/my-template:1.0.0", false},
 		{"WindowsAbsPath", `C:\code\my-template`, true},
 		{"UnixAbsPath", "/home/user/my-template", true},
 		{"RelativePath", "my-template", true},
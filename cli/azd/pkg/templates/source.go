@@ -28,6 +28,8 @@ const (
 	SourceKindGh         SourceKind = "gh"
 	SourceKindResource   SourceKind = "default"
 	SourceKindAwesomeAzd SourceKind = "awesome-azd"
+	// SourceKindOci is a template catalog pushed as an artifact to an OCI registry.
+	SourceKindOci SourceKind = "oci"
 )
 
 type SourceConfig struct {
@@ -9,11 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/azure/azure-dev/cli/azd/pkg/config"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
 	"github.com/azure/azure-dev/cli/azd/pkg/ioc"
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
 	"github.com/azure/azure-dev/cli/azd/pkg/tools/github"
 	"github.com/azure/azure-dev/cli/azd/resources"
 )
@@ -79,6 +81,9 @@ type sourceManager struct {
 	serviceLocator ioc.ServiceLocator
 	configManager  config.UserConfigManager
 	transport      policy.Transporter
+
+	ociClientOnce     sync.Once
+	ociClientInstance *oci.Client
 }
 
 // NewSourceManager creates a new SourceManager.
@@ -229,6 +234,8 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 		source, err = newFileTemplateSource(config.Name, config.Location)
 	case SourceKindUrl:
 		source, err = newUrlTemplateSource(ctx, config.Name, config.Location, sm.transport)
+	case SourceKindOci:
+		source, err = newOciTemplateSource(ctx, config.Name, config.Location, sm.ociClient())
 	case SourceKindAwesomeAzd:
 		source, err = newAwesomeAzdTemplateSource(ctx, SourceAwesomeAzd.Name, SourceAwesomeAzd.Location, sm.transport)
 	case SourceKindResource:
@@ -252,6 +259,16 @@ func (sm *sourceManager) CreateSource(ctx context.Context, config *SourceConfig)
 	return source, nil
 }
 
+// ociClient returns the client pulling the catalogs of OCI template sources, which authenticates with the
+// credentials of the Docker configuration.
+func (sm *sourceManager) ociClient() *oci.Client {
+	sm.ociClientOnce.Do(func() {
+		sm.ociClientInstance = newOciClient(sm.serviceLocator, sm.transport)
+	})
+
+	return sm.ociClientInstance
+}
+
 func (sm *sourceManager) addInternal(source *SourceConfig) error {
 	config, err := sm.configManager.Load()
 	if err != nil {
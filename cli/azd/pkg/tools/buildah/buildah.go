@@ -0,0 +1,193 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package buildah wraps the buildah CLI, which builds container images from a Dockerfile without a container daemon.
+package buildah
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/blang/semver/v4"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *Cli) versionInfo() tools.VersionInfo {
+	// --cache-from and --cache-to were added in buildah 1.27
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 1,
+			Minor: 27,
+			Patch: 0},
+		UpdateCommand: "Visit https://github.com/containers/buildah/blob/main/install.md to upgrade",
+	}
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := cli.commandRunner.ToolInPath("buildah"); err != nil {
+		return err
+	}
+
+	versionOutput, err := tools.ExecuteCommand(ctx, cli.commandRunner, "buildah", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("buildah version: %s", versionOutput)
+
+	version, err := tools.ExtractVersion(versionOutput)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+
+	versionInfo := cli.versionInfo()
+	if version.LT(versionInfo.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: versionInfo}
+	}
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://github.com/containers/buildah/blob/main/install.md"
+}
+
+func (cli *Cli) Name() string {
+	return "Buildah"
+}
+
+// BuildAndPush builds the image described by options and pushes it to its registry, writing the output of buildah
+// to buildProgress when it is not nil. Images built for more than one platform are pushed as a manifest list.
+//
+// The image is removed from the local storage of buildah once it is pushed.
+func (cli *Cli) BuildAndPush(
+	ctx context.Context,
+	cwd string,
+	options *docker.BuildPushOptions,
+	buildProgress io.Writer,
+) error {
+	var authArgs []string
+	if options.Credentials != nil {
+		tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-buildah")
+		if err != nil {
+			return fmt.Errorf("building image: %w", err)
+		}
+		defer func() {
+			// fail to remove tmp files is not so bad as the OS will delete it
+			// eventually
+			_ = os.RemoveAll(tmpFolder)
+		}()
+
+		authFile, err := docker.WriteAuthConfig(tmpFolder, options.Credentials)
+		if err != nil {
+			return fmt.Errorf("building image: %w", err)
+		}
+
+		authArgs = []string{"--authfile", authFile}
+	}
+
+	multiPlatform := len(options.Platforms) > 1
+
+	args := []string{
+		"build",
+		"-f", options.Dockerfile,
+		"--platform", strings.Join(options.Platforms, ","),
+	}
+
+	if multiPlatform {
+		args = append(args, "--manifest", options.Image)
+	} else {
+		args = append(args, "-t", options.Image)
+	}
+
+	if options.Target != "" {
+		args = append(args, "--target", options.Target)
+	}
+
+	if options.Network != "" {
+		args = append(args, "--network", options.Network)
+	}
+
+	for _, arg := range options.BuildArgs {
+		args = append(args, "--build-arg", arg)
+	}
+
+	for _, arg := range options.BuildSecrets {
+		args = append(args, "--secret", arg)
+	}
+
+	if len(options.CacheFrom) > 0 || len(options.CacheTo) > 0 {
+		// the layer cache of buildah is only used when building with --layers
+		args = append(args, "--layers")
+	}
+
+	for _, ref := range options.CacheFrom {
+		args = append(args, "--cache-from", ref)
+	}
+
+	for _, ref := range options.CacheTo {
+		args = append(args, "--cache-to", ref)
+	}
+
+	args = append(args, authArgs...)
+	args = append(args, options.Context)
+
+	if _, err := cli.run(ctx, cwd, options.BuildEnv, buildProgress, args...); err != nil {
+		return fmt.Errorf("building image: %w", err)
+	}
+
+	var pushArgs, removeArgs []string
+	if multiPlatform {
+		pushArgs = []string{"manifest", "push", "--all"}
+		removeArgs = []string{"manifest", "rm", options.Image}
+	} else {
+		pushArgs = []string{"push"}
+		removeArgs = []string{"rmi", options.Image}
+	}
+
+	pushArgs = append(pushArgs, authArgs...)
+	pushArgs = append(pushArgs, options.Image, "docker://"+options.Image)
+
+	if _, err := cli.run(ctx, cwd, options.BuildEnv, buildProgress, pushArgs...); err != nil {
+		return fmt.Errorf("pushing image: %w", err)
+	}
+
+	if _, err := cli.run(ctx, cwd, nil, nil, removeArgs...); err != nil {
+		log.Printf("removing image %s from buildah storage: %v", options.Image, err)
+	}
+
+	return nil
+}
+
+func (cli *Cli) run(
+	ctx context.Context,
+	cwd string,
+	env []string,
+	progress io.Writer,
+	args ...string,
+) (exec.RunResult, error) {
+	runArgs := exec.NewRunArgs("buildah", args...).WithCwd(cwd).WithEnv(env)
+
+	if progress != nil {
+		runArgs = runArgs.WithStdOut(progress).WithStdErr(progress)
+	}
+
+	return cli.commandRunner.Run(ctx, runArgs)
+}
@@ -0,0 +1,151 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package buildah
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "Supported", version: "buildah version 1.33.7 (image-spec 1.1.0, runtime-spec 1.1.0)"},
+		{name: "TooOld", version: "buildah version 1.23.1 (image-spec 1.0.1, runtime-spec 1.0.2-dev)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			mockContext.CommandRunner.MockToolInPath("buildah", nil)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return command == "buildah --version"
+			}).Respond(exec.NewRunResult(0, tt.version, ""))
+
+			err := NewCli(mockContext.CommandRunner).CheckInstalled(*mockContext.Context)
+			if tt.wantErr {
+				var semverErr *tools.ErrSemver
+				require.ErrorAs(t, err, &semverErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_BuildAndPush(t *testing.T) {
+	t.Run("SinglePlatform", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		ran := []exec.RunArgs{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "buildah"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = append(ran, args)
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := NewCli(mockContext.CommandRunner).BuildAndPush(*mockContext.Context, "src", &docker.BuildPushOptions{
+			Dockerfile: "src/Dockerfile",
+			Context:    "src",
+			Platforms:  []string{"linux/amd64"},
+			Target:     "final",
+			BuildArgs:  []string{"foo=bar"},
+			BuildEnv:   []string{"KEY=value"},
+			Image:      "contoso.io/api:v1",
+		}, nil)
+		require.NoError(t, err)
+
+		require.Len(t, ran, 3)
+		require.Equal(t, []string{
+			"build",
+			"-f", "src/Dockerfile",
+			"--platform", "linux/amd64",
+			"-t", "contoso.io/api:v1",
+			"--target", "final",
+			"--build-arg", "foo=bar",
+			"src",
+		}, ran[0].Args)
+		require.Equal(t, "src", ran[0].Cwd)
+		require.Equal(t, []string{"KEY=value"}, ran[0].Env)
+		require.Equal(t, []string{"push", "contoso.io/api:v1", "docker://contoso.io/api:v1"}, ran[1].Args)
+		require.Equal(t, []string{"rmi", "contoso.io/api:v1"}, ran[2].Args)
+	})
+
+	t.Run("MultiPlatformWithCacheAndCredentials", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+		mockContext := mocks.NewMockContext(t.Context())
+		ran := []exec.RunArgs{}
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return args.Cmd == "buildah"
+		}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+			ran = append(ran, args)
+			if args.Args[1] == "rm" {
+				return exec.NewRunResult(0, "", ""), nil
+			}
+
+			idx := slices.Index(args.Args, "--authfile")
+			require.GreaterOrEqual(t, idx, 0)
+			contents, err := os.ReadFile(args.Args[idx+1])
+			require.NoError(t, err)
+			require.Contains(t, string(contents), "contoso.azurecr.io")
+
+			return exec.NewRunResult(0, "", ""), nil
+		})
+
+		err := NewCli(mockContext.CommandRunner).BuildAndPush(*mockContext.Context, "src", &docker.BuildPushOptions{
+			Dockerfile: "src/Dockerfile",
+			Context:    "src",
+			Platforms:  []string{"linux/amd64", "linux/arm64"},
+			Image:      "contoso.azurecr.io/api:v1",
+			CacheFrom:  []string{"contoso.azurecr.io/cache"},
+			CacheTo:    []string{"contoso.azurecr.io/cache"},
+			Credentials: &docker.RegistryCredentials{
+				Server:   "contoso.azurecr.io",
+				Username: "user",
+				Password: "password",
+			},
+		}, nil)
+		require.NoError(t, err)
+
+		require.Len(t, ran, 3)
+		build := strings.Join(ran[0].Args, " ")
+		require.Contains(t, build, "--platform linux/amd64,linux/arm64 --manifest contoso.azurecr.io/api:v1")
+		require.Contains(t, build,
+			"--layers --cache-from contoso.azurecr.io/cache --cache-to contoso.azurecr.io/cache --authfile")
+		require.Equal(t, []string{"manifest", "push", "--all"}, ran[1].Args[:3])
+		require.Equal(t,
+			[]string{"contoso.azurecr.io/api:v1", "docker://contoso.azurecr.io/api:v1"},
+			ran[1].Args[len(ran[1].Args)-2:])
+		require.Equal(t, []string{"manifest", "rm", "contoso.azurecr.io/api:v1"}, ran[2].Args)
+	})
+
+	t.Run("BuildError", func(t *testing.T) {
+		mockContext := mocks.NewMockContext(t.Context())
+		mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+			return strings.HasPrefix(command, "buildah build")
+		}).SetError(errors.New("exit code: 1"))
+
+		err := NewCli(mockContext.CommandRunner).BuildAndPush(*mockContext.Context, "src", &docker.BuildPushOptions{
+			Dockerfile: "src/Dockerfile",
+			Context:    "src",
+			Platforms:  []string{"linux/amd64"},
+			Image:      "contoso.io/api:v1",
+		}, nil)
+		require.ErrorContains(t, err, "building image")
+	})
+}
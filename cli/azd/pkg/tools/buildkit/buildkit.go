@@ -0,0 +1,154 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package buildkit wraps buildctl, the client of a BuildKit daemon, which builds container images from a Dockerfile
+// without a Docker daemon.
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/blang/semver/v4"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+type Cli struct {
+	commandRunner exec.CommandRunner
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *Cli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 0,
+			Minor: 11,
+			Patch: 0},
+		UpdateCommand: "Visit https://github.com/moby/buildkit/releases to upgrade",
+	}
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	if err := cli.commandRunner.ToolInPath("buildctl"); err != nil {
+		return err
+	}
+
+	versionOutput, err := tools.ExecuteCommand(ctx, cli.commandRunner, "buildctl", "--version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+	log.Printf("buildctl version: %s", versionOutput)
+
+	version, err := tools.ExtractVersion(versionOutput)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+
+	versionInfo := cli.versionInfo()
+	if version.LT(versionInfo.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: versionInfo}
+	}
+
+	return nil
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://github.com/moby/buildkit#quick-start"
+}
+
+func (cli *Cli) Name() string {
+	return "BuildKit"
+}
+
+// BuildAndPush builds the image described by options with the Dockerfile frontend and pushes it to its registry,
+// writing the output of buildctl to buildProgress when it is not nil. Images built for more than one platform are
+// pushed as an image index.
+//
+// The BuildKit daemon is the one configured for buildctl, e.g. with BUILDKIT_HOST. Build arguments must have a
+// value, as buildctl does not read them from its environment.
+func (cli *Cli) BuildAndPush(
+	ctx context.Context,
+	cwd string,
+	options *docker.BuildPushOptions,
+	buildProgress io.Writer,
+) error {
+	env := options.BuildEnv
+	if options.Credentials != nil {
+		tmpFolder, err := os.MkdirTemp(os.TempDir(), "azd-buildctl")
+		if err != nil {
+			return fmt.Errorf("building image: %w", err)
+		}
+		defer func() {
+			// fail to remove tmp files is not so bad as the OS will delete it
+			// eventually
+			_ = os.RemoveAll(tmpFolder)
+		}()
+
+		if _, err := docker.WriteAuthConfig(tmpFolder, options.Credentials); err != nil {
+			return fmt.Errorf("building image: %w", err)
+		}
+
+		// buildctl reads the registry credentials from the Docker configuration in DOCKER_CONFIG
+		env = append(env[:len(env):len(env)], "DOCKER_CONFIG="+tmpFolder)
+	}
+
+	args := []string{
+		"build",
+		"--frontend", "dockerfile.v0",
+		"--local", "context=" + options.Context,
+		"--local", "dockerfile=" + filepath.Dir(options.Dockerfile),
+		"--opt", "filename=" + filepath.Base(options.Dockerfile),
+		"--opt", "platform=" + strings.Join(options.Platforms, ","),
+	}
+
+	if options.Target != "" {
+		args = append(args, "--opt", "target="+options.Target)
+	}
+
+	if options.Network != "" {
+		args = append(args, "--opt", "force-network-mode="+options.Network)
+	}
+
+	for _, arg := range options.BuildArgs {
+		args = append(args, "--opt", "build-arg:"+arg)
+	}
+
+	for _, arg := range options.BuildSecrets {
+		args = append(args, "--secret", arg)
+	}
+
+	for _, ref := range options.CacheFrom {
+		args = append(args, "--import-cache", "type=registry,ref="+ref)
+	}
+
+	for _, ref := range options.CacheTo {
+		args = append(args, "--export-cache", "type=registry,ref="+ref+",mode=max")
+	}
+
+	args = append(args, "--output", fmt.Sprintf("type=image,name=%s,push=true", options.Image))
+
+	runArgs := exec.NewRunArgs("buildctl", args...).WithCwd(cwd).WithEnv(env)
+	if buildProgress != nil {
+		runArgs = runArgs.WithStdOut(buildProgress).WithStdErr(buildProgress)
+	}
+
+	if _, err := cli.commandRunner.Run(ctx, runArgs); err != nil {
+		return fmt.Errorf("building image: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package buildkit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools/docker"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "Supported", version: "buildctl github.com/moby/buildkit v0.12.5 bac3f2b673f3f9d33e79046008e7a38e856b3dc6"},
+		{name: "TooOld", version: "buildctl github.com/moby/buildkit v0.10.6 0c9b5aeb269c740650786ba77d882b0259415ec7",
+			wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			mockContext.CommandRunner.MockToolInPath("buildctl", nil)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return command == "buildctl --version"
+			}).Respond(exec.NewRunResult(0, tt.version, ""))
+
+			err := NewCli(mockContext.CommandRunner).CheckInstalled(*mockContext.Context)
+			if tt.wantErr {
+				var semverErr *tools.ErrSemver
+				require.ErrorAs(t, err, &semverErr)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_BuildAndPush(t *testing.T) {
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+	mockContext := mocks.NewMockContext(t.Context())
+	var ran *exec.RunArgs
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "buildctl"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = &args
+
+		var dockerConfig string
+		for _, envVar := range args.Env {
+			if value, ok := strings.CutPrefix(envVar, "DOCKER_CONFIG="); ok {
+				dockerConfig = value
+			}
+		}
+		require.NotEmpty(t, dockerConfig)
+
+		contents, err := os.ReadFile(filepath.Join(dockerConfig, "config.json"))
+		require.NoError(t, err)
+		require.Contains(t, string(contents), "contoso.azurecr.io")
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	dockerfile := filepath.Join("src", "docker", "Dockerfile.prod")
+	buildEnv := []string{"KEY=value"}
+	err := NewCli(mockContext.CommandRunner).BuildAndPush(*mockContext.Context, "src", &docker.BuildPushOptions{
+		Dockerfile:   dockerfile,
+		Context:      "src",
+		Platforms:    []string{"linux/amd64", "linux/arm64"},
+		Target:       "final",
+		Network:      "host",
+		BuildArgs:    []string{"foo=bar"},
+		BuildSecrets: []string{"id=token,env=TOKEN"},
+		BuildEnv:     buildEnv,
+		Image:        "contoso.azurecr.io/api:v1",
+		CacheFrom:    []string{"contoso.azurecr.io/cache"},
+		CacheTo:      []string{"contoso.azurecr.io/cache"},
+		Credentials: &docker.RegistryCredentials{
+			Server:   "contoso.azurecr.io",
+			Username: "user",
+			Password: "password",
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	require.NotNil(t, ran)
+	require.Equal(t, []string{
+		"build",
+		"--frontend", "dockerfile.v0",
+		"--local", "context=src",
+		"--local", "dockerfile=" + filepath.Join("src", "docker"),
+		"--opt", "filename=Dockerfile.prod",
+		"--opt", "platform=linux/amd64,linux/arm64",
+		"--opt", "target=final",
+		"--opt", "force-network-mode=host",
+		"--opt", "build-arg:foo=bar",
+		"--secret", "id=token,env=TOKEN",
+		"--import-cache", "type=registry,ref=contoso.azurecr.io/cache",
+		"--export-cache", "type=registry,ref=contoso.azurecr.io/cache,mode=max",
+		"--output", "type=image,name=contoso.azurecr.io/api:v1,push=true",
+	}, ran.Args)
+	require.Equal(t, "src", ran.Cwd)
+
+	// the build env of the caller is not modified
+	require.Equal(t, []string{"KEY=value"}, buildEnv)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// BuildPushOptions are the options of a Dockerfile build that pushes the built image to a registry, as run by the
+// daemonless builders which do not keep the image in a local image store.
+type BuildPushOptions struct {
+	// Dockerfile is the path to the Dockerfile.
+	Dockerfile string
+	// Context is the path to the build context.
+	Context string
+	// Platforms are the target platforms of the image, e.g. linux/amd64. A multi-platform image is pushed as
+	// an image index when more than one platform is specified.
+	Platforms []string
+	// Target is the build stage to build, when set.
+	Target string
+	// Network is the networking mode for RUN instructions, when set.
+	Network string
+	// BuildArgs are the build arguments, in the form NAME=VALUE.
+	BuildArgs []string
+	// BuildSecrets are the build secrets, in the form accepted by `docker build --secret`.
+	BuildSecrets []string
+	// BuildEnv is the environment of the build, in the form NAME=VALUE.
+	BuildEnv []string
+	// Image is the fully qualified name of the image to push.
+	Image string
+	// CacheFrom are the registry references the build cache is imported from.
+	CacheFrom []string
+	// CacheTo are the registry references the build cache is exported to.
+	CacheTo []string
+	// Credentials authenticate to the registry of the image. When nil, the credentials already configured for
+	// the builder are used.
+	Credentials *RegistryCredentials
+}
+
+// RegistryCredentials are the credentials of a container registry.
+type RegistryCredentials struct {
+	Server   string
+	Username string
+	Password string
+}
+
+// WriteAuthConfig writes a Docker config file named config.json to dir, which authenticates to the registry of
+// creds in addition to the registries of the current Docker configuration. It returns the path of the file.
+//
+// The credential store of the current configuration is not kept, as it would take precedence over the
+// credentials written to the file.
+func WriteAuthConfig(dir string, creds *RegistryCredentials) (string, error) {
+	config := map[string]any{}
+
+	current, err := os.ReadFile(filepath.Join(configDir(), "config.json"))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("reading docker config: %w", err)
+	} else if err == nil {
+		if err := json.Unmarshal(current, &config); err != nil {
+			return "", fmt.Errorf("parsing docker config: %w", err)
+		}
+	}
+
+	delete(config, "credsStore")
+	if helpers, ok := config["credHelpers"].(map[string]any); ok {
+		delete(helpers, creds.Server)
+	}
+
+	auths, ok := config["auths"].(map[string]any)
+	if !ok {
+		auths = map[string]any{}
+		config["auths"] = auths
+	}
+
+	auths[creds.Server] = map[string]any{
+		"auth": base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password)),
+	}
+
+	contents, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("marshalling docker config: %w", err)
+	}
+
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, contents, osutil.PermissionFileOwnerOnly); err != nil {
+		return "", fmt.Errorf("writing docker config: %w", err)
+	}
+
+	return configPath, nil
+}
+
+// configDir returns the directory of the current Docker configuration.
+func configDir() string {
+	if dir := os.Getenv("DOCKER_CONFIG"); dir != "" {
+		return dir
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".docker"
+	}
+
+	return filepath.Join(home, ".docker")
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package docker
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WriteAuthConfig(t *testing.T) {
+	creds := &RegistryCredentials{
+		Server:   "contoso.azurecr.io",
+		Username: "user",
+		Password: "password",
+	}
+	wantAuth := base64.StdEncoding.EncodeToString([]byte("user:password"))
+
+	t.Run("NoCurrentConfig", func(t *testing.T) {
+		t.Setenv("DOCKER_CONFIG", t.TempDir())
+
+		configPath, err := WriteAuthConfig(t.TempDir(), creds)
+		require.NoError(t, err)
+
+		config := readConfig(t, configPath)
+		require.Equal(t, map[string]any{
+			"auths": map[string]any{
+				"contoso.azurecr.io": map[string]any{"auth": wantAuth},
+			},
+		}, config)
+	})
+
+	t.Run("MergesCurrentConfig", func(t *testing.T) {
+		currentDir := t.TempDir()
+		t.Setenv("DOCKER_CONFIG", currentDir)
+		require.NoError(t, os.WriteFile(filepath.Join(currentDir, "config.json"), []byte(`{
+			"auths": {"docker.io": {"auth": "ZG9ja2VyOmh1Yg=="}},
+			"credsStore": "desktop",
+			"credHelpers": {"contoso.azurecr.io": "acr-env", "gcr.io": "gcloud"}
+		}`), 0600))
+
+		configPath, err := WriteAuthConfig(t.TempDir(), creds)
+		require.NoError(t, err)
+
+		config := readConfig(t, configPath)
+		require.Equal(t, map[string]any{
+			"auths": map[string]any{
+				"docker.io":          map[string]any{"auth": "ZG9ja2VyOmh1Yg=="},
+				"contoso.azurecr.io": map[string]any{"auth": wantAuth},
+			},
+			"credHelpers": map[string]any{"gcr.io": "gcloud"},
+		}, config)
+	})
+}
+
+func readConfig(t *testing.T, path string) map[string]any {
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	config := map[string]any{}
+	require.NoError(t, json.Unmarshal(contents, &config))
+	return config
+}
@@ -0,0 +1,212 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/blang/semver/v4"
+)
+
+var _ tools.ExternalTool = (*Cli)(nil)
+
+type Cli struct {
+	commandRunner exec.CommandRunner
+	env           []string
+}
+
+func NewCli(commandRunner exec.CommandRunner) *Cli {
+	return &Cli{
+		commandRunner: commandRunner,
+	}
+}
+
+func (cli *Cli) Name() string {
+	return "Pulumi CLI"
+}
+
+func (cli *Cli) InstallUrl() string {
+	return "https://www.pulumi.com/docs/iac/download-install/"
+}
+
+func (cli *Cli) versionInfo() tools.VersionInfo {
+	return tools.VersionInfo{
+		MinimumVersion: semver.Version{
+			Major: 3,
+			Minor: 100,
+			Patch: 0},
+		UpdateCommand: "Download newer version from https://www.pulumi.com/docs/iac/download-install/",
+	}
+}
+
+func (cli *Cli) CheckInstalled(ctx context.Context) error {
+	err := cli.commandRunner.ToolInPath("pulumi")
+	if err != nil {
+		return err
+	}
+	verOutput, err := tools.ExecuteCommand(ctx, cli.commandRunner, "pulumi", "version")
+	if err != nil {
+		return fmt.Errorf("checking %s version: %w", cli.Name(), err)
+	}
+
+	log.Printf("pulumi version: %s", strings.TrimSpace(verOutput))
+
+	pulumiSemver, err := tools.ExtractVersion(verOutput)
+	if err != nil {
+		return fmt.Errorf("converting to semver version fails: %w", err)
+	}
+	updateDetail := cli.versionInfo()
+	if pulumiSemver.LT(updateDetail.MinimumVersion) {
+		return &tools.ErrSemver{ToolName: cli.Name(), VersionInfo: updateDetail}
+	}
+	return nil
+}
+
+// Set environment variables to be used in all pulumi commands
+func (cli *Cli) SetEnv(env []string) {
+	cli.env = env
+}
+
+func (cli *Cli) runCommand(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.
+		NewRunArgs("pulumi", args...).
+		WithEnv(cli.env)
+
+	return cli.commandRunner.Run(ctx, runArgs)
+}
+
+func (cli *Cli) runInteractive(ctx context.Context, args ...string) (exec.RunResult, error) {
+	runArgs := exec.
+		NewRunArgs("pulumi", args...).
+		WithEnv(cli.env).
+		WithInteractive(true)
+
+	return cli.commandRunner.Run(ctx, runArgs)
+}
+
+// SelectStack selects the stack of the project in projectPath, creating it when it does not exist yet.
+func (cli *Cli) SelectStack(ctx context.Context, projectPath string, stack string) error {
+	cmdRes, err := cli.runCommand(ctx,
+		"stack", "select", stack, "--create", "--non-interactive", "--cwd", projectPath)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running pulumi stack select: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
+
+// SetConfig sets a configuration value of the stack. The value is passed through stdin, so secrets never show up
+// in the command line.
+func (cli *Cli) SetConfig(
+	ctx context.Context,
+	projectPath string,
+	stack string,
+	key string,
+	value string,
+	secret bool,
+) error {
+	args := []string{"config", "set", key, "--stack", stack, "--non-interactive", "--cwd", projectPath}
+	if secret {
+		args = append(args, "--secret")
+	} else {
+		args = append(args, "--plaintext")
+	}
+
+	runArgs := exec.
+		NewRunArgs("pulumi", args...).
+		WithEnv(cli.env).
+		WithStdIn(strings.NewReader(value))
+
+	cmdRes, err := cli.commandRunner.Run(ctx, runArgs)
+	if err != nil {
+		return fmt.Errorf(
+			"failed running pulumi config set %s: %s (%w)",
+			key,
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return nil
+}
+
+// Preview runs pulumi preview and returns its JSON output.
+func (cli *Cli) Preview(ctx context.Context, projectPath string, stack string) (string, error) {
+	cmdRes, err := cli.runCommand(ctx,
+		"preview", "--stack", stack, "--json", "--non-interactive", "--cwd", projectPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi preview: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+func (cli *Cli) Up(ctx context.Context, projectPath string, stack string) (string, error) {
+	cmdRes, err := cli.runInteractive(ctx,
+		"up", "--stack", stack, "--yes", "--skip-preview", "--non-interactive", "--cwd", projectPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi up: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+// Destroy deletes the resources of the stack. Unless autoApprove is set, pulumi asks for confirmation first.
+func (cli *Cli) Destroy(ctx context.Context, projectPath string, stack string, autoApprove bool) (string, error) {
+	args := []string{"destroy", "--stack", stack, "--cwd", projectPath}
+	if autoApprove {
+		args = append(args, "--yes", "--skip-preview", "--non-interactive")
+	}
+
+	cmdRes, err := cli.runInteractive(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi destroy: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+// StackOutput returns the outputs of the stack as JSON, secrets included.
+func (cli *Cli) StackOutput(ctx context.Context, projectPath string, stack string) (string, error) {
+	cmdRes, err := cli.runCommand(ctx,
+		"stack", "output", "--stack", stack, "--json", "--show-secrets", "--non-interactive", "--cwd", projectPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack output: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+// StackExport returns the checkpoint of the stack as JSON.
+func (cli *Cli) StackExport(ctx context.Context, projectPath string, stack string) (string, error) {
+	cmdRes, err := cli.runCommand(ctx,
+		"stack", "export", "--stack", stack, "--non-interactive", "--cwd", projectPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running pulumi stack export: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
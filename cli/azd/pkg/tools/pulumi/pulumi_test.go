@@ -0,0 +1,89 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package pulumi
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/exec"
+	"github.com/azure/azure-dev/cli/azd/pkg/tools"
+	"github.com/azure/azure-dev/cli/azd/test/mocks"
+)
+
+func Test_WithEnv(t *testing.T) {
+	ran := false
+	expectedEnvVars := []string{"PULUMI_BACKEND_URL=file:///state"}
+
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		ran = true
+		require.Equal(t, expectedEnvVars, args.Env)
+		require.Equal(t, []string{"stack", "select", "dev", "--create", "--non-interactive", "--cwd", "infra"}, args.Args)
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	cli.SetEnv(expectedEnvVars)
+
+	err := cli.SelectStack(*mockContext.Context, "infra", "dev")
+
+	require.NoError(t, err)
+	require.True(t, ran)
+}
+
+func Test_SetConfig_PassesValueThroughStdIn(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+		return args.Cmd == "pulumi"
+	}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+		require.NotContains(t, args.Args, "s3cr3t")
+		require.Contains(t, args.Args, "--secret")
+
+		value, err := io.ReadAll(args.StdIn)
+		require.NoError(t, err)
+		require.Equal(t, "s3cr3t", string(value))
+
+		return exec.NewRunResult(0, "", ""), nil
+	})
+
+	cli := NewCli(mockContext.CommandRunner)
+	err := cli.SetConfig(*mockContext.Context, "infra", "dev", "dbPassword", "s3cr3t", true)
+	require.NoError(t, err)
+}
+
+func Test_CheckInstalled(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		wantErr bool
+	}{
+		{name: "supported", version: "v3.130.0\n"},
+		{name: "too old", version: "v3.50.0\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockContext := mocks.NewMockContext(t.Context())
+			mockContext.CommandRunner.MockToolInPath("pulumi", nil)
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return strings.Contains(command, "pulumi version")
+			}).Respond(exec.NewRunResult(0, tt.version, ""))
+
+			err := NewCli(mockContext.CommandRunner).CheckInstalled(*mockContext.Context)
+			if tt.wantErr {
+				_, ok := err.(*tools.ErrSemver)
+				require.True(t, ok, "expected ErrSemver, got %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -234,3 +234,66 @@ func (cli *Cli) Destroy(ctx context.Context, modulePath string, additionalArgs .
 	}
 	return cmdRes.Stdout, nil
 }
+
+// Import imports the existing resource id into the state of the terraform resource at address.
+func (cli *Cli) Import(
+	ctx context.Context,
+	modulePath string,
+	address string,
+	id string,
+	additionalArgs ...string,
+) (string, error) {
+	args := []string{
+		fmt.Sprintf("-chdir=%s", modulePath),
+		"import",
+		"-input=false",
+	}
+
+	args = append(args, additionalArgs...)
+	args = append(args, address, id)
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if err != nil {
+		return "", fmt.Errorf(
+			"failed running terraform import: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return cmdRes.Stdout, nil
+}
+
+// planChangesExitCode is the exit code of terraform plan -detailed-exitcode when the plan holds changes.
+const planChangesExitCode = 2
+
+// PlanRefreshOnly writes a refresh-only plan to planFilePath, which only holds the changes made to the remote objects
+// since they were last applied. It reports whether there are any such changes.
+func (cli *Cli) PlanRefreshOnly(
+	ctx context.Context,
+	modulePath string,
+	planFilePath string,
+	additionalArgs ...string,
+) (bool, error) {
+	args := []string{
+		fmt.Sprintf("-chdir=%s", modulePath),
+		"plan",
+		"-refresh-only",
+		"-detailed-exitcode",
+		"-input=false",
+		fmt.Sprintf("-out=%s", planFilePath),
+		"-lock=false",
+	}
+
+	args = append(args, additionalArgs...)
+	cmdRes, err := cli.runCommand(ctx, args...)
+	if cmdRes.ExitCode == planChangesExitCode {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf(
+			"failed running terraform plan: %s (%w)",
+			cmdRes.Stderr,
+			err,
+		)
+	}
+	return false, nil
+}
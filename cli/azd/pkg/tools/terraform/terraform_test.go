@@ -614,3 +614,45 @@ func Test_ImplementsExternalTool(t *testing.T) {
 	// Verify the Cli type satisfies the tools.ExternalTool interface
 	var _ tools.ExternalTool = cli
 }
+
+func Test_PlanRefreshOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		exitCode    int
+		runErr      error
+		wantChanges bool
+		wantErr     bool
+	}{
+		{name: "NoChanges", exitCode: 0},
+		{name: "Changes", exitCode: planChangesExitCode, runErr: errors.New("exit code: 2"), wantChanges: true},
+		{name: "Error", exitCode: 1, runErr: errors.New("exit code: 1"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var capturedArgs []string
+			mockContext := mocks.NewMockContext(t.Context())
+			mockContext.CommandRunner.When(func(args exec.RunArgs, command string) bool {
+				return args.Cmd == "terraform"
+			}).RespondFn(func(args exec.RunArgs) (exec.RunResult, error) {
+				capturedArgs = args.Args
+				return exec.NewRunResult(tt.exitCode, "", "plan output"), tt.runErr
+			})
+
+			cli := NewCli(mockContext.CommandRunner)
+			changes, err := cli.PlanRefreshOnly(*mockContext.Context, "/module", "/plan.tfplan", "-var-file=vars.json")
+
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantChanges, changes)
+			require.Equal(t, []string{
+				"-chdir=/module", "plan", "-refresh-only", "-detailed-exitcode", "-input=false",
+				"-out=/plan.tfplan", "-lock=false", "-var-file=vars.json",
+			}, capturedArgs)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/osutil"
+)
+
+// evalCondition evaluates a step's `if` expression. Environment references (`${NAME}`) are expanded
+// with getenv. An expression of the form `<left> == <right>` or `<left> != <right>` compares the
+// expanded sides as strings, ignoring surrounding quotes. Any other expression is true when it
+// expands to a truthy value (1, true, yes in any case).
+func evalCondition(expr string, getenv func(string) string) (bool, error) {
+	for _, op := range []string{"==", "!="} {
+		left, right, found := strings.Cut(expr, op)
+		if !found {
+			continue
+		}
+
+		l, err := expandOperand(left, getenv)
+		if err != nil {
+			return false, err
+		}
+		r, err := expandOperand(right, getenv)
+		if err != nil {
+			return false, err
+		}
+
+		return (l == r) == (op == "=="), nil
+	}
+
+	value, err := expandOperand(expr, getenv)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(value) {
+	case "1", "true", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// expandOperand expands environment references in one side of a condition and strips quotes.
+func expandOperand(operand string, getenv func(string) string) (string, error) {
+	value, err := osutil.NewExpandableString(strings.TrimSpace(operand)).Envsubst(getenv)
+	if err != nil {
+		return "", fmt.Errorf("malformed condition '%s': %w", operand, err)
+	}
+
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		value = value[1 : len(value)-1]
+	}
+
+	return value, nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_evalCondition(t *testing.T) {
+	env := map[string]string{
+		"DEPLOY_INFRA": "true",
+		"SKIP_TESTS":   "no",
+		"ENV_TYPE":     "prod",
+	}
+	getenv := func(key string) string { return env[key] }
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{"${DEPLOY_INFRA}", true},
+		{"${SKIP_TESTS}", false},
+		{"${MISSING}", false},
+		{"YES", true},
+		{"${ENV_TYPE} == prod", true},
+		{"${ENV_TYPE} == 'prod'", true},
+		{`${ENV_TYPE} == "dev"`, false},
+		{"${ENV_TYPE} != dev", true},
+		{"${MISSING} == ''", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalCondition(tt.expr, getenv)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_evalCondition_Malformed(t *testing.T) {
+	_, err := evalCondition("${UNCLOSED", func(string) string { return "" })
+	require.Error(t, err)
+}
@@ -100,6 +100,58 @@ func Test_WorkflowMap_UnmarshallYAML(t *testing.T) {
 		assertWorkflow(t, upWorkflow)
 	})
 
+	t.Run("rich steps", func(t *testing.T) {
+		var workflowMap WorkflowMap
+		yamlString := heredoc.Doc(`
+			release:
+			  - name: unit tests
+			    run: ./scripts/test.sh
+			    continueOnError: true
+			  - azd: provision
+			    if: ${DEPLOY_INFRA}
+			  - parallel:
+			      - azd: deploy api
+			      - run: echo deployed
+			        shell: sh
+		`)
+
+		err := yaml.Unmarshal([]byte(yamlString), &workflowMap)
+		require.NoError(t, err)
+
+		release := workflowMap["release"]
+		require.NotNil(t, release)
+		require.Len(t, release.Steps, 3)
+
+		script := release.Steps[0]
+		require.Equal(t, "unit tests", script.Name)
+		require.True(t, script.ContinueOnError)
+		require.NotNil(t, script.Script)
+		require.Equal(t, "./scripts/test.sh", script.Script.Run)
+		require.False(t, script.Script.ContinueOnError, "continueOnError is handled by the workflow runner")
+		require.Empty(t, script.Script.Name)
+
+		require.Equal(t, []string{"provision"}, release.Steps[1].AzdCommand.Args)
+		require.Equal(t, "${DEPLOY_INFRA}", release.Steps[1].If)
+		require.Nil(t, release.Steps[1].Script)
+
+		group := release.Steps[2]
+		require.Len(t, group.Parallel, 2)
+		require.Equal(t, []string{"deploy", "api"}, group.Parallel[0].AzdCommand.Args)
+		require.Equal(t, "sh", group.Parallel[1].Script.Shell)
+	})
+
+	t.Run("step with several kinds", func(t *testing.T) {
+		var workflowMap WorkflowMap
+		yamlString := heredoc.Doc(`
+			release:
+			  - azd: provision
+			    run: ./scripts/test.sh
+		`)
+
+		err := yaml.Unmarshal([]byte(yamlString), &workflowMap)
+		require.ErrorContains(t, err, "exactly one of 'azd', 'run' or 'parallel'")
+	})
+
 	t.Run("invalid workflow", func(t *testing.T) {
 		var workflowMap WorkflowMap
 		yamlString := heredoc.Doc(`
@@ -120,3 +172,32 @@ func assertWorkflow(t *testing.T, workflow *Workflow) {
 	require.Equal(t, "package", workflow.Steps[0].AzdCommand.Args[0])
 	require.Equal(t, "--all", workflow.Steps[0].AzdCommand.Args[1])
 }
+
+func Test_Step_MarshalYAML_RoundTrip(t *testing.T) {
+	yamlString := heredoc.Doc(`
+		release:
+		  - name: unit tests
+		    run: ./scripts/test.sh
+		    continueOnError: true
+		    if: ${RUN_TESTS} == true
+		  - parallel:
+		      - azd: deploy api
+	`)
+
+	var workflowMap WorkflowMap
+	require.NoError(t, yaml.Unmarshal([]byte(yamlString), &workflowMap))
+
+	data, err := yaml.Marshal(workflowMap)
+	require.NoError(t, err)
+
+	var roundTripped WorkflowMap
+	require.NoError(t, yaml.Unmarshal(data, &roundTripped))
+
+	steps := roundTripped["release"].Steps
+	require.Len(t, steps, 2)
+	require.Equal(t, "unit tests", steps[0].Name)
+	require.Equal(t, "./scripts/test.sh", steps[0].Script.Run)
+	require.Equal(t, "${RUN_TESTS} == true", steps[0].If)
+	require.True(t, steps[0].ContinueOnError)
+	require.Equal(t, []string{"deploy", "api"}, steps[1].Parallel[0].AzdCommand.Args)
+}
@@ -7,10 +7,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"os"
 	"strings"
+	"sync"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/exegraph"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/pkg/input"
+	"github.com/azure/azure-dev/cli/azd/pkg/output"
 )
 
 // AzdCommandRunner abstracts the execution of an azd command given a set of arguments and context.
@@ -18,10 +24,30 @@ type AzdCommandRunner interface {
 	ExecuteContext(ctx context.Context, args []string) error
 }
 
+// ScriptRunner abstracts the execution of a script step, typically through the hook executors.
+type ScriptRunner interface {
+	RunScript(ctx context.Context, name string, script *ext.HookConfig) error
+}
+
+// RunOptions configures the capabilities available to the steps of a workflow run.
+type RunOptions struct {
+	// Scripts executes `run` steps. When nil, workflows containing script steps fail.
+	Scripts ScriptRunner
+
+	// Getenv returns the lookup used to evaluate `if` conditions. It is called before each
+	// conditional step so values written by earlier steps are visible. When nil, conditions are
+	// evaluated against the process environment.
+	Getenv func(ctx context.Context) (func(string) string, error)
+}
+
 // Runner is responsible for executing a workflow
 type Runner struct {
 	azdRunner AzdCommandRunner
 	console   input.Console
+
+	// commandMu serializes azd command steps. Commands share the state of the process, such as the
+	// root command, its flags and the console, so only script steps of a parallel group overlap.
+	commandMu sync.Mutex
 }
 
 // NewRunner creates a new instance of the Runner.
@@ -34,26 +60,124 @@ func NewRunner(azdRunner AzdCommandRunner, console input.Console) *Runner {
 
 // Run executes the specified workflow against the root cobra command
 func (r *Runner) Run(ctx context.Context, workflow *Workflow) error {
+	return r.RunWithOptions(ctx, workflow, RunOptions{})
+}
+
+// RunWithOptions executes the specified workflow, running script steps and evaluating conditions
+// with the given options. Steps run in order; the script steps of a `parallel` group run concurrently.
+func (r *Runner) RunWithOptions(ctx context.Context, workflow *Workflow, options RunOptions) error {
 	for _, step := range workflow.Steps {
-		// Create a child context for this step to enable automatic handler cleanup
-		stepCtx, cancel := context.WithCancel(ctx)
+		if err := r.runStep(ctx, step, options); err != nil {
+			return err
+		}
+	}
 
-		// Execute the step with the step-scoped context and command args
-		err := r.azdRunner.ExecuteContext(stepCtx, step.AzdCommand.Args)
+	return nil
+}
 
-		// Cancel the step context to trigger automatic cleanup of any handlers
-		// registered during this step execution
-		cancel()
+// runStep evaluates the step's condition, executes it and applies its continueOnError setting.
+func (r *Runner) runStep(ctx context.Context, step *Step, options RunOptions) error {
+	if step.If != "" {
+		getenv := os.Getenv
+		if options.Getenv != nil {
+			lookup, err := options.Getenv(ctx)
+			if err != nil {
+				return fmt.Errorf("evaluating condition for step '%s': %w", step.DisplayName(), err)
+			}
+			getenv = lookup
+		}
 
+		run, err := evalCondition(step.If, getenv)
 		if err != nil {
-			// User intentionally aborted — stop the workflow without wrapping the error.
-			// Returning the original error preserves errors.Is checks upstream.
-			if errors.Is(err, internal.ErrAbortedByUser) {
-				return err
-			}
-			return fmt.Errorf("error executing step command '%s': %w", strings.Join(step.AzdCommand.Args, " "), err)
+			return fmt.Errorf("evaluating condition for step '%s': %w", step.DisplayName(), err)
+		}
+
+		if !run {
+			r.console.Message(ctx, output.WithGrayFormat(
+				"Skipping step '%s' since its condition '%s' is false", step.DisplayName(), step.If))
+			return nil
 		}
 	}
 
+	err := r.execStep(ctx, step, options)
+
+	// A user abort always stops the workflow, even for steps that continue on error.
+	if err == nil || !step.ContinueOnError || errors.Is(err, internal.ErrAbortedByUser) {
+		return err
+	}
+
+	r.console.Message(ctx, output.WithWarningFormat("WARNING: %s", err.Error()))
+	r.console.Message(ctx, output.WithWarningFormat(
+		"Execution will continue since continueOnError has been set to true."))
+	log.Println(err.Error())
+
 	return nil
 }
+
+func (r *Runner) execStep(ctx context.Context, step *Step, options RunOptions) error {
+	switch {
+	case len(step.Parallel) > 0:
+		return r.runParallel(ctx, step, options)
+	case step.Script != nil:
+		if options.Scripts == nil {
+			return fmt.Errorf(
+				"step '%s' runs a script, which is not available here: %w",
+				step.DisplayName(), internal.ErrUnsupportedOperation)
+		}
+
+		err := options.Scripts.RunScript(ctx, step.DisplayName(), step.Script)
+		if err != nil && !errors.Is(err, internal.ErrAbortedByUser) {
+			return fmt.Errorf("error executing step script '%s': %w", step.DisplayName(), err)
+		}
+		return err
+	}
+
+	r.commandMu.Lock()
+	defer r.commandMu.Unlock()
+
+	// A parallel group may have been canceled while the step waited for another command to complete
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Create a child context for this step to enable automatic handler cleanup
+	stepCtx, cancel := context.WithCancel(ctx)
+
+	// Execute the step with the step-scoped context and command args
+	err := r.azdRunner.ExecuteContext(stepCtx, step.AzdCommand.Args)
+
+	// Cancel the step context to trigger automatic cleanup of any handlers
+	// registered during this step execution
+	cancel()
+
+	if err != nil {
+		// User intentionally aborted — stop the workflow without wrapping the error.
+		// Returning the original error preserves errors.Is checks upstream.
+		if errors.Is(err, internal.ErrAbortedByUser) {
+			return err
+		}
+		return fmt.Errorf("error executing step command '%s': %w", strings.Join(step.AzdCommand.Args, " "), err)
+	}
+
+	return nil
+}
+
+// runParallel executes the steps of a `parallel` group concurrently through an execution graph.
+// Script steps overlap, while azd command steps wait for each other (see [Runner.commandMu]).
+// The first failing step cancels the others unless it sets continueOnError.
+func (r *Runner) runParallel(ctx context.Context, group *Step, options RunOptions) error {
+	g := exegraph.NewGraph()
+	for i, child := range group.Parallel {
+		if err := g.AddStep(&exegraph.Step{
+			Name: fmt.Sprintf("%d-%s", i+1, child.DisplayName()),
+			Tags: []string{"workflow"},
+			Action: func(ctx context.Context) error {
+				return r.runStep(ctx, child, options)
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return exegraph.Run(ctx, g, exegraph.RunOptions{})
+}
@@ -7,9 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/azure/azure-dev/cli/azd/internal"
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/azure/azure-dev/cli/azd/test/mocks"
 	"github.com/stretchr/testify/require"
 )
@@ -149,3 +154,204 @@ func TestRunner_Run_WrappedErrAbortedByUser(t *testing.T) {
 	require.ErrorIs(t, err, internal.ErrAbortedByUser)
 	require.NotContains(t, err.Error(), "error executing step command")
 }
+
+type mockScriptRunner struct {
+	runFn func(ctx context.Context, name string, script *ext.HookConfig) error
+}
+
+func (m *mockScriptRunner) RunScript(ctx context.Context, name string, script *ext.HookConfig) error {
+	return m.runFn(ctx, name, script)
+}
+
+func TestRunner_RunWithOptions_ConditionsAndScripts(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	var mu sync.Mutex
+	called := []string{}
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		called = append(called, name)
+	}
+
+	runner := NewRunner(&mockCommandRunner{
+		execFn: func(ctx context.Context, args []string) error {
+			record(strings.Join(args, " "))
+			return nil
+		},
+	}, mockContext.Console)
+
+	env := map[string]string{"DEPLOY_INFRA": "false"}
+	options := RunOptions{
+		Scripts: &mockScriptRunner{
+			runFn: func(ctx context.Context, name string, script *ext.HookConfig) error {
+				record("script:" + name)
+				env["DEPLOY_INFRA"] = "true"
+				return nil
+			},
+		},
+		Getenv: func(ctx context.Context) (func(string) string, error) {
+			return func(key string) string { return env[key] }, nil
+		},
+	}
+
+	workflow := &Workflow{
+		Name: "release",
+		Steps: []*Step{
+			{AzdCommand: Command{Args: []string{"provision"}}, If: "${DEPLOY_INFRA}"},
+			{Name: "configure", Script: &ext.HookConfig{Run: "./configure.sh"}},
+			{AzdCommand: Command{Args: []string{"provision"}}, If: "${DEPLOY_INFRA}"},
+		},
+	}
+
+	err := runner.RunWithOptions(*mockContext.Context, workflow, options)
+	require.NoError(t, err)
+	// The first provision is skipped; the script makes the condition true for the second one.
+	require.Equal(t, []string{"script:configure", "provision"}, called)
+}
+
+func TestRunner_RunWithOptions_ContinueOnError(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	stepsCalled := []string{}
+
+	runner := NewRunner(&mockCommandRunner{
+		execFn: func(ctx context.Context, args []string) error {
+			stepsCalled = append(stepsCalled, args[0])
+			if args[0] == "test" {
+				return errors.New("tests failed")
+			}
+			return nil
+		},
+	}, mockContext.Console)
+
+	workflow := &Workflow{
+		Name: "release",
+		Steps: []*Step{
+			{AzdCommand: Command{Args: []string{"test"}}, ContinueOnError: true},
+			{AzdCommand: Command{Args: []string{"deploy"}}},
+		},
+	}
+
+	err := runner.RunWithOptions(*mockContext.Context, workflow, RunOptions{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"test", "deploy"}, stepsCalled)
+}
+
+func TestRunner_RunWithOptions_ContinueOnErrorStillStopsOnAbort(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	runner := NewRunner(&mockCommandRunner{
+		execFn: func(ctx context.Context, args []string) error {
+			return internal.ErrAbortedByUser
+		},
+	}, mockContext.Console)
+
+	workflow := &Workflow{
+		Name: "release",
+		Steps: []*Step{
+			{AzdCommand: Command{Args: []string{"provision"}}, ContinueOnError: true},
+		},
+	}
+
+	err := runner.RunWithOptions(*mockContext.Context, workflow, RunOptions{})
+	require.ErrorIs(t, err, internal.ErrAbortedByUser)
+}
+
+func TestRunner_RunWithOptions_Parallel(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	// Both scripts must be running at the same time for either to finish.
+	var started sync.WaitGroup
+	started.Add(2)
+
+	runner := NewRunner(&mockCommandRunner{
+		execFn: func(ctx context.Context, args []string) error { return nil },
+	}, mockContext.Console)
+
+	options := RunOptions{
+		Scripts: &mockScriptRunner{
+			runFn: func(ctx context.Context, name string, script *ext.HookConfig) error {
+				started.Done()
+				started.Wait()
+				if name == "lint" {
+					return errors.New("lint failed")
+				}
+				return nil
+			},
+		},
+	}
+
+	workflow := &Workflow{
+		Name: "release",
+		Steps: []*Step{
+			{Parallel: []*Step{
+				{Name: "test", Script: &ext.HookConfig{Run: "go test ./..."}},
+				{Name: "lint", Script: &ext.HookConfig{Run: "golangci-lint run"}},
+			}},
+		},
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- runner.RunWithOptions(*mockContext.Context, workflow, options) }()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error executing step script 'lint'")
+	case <-time.After(5 * time.Second):
+		t.Fatal("parallel steps did not run concurrently")
+	}
+}
+
+func TestRunner_RunWithOptions_ParallelCommandsRunOneAtATime(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+
+	var running, peak atomic.Int32
+	var mu sync.Mutex
+	called := []string{}
+
+	runner := NewRunner(&mockCommandRunner{
+		execFn: func(ctx context.Context, args []string) error {
+			current := running.Add(1)
+			defer running.Add(-1)
+			if current > peak.Load() {
+				peak.Store(current)
+			}
+
+			time.Sleep(10 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			called = append(called, strings.Join(args, " "))
+			return nil
+		},
+	}, mockContext.Console)
+
+	workflow := &Workflow{
+		Name: "release",
+		Steps: []*Step{
+			{Parallel: []*Step{
+				{AzdCommand: Command{Args: []string{"deploy", "api"}}},
+				{AzdCommand: Command{Args: []string{"deploy", "web"}}},
+				{AzdCommand: Command{Args: []string{"deploy", "worker"}}},
+			}},
+		},
+	}
+
+	require.NoError(t, runner.RunWithOptions(*mockContext.Context, workflow, RunOptions{}))
+	require.ElementsMatch(t, []string{"deploy api", "deploy web", "deploy worker"}, called)
+	require.Equal(t, int32(1), peak.Load(), "azd commands of a parallel group must not overlap")
+}
+
+func TestRunner_Run_ScriptWithoutScriptRunner(t *testing.T) {
+	mockContext := mocks.NewMockContext(t.Context())
+	runner := NewRunner(&mockCommandRunner{}, mockContext.Console)
+
+	workflow := &Workflow{
+		Name:  "release",
+		Steps: []*Step{{Script: &ext.HookConfig{Run: "./test.sh"}}},
+	}
+
+	err := runner.Run(*mockContext.Context, workflow)
+	require.ErrorIs(t, err, internal.ErrUnsupportedOperation)
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/azure/azure-dev/cli/azd/pkg/ext"
 	"github.com/braydonk/yaml"
 )
 
@@ -87,10 +88,125 @@ func (w *Workflow) unmarshalSteps(rawSteps any) ([]*Step, error) {
 	return steps, nil
 }
 
-// Step stores a single step to execute within a workflow
-// This struct can be expanded over time to support other types of steps/commands
+// Step stores a single step to execute within a workflow.
+// A step runs exactly one of an azd command (`azd`), a hook-style script (`run`) or a group of
+// steps that execute concurrently (`parallel`).
 type Step struct {
+	// Optional display name for the step
+	Name string `yaml:"name,omitempty"`
+	// The azd command to execute
 	AzdCommand Command `yaml:"azd,omitempty"`
+	// The script to execute. Script steps are written like hooks in azure.yaml (`run`, `kind`,
+	// `shell`, `dir`, `interactive`, `secrets`, `windows`, `posix`) and run through the same executors.
+	Script *ext.HookConfig `yaml:"-"`
+	// Steps to execute concurrently. The group completes when all of its steps complete. Only script
+	// steps overlap: azd command steps of the group run one at a time.
+	Parallel []*Step `yaml:"parallel,omitempty"`
+	// Condition over environment values, e.g. `${DEPLOY_INFRA}` or `${AZURE_ENV_TYPE} == prod`.
+	// When it evaluates to false the step is skipped.
+	If string `yaml:"if,omitempty"`
+	// When set to true a failure of this step is reported as a warning and the workflow continues.
+	ContinueOnError bool `yaml:"continueOnError,omitempty"`
+}
+
+// scriptKeys are the step keys that make a step a script step.
+var scriptKeys = []string{"run", "windows", "posix"}
+
+// UnmarshalYAML will unmarshal the Step from YAML, reading hook-style script keys into Script.
+func (s *Step) UnmarshalYAML(unmarshal func(any) error) error {
+	type rawStep Step
+
+	var raw rawStep
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*s = Step(raw)
+
+	var keys map[string]any
+	if err := unmarshal(&keys); err != nil {
+		return err
+	}
+
+	for _, key := range scriptKeys {
+		if _, has := keys[key]; has {
+			var script ext.HookConfig
+			if err := unmarshal(&script); err != nil {
+				return err
+			}
+
+			// The step owns its name and error handling; the hook executor should not see them.
+			script.Name = ""
+			script.ContinueOnError = false
+			s.Script = &script
+			break
+		}
+	}
+
+	return s.validate()
+}
+
+// MarshalYAML will marshal the Step to YAML, writing Script back as inline hook-style keys.
+func (s Step) MarshalYAML() (any, error) {
+	type rawStep Step
+
+	if s.Script == nil {
+		return rawStep(s), nil
+	}
+
+	script := *s.Script
+	script.Name = s.Name
+	script.ContinueOnError = s.ContinueOnError
+
+	var node yaml.Node
+	if err := node.Encode(&script); err != nil {
+		return nil, err
+	}
+
+	if s.If != "" {
+		node.Content = append(node.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: "if"},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: s.If},
+		)
+	}
+
+	return &node, nil
+}
+
+// validate ensures the step defines exactly one kind of work.
+func (s *Step) validate() error {
+	kinds := 0
+	if len(s.AzdCommand.Args) > 0 {
+		kinds++
+	}
+	if s.Script != nil {
+		kinds++
+	}
+	if len(s.Parallel) > 0 {
+		kinds++
+	}
+
+	if kinds != 1 {
+		return fmt.Errorf("workflow step must define exactly one of 'azd', 'run' or 'parallel'")
+	}
+
+	return nil
+}
+
+// DisplayName returns the step name, or a description derived from its contents when unnamed.
+func (s *Step) DisplayName() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.Script != nil && s.Script.Run != "":
+		firstLine, _, _ := strings.Cut(strings.TrimSpace(s.Script.Run), "\n")
+		return strings.TrimSpace(firstLine)
+	case s.Script != nil:
+		return "script"
+	case len(s.Parallel) > 0:
+		return fmt.Sprintf("parallel group (%d steps)", len(s.Parallel))
+	default:
+		return "azd " + strings.Join(s.AzdCommand.Args, " ")
+	}
 }
 
 // NewAzdCommandStep creates a new step that executes an azd command with the specified name and args
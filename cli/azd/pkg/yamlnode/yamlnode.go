@@ -35,6 +35,7 @@ package yamlnode
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -154,6 +155,99 @@ func Append(root *yaml.Node, path string, node *yaml.Node) error {
 	return nil
 }
 
+// Delete removes the node at the given path, along with its key if the node is the value of a mapping node.
+// If the node is not present, ErrNodeNotFound is returned.
+//
+// Examples:
+//   - a.map.key - removes 'key' from 'map'
+//   - b.items[1] - removes the second item of 'items'
+func Delete(root *yaml.Node, path string) error {
+	parts, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	anchor, err := find(root, parts[:len(parts)-1], true)
+	if err != nil {
+		return err
+	}
+	if anchor == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, path)
+	}
+
+	part := parts[len(parts)-1]
+	switch part.kind {
+	case keyElem:
+		if anchor.Kind != yaml.MappingNode {
+			return fmt.Errorf("%w: %s is not a mapping node", ErrNodeWrongKind, part.key)
+		}
+
+		for i := 0; i < len(anchor.Content); i += 2 {
+			if anchor.Content[i].Value == part.key {
+				anchor.Content = slices.Delete(anchor.Content, i, i+2)
+				return nil
+			}
+		}
+	case indexElem:
+		if anchor.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%w: %s is not a sequence node", ErrNodeWrongKind, parts[len(parts)-2].key)
+		}
+
+		if part.idx < len(anchor.Content) {
+			anchor.Content = slices.Delete(anchor.Content, part.idx, part.idx+1)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrNodeNotFound, path)
+}
+
+// Rename renames the key of the mapping node entry at the given path, keeping its position, value and comments.
+// If the node is not present, ErrNodeNotFound is returned.
+//
+// Examples:
+//   - a.map.key - renames 'key' of 'map' to newKey
+func Rename(root *yaml.Node, path string, newKey string) error {
+	parts, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	part := parts[len(parts)-1]
+	if part.kind != keyElem {
+		return fmt.Errorf("%w: %s is not a mapping key", ErrNodeWrongKind, path)
+	}
+
+	anchor, err := find(root, parts[:len(parts)-1], true)
+	if err != nil {
+		return err
+	}
+	if anchor == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, path)
+	}
+
+	if anchor.Kind != yaml.MappingNode {
+		return fmt.Errorf("%w: %s is not a mapping node", ErrNodeWrongKind, part.key)
+	}
+
+	var keyNode *yaml.Node
+	for i := 0; i < len(anchor.Content); i += 2 {
+		switch anchor.Content[i].Value {
+		case newKey:
+			return fmt.Errorf("key '%s' already exists", newKey)
+		case part.key:
+			keyNode = anchor.Content[i]
+		}
+	}
+
+	if keyNode == nil {
+		return fmt.Errorf("%w: %s", ErrNodeNotFound, path)
+	}
+
+	keyNode.Value = newKey
+	return nil
+}
+
 // Encode encodes a value into a YAML node.
 func Encode(value any) (*yaml.Node, error) {
 	var node yaml.Node
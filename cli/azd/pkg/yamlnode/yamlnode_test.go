@@ -191,6 +191,101 @@ func TestAppend(t *testing.T) {
 	}
 }
 
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		parent   string // Path of the parent node after deletion
+		expected any    // Expected parent value after deletion
+		wantErr  bool
+	}{
+		{"Delete key", "root.nested.key", "root.nested", map[string]string{}, false},
+		{"Delete array item", "root.array[1]", "root.array", []string{"item1", "item3"}, false},
+		{"Delete nested object key", "root.mixedArray[1].nestedObj", "root.mixedArray[1]", map[string]string{}, false},
+		{"Delete nested array item", "root.mixedArray[2].nestedArr[0]", "root.mixedArray[2].nestedArr",
+			[]string{"item2"}, false},
+		{"Non-existent key", "root.nested.nonexistent", "", nil, true},
+		{"Non-existent parent", "root.nonexistent.key", "", nil, true},
+		{"Invalid array index", "root.array[3]", "", nil, true},
+		{"Invalid path (not a mapping)", "root.array.key", "", nil, true},
+		{"Invalid path format", "root.array.[1]", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			err := yaml.Unmarshal([]byte(doc), &root)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal YAML: %v", err)
+			}
+
+			err = Delete(&root, tt.path)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				// Verify the parent no longer contains the deleted node
+				node, err := Find(&root, tt.parent)
+				if err != nil {
+					t.Errorf("Failed to get parent value: %v", err)
+					return
+				}
+
+				assertNodeEquals(t, "Delete()", node, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRename(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		newKey   string
+		parent   string // Path of the parent node after renaming
+		expected any    // Expected parent value after renaming
+		wantErr  bool
+	}{
+		{"Rename key", "root.nested.key", "renamed", "root.nested", map[string]string{"renamed": "value"}, false},
+		{"Rename nested object key", "root.mixedArray[1].nestedObj", "obj", "root.mixedArray[1].obj",
+			map[string]string{"deepKey": "deepValue"}, false},
+		{"Existing key", "root.nested", "array", "", nil, true},
+		{"Non-existent key", "root.nested.nonexistent", "renamed", "", nil, true},
+		{"Array item", "root.array[1]", "renamed", "", nil, true},
+		{"Invalid path (not a mapping)", "root.array.key", "renamed", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var root yaml.Node
+			err := yaml.Unmarshal([]byte(doc), &root)
+			if err != nil {
+				t.Fatalf("Failed to unmarshal YAML: %v", err)
+			}
+
+			err = Rename(&root, tt.path, tt.newKey)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Rename() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr {
+				node, err := Find(&root, tt.parent)
+				if err != nil {
+					t.Errorf("Failed to get parent value: %v", err)
+					return
+				}
+
+				assertNodeEquals(t, "Rename()", node, tt.expected)
+			}
+		})
+	}
+}
+
 func assertNodeEquals(t *testing.T, funcName string, node *yaml.Node, expected any) {
 	t.Helper()
 	wantStr, err := yaml.Marshal(expected)
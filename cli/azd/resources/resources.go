@@ -39,3 +39,6 @@ var PipelineFiles embed.FS
 
 //go:embed error_suggestions.yaml
 var ErrorSuggestions []byte
+
+//go:embed prices.json
+var PriceSheet []byte
@@ -37,12 +37,17 @@ func Test_CLI_Extension_ForceInstall(t *testing.T) {
 	_, err := cli.RunCommand(ctx, "ext", "source", "add", "-n", "test-local", "-t", "file", "-l", registryPath)
 	require.NoError(t, err)
 
+	// The local copy of the registry has no trusted public keys to verify the extensions with
+	_, err = cli.RunCommand(ctx, "config", "set", "extension.requireSignatures", "false")
+	require.NoError(t, err)
+
 	// Cleanup function to ensure extension is uninstalled and source removed
 	defer func() {
 		t.Log("Cleaning up: uninstalling microsoft.azd.demo extension")
 		_, _ = cli.RunCommand(ctx, "ext", "uninstall", "microsoft.azd.demo")
 		t.Log("Cleaning up: removing test-local source")
 		_, _ = cli.RunCommand(ctx, "ext", "source", "remove", "test-local")
+		_, _ = cli.RunCommand(ctx, "config", "unset", "extension.requireSignatures")
 	}()
 
 	// Step 1: Install the latest version of microsoft.azd.demo extension
@@ -202,8 +207,14 @@ func Test_CLI_Extension_Capabilities(t *testing.T) {
 	_, err = cliForExtBuild.RunCommand(ctx, "x", "publish")
 	require.NoError(t, err)
 
-	// Install the demo extension from local source
+	// Install the unsigned demo extension from local source
 	t.Log("Installing demo extension from local source")
+	_, err = cliNoSession.RunCommand(ctx, "config", "set", "extension.requireSignatures", "false")
+	require.NoError(t, err)
+	defer func() {
+		_, _ = cliNoSession.RunCommand(ctx, "config", "unset", "extension.requireSignatures")
+	}()
+
 	_, err = cliNoSession.RunCommand(ctx, "ext", "install", "microsoft.azd.demo", "-s", "local")
 	require.NoError(t, err)
 
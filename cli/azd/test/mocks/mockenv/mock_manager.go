@@ -69,6 +69,11 @@ func (m *MockEnvManager) Delete(ctx context.Context, name string) error {
 	return args.Error(0)
 }
 
+func (m *MockEnvManager) History(ctx context.Context, name string) ([]*environment.Revision, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*environment.Revision), args.Error(1)
+}
+
 func (m *MockEnvManager) InvalidateEnvCache(ctx context.Context, envName string) error {
 	args := m.Called(ctx, envName)
 	return args.Error(0)
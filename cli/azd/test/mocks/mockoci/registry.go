@@ -0,0 +1,220 @@
+// Copyright (c) Microsoft Corporation. All rights reserved.
+// Licensed under the MIT License.
+
+// Package mockoci provides an in-memory stand-in for an OCI distribution registry, such as the registry:2 image.
+package mockoci
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/azure/azure-dev/cli/azd/pkg/oci"
+	"github.com/stretchr/testify/require"
+)
+
+// Layer is the content of a layer pushed to the Registry.
+type Layer struct {
+	MediaType string
+	// Title is the file name of the layer, recorded in its title annotation when not empty
+	Title   string
+	Content []byte
+	// Annotations are additional annotations of the layer
+	Annotations map[string]string
+}
+
+// Registry serves the pull endpoints of the OCI distribution API, and the token endpoint of its token
+// authentication, over a local HTTP server.
+type Registry struct {
+	// Host is the host:port of the registry, to use in artifact references.
+	Host string
+
+	server    *httptest.Server
+	mu        sync.Mutex
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	username  string
+	password  string
+	token     string
+	realm     string
+}
+
+// NewRegistry starts a Registry, which is stopped when the test completes.
+func NewRegistry(t *testing.T) *Registry {
+	registry := &Registry{
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+
+	registry.server = httptest.NewServer(http.HandlerFunc(registry.serveHTTP))
+	t.Cleanup(registry.server.Close)
+
+	registry.Host = strings.TrimPrefix(registry.server.URL, "http://")
+
+	return registry
+}
+
+// RequireAuth requires clients to get access tokens with the basic credentials username and password.
+func (r *Registry) RequireAuth(username string, password string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.username = username
+	r.password = password
+	r.token = "token-" + digest([]byte(username + ":" + password))[7:19]
+}
+
+// SetRealm sends clients to the token server realm for access tokens, instead of the token endpoint of the registry.
+func (r *Registry) SetRealm(realm string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.realm = realm
+}
+
+// Push stores an artifact made of layers in repository, tagged with tag, and returns its reference with the oci://
+// scheme.
+func (r *Registry) Push(t *testing.T, repository string, tag string, layers ...Layer) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	config := []byte("{}")
+	r.blobs[digest(config)] = config
+
+	manifest := oci.Manifest{
+		SchemaVersion: 2,
+		MediaType:     oci.MediaTypeImageManifest,
+		Config: oci.Descriptor{
+			MediaType: "application/vnd.oci.empty.v1+json",
+			Digest:    digest(config),
+			Size:      int64(len(config)),
+		},
+	}
+
+	for _, layer := range layers {
+		descriptor := oci.Descriptor{
+			MediaType:   layer.MediaType,
+			Digest:      digest(layer.Content),
+			Size:        int64(len(layer.Content)),
+			Annotations: map[string]string{},
+		}
+
+		for name, value := range layer.Annotations {
+			descriptor.Annotations[name] = value
+		}
+
+		if layer.Title != "" {
+			descriptor.Annotations[oci.AnnotationTitle] = layer.Title
+		}
+
+		r.blobs[descriptor.Digest] = layer.Content
+		manifest.Layers = append(manifest.Layers, descriptor)
+	}
+
+	contents, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	r.manifests[repository+":"+tag] = contents
+	r.manifests[repository+"@"+digest(contents)] = contents
+
+	return fmt.Sprintf("%s%s/%s:%s", oci.Scheme, r.Host, repository, tag)
+}
+
+// Digest returns the digest of the manifest tagged with tag in repository.
+func (r *Registry) Digest(repository string, tag string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return digest(r.manifests[repository+":"+tag])
+}
+
+func (r *Registry) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if req.URL.Path == "/token" {
+		r.serveToken(w, req)
+		return
+	}
+
+	path, ok := strings.CutPrefix(req.URL.Path, "/v2/")
+	if !ok || req.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	repository := path
+	if index := strings.LastIndex(path, "/manifests/"); index >= 0 {
+		repository = path[:index]
+	} else if index := strings.LastIndex(path, "/blobs/"); index >= 0 {
+		repository = path[:index]
+	}
+
+	if r.token != "" && req.Header.Get("Authorization") != "Bearer "+r.token {
+		realm := r.realm
+		if realm == "" {
+			realm = r.server.URL + "/token"
+		}
+
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer realm="%s",service="mockoci",scope="repository:%s:pull"`, realm, repository))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if index := strings.LastIndex(path, "/manifests/"); index >= 0 {
+		reference := path[index+len("/manifests/"):]
+		separator := ":"
+		if strings.Contains(reference, ":") {
+			separator = "@"
+		}
+
+		manifest, has := r.manifests[repository+separator+reference]
+		if !has {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", oci.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest(manifest))
+		_, _ = w.Write(manifest)
+		return
+	}
+
+	if index := strings.LastIndex(path, "/blobs/"); index >= 0 {
+		blob, has := r.blobs[path[index+len("/blobs/"):]]
+		if !has {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(blob)
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (r *Registry) serveToken(w http.ResponseWriter, req *http.Request) {
+	expected := "Basic " + base64.StdEncoding.EncodeToString([]byte(r.username+":"+r.password))
+	if r.token == "" || req.Header.Get("Authorization") != expected {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"token": r.token})
+}
+
+func digest(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}